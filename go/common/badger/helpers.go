@@ -69,14 +69,6 @@ func (gc *GCWorker) worker() {
 	ticker := time.NewTicker(gcInterval)
 	defer ticker.Stop()
 
-	doGC := func() error {
-		for {
-			if err := gc.db.RunValueLogGC(gcDiscardRatio); err != nil {
-				return err
-			}
-		}
-	}
-
 	for {
 		select {
 		case <-gc.closeCh:
@@ -85,10 +77,7 @@ func (gc *GCWorker) worker() {
 		}
 
 		// Run the value log GC.
-		err := doGC()
-		switch err {
-		case nil, badger.ErrNoRewrite:
-		default:
+		if err := RunValueLogGC(gc.db); err != nil {
 			gc.logger.Error("failed to GC value log",
 				"err", err,
 			)
@@ -96,6 +85,20 @@ func (gc *GCWorker) worker() {
 	}
 }
 
+// RunValueLogGC repeatedly runs the value log GC until there is nothing left to rewrite.
+func RunValueLogGC(db *badger.DB) error {
+	for {
+		err := db.RunValueLogGC(gcDiscardRatio)
+		switch err {
+		case nil:
+		case badger.ErrNoRewrite:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 // NewGCWorker creates a new BadgerDB value log GC worker for the provided
 // db, logging to the specified logger.
 func NewGCWorker(logger *logging.Logger, db *badger.DB) *GCWorker {
@@ -0,0 +1,81 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// OpenIndex opens a BadgerDB database used as an index at the given path and starts a value log
+// GC worker for it.
+//
+// Writes are synchronous and compression is disabled.
+func OpenIndex(fn string, logger *logging.Logger) (*badger.DB, *GCWorker, error) {
+	opts := badger.DefaultOptions(fn)
+	opts = opts.WithLogger(NewLogAdapter(logger))
+	opts = opts.WithSyncWrites(true)
+	opts = opts.WithCompression(options.None)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, NewGCWorker(logger, db), nil
+}
+
+// EnsureMetadata makes sure that the database metadata stored under the given key is compatible.
+//
+// In case no metadata is stored yet, the CBOR-serialized initial metadata is stored. Otherwise the
+// stored metadata is passed to the verify function.
+func EnsureMetadata(db *badger.DB, key []byte, initial interface{}, verify func(raw []byte) error) error {
+	return db.Update(func(tx *badger.Txn) error {
+		item, err := tx.Get(key)
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			// Create new metadata section.
+			return tx.Set(key, cbor.Marshal(initial))
+		default:
+			return err
+		}
+
+		return item.Value(verify)
+	})
+}
+
+// DeleteIndexed deletes all keys with the given prefix together with the keys derived from them
+// by the given function. The function must return false in case a key cannot be decoded.
+//
+// This is useful for removing index entries via a secondary index that lists them.
+func DeleteIndexed(tx *badger.Txn, prefix []byte, derive func(key []byte) ([]byte, bool)) error {
+	// NOTE: Only one iterator can be active at a time in a read-write transaction so the
+	//       keys to delete are collected first.
+	var keys [][]byte
+	func() {
+		// NOTE: Do not prefetch values as we are only looking at keys.
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix: prefix,
+		})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			derived, ok := derive(it.Item().Key())
+			if !ok {
+				// This should not happen as the Badger iterator should take care of it.
+				panic("common/badger: bad iterator")
+			}
+			keys = append(keys, derived, it.Item().KeyCopy(nil))
+		}
+	}()
+
+	for _, key := range keys {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Package multisig implements threshold (M-of-N) multi-signature account
+// descriptors and envelopes.
+package multisig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// MaxSigners is the maximum number of signers in a multisig account.
+const MaxSigners = 32
+
+var (
+	// ErrInvalidAccount is the error returned when a multisig account
+	// descriptor is malformed.
+	ErrInvalidAccount = errors.New("multisig: invalid account descriptor")
+
+	// ErrInsufficientSignatures is the error returned when an envelope does
+	// not carry enough valid signatures to satisfy the account threshold.
+	ErrInsufficientSignatures = errors.New("multisig: insufficient signatures")
+
+	// ErrUnknownSigner is the error returned when an envelope carries a
+	// signature by a key that is not a signer of the account.
+	ErrUnknownSigner = errors.New("multisig: signature by unknown signer")
+
+	// ErrDuplicateSignature is the error returned when an envelope carries
+	// more than one signature by the same signer.
+	ErrDuplicateSignature = errors.New("multisig: duplicate signature")
+)
+
+// Account is a multisig account descriptor.
+//
+// The account is controlled by the set of signers and any transaction must
+// be signed by at least Threshold distinct signers.
+type Account struct {
+	// Signers are the public keys of the account signers.
+	Signers []signature.PublicKey `json:"signers"`
+
+	// Threshold is the minimum number of signers that must sign.
+	Threshold uint8 `json:"threshold"`
+}
+
+// ValidateBasic performs basic multisig account descriptor validity checks.
+func (a *Account) ValidateBasic() error {
+	switch {
+	case len(a.Signers) == 0:
+		return fmt.Errorf("%w: no signers", ErrInvalidAccount)
+	case len(a.Signers) > MaxSigners:
+		return fmt.Errorf("%w: too many signers (max: %d)", ErrInvalidAccount, MaxSigners)
+	case a.Threshold == 0:
+		return fmt.Errorf("%w: zero threshold", ErrInvalidAccount)
+	case int(a.Threshold) > len(a.Signers):
+		return fmt.Errorf("%w: threshold %d exceeds number of signers %d", ErrInvalidAccount, a.Threshold, len(a.Signers))
+	}
+
+	seen := make(map[signature.PublicKey]bool, len(a.Signers))
+	for _, pk := range a.Signers {
+		if !pk.IsValid() {
+			return fmt.Errorf("%w: malformed signer %s", ErrInvalidAccount, pk)
+		}
+		if pk.IsBlacklisted() {
+			return fmt.Errorf("%w: blacklisted signer %s", ErrInvalidAccount, pk)
+		}
+		if seen[pk] {
+			return fmt.Errorf("%w: duplicate signer %s", ErrInvalidAccount, pk)
+		}
+		seen[pk] = true
+	}
+
+	return nil
+}
+
+// HasSigner returns true iff the given public key is one of the account
+// signers.
+func (a *Account) HasSigner(pk signature.PublicKey) bool {
+	for _, v := range a.Signers {
+		if v.Equal(pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash returns the cryptographic hash of the account descriptor.
+func (a *Account) Hash() hash.Hash {
+	return hash.NewFrom(a)
+}
+
+// Verify verifies that the given signatures over the context and message
+// satisfy the account's threshold.
+//
+// Every signature must be valid and made by a distinct account signer.
+func (a *Account) Verify(context signature.Context, message []byte, sigs []signature.Signature) error {
+	if err := a.ValidateBasic(); err != nil {
+		return err
+	}
+
+	seen := make(map[signature.PublicKey]bool, len(sigs))
+	for _, sig := range sigs {
+		if !a.HasSigner(sig.PublicKey) {
+			return fmt.Errorf("%w: %s", ErrUnknownSigner, sig.PublicKey)
+		}
+		if seen[sig.PublicKey] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSignature, sig.PublicKey)
+		}
+		seen[sig.PublicKey] = true
+	}
+	if len(seen) < int(a.Threshold) {
+		return fmt.Errorf("%w: have %d, need %d", ErrInsufficientSignatures, len(seen), a.Threshold)
+	}
+	if !signature.VerifyManyToOne(context, message, sigs) {
+		return signature.ErrVerifyFailed
+	}
+
+	return nil
+}
+
+// Envelope is a blob signed by a threshold of multisig account signers.
+type Envelope struct {
+	// Account is the multisig account descriptor.
+	Account Account `json:"account"`
+
+	// Blob is the signed blob.
+	Blob []byte `json:"untrusted_raw_value"`
+
+	// Signatures are the signatures over the blob.
+	Signatures []signature.Signature `json:"signatures"`
+}
+
+// NewEnvelope creates a new envelope without any signatures over the
+// CBOR-serialized message.
+func NewEnvelope(account *Account, src interface{}) (*Envelope, error) {
+	if err := account.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		Account: *account,
+		Blob:    cbor.Marshal(src),
+	}, nil
+}
+
+// Sign adds a signature by the given signer to the envelope.
+func (e *Envelope) Sign(signer signature.Signer, context signature.Context) error {
+	pk := signer.Public()
+	if !e.Account.HasSigner(pk) {
+		return fmt.Errorf("%w: %s", ErrUnknownSigner, pk)
+	}
+	if e.IsSignedBy(pk) {
+		return fmt.Errorf("%w: %s", ErrDuplicateSignature, pk)
+	}
+
+	sig, err := signature.Sign(signer, context, e.Blob)
+	if err != nil {
+		return err
+	}
+	e.Signatures = append(e.Signatures, *sig)
+
+	return nil
+}
+
+// IsSignedBy returns true iff the envelope includes a signature for the
+// provided public key.
+//
+// Note: This does not verify the signature.
+func (e *Envelope) IsSignedBy(pk signature.PublicKey) bool {
+	for _, v := range e.Signatures {
+		if v.PublicKey.Equal(pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open first verifies the blob signatures against the account, and then
+// unmarshals the blob.
+func (e *Envelope) Open(context signature.Context, dst interface{}) error {
+	if err := e.Account.Verify(context, e.Blob, e.Signatures); err != nil {
+		return err
+	}
+
+	return cbor.Unmarshal(e.Blob, dst)
+}
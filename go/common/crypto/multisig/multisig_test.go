@@ -0,0 +1,98 @@
+package multisig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+var testContext = signature.NewContext("oasis-core/multisig: test")
+
+func TestAccountValidateBasic(t *testing.T) {
+	require := require.New(t)
+
+	pk1 := memorySigner.NewTestSigner("multisig test signer 1").Public()
+	pk2 := memorySigner.NewTestSigner("multisig test signer 2").Public()
+
+	for _, tc := range []struct {
+		account Account
+		valid   bool
+		msg     string
+	}{
+		{Account{}, false, "no signers"},
+		{Account{Signers: []signature.PublicKey{pk1}}, false, "zero threshold"},
+		{Account{Signers: []signature.PublicKey{pk1}, Threshold: 2}, false, "threshold too large"},
+		{Account{Signers: []signature.PublicKey{pk1, pk1}, Threshold: 1}, false, "duplicate signer"},
+		{Account{Signers: []signature.PublicKey{pk1, pk2}, Threshold: 1}, true, "1-of-2"},
+		{Account{Signers: []signature.PublicKey{pk1, pk2}, Threshold: 2}, true, "2-of-2"},
+	} {
+		err := tc.account.ValidateBasic()
+		switch tc.valid {
+		case true:
+			require.NoError(err, tc.msg)
+		case false:
+			require.ErrorIs(err, ErrInvalidAccount, tc.msg)
+		}
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	require := require.New(t)
+
+	signer1 := memorySigner.NewTestSigner("multisig test signer 1")
+	signer2 := memorySigner.NewTestSigner("multisig test signer 2")
+	signer3 := memorySigner.NewTestSigner("multisig test signer 3")
+	outsider := memorySigner.NewTestSigner("multisig test outsider")
+
+	account := Account{
+		Signers:   []signature.PublicKey{signer1.Public(), signer2.Public(), signer3.Public()},
+		Threshold: 2,
+	}
+	msg := "hello multisig"
+
+	env, err := NewEnvelope(&account, msg)
+	require.NoError(err, "NewEnvelope")
+
+	var opened string
+	err = env.Open(testContext, &opened)
+	require.ErrorIs(err, ErrInsufficientSignatures, "Open without signatures")
+
+	err = env.Sign(signer1, testContext)
+	require.NoError(err, "Sign (signer 1)")
+	err = env.Sign(signer1, testContext)
+	require.ErrorIs(err, ErrDuplicateSignature, "Sign (signer 1 again)")
+	err = env.Sign(outsider, testContext)
+	require.ErrorIs(err, ErrUnknownSigner, "Sign (outsider)")
+
+	err = env.Open(testContext, &opened)
+	require.ErrorIs(err, ErrInsufficientSignatures, "Open below threshold")
+
+	err = env.Sign(signer3, testContext)
+	require.NoError(err, "Sign (signer 3)")
+	require.True(env.IsSignedBy(signer1.Public()), "IsSignedBy (signer 1)")
+	require.False(env.IsSignedBy(signer2.Public()), "IsSignedBy (signer 2)")
+
+	err = env.Open(testContext, &opened)
+	require.NoError(err, "Open at threshold")
+	require.Equal(msg, opened, "opened message")
+
+	// Wrong context must fail verification.
+	err = env.Open(signature.NewContext("oasis-core/multisig: test other"), &opened)
+	require.ErrorIs(err, signature.ErrVerifyFailed, "Open with wrong context")
+
+	// Injected signature by an unknown signer must be rejected.
+	sig, err := signature.Sign(outsider, testContext, env.Blob)
+	require.NoError(err, "Sign (outsider, raw)")
+	tampered := *env
+	tampered.Signatures = append(append([]signature.Signature{}, env.Signatures...), *sig)
+	err = tampered.Open(testContext, &opened)
+	require.ErrorIs(err, ErrUnknownSigner, "Open with unknown signer")
+
+	// Duplicate signatures must not count towards the threshold.
+	tampered.Signatures = []signature.Signature{env.Signatures[0], env.Signatures[0]}
+	err = tampered.Open(testContext, &opened)
+	require.ErrorIs(err, ErrDuplicateSignature, "Open with duplicate signature")
+}
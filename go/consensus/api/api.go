@@ -14,6 +14,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/service"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -30,8 +31,10 @@ import (
 )
 
 const (
+	// ModuleName is the consensus module name.
+	ModuleName = "consensus"
 	// moduleName is the module name used for error definitions.
-	moduleName = "consensus"
+	moduleName = ModuleName
 
 	// HeightLatest is the height that represents the most recent block height.
 	HeightLatest int64 = 0
@@ -70,6 +73,10 @@ const (
 	// FeatureFullNode indicates that the consensus backend is independently fully verifying all
 	// consensus-layer blocks.
 	FeatureFullNode FeatureMask = 1 << 1
+
+	// FeatureArchiveNode indicates that the consensus backend is serving historic consensus state
+	// from an existing data directory without participating in consensus.
+	FeatureArchiveNode FeatureMask = 1 << 2
 )
 
 // String returns a string representation of the consensus backend feature bitmask.
@@ -81,6 +88,9 @@ func (m FeatureMask) String() string {
 	if m&FeatureFullNode != 0 {
 		ret = append(ret, "full node")
 	}
+	if m&FeatureArchiveNode != 0 {
+		ret = append(ret, "archive node")
+	}
 
 	return strings.Join(ret, ",")
 }
@@ -100,12 +110,23 @@ type ClientBackend interface {
 	// in a block. Use SubmitTxNoWait if you only need to broadcast the transaction.
 	SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error
 
+	// SubmitMultiSignedTx submits a consensus transaction signed by a threshold of multisig
+	// account signers and waits for the transaction to be included in a block.
+	SubmitMultiSignedTx(ctx context.Context, tx *transaction.MultiSignedTransaction) error
+
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error)
 
 	// EstimateGas calculates the amount of gas required to execute the given transaction.
 	EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error)
 
+	// SimulateTx executes the given transaction against a copy of the consensus state at the
+	// given height, as if it was included in the following block, without committing any changes.
+	//
+	// The returned result includes the transaction execution result with emitted events and the
+	// resulting changes of balances of the involved accounts.
+	SimulateTx(ctx context.Context, req *SimulateTxRequest) (*SimulateTxResponse, error)
+
 	// GetBlock returns a consensus block at a specific height.
 	GetBlock(ctx context.Context, height int64) (*Block, error)
 
@@ -120,6 +141,13 @@ type ClientBackend interface {
 	// height.
 	GetTransactionsWithResults(ctx context.Context, height int64) (*TransactionsWithResults, error)
 
+	// GetTransactionsByAddress returns consensus transactions that were signed by the given
+	// account address or that emitted staking events involving it.
+	//
+	// This requires the consensus transaction indexer to be enabled on the node, otherwise
+	// ErrUnsupported is returned.
+	GetTransactionsByAddress(ctx context.Context, request *GetTransactionsByAddressRequest) (*GetTransactionsByAddressResponse, error)
+
 	// GetUnconfirmedTransactions returns a list of transactions currently in the local node's
 	// mempool. These have not yet been included in a block.
 	GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error)
@@ -128,6 +156,14 @@ type ClientBackend interface {
 	// blocks as they are being finalized.
 	WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error)
 
+	// WatchAllEvents returns a channel that produces a stream of events emitted by all consensus
+	// services that pass the given filter. In case a starting height is given, historic events
+	// are replayed before switching to live events.
+	//
+	// All events of a height are followed by an end of height marker, which should be used to
+	// determine the height to resume from (see WatchAllEventsRequest.FromHeight).
+	WatchAllEvents(ctx context.Context, request *WatchAllEventsRequest) (<-chan *Event, pubsub.ClosableSubscription, error)
+
 	// GetGenesisDocument returns the original genesis document.
 	GetGenesisDocument(ctx context.Context) (*genesis.Document, error)
 
@@ -140,6 +176,9 @@ type ClientBackend interface {
 	// GetNextBlockState returns the state of the next block being voted on by validators.
 	GetNextBlockState(ctx context.Context) (*NextBlockState, error)
 
+	// GasPrice returns the gas price suggested by the node's price discovery mechanism.
+	GasPrice(ctx context.Context) (*quantity.Quantity, error)
+
 	// Beacon returns the beacon backend.
 	Beacon() beacon.Backend
 
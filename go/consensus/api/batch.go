@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+// MaxBatchCalls is the maximum number of calls in a single batch.
+const MaxBatchCalls = 32
+
+// MethodBatch is the method name for executing a batch of calls atomically.
+var MethodBatch = transaction.NewMethodName(ModuleName, "Batch", Batch{})
+
+// BatchCall is a single call in a batch.
+type BatchCall struct {
+	// Method is the called method.
+	Method transaction.MethodName `json:"method"`
+	// Body is the method call body.
+	Body cbor.RawMessage `json:"body,omitempty"`
+}
+
+// Batch is a batch of calls executed atomically in order under the nonce and fee of the
+// enclosing transaction. In case any of the calls fails, none of the calls have any effect.
+//
+// Before the events of each executed call, a results.BatchCallEvent is emitted.
+type Batch struct {
+	Calls []BatchCall `json:"calls"`
+}
+
+// ValidateBasic performs basic batch validity checks.
+func (b *Batch) ValidateBasic() error {
+	if len(b.Calls) == 0 {
+		return fmt.Errorf("%w: empty batch", ErrInvalidArgument)
+	}
+	if len(b.Calls) > MaxBatchCalls {
+		return fmt.Errorf("%w: too many batch calls (max: %d)", ErrInvalidArgument, MaxBatchCalls)
+	}
+	for i, call := range b.Calls {
+		switch {
+		case call.Method == MethodBatch:
+			return fmt.Errorf("%w: nested batch in call %d", ErrInvalidArgument, i)
+		case call.Method.BodyType() == nil:
+			return fmt.Errorf("%w: unknown method in call %d: %s", ErrInvalidArgument, i, call.Method)
+		case call.Method.IsCritical():
+			// Critical methods are exempt from fees so they must not be batched.
+			return fmt.Errorf("%w: critical method in call %d: %s", ErrInvalidArgument, i, call.Method)
+		}
+	}
+	return nil
+}
+
+// PrettyPrint writes a pretty-printed representation of Batch to the given writer.
+func (b Batch) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	for i, call := range b.Calls {
+		fmt.Fprintf(w, "%sCall %d:\n", prefix, i)
+		fmt.Fprintf(w, "%s  Method: %s\n", prefix, call.Method)
+		fmt.Fprintf(w, "%s  Body:\n", prefix)
+		tx := transaction.Transaction{Method: call.Method, Body: call.Body}
+		tx.PrettyPrintBody(ctx, prefix+"    ", w)
+	}
+}
+
+// PrettyType returns a representation of Batch that can be used for pretty printing.
+func (b Batch) PrettyType() (interface{}, error) {
+	return b, nil
+}
+
+// NewBatchCall creates a new batch call for the given method and body.
+func NewBatchCall(method transaction.MethodName, body interface{}) BatchCall {
+	return BatchCall{
+		Method: method,
+		Body:   cbor.Marshal(body),
+	}
+}
+
+// NewBatchTx creates a new batch transaction.
+func NewBatchTx(nonce uint64, fee *transaction.Fee, batch *Batch) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodBatch, batch)
+}
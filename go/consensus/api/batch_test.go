@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestBatchValidateBasic(t *testing.T) {
+	require := require.New(t)
+
+	transfer := NewBatchCall(staking.MethodTransfer, &staking.Transfer{})
+	escrow := NewBatchCall(staking.MethodAddEscrow, &staking.Escrow{})
+
+	batch := Batch{Calls: []BatchCall{transfer, escrow}}
+	require.NoError(batch.ValidateBasic(), "ValidateBasic should succeed for a valid batch")
+
+	tx := NewBatchTx(0, nil, &batch)
+	require.EqualValues(MethodBatch, tx.Method)
+	var decBatch Batch
+	require.NoError(cbor.Unmarshal(tx.Body, &decBatch), "batch transaction body should decode")
+	require.EqualValues(batch, decBatch, "batch transaction body should round-trip")
+
+	batch = Batch{}
+	require.ErrorIs(batch.ValidateBasic(), ErrInvalidArgument, "ValidateBasic should fail for an empty batch")
+
+	batch = Batch{}
+	for i := 0; i <= MaxBatchCalls; i++ {
+		batch.Calls = append(batch.Calls, transfer)
+	}
+	require.ErrorIs(batch.ValidateBasic(), ErrInvalidArgument, "ValidateBasic should fail for too many calls")
+
+	batch = Batch{Calls: []BatchCall{transfer, NewBatchCall(MethodBatch, &Batch{Calls: []BatchCall{transfer}})}}
+	require.ErrorIs(batch.ValidateBasic(), ErrInvalidArgument, "ValidateBasic should fail for nested batches")
+
+	batch = Batch{Calls: []BatchCall{{Method: "test.Unknown"}}}
+	require.ErrorIs(batch.ValidateBasic(), ErrInvalidArgument, "ValidateBasic should fail for unknown methods")
+}
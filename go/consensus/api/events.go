@@ -0,0 +1,390 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	maxEventsRetryElapsedTime = 60 * time.Second
+	maxEventsRetryInterval    = 10 * time.Second
+)
+
+// Event is a consensus event emitted by any of the consensus services.
+//
+// Exactly one of the service-specific event fields is set, unless the event is an end of height
+// marker in which case none are set.
+type Event struct {
+	// Height is the height of the block that emitted the event.
+	Height int64 `json:"height"`
+	// TxHash is the hash of the transaction that emitted the event (if any).
+	TxHash hash.Hash `json:"tx_hash,omitempty"`
+	// EndOfHeight is set on the marker that follows all events of a height.
+	EndOfHeight bool `json:"end_of_height,omitempty"`
+
+	Staking    *staking.Event    `json:"staking,omitempty"`
+	Registry   *registry.Event   `json:"registry,omitempty"`
+	RootHash   *roothash.Event   `json:"roothash,omitempty"`
+	Governance *governance.Event `json:"governance,omitempty"`
+}
+
+// Module returns the name of the module that emitted the event.
+func (e *Event) Module() string {
+	switch {
+	case e.Staking != nil:
+		return staking.ModuleName
+	case e.Registry != nil:
+		return registry.ModuleName
+	case e.RootHash != nil:
+		return roothash.ModuleName
+	case e.Governance != nil:
+		return governance.ModuleName
+	default:
+		return ""
+	}
+}
+
+// Kind returns the kind of the event (e.g., "transfer").
+func (e *Event) Kind() string {
+	switch {
+	case e.Staking != nil:
+		ev := e.Staking
+		switch {
+		case ev.Transfer != nil:
+			return ev.Transfer.EventKind()
+		case ev.Burn != nil:
+			return ev.Burn.EventKind()
+		case ev.Escrow != nil && ev.Escrow.Add != nil:
+			return ev.Escrow.Add.EventKind()
+		case ev.Escrow != nil && ev.Escrow.Take != nil:
+			return ev.Escrow.Take.EventKind()
+		case ev.Escrow != nil && ev.Escrow.DebondingStart != nil:
+			return ev.Escrow.DebondingStart.EventKind()
+		case ev.Escrow != nil && ev.Escrow.Reclaim != nil:
+			return ev.Escrow.Reclaim.EventKind()
+		case ev.AllowanceChange != nil:
+			return ev.AllowanceChange.EventKind()
+		case ev.Vesting != nil:
+			return ev.Vesting.EventKind()
+		}
+	case e.Registry != nil:
+		ev := e.Registry
+		switch {
+		case ev.RuntimeEvent != nil:
+			return ev.RuntimeEvent.EventKind()
+		case ev.EntityEvent != nil:
+			return ev.EntityEvent.EventKind()
+		case ev.NodeEvent != nil:
+			return ev.NodeEvent.EventKind()
+		case ev.NodeUnfrozenEvent != nil:
+			return ev.NodeUnfrozenEvent.EventKind()
+		}
+	case e.RootHash != nil:
+		ev := e.RootHash
+		switch {
+		case ev.ExecutorCommitted != nil:
+			return ev.ExecutorCommitted.EventKind()
+		case ev.ExecutionDiscrepancyDetected != nil:
+			return ev.ExecutionDiscrepancyDetected.EventKind()
+		case ev.Finalized != nil:
+			return ev.Finalized.EventKind()
+		case ev.InMsgProcessed != nil:
+			return ev.InMsgProcessed.EventKind()
+		}
+	case e.Governance != nil:
+		ev := e.Governance
+		switch {
+		case ev.ProposalSubmitted != nil:
+			return ev.ProposalSubmitted.EventKind()
+		case ev.ProposalExecuted != nil:
+			return ev.ProposalExecuted.EventKind()
+		case ev.ProposalFinalized != nil:
+			return ev.ProposalFinalized.EventKind()
+		case ev.Vote != nil:
+			return ev.Vote.EventKind()
+		}
+	}
+	return ""
+}
+
+// Addresses returns the staking account addresses involved in the event.
+func (e *Event) Addresses() []staking.Address {
+	var addrs []staking.Address
+	switch {
+	case e.Staking != nil:
+		ev := e.Staking
+		switch {
+		case ev.Transfer != nil:
+			addrs = append(addrs, ev.Transfer.From, ev.Transfer.To)
+		case ev.Burn != nil:
+			addrs = append(addrs, ev.Burn.Owner)
+		case ev.Escrow != nil && ev.Escrow.Add != nil:
+			addrs = append(addrs, ev.Escrow.Add.Owner, ev.Escrow.Add.Escrow)
+		case ev.Escrow != nil && ev.Escrow.Take != nil:
+			addrs = append(addrs, ev.Escrow.Take.Owner)
+		case ev.Escrow != nil && ev.Escrow.DebondingStart != nil:
+			addrs = append(addrs, ev.Escrow.DebondingStart.Owner, ev.Escrow.DebondingStart.Escrow)
+		case ev.Escrow != nil && ev.Escrow.Reclaim != nil:
+			addrs = append(addrs, ev.Escrow.Reclaim.Owner, ev.Escrow.Reclaim.Escrow)
+		case ev.AllowanceChange != nil:
+			addrs = append(addrs, ev.AllowanceChange.Owner, ev.AllowanceChange.Beneficiary)
+		case ev.Vesting != nil:
+			addrs = append(addrs, ev.Vesting.From, ev.Vesting.To)
+		}
+	case e.Registry != nil:
+		ev := e.Registry
+		switch {
+		case ev.RuntimeEvent != nil && ev.RuntimeEvent.Runtime != nil:
+			addrs = append(addrs,
+				staking.NewRuntimeAddress(ev.RuntimeEvent.Runtime.ID),
+				staking.NewAddress(ev.RuntimeEvent.Runtime.EntityID),
+			)
+		case ev.EntityEvent != nil && ev.EntityEvent.Entity != nil:
+			addrs = append(addrs, staking.NewAddress(ev.EntityEvent.Entity.ID))
+		case ev.NodeEvent != nil && ev.NodeEvent.Node != nil:
+			addrs = append(addrs,
+				staking.NewAddress(ev.NodeEvent.Node.ID),
+				staking.NewAddress(ev.NodeEvent.Node.EntityID),
+			)
+		case ev.NodeUnfrozenEvent != nil:
+			addrs = append(addrs, staking.NewAddress(ev.NodeUnfrozenEvent.NodeID))
+		}
+	case e.RootHash != nil:
+		addrs = append(addrs, staking.NewRuntimeAddress(e.RootHash.RuntimeID))
+		if e.RootHash.InMsgProcessed != nil {
+			addrs = append(addrs, e.RootHash.InMsgProcessed.Caller)
+		}
+	case e.Governance != nil:
+		ev := e.Governance
+		switch {
+		case ev.ProposalSubmitted != nil:
+			addrs = append(addrs, ev.ProposalSubmitted.Submitter)
+		case ev.Vote != nil:
+			addrs = append(addrs, ev.Vote.Submitter)
+		}
+	}
+	return addrs
+}
+
+// EventFilter is a filter for consensus events.
+//
+// Each non-empty criterion must match for the event to pass the filter. Within a criterion, any of
+// the listed values may match.
+type EventFilter struct {
+	// Modules is a list of modules (e.g., "staking") whose events should be included.
+	Modules []string `json:"modules,omitempty"`
+	// Kinds is a list of event kinds (e.g., "transfer") that should be included.
+	Kinds []string `json:"kinds,omitempty"`
+	// Addresses is a list of staking account addresses of which at least one must be involved in
+	// the event.
+	Addresses []staking.Address `json:"addresses,omitempty"`
+}
+
+// Matches returns true iff the given event passes the filter.
+func (f *EventFilter) Matches(ev *Event) bool {
+	if len(f.Modules) > 0 && !containsString(f.Modules, ev.Module()) {
+		return false
+	}
+	if len(f.Kinds) > 0 && !containsString(f.Kinds, ev.Kind()) {
+		return false
+	}
+	if len(f.Addresses) > 0 {
+		var found bool
+		for _, addr := range ev.Addresses() {
+			for _, filterAddr := range f.Addresses {
+				if addr.Equal(filterAddr) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchAllEventsRequest is a WatchAllEvents request.
+type WatchAllEventsRequest struct {
+	// Filter is the filter that events must pass in order to be emitted.
+	Filter EventFilter `json:"filter"`
+
+	// FromHeight is the height to start emitting events from. In case it is in the past, events
+	// from historic blocks are replayed before switching to live events.
+	//
+	// After all events of a height have been emitted, an end of height marker for that height is
+	// emitted (even if the filter matched no events). To resume an interrupted stream without
+	// missing or duplicating events, clients should set FromHeight to the height of the last
+	// received end of height marker plus one and discard any events received after that marker.
+	//
+	// If zero, only events from new blocks are emitted.
+	FromHeight int64 `json:"from_height,omitempty"`
+}
+
+// AllEventsFunc returns the events emitted by all consensus services at the given height in the
+// order in which they were emitted.
+type AllEventsFunc func(ctx context.Context, height int64) ([]*Event, error)
+
+// getFilteredEvents returns the events at the given height that pass the given filter, retrying
+// with exponential backoff in case the events cannot be retrieved.
+func getFilteredEvents(ctx context.Context, getAllEvents AllEventsFunc, height int64, filter *EventFilter) ([]*Event, error) {
+	sched := cmnBackoff.NewExponentialBackOff()
+	sched.MaxInterval = maxEventsRetryInterval
+	sched.MaxElapsedTime = maxEventsRetryElapsedTime
+
+	var evs []*Event
+	err := backoff.Retry(func() error {
+		var err error
+		evs, err = getAllEvents(ctx, height)
+		if errors.Is(err, ErrVersionNotFound) {
+			// Pruned heights will never become available again.
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithContext(sched, ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events at height %d: %w", height, err)
+	}
+
+	filtered := make([]*Event, 0, len(evs))
+	for _, ev := range evs {
+		if filter.Matches(ev) {
+			filtered = append(filtered, ev)
+		}
+	}
+	return filtered, nil
+}
+
+// WatchAllEvents implements ClientBackend.WatchAllEvents on top of the given function for
+// retrieving all events at a height and WatchBlocks of the given backend.
+//
+// Events are emitted in height order and, within a height, in emission order, each height being
+// terminated by an end of height marker. Retrieving events is retried with exponential backoff.
+// The returned channel is closed when the subscription is closed, the context is canceled or in
+// case events still cannot be retrieved after retrying, in which case the error is logged.
+func WatchAllEvents(
+	ctx context.Context,
+	backend ClientBackend,
+	getAllEvents AllEventsFunc,
+	request *WatchAllEventsRequest,
+) (<-chan *Event, pubsub.ClosableSubscription, error) {
+	if request.FromHeight < 0 {
+		return nil, nil, ErrInvalidArgument
+	}
+	if request.FromHeight > 0 {
+		status, err := backend.GetStatus(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if request.FromHeight < status.LastRetainedHeight {
+			return nil, nil, ErrVersionNotFound
+		}
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	blkCh, blkSub, err := backend.WatchBlocks(ctx)
+	if err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+
+	logger := logging.GetLogger("consensus/events")
+	ch := make(chan *Event)
+	go func() {
+		defer close(ch)
+		defer blkSub.Close()
+
+		// emitHeights emits all events in the given range of heights (inclusive), followed by an
+		// end of height marker for each height.
+		emitHeights := func(from, to int64) bool {
+			for height := from; height <= to; height++ {
+				evs, err := getFilteredEvents(ctx, getAllEvents, height, &request.Filter)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error("failed to get events, closing event stream",
+							"err", err,
+							"height", height,
+						)
+					}
+					return false
+				}
+				evs = append(evs, &Event{Height: height, EndOfHeight: true})
+				for _, ev := range evs {
+					select {
+					case ch <- ev:
+					case <-ctx.Done():
+						return false
+					}
+				}
+			}
+			return true
+		}
+
+		// Replay historic events when resuming from a past height. The block subscription has
+		// already been established so no blocks can be missed.
+		nextHeight := request.FromHeight
+		if nextHeight > 0 {
+			blk, err := backend.GetBlock(ctx, HeightLatest)
+			if err != nil {
+				logger.Error("failed to get latest block, closing event stream",
+					"err", err,
+				)
+				return
+			}
+			if !emitHeights(nextHeight, blk.Height) {
+				return
+			}
+			if blk.Height >= nextHeight {
+				nextHeight = blk.Height + 1
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case blk, ok := <-blkCh:
+				if !ok {
+					return
+				}
+				if nextHeight == 0 {
+					nextHeight = blk.Height
+				}
+				if blk.Height < nextHeight {
+					// Already emitted while replaying history.
+					continue
+				}
+				// Also emit any heights that might have been skipped.
+				if !emitHeights(nextHeight, blk.Height) {
+					return
+				}
+				nextHeight = blk.Height + 1
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestEventFilter(t *testing.T) {
+	require := require.New(t)
+
+	addr1 := staking.NewAddress(signature.NewPublicKey("1000000000000000000000000000000000000000000000000000000000000000"))
+	addr2 := staking.NewAddress(signature.NewPublicKey("2000000000000000000000000000000000000000000000000000000000000000"))
+	addr3 := staking.NewAddress(signature.NewPublicKey("3000000000000000000000000000000000000000000000000000000000000000"))
+	var runtimeID common.Namespace
+	_ = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+
+	transfer := &Event{Staking: &staking.Event{Transfer: &staking.TransferEvent{From: addr1, To: addr2}}}
+	reclaim := &Event{Staking: &staking.Event{Escrow: &staking.EscrowEvent{
+		Reclaim: &staking.ReclaimEscrowEvent{Owner: addr2, Escrow: addr3},
+	}}}
+	vote := &Event{Governance: &governance.Event{Vote: &governance.VoteEvent{Submitter: addr3}}}
+	finalized := &Event{RootHash: &roothash.Event{RuntimeID: runtimeID, Finalized: &roothash.FinalizedEvent{}}}
+
+	require.Equal("staking", transfer.Module())
+	require.Equal("transfer", transfer.Kind())
+	require.Equal("reclaim_escrow", reclaim.Kind())
+	require.Equal("governance", vote.Module())
+	require.Equal("vote", vote.Kind())
+	require.Equal("roothash", finalized.Module())
+	require.Equal("finalized", finalized.Kind())
+	require.EqualValues([]staking.Address{staking.NewRuntimeAddress(runtimeID)}, finalized.Addresses())
+
+	all := []*Event{transfer, reclaim, vote, finalized}
+	for _, tc := range []struct {
+		filter   EventFilter
+		expected []*Event
+	}{
+		{EventFilter{}, all},
+		{EventFilter{Modules: []string{"staking"}}, []*Event{transfer, reclaim}},
+		{EventFilter{Modules: []string{"staking", "roothash"}, Kinds: []string{"transfer", "finalized"}}, []*Event{transfer, finalized}},
+		{EventFilter{Addresses: []staking.Address{addr3}}, []*Event{reclaim, vote}},
+		{EventFilter{Modules: []string{"staking"}, Addresses: []staking.Address{addr3}}, []*Event{reclaim}},
+		{EventFilter{Kinds: []string{"burn"}}, nil},
+	} {
+		var matched []*Event
+		for _, ev := range all {
+			if tc.filter.Matches(ev) {
+				matched = append(matched, ev)
+			}
+		}
+		require.EqualValues(tc.expected, matched, "filter %+v", tc.filter)
+	}
+}
+
+// eventsBackend is a minimal consensus backend that emits a transfer event followed by a vote
+// event at selected heights.
+type eventsBackend struct {
+	ClientBackend
+
+	latestHeight   int64
+	transferHeight map[int64]bool
+	blkCh          chan *Block
+
+	// failures is the number of times retrieving events fails before succeeding.
+	failures int
+}
+
+func (b *eventsBackend) GetStatus(ctx context.Context) (*Status, error) {
+	return &Status{LastRetainedHeight: 1}, nil
+}
+
+func (b *eventsBackend) GetBlock(ctx context.Context, height int64) (*Block, error) {
+	return &Block{Height: b.latestHeight}, nil
+}
+
+func (b *eventsBackend) WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error) {
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return b.blkCh, sub, nil
+}
+
+func (b *eventsBackend) getAllEvents(ctx context.Context, height int64) ([]*Event, error) {
+	if b.failures > 0 {
+		b.failures--
+		return nil, fmt.Errorf("transient failure")
+	}
+	if !b.transferHeight[height] {
+		return nil, nil
+	}
+	return []*Event{
+		{Height: height, Staking: &staking.Event{Height: height, Transfer: &staking.TransferEvent{}}},
+		{Height: height, Governance: &governance.Event{Height: height, Vote: &governance.VoteEvent{}}},
+	}, nil
+}
+
+func TestWatchAllEvents(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	backend := &eventsBackend{
+		latestHeight:   3,
+		transferHeight: map[int64]bool{2: true, 4: true},
+		blkCh:          make(chan *Block, 1),
+	}
+
+	type emitted struct {
+		height      int64
+		module      string
+		endOfHeight bool
+	}
+	recvEvents := func(ch <-chan *Event, n int) []emitted {
+		var evs []emitted
+		for i := 0; i < n; i++ {
+			select {
+			case ev := <-ch:
+				require.NotNil(ev, "returned event should not be nil")
+				evs = append(evs, emitted{ev.Height, ev.Module(), ev.EndOfHeight})
+			case <-time.After(5 * time.Second):
+				t.Fatalf("failed to receive event")
+			}
+		}
+		return evs
+	}
+
+	// Historic heights should be replayed, each terminated by an end of height marker, followed
+	// by live heights.
+	ch, sub, err := WatchAllEvents(ctx, backend, backend.getAllEvents, &WatchAllEventsRequest{FromHeight: 1})
+	require.NoError(err, "WatchAllEvents")
+	require.EqualValues([]emitted{
+		{1, "", true},
+		{2, staking.ModuleName, false},
+		{2, governance.ModuleName, false},
+		{2, "", true},
+		{3, "", true},
+	}, recvEvents(ch, 5), "historic events should be replayed in order")
+
+	backend.latestHeight = 4
+	backend.blkCh <- &Block{Height: 4}
+	require.EqualValues([]emitted{
+		{4, staking.ModuleName, false},
+		{4, governance.ModuleName, false},
+		{4, "", true},
+	}, recvEvents(ch, 3), "live events should follow historic events")
+	sub.Close()
+
+	// Resuming after the last end of height marker should continue with the next height, retrying
+	// in case events cannot be retrieved.
+	backend.failures = 1
+	ch, sub, err = WatchAllEvents(ctx, backend, backend.getAllEvents, &WatchAllEventsRequest{FromHeight: 4})
+	require.NoError(err, "WatchAllEvents")
+	require.EqualValues([]emitted{
+		{4, staking.ModuleName, false},
+		{4, governance.ModuleName, false},
+		{4, "", true},
+	}, recvEvents(ch, 3), "resumed stream should start at the requested height")
+	sub.Close()
+
+	// Markers should be emitted even if no events pass the filter.
+	ch, sub, err = WatchAllEvents(ctx, backend, backend.getAllEvents, &WatchAllEventsRequest{
+		Filter:     EventFilter{Modules: []string{registry.ModuleName}},
+		FromHeight: 2,
+	})
+	require.NoError(err, "WatchAllEvents")
+	require.EqualValues([]emitted{
+		{2, "", true},
+		{3, "", true},
+		{4, "", true},
+	}, recvEvents(ch, 3), "end of height markers should not be filtered")
+	sub.Close()
+
+	_, _, err = WatchAllEvents(ctx, backend, backend.getAllEvents, &WatchAllEventsRequest{FromHeight: -1})
+	require.ErrorIs(err, ErrInvalidArgument, "WatchAllEvents with negative height should fail")
+}
@@ -8,6 +8,7 @@ import (
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
@@ -26,10 +27,14 @@ var (
 
 	// methodSubmitTx is the SubmitTx method.
 	methodSubmitTx = serviceName.NewMethod("SubmitTx", transaction.SignedTransaction{})
+	// methodSubmitMultiSignedTx is the SubmitMultiSignedTx method.
+	methodSubmitMultiSignedTx = serviceName.NewMethod("SubmitMultiSignedTx", transaction.MultiSignedTransaction{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodEstimateGas is the EstimateGas method.
 	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
+	// methodSimulateTx is the SimulateTx method.
+	methodSimulateTx = serviceName.NewMethod("SimulateTx", &SimulateTxRequest{})
 	// methodGetSignerNonce is a GetSignerNonce method.
 	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
 	// methodGetBlock is the GetBlock method.
@@ -38,6 +43,8 @@ var (
 	methodGetTransactions = serviceName.NewMethod("GetTransactions", int64(0))
 	// methodGetTransactionsWithResults is the GetTransactionsWithResults method.
 	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", int64(0))
+	// methodGetTransactionsByAddress is the GetTransactionsByAddress method.
+	methodGetTransactionsByAddress = serviceName.NewMethod("GetTransactionsByAddress", &GetTransactionsByAddressRequest{})
 	// methodGetUnconfirmedTransactions is the GetUnconfirmedTransactions method.
 	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", nil)
 	// methodGetGenesisDocument is the GetGenesisDocument method.
@@ -48,9 +55,13 @@ var (
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 	// methodGetNextBlockState is the GetNextBlockState method.
 	methodGetNextBlockState = serviceName.NewMethod("GetNextBlockState", nil)
+	// methodGasPrice is the GasPrice method.
+	methodGasPrice = serviceName.NewMethod("GasPrice", nil)
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", nil)
+	// methodWatchAllEvents is the WatchAllEvents method.
+	methodWatchAllEvents = serviceName.NewMethod("WatchAllEvents", WatchAllEventsRequest{})
 
 	// methodGetLightBlock is the GetLightBlock method.
 	methodGetLightBlock = lightServiceName.NewMethod("GetLightBlock", int64(0))
@@ -76,6 +87,10 @@ var (
 				MethodName: methodSubmitTx.ShortName(),
 				Handler:    handlerSubmitTx,
 			},
+			{
+				MethodName: methodSubmitMultiSignedTx.ShortName(),
+				Handler:    handlerSubmitMultiSignedTx,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -84,6 +99,10 @@ var (
 				MethodName: methodEstimateGas.ShortName(),
 				Handler:    handlerEstimateGas,
 			},
+			{
+				MethodName: methodSimulateTx.ShortName(),
+				Handler:    handlerSimulateTx,
+			},
 			{
 				MethodName: methodGetSignerNonce.ShortName(),
 				Handler:    handlerGetSignerNonce,
@@ -100,6 +119,10 @@ var (
 				MethodName: methodGetTransactionsWithResults.ShortName(),
 				Handler:    handlerGetTransactionsWithResults,
 			},
+			{
+				MethodName: methodGetTransactionsByAddress.ShortName(),
+				Handler:    handlerGetTransactionsByAddress,
+			},
 			{
 				MethodName: methodGetUnconfirmedTransactions.ShortName(),
 				Handler:    handlerGetUnconfirmedTransactions,
@@ -120,6 +143,10 @@ var (
 				MethodName: methodGetNextBlockState.ShortName(),
 				Handler:    handlerGetNextBlockState,
 			},
+			{
+				MethodName: methodGasPrice.ShortName(),
+				Handler:    handlerGasPrice,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -127,6 +154,11 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchAllEvents.ShortName(),
+				Handler:       handlerWatchAllEvents,
+				ServerStreams: true,
+			},
 		},
 	}
 
@@ -190,6 +222,29 @@ func handlerSubmitTx( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSubmitMultiSignedTx( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(transaction.MultiSignedTransaction)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(ClientBackend).SubmitMultiSignedTx(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSubmitMultiSignedTx.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(ClientBackend).SubmitMultiSignedTx(ctx, req.(*transaction.MultiSignedTransaction))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -236,6 +291,29 @@ func handlerEstimateGas( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSimulateTx( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(SimulateTxRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).SimulateTx(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSimulateTx.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).SimulateTx(ctx, req.(*SimulateTxRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerGetSignerNonce( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -328,6 +406,29 @@ func handlerGetTransactionsWithResults( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetTransactionsByAddress( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq GetTransactionsByAddressRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetTransactionsByAddress(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionsByAddress.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetTransactionsByAddress(ctx, req.(*GetTransactionsByAddressRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerGetUnconfirmedTransactions( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -404,6 +505,25 @@ func handlerGetStatus( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGasPrice( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(ClientBackend).GasPrice(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGasPrice.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GasPrice(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerGetNextBlockState( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -451,6 +571,35 @@ func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchAllEvents(srv interface{}, stream grpc.ServerStream) error {
+	var rq WatchAllEventsRequest
+	if err := stream.RecvMsg(&rq); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(ClientBackend).WatchAllEvents(ctx, &rq)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func handlerGetLightBlock( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -701,6 +850,10 @@ func (c *consensusClient) SubmitTx(ctx context.Context, tx *transaction.SignedTr
 	return c.conn.Invoke(ctx, methodSubmitTx.FullName(), tx, nil)
 }
 
+func (c *consensusClient) SubmitMultiSignedTx(ctx context.Context, tx *transaction.MultiSignedTransaction) error {
+	return c.conn.Invoke(ctx, methodSubmitMultiSignedTx.FullName(), tx, nil)
+}
+
 func (c *consensusClient) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	var rsp genesis.Document
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -717,6 +870,14 @@ func (c *consensusClient) EstimateGas(ctx context.Context, req *EstimateGasReque
 	return gas, nil
 }
 
+func (c *consensusClient) SimulateTx(ctx context.Context, req *SimulateTxRequest) (*SimulateTxResponse, error) {
+	var rsp SimulateTxResponse
+	if err := c.conn.Invoke(ctx, methodSimulateTx.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
 	var nonce uint64
 	if err := c.conn.Invoke(ctx, methodGetSignerNonce.FullName(), req, &nonce); err != nil {
@@ -749,6 +910,14 @@ func (c *consensusClient) GetTransactionsWithResults(ctx context.Context, height
 	return &rsp, nil
 }
 
+func (c *consensusClient) GetTransactionsByAddress(ctx context.Context, request *GetTransactionsByAddressRequest) (*GetTransactionsByAddressResponse, error) {
+	var rsp GetTransactionsByAddressResponse
+	if err := c.conn.Invoke(ctx, methodGetTransactionsByAddress.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
 	var rsp [][]byte
 	if err := c.conn.Invoke(ctx, methodGetUnconfirmedTransactions.FullName(), nil, &rsp); err != nil {
@@ -781,6 +950,14 @@ func (c *consensusClient) GetStatus(ctx context.Context) (*Status, error) {
 	return &rsp, nil
 }
 
+func (c *consensusClient) GasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	var rsp quantity.Quantity
+	if err := c.conn.Invoke(ctx, methodGasPrice.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetNextBlockState(ctx context.Context) (*NextBlockState, error) {
 	var rsp NextBlockState
 	if err := c.conn.Invoke(ctx, methodGetNextBlockState.FullName(), nil, &rsp); err != nil {
@@ -824,6 +1001,41 @@ func (c *consensusClient) WatchBlocks(ctx context.Context) (<-chan *Block, pubsu
 	return ch, sub, nil
 }
 
+func (c *consensusClient) WatchAllEvents(ctx context.Context, request *WatchAllEventsRequest) (<-chan *Event, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchAllEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *Event)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev Event
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *consensusClient) Beacon() beacon.Backend {
 	return beacon.NewBeaconClient(c.conn)
 }
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	// DefaultTransactionsByAddressLimit is the number of transactions returned by a single
+	// GetTransactionsByAddress call in case no limit is given.
+	DefaultTransactionsByAddressLimit = 100
+	// MaxTransactionsByAddressLimit is the maximum number of transactions returned by a single
+	// GetTransactionsByAddress call.
+	MaxTransactionsByAddressLimit = 1000
+)
+
+// TransactionPosition is the position of a transaction within the chain.
+type TransactionPosition struct {
+	// Height is the height of the block that includes the transaction.
+	Height int64 `json:"height"`
+	// Index is the index of the transaction within the block.
+	Index uint32 `json:"index"`
+}
+
+// AddressTransaction is an indexed consensus transaction that involves a given account address.
+type AddressTransaction struct {
+	TransactionPosition
+
+	// TxHash is the hash of the transaction.
+	TxHash hash.Hash `json:"tx_hash"`
+	// Method is the transaction method. It is empty in case the transaction is malformed.
+	Method transaction.MethodName `json:"method,omitempty"`
+	// Calls are the methods of the individual calls in case the transaction is a batch (see
+	// MethodBatch).
+	Calls []transaction.MethodName `json:"calls,omitempty"`
+	// Success is true iff the transaction has been executed successfully.
+	Success bool `json:"success"`
+
+	// Signer is true iff the address (possibly a multisig account) signed the transaction.
+	Signer bool `json:"signer,omitempty"`
+	// EventParticipant is true iff the address is involved in any staking event emitted by the
+	// transaction.
+	EventParticipant bool `json:"event_participant,omitempty"`
+}
+
+// GetTransactionsByAddressRequest is a GetTransactionsByAddress request.
+type GetTransactionsByAddressRequest struct {
+	// Address is the account address to return the transactions for.
+	Address staking.Address `json:"address"`
+
+	// HeightMin is the minimum height (inclusive).
+	HeightMin int64 `json:"height_min,omitempty"`
+	// HeightMax is the maximum height (inclusive). Zero means no upper bound.
+	HeightMax int64 `json:"height_max,omitempty"`
+	// Methods optionally restricts the returned transactions to the given methods.
+	Methods []transaction.MethodName `json:"methods,omitempty"`
+
+	// After is the position after which to start returning transactions. It should be set to the
+	// Next field of the previous response when paginating.
+	After *TransactionPosition `json:"after,omitempty"`
+	// Limit is the maximum number of returned transactions. Zero means that the default limit
+	// (DefaultTransactionsByAddressLimit) is used.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// GetTransactionsByAddressResponse is a GetTransactionsByAddress response.
+type GetTransactionsByAddressResponse struct {
+	// Transactions are the matching transactions ordered by their position in the chain.
+	Transactions []*AddressTransaction `json:"transactions"`
+	// Next is the position to pass as After to fetch the next page of results. It is nil in case
+	// there are no more results.
+	Next *TransactionPosition `json:"next,omitempty"`
+
+	// IndexedHeightMin is the first height covered by the index (inclusive). Transactions in
+	// earlier blocks are not returned as they have either been pruned or were never indexed.
+	IndexedHeightMin int64 `json:"indexed_height_min"`
+	// IndexedHeightMax is the last height covered by the index (inclusive). Transactions in later
+	// blocks are not returned until the indexer catches up.
+	//
+	// Both bounds are zero in case nothing has been indexed yet.
+	IndexedHeightMax int64 `json:"indexed_height_max"`
+}
+
+// ValidateBasic performs basic request validation and applies the default limit.
+func (r *GetTransactionsByAddressRequest) ValidateBasic() error {
+	if r.HeightMin < 0 || r.HeightMax < 0 {
+		return ErrInvalidArgument
+	}
+	if r.HeightMax != 0 && r.HeightMin > r.HeightMax {
+		return ErrInvalidArgument
+	}
+	switch {
+	case r.Limit == 0:
+		r.Limit = DefaultTransactionsByAddressLimit
+	case r.Limit > MaxTransactionsByAddressLimit:
+		return ErrInvalidArgument
+	}
+	return nil
+}
+
+// MatchesTransaction returns true iff the given transaction passes the request's method filter.
+//
+// In case the transaction is a batch, it also matches when any of its calls matches the filter.
+func (r *GetTransactionsByAddressRequest) MatchesTransaction(at *AddressTransaction) bool {
+	if len(r.Methods) == 0 {
+		return true
+	}
+	for _, m := range r.Methods {
+		if m == at.Method {
+			return true
+		}
+		for _, call := range at.Calls {
+			if m == call {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+const (
+	// PriceDiscoveryStatic is the name of the static price discovery mechanism.
+	PriceDiscoveryStatic = "static"
+	// PriceDiscoveryDynamic is the name of the dynamic price discovery mechanism.
+	PriceDiscoveryDynamic = "dynamic"
+)
+
+// DynamicPriceDiscoveryConfig is the dynamic price discovery mechanism configuration.
+type DynamicPriceDiscoveryConfig struct {
+	// Window is the number of most recent blocks whose transaction fees are sampled.
+	Window uint64
+	// Percentile is the percentile (0-100) of the sampled gas prices that is used as the gas
+	// price in case the mempool is not congested.
+	Percentile uint64
+	// CongestedPercentile is the percentile (0-100) of the sampled gas prices that is used as
+	// the gas price in case the mempool is congested.
+	CongestedPercentile uint64
+	// CongestionThreshold is the number of unconfirmed transactions in the local mempool at
+	// which the mempool is considered congested. Zero disables congestion detection.
+	CongestionThreshold uint64
+	// MinPrice is the minimum gas price that is ever returned.
+	MinPrice uint64
+	// MaxPrice is the maximum gas price that is ever returned. Zero means no limit.
+	MaxPrice uint64
+}
+
+// ValidateBasic performs basic configuration validity checks.
+func (cfg *DynamicPriceDiscoveryConfig) ValidateBasic() error {
+	if cfg.Window == 0 {
+		return fmt.Errorf("window must be greater than zero")
+	}
+	if cfg.Percentile > 100 {
+		return fmt.Errorf("percentile must be between 0 and 100")
+	}
+	if cfg.CongestedPercentile > 100 {
+		return fmt.Errorf("congested percentile must be between 0 and 100")
+	}
+	if cfg.MaxPrice != 0 && cfg.MaxPrice < cfg.MinPrice {
+		return fmt.Errorf("maximum price (%d) is less than minimum price (%d)", cfg.MaxPrice, cfg.MinPrice)
+	}
+	return nil
+}
+
+// PriceDiscoveryBackend is the consensus backend used by the dynamic price discovery mechanism.
+type PriceDiscoveryBackend interface {
+	ClientBackend
+
+	// GetUnconfirmedTransactionCount returns the number of transactions currently in the local
+	// node's mempool.
+	GetUnconfirmedTransactionCount(ctx context.Context) (uint64, error)
+}
+
+type dynamicPriceDiscovery struct {
+	sync.Mutex
+
+	backend PriceDiscoveryBackend
+	cfg     DynamicPriceDiscoveryConfig
+
+	minPrice quantity.Quantity
+	maxPrice quantity.Quantity
+
+	// samples contains the sampled gas prices of each block height within the window. Since
+	// blocks are final, each block only needs to be sampled once.
+	samples map[int64][]*quantity.Quantity
+
+	lastHeight    int64
+	lastCongested bool
+	lastPrice     *quantity.Quantity
+
+	logger *logging.Logger
+}
+
+// NewDynamicPriceDiscovery creates a price discovery mechanism which derives the gas price from
+// the fees paid by transactions included in recent blocks.
+//
+// The gas price is the configured percentile of gas prices paid by transactions in the most
+// recent blocks. In case the local mempool is congested, a (usually higher) congested percentile
+// is used instead. The result is always clamped to the configured minimum and maximum price.
+func NewDynamicPriceDiscovery(backend PriceDiscoveryBackend, cfg *DynamicPriceDiscoveryConfig) (PriceDiscovery, error) {
+	if err := cfg.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("submission: invalid dynamic price discovery configuration: %w", err)
+	}
+
+	pd := &dynamicPriceDiscovery{
+		backend: backend,
+		cfg:     *cfg,
+		samples: make(map[int64][]*quantity.Quantity),
+		logger:  logging.GetLogger("consensus/submission/pricediscovery"),
+	}
+	if err := pd.minPrice.FromUint64(cfg.MinPrice); err != nil {
+		return nil, fmt.Errorf("submission: failed to convert minimum gas price: %w", err)
+	}
+	if err := pd.maxPrice.FromUint64(cfg.MaxPrice); err != nil {
+		return nil, fmt.Errorf("submission: failed to convert maximum gas price: %w", err)
+	}
+	return pd, nil
+}
+
+func (pd *dynamicPriceDiscovery) GasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	pd.Lock()
+	defer pd.Unlock()
+
+	status, err := pd.backend.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("submission: failed to get consensus status: %w", err)
+	}
+	congested, err := pd.isCongested(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only recompute the price in case something has changed since the last time.
+	if pd.lastPrice != nil && pd.lastHeight == status.LatestHeight && pd.lastCongested == congested {
+		return pd.lastPrice.Clone(), nil
+	}
+
+	prices, err := pd.samplePrices(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	percentile := pd.cfg.Percentile
+	if congested {
+		percentile = pd.cfg.CongestedPercentile
+	}
+	price := pd.clamp(pricePercentile(prices, percentile))
+
+	pd.logger.Debug("computed gas price",
+		"height", status.LatestHeight,
+		"num_samples", len(prices),
+		"congested", congested,
+		"percentile", percentile,
+		"price", price,
+	)
+
+	pd.lastHeight = status.LatestHeight
+	pd.lastCongested = congested
+	pd.lastPrice = price
+
+	return price.Clone(), nil
+}
+
+func (pd *dynamicPriceDiscovery) isCongested(ctx context.Context) (bool, error) {
+	if pd.cfg.CongestionThreshold == 0 {
+		return false, nil
+	}
+
+	count, err := pd.backend.GetUnconfirmedTransactionCount(ctx)
+	if err != nil {
+		return false, fmt.Errorf("submission: failed to get unconfirmed transaction count: %w", err)
+	}
+	return count >= pd.cfg.CongestionThreshold, nil
+}
+
+func (pd *dynamicPriceDiscovery) samplePrices(ctx context.Context, status *Status) ([]*quantity.Quantity, error) {
+	// Blocks which are no longer retained cannot be sampled.
+	startHeight := status.LatestHeight - int64(pd.cfg.Window) + 1
+	if startHeight < status.LastRetainedHeight {
+		startHeight = status.LastRetainedHeight
+	}
+
+	// Forget samples of blocks that are no longer within the window.
+	for height := range pd.samples {
+		if height < startHeight || height > status.LatestHeight {
+			delete(pd.samples, height)
+		}
+	}
+
+	var prices []*quantity.Quantity
+	for height := startHeight; height <= status.LatestHeight; height++ {
+		samples, ok := pd.samples[height]
+		if !ok {
+			var err error
+			if samples, err = pd.sampleBlock(ctx, height); err != nil {
+				return nil, err
+			}
+			pd.samples[height] = samples
+		}
+		prices = append(prices, samples...)
+	}
+	return prices, nil
+}
+
+func (pd *dynamicPriceDiscovery) sampleBlock(ctx context.Context, height int64) ([]*quantity.Quantity, error) {
+	txs, err := pd.backend.GetTransactionsWithResults(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("submission: failed to get transactions at height %d: %w", height, err)
+	}
+
+	var prices []*quantity.Quantity
+	for i, rawTx := range txs.Transactions {
+		// Only consider successfully executed transactions.
+		if i >= len(txs.Results) || !txs.Results[i].IsSuccess() {
+			continue
+		}
+
+		fee := decodeTransactionFee(rawTx)
+		if fee == nil || fee.Gas == 0 {
+			continue
+		}
+		prices = append(prices, fee.GasPrice())
+	}
+	return prices, nil
+}
+
+func (pd *dynamicPriceDiscovery) clamp(price *quantity.Quantity) *quantity.Quantity {
+	if price == nil || price.Cmp(&pd.minPrice) < 0 {
+		return pd.minPrice.Clone()
+	}
+	if !pd.maxPrice.IsZero() && price.Cmp(&pd.maxPrice) > 0 {
+		return pd.maxPrice.Clone()
+	}
+	return price
+}
+
+// pricePercentile returns the given percentile of the passed gas prices using the nearest-rank
+// method. In case no prices are given, nil is returned.
+func pricePercentile(prices []*quantity.Quantity, percentile uint64) *quantity.Quantity {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	sorted := make([]*quantity.Quantity, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+
+	// Nearest rank is ceil(percentile/100 * n), indices are zero-based.
+	rank := (percentile*uint64(len(sorted)) + 99) / 100
+	if rank > 0 {
+		rank--
+	}
+	return sorted[rank].Clone()
+}
+
+// decodeTransactionFee extracts the fee from a raw (single or multi-signed) transaction without
+// verifying its signatures. In case the transaction is malformed or has no fee, nil is returned.
+func decodeTransactionFee(rawTx []byte) *transaction.Fee {
+	sigTx, multiSigTx, err := transaction.UnmarshalSignedTransaction(rawTx)
+	if err != nil {
+		return nil
+	}
+	var blob []byte
+	switch {
+	case sigTx != nil:
+		blob = sigTx.Blob
+	default:
+		blob = multiSigTx.Blob
+	}
+
+	var tx transaction.Transaction
+	if err = cbor.Unmarshal(blob, &tx); err != nil {
+		return nil
+	}
+	return tx.Fee
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+)
+
+func TestPricePercentile(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(pricePercentile(nil, 50), "percentile of no prices should be nil")
+
+	var prices []*quantity.Quantity
+	for _, p := range []uint64{50, 10, 40, 20, 30} {
+		prices = append(prices, quantity.NewFromUint64(p))
+	}
+
+	for _, tc := range []struct {
+		percentile uint64
+		expected   uint64
+	}{
+		{0, 10},
+		{1, 10},
+		{20, 10},
+		{21, 20},
+		{50, 30},
+		{60, 30},
+		{90, 50},
+		{100, 50},
+	} {
+		price := pricePercentile(prices, tc.percentile)
+		require.Zero(price.Cmp(quantity.NewFromUint64(tc.expected)), "percentile %d", tc.percentile)
+	}
+	require.Zero(prices[0].Cmp(quantity.NewFromUint64(50)), "input prices should not be reordered")
+}
+
+func TestDynamicPriceDiscoveryConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DynamicPriceDiscoveryConfig{
+		Window:              10,
+		Percentile:          60,
+		CongestedPercentile: 90,
+		MinPrice:            1,
+		MaxPrice:            100,
+	}
+	require.NoError(cfg.ValidateBasic(), "valid configuration")
+
+	invalid := cfg
+	invalid.Window = 0
+	require.Error(invalid.ValidateBasic(), "zero window should be invalid")
+
+	invalid = cfg
+	invalid.Percentile = 101
+	require.Error(invalid.ValidateBasic(), "percentile above 100 should be invalid")
+
+	invalid = cfg
+	invalid.CongestedPercentile = 101
+	require.Error(invalid.ValidateBasic(), "congested percentile above 100 should be invalid")
+
+	invalid = cfg
+	invalid.MaxPrice = 0
+	require.NoError(invalid.ValidateBasic(), "zero maximum price should be valid")
+	invalid.MinPrice = 1000
+	require.NoError(invalid.ValidateBasic(), "zero maximum price should be valid")
+	invalid.MaxPrice = 10
+	require.Error(invalid.ValidateBasic(), "maximum price below minimum price should be invalid")
+}
+
+func TestDecodeTransactionFee(t *testing.T) {
+	require := require.New(t)
+
+	// Signatures are not verified so there is no need to actually sign the transactions.
+	fee := &transaction.Fee{Amount: *quantity.NewFromUint64(1000), Gas: 10}
+	tx := transaction.NewTransaction(0, fee, "test.Method", nil)
+
+	sigTx := &transaction.SignedTransaction{Signed: signature.Signed{Blob: cbor.Marshal(tx)}}
+
+	decoded := decodeTransactionFee(cbor.Marshal(sigTx))
+	require.NotNil(decoded, "fee should be decoded")
+	require.Zero(decoded.GasPrice().Cmp(quantity.NewFromUint64(100)), "decoded gas price should be correct")
+
+	require.Nil(decodeTransactionFee([]byte("malformed")), "malformed transactions should be ignored")
+
+	tx = transaction.NewTransaction(0, nil, "test.Method", nil)
+	sigTx = &transaction.SignedTransaction{Signed: signature.Signed{Blob: cbor.Marshal(tx)}}
+	require.Nil(decodeTransactionFee(cbor.Marshal(sigTx)), "transactions without a fee should be ignored")
+}
+
+type testPriceDiscoveryBackend struct {
+	ClientBackend
+
+	latestHeight int64
+	prices       map[int64][]uint64
+	numSampled   map[int64]int
+	mempoolSize  uint64
+}
+
+func (b *testPriceDiscoveryBackend) GetStatus(ctx context.Context) (*Status, error) {
+	return &Status{LatestHeight: b.latestHeight, LastRetainedHeight: 1}, nil
+}
+
+func (b *testPriceDiscoveryBackend) GetTransactionsWithResults(ctx context.Context, height int64) (*TransactionsWithResults, error) {
+	b.numSampled[height]++
+
+	var txs TransactionsWithResults
+	for _, price := range b.prices[height] {
+		fee := &transaction.Fee{Amount: *quantity.NewFromUint64(price * 10), Gas: 10}
+		tx := transaction.NewTransaction(0, fee, "test.Method", nil)
+		sigTx := &transaction.SignedTransaction{Signed: signature.Signed{Blob: cbor.Marshal(tx)}}
+		txs.Transactions = append(txs.Transactions, cbor.Marshal(sigTx))
+		txs.Results = append(txs.Results, &results.Result{})
+	}
+	return &txs, nil
+}
+
+func (b *testPriceDiscoveryBackend) GetUnconfirmedTransactionCount(ctx context.Context) (uint64, error) {
+	return b.mempoolSize, nil
+}
+
+func TestDynamicPriceDiscovery(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	backend := &testPriceDiscoveryBackend{
+		latestHeight: 3,
+		prices: map[int64][]uint64{
+			1: {10},
+			2: {20, 30},
+			3: {40},
+			4: {50},
+		},
+		numSampled: make(map[int64]int),
+	}
+	pd, err := NewDynamicPriceDiscovery(backend, &DynamicPriceDiscoveryConfig{
+		Window:              3,
+		Percentile:          50,
+		CongestedPercentile: 100,
+		CongestionThreshold: 5,
+	})
+	require.NoError(err, "NewDynamicPriceDiscovery")
+
+	price, err := pd.GasPrice(ctx)
+	require.NoError(err, "GasPrice")
+	require.EqualValues(quantity.NewFromUint64(20), price, "gas price should be the median of the window")
+
+	// Advancing the height should only sample the new block and drop the oldest one.
+	backend.latestHeight = 4
+	price, err = pd.GasPrice(ctx)
+	require.NoError(err, "GasPrice")
+	require.EqualValues(quantity.NewFromUint64(30), price, "gas price should be the median of the window")
+	require.EqualValues(map[int64]int{1: 1, 2: 1, 3: 1, 4: 1}, backend.numSampled, "each block should only be sampled once")
+
+	// A congested mempool should use the congested percentile.
+	backend.mempoolSize = 5
+	price, err = pd.GasPrice(ctx)
+	require.NoError(err, "GasPrice")
+	require.EqualValues(quantity.NewFromUint64(50), price, "congested gas price should be used")
+	require.EqualValues(map[int64]int{1: 1, 2: 1, 3: 1, 4: 1}, backend.numSampled, "each block should only be sampled once")
+}
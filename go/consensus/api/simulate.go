@@ -0,0 +1,68 @@
+package api
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// SimulateTxRequest is a SimulateTx request.
+type SimulateTxRequest struct {
+	// Signer is the public key of the transaction signer.
+	Signer signature.PublicKey `json:"signer"`
+	// Transaction is the transaction to simulate.
+	Transaction *transaction.Transaction `json:"transaction"`
+	// Height is the height of the state to simulate the transaction against.
+	Height int64 `json:"height"`
+}
+
+// SimulateTxResponse is a SimulateTx response.
+type SimulateTxResponse struct {
+	// Height is the height of the state the transaction has been simulated against.
+	Height int64 `json:"height"`
+	// Result is the transaction execution result including any emitted events.
+	Result *results.Result `json:"result"`
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed transaction.Gas `json:"gas_used"`
+	// BalanceChanges are the changes of balances of accounts involved in the transaction, either
+	// as the signer or as a participant in any of the emitted staking events.
+	BalanceChanges []*BalanceChange `json:"balance_changes,omitempty"`
+}
+
+// AccountBalances are the balances of a staking account.
+type AccountBalances struct {
+	// General is the general balance.
+	General quantity.Quantity `json:"general"`
+	// EscrowActive is the active escrow balance.
+	EscrowActive quantity.Quantity `json:"escrow_active"`
+	// EscrowDebonding is the debonding escrow balance.
+	EscrowDebonding quantity.Quantity `json:"escrow_debonding"`
+}
+
+// NewAccountBalances returns the balances of the given staking account.
+func NewAccountBalances(account *staking.Account) AccountBalances {
+	return AccountBalances{
+		General:         account.General.Balance,
+		EscrowActive:    account.Escrow.Active.Balance,
+		EscrowDebonding: account.Escrow.Debonding.Balance,
+	}
+}
+
+// Equal compares account balances for equality.
+func (b *AccountBalances) Equal(other *AccountBalances) bool {
+	return b.General.Cmp(&other.General) == 0 &&
+		b.EscrowActive.Cmp(&other.EscrowActive) == 0 &&
+		b.EscrowDebonding.Cmp(&other.EscrowDebonding) == 0
+}
+
+// BalanceChange is a change of staking account balances caused by a transaction.
+type BalanceChange struct {
+	// Address is the account address.
+	Address staking.Address `json:"address"`
+	// Before are the account balances before the transaction.
+	Before AccountBalances `json:"before"`
+	// After are the account balances after the transaction.
+	After AccountBalances `json:"after"`
+}
@@ -45,6 +45,32 @@ func (pd *staticPriceDiscovery) GasPrice(ctx context.Context) (*quantity.Quantit
 	return pd.price.Clone(), nil
 }
 
+// BaseGasPriceProvider provides the consensus-enforced minimum gas price.
+type BaseGasPriceProvider interface {
+	// BaseGasPrice returns the current consensus-enforced minimum gas price.
+	BaseGasPrice(ctx context.Context, height int64) (*quantity.Quantity, error)
+}
+
+type baseGasPriceDiscovery struct {
+	priceDiscovery PriceDiscovery
+	provider       BaseGasPriceProvider
+}
+
+func (pd *baseGasPriceDiscovery) GasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	price, err := pd.priceDiscovery.GasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseGasPrice, err := pd.provider.BaseGasPrice(ctx, HeightLatest)
+	if err != nil {
+		return nil, fmt.Errorf("submission: failed to query base gas price: %w", err)
+	}
+	if price.Cmp(baseGasPrice) < 0 {
+		return baseGasPrice, nil
+	}
+	return price, nil
+}
+
 // SubmissionManager is a transaction submission manager interface.
 type SubmissionManager interface {
 	// PriceDiscovery returns the configured price discovery mechanism instance.
@@ -180,7 +206,23 @@ func (m *submissionManager) SignAndSubmitTx(ctx context.Context, signer signatur
 }
 
 // NewSubmissionManager creates a new transaction submission manager.
-func NewSubmissionManager(backend ClientBackend, priceDiscovery PriceDiscovery, maxFee uint64) SubmissionManager {
+//
+// In case a base gas price provider is given, the gas price used by the submission manager is
+// never lower than the consensus-enforced minimum gas price, regardless of what the given price
+// discovery mechanism returns.
+func NewSubmissionManager(
+	backend ClientBackend,
+	priceDiscovery PriceDiscovery,
+	baseGasPrice BaseGasPriceProvider,
+	maxFee uint64,
+) SubmissionManager {
+	if baseGasPrice != nil {
+		priceDiscovery = &baseGasPriceDiscovery{
+			priceDiscovery: priceDiscovery,
+			provider:       baseGasPrice,
+		}
+	}
+
 	sm := &submissionManager{
 		backend:        backend,
 		priceDiscovery: priceDiscovery,
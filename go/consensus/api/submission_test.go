@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// feeMarketBackend is a minimal consensus backend that enforces a base gas price on submitted
+// transactions, like the staking application does when the fee market is enabled.
+type feeMarketBackend struct {
+	ClientBackend
+
+	baseGasPrice quantity.Quantity
+	submitted    []*transaction.Transaction
+}
+
+func (b *feeMarketBackend) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
+	return uint64(len(b.submitted)), nil
+}
+
+func (b *feeMarketBackend) EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error) {
+	return 1000, nil
+}
+
+func (b *feeMarketBackend) SubmitTx(ctx context.Context, sigTx *transaction.SignedTransaction) error {
+	var tx transaction.Transaction
+	if err := sigTx.Open(&tx); err != nil {
+		return err
+	}
+
+	if tx.Fee.GasPrice().Cmp(&b.baseGasPrice) < 0 {
+		return transaction.ErrGasPriceTooLow
+	}
+
+	b.submitted = append(b.submitted, &tx)
+	return nil
+}
+
+func (b *feeMarketBackend) BaseGasPrice(ctx context.Context, height int64) (*quantity.Quantity, error) {
+	return b.baseGasPrice.Clone(), nil
+}
+
+func TestSubmissionManagerBaseGasPrice(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	signature.SetChainContext("test: consensus submission manager")
+
+	signer := memorySigner.NewTestSigner("consensus submission manager test signer")
+	newTx := func() *transaction.Transaction {
+		return transaction.NewTransaction(0, nil, staking.MethodTransfer, &staking.Transfer{})
+	}
+
+	backend := &feeMarketBackend{
+		baseGasPrice: *quantity.NewFromUint64(10),
+	}
+	pd, err := NewStaticPriceDiscovery(1)
+	require.NoError(err, "NewStaticPriceDiscovery")
+
+	// Without taking the base gas price into account, transactions get rejected.
+	sm := NewSubmissionManager(backend, pd, nil, 0)
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.ErrorIs(err, transaction.ErrGasPriceTooLow, "SignAndSubmitTx should fail below base gas price")
+
+	// The base gas price should be used in case price discovery returns a lower price.
+	sm = NewSubmissionManager(backend, pd, backend, 0)
+	gasPrice, err := sm.PriceDiscovery().GasPrice(ctx)
+	require.NoError(err, "GasPrice")
+	require.Zero(gasPrice.Cmp(&backend.baseGasPrice), "gas price should be the base gas price")
+
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx")
+	require.Len(backend.submitted, 1, "transaction should be submitted")
+	require.EqualValues(1000, backend.submitted[0].Fee.Gas, "fee gas should be estimated")
+	require.Zero(backend.submitted[0].Fee.Amount.Cmp(quantity.NewFromUint64(10_000)), "fee amount should use base gas price")
+
+	// A higher discovered price should be used as is.
+	backend.baseGasPrice = *quantity.NewFromUint64(0)
+	pd, err = NewStaticPriceDiscovery(20)
+	require.NoError(err, "NewStaticPriceDiscovery")
+	sm = NewSubmissionManager(backend, pd, backend, 0)
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx")
+	require.Len(backend.submitted, 2, "transaction should be submitted")
+	require.Zero(backend.submitted[1].Fee.Amount.Cmp(quantity.NewFromUint64(20_000)), "fee amount should use discovered gas price")
+}
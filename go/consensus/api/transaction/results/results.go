@@ -1,7 +1,9 @@
 package results
 
 import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
@@ -11,12 +13,41 @@ import (
 // Event is a consensus service event that may be emitted during processing of
 // a transaction.
 type Event struct {
+	Consensus  *ConsensusEvent   `json:"consensus,omitempty"`
 	Staking    *staking.Event    `json:"staking,omitempty"`
 	Registry   *registry.Event   `json:"registry,omitempty"`
 	RootHash   *roothash.Event   `json:"roothash,omitempty"`
 	Governance *governance.Event `json:"governance,omitempty"`
 }
 
+// ConsensusEvent is an event emitted by the consensus layer itself rather than by one of the
+// consensus services.
+type ConsensusEvent struct {
+	Height int64     `json:"height,omitempty"`
+	TxHash hash.Hash `json:"tx_hash,omitempty"`
+
+	BatchCall *BatchCallEvent `json:"batch_call,omitempty"`
+}
+
+// BatchCallEvent is the event emitted before the events of each call in an executed batch.
+//
+// As transaction events are kept in emission order, all events following it up to the next
+// BatchCallEvent (or the end of the transaction's events) were emitted by the call with the given
+// index.
+type BatchCallEvent struct {
+	// Index is the index of the call in the batch.
+	Index uint32 `json:"index"`
+	// Method is the called method.
+	Method transaction.MethodName `json:"method"`
+	// GasUsed is the amount of gas used by the call.
+	GasUsed transaction.Gas `json:"gas_used"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *BatchCallEvent) EventKind() string {
+	return "batch_call"
+}
+
 // Error is a transaction execution error.
 type Error struct {
 	Module  string `json:"module,omitempty"`
@@ -26,7 +57,8 @@ type Error struct {
 
 // Result is a transaction execution result.
 type Result struct {
-	Error  Error    `json:"error"`
+	Error Error `json:"error"`
+	// Events are the events emitted by the transaction in emission order.
 	Events []*Event `json:"events"`
 }
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
@@ -34,6 +35,7 @@ var (
 
 	_ prettyprint.PrettyPrinter = (*Transaction)(nil)
 	_ prettyprint.PrettyPrinter = (*SignedTransaction)(nil)
+	_ prettyprint.PrettyPrinter = (*MultiSignedTransaction)(nil)
 )
 
 // Transaction is an unsigned consensus transaction.
@@ -221,6 +223,122 @@ func Sign(signer signature.Signer, tx *Transaction) (*SignedTransaction, error)
 	return &SignedTransaction{Signed: *signed}, nil
 }
 
+// MultiSignedTransaction is a transaction signed by a threshold of signers of
+// a multisig account.
+type MultiSignedTransaction struct {
+	multisig.Envelope
+}
+
+// Hash returns the cryptographic hash of the encoded transaction.
+func (s *MultiSignedTransaction) Hash() hash.Hash {
+	return hash.NewFrom(s)
+}
+
+// PrettyPrint writes a pretty-printed representation of the type
+// to the given writer.
+func (s MultiSignedTransaction) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sHash: %s\n", prefix, s.Hash())
+
+	fmt.Fprintf(w, "%sAccount:\n", prefix)
+	fmt.Fprintf(w, "%s  Threshold: %d\n", prefix, s.Account.Threshold)
+	fmt.Fprintf(w, "%s  Signers:\n", prefix)
+	for _, pk := range s.Account.Signers {
+		signed := " "
+		if s.IsSignedBy(pk) {
+			signed = "x"
+		}
+		fmt.Fprintf(w, "%s    [%s] %s\n", prefix, signed, pk)
+	}
+
+	// Check if signatures are valid.
+	if err := s.Account.Verify(SignatureContext, s.Blob, s.Signatures); err != nil {
+		fmt.Fprintf(w, "%s  [INVALID SIGNATURES: %s]\n", prefix, err)
+	}
+
+	// Display the blob even if signature verification failed as it may
+	// be useful to look into it regardless.
+	var tx Transaction
+	fmt.Fprintf(w, "%sContent:\n", prefix)
+	if err := cbor.Unmarshal(s.Blob, &tx); err != nil {
+		fmt.Fprintf(w, "%s  <error: %s>\n", prefix, err)
+		fmt.Fprintf(w, "%s  <malformed: %s>\n", prefix, base64.StdEncoding.EncodeToString(s.Blob))
+		return
+	}
+
+	tx.PrettyPrint(ctx, prefix+"  ", w)
+}
+
+// PrettyType returns a representation of the type that can be used for pretty printing.
+func (s MultiSignedTransaction) PrettyType() (interface{}, error) {
+	var tx Transaction
+	if err := cbor.Unmarshal(s.Blob, &tx); err != nil {
+		return nil, fmt.Errorf("malformed signed blob: %w", err)
+	}
+	body, err := tx.PrettyType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pretty print transaction: %w", err)
+	}
+	return &PrettyMultiSignedTransaction{
+		Account:    s.Account,
+		Body:       body,
+		Signatures: s.Signatures,
+	}, nil
+}
+
+// Open first verifies that the blob is signed by a threshold of the account's
+// signers and then unmarshals the blob.
+func (s *MultiSignedTransaction) Open(tx *Transaction) error { // nolint: interfacer
+	return s.Envelope.Open(SignatureContext, tx)
+}
+
+// Sign adds a signature by the given signer, which must be one of the
+// account's signers, to the multi-signed transaction.
+func (s *MultiSignedTransaction) Sign(signer signature.Signer) error {
+	return s.Envelope.Sign(signer, SignatureContext)
+}
+
+// PrettyMultiSignedTransaction is used for pretty-printing multi-signed
+// transactions so that the actual content is displayed instead of the
+// binary blob.
+//
+// It should only be used for pretty printing.
+type PrettyMultiSignedTransaction struct {
+	Account    multisig.Account      `json:"account"`
+	Body       interface{}           `json:"untrusted_raw_value"`
+	Signatures []signature.Signature `json:"signatures"`
+}
+
+// NewMultiSignedTransaction creates a new multi-signed transaction for the
+// given multisig account without any signatures.
+//
+// Signatures can be added by the account's signers via Sign.
+func NewMultiSignedTransaction(account *multisig.Account, tx *Transaction) (*MultiSignedTransaction, error) {
+	env, err := multisig.NewEnvelope(account, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignedTransaction{Envelope: *env}, nil
+}
+
+// UnmarshalSignedTransaction unmarshals a raw transaction envelope which is either single-signed
+// or multi-signed. On success, exactly one of the returned transactions is non-nil.
+//
+// Signatures are not verified, use Open on the returned transaction to do so.
+func UnmarshalSignedTransaction(rawTx []byte) (*SignedTransaction, *MultiSignedTransaction, error) {
+	var sigTx SignedTransaction
+	err := cbor.Unmarshal(rawTx, &sigTx)
+	if err == nil {
+		return &sigTx, nil, nil
+	}
+
+	// Not a single-signed transaction, try the multisig envelope.
+	var multiSigTx MultiSignedTransaction
+	if multiErr := cbor.Unmarshal(rawTx, &multiSigTx); multiErr != nil {
+		return nil, nil, fmt.Errorf("transaction: failed to unmarshal signed transaction: %w (multisig: %s)", err, multiErr)
+	}
+	return nil, &multiSigTx, nil
+}
+
 // MethodSeparator is the separator used to separate backend name from method name.
 const MethodSeparator = "."
 
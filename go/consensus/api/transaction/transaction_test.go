@@ -4,6 +4,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 )
 
 type testMethodBodyNormal struct{}
@@ -24,3 +29,31 @@ func TestMethodMetadata(t *testing.T) {
 	require.False(methodNormal.IsCritical())
 	require.True(methodCritical.IsCritical())
 }
+
+func TestUnmarshalSignedTransaction(t *testing.T) {
+	require := require.New(t)
+
+	// Signatures are not verified so there is no need to actually sign the transactions.
+	tx := NewTransaction(0, nil, "test.Method", nil)
+	sigTx := &SignedTransaction{Signed: signature.Signed{Blob: cbor.Marshal(tx)}}
+
+	decodedSigTx, decodedMultiSigTx, err := UnmarshalSignedTransaction(cbor.Marshal(sigTx))
+	require.NoError(err, "UnmarshalSignedTransaction")
+	require.EqualValues(sigTx, decodedSigTx, "single-signed transaction should be decoded")
+	require.Nil(decodedMultiSigTx, "multi-signed transaction should be nil")
+
+	account := multisig.Account{
+		Signers:   []signature.PublicKey{memorySigner.NewTestSigner("transaction test signer").Public()},
+		Threshold: 1,
+	}
+	multiSigTx, err := NewMultiSignedTransaction(&account, tx)
+	require.NoError(err, "NewMultiSignedTransaction")
+
+	decodedSigTx, decodedMultiSigTx, err = UnmarshalSignedTransaction(cbor.Marshal(multiSigTx))
+	require.NoError(err, "UnmarshalSignedTransaction")
+	require.Nil(decodedSigTx, "single-signed transaction should be nil")
+	require.EqualValues(multiSigTx, decodedMultiSigTx, "multi-signed transaction should be decoded")
+
+	_, _, err = UnmarshalSignedTransaction([]byte("malformed"))
+	require.Error(err, "malformed transactions should fail to decode")
+}
@@ -39,6 +39,13 @@ type Parameters struct { // nolint: maligned
 
 	// PublicKeyBlacklist is the network-wide public key blacklist.
 	PublicKeyBlacklist []signature.PublicKey `json:"public_key_blacklist,omitempty"`
+
+	// EnableBatchTransactions specifies whether batch transactions (consensus.Batch) are accepted.
+	EnableBatchTransactions bool `json:"enable_batch_transactions,omitempty"`
+
+	// EnableMultisigTransactions specifies whether transactions authorized by multisig accounts
+	// (transaction.MultiSignedTransaction) are accepted.
+	EnableMultisigTransactions bool `json:"enable_multisig_transactions,omitempty"`
 }
 
 const (
@@ -0,0 +1,67 @@
+package abci
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+)
+
+// executeBatch executes all calls of the given batch transaction atomically.
+//
+// The transaction must have already been authenticated and its fee paid. Each call is dispatched
+// to the handling application as if it was a separate transaction with the same nonce and fee. In
+// case any of the calls fails, the state updates and events of all calls are discarded.
+func (mux *abciMux) executeBatch(ctx *api.Context, tx *transaction.Transaction) error {
+	var batch consensus.Batch
+	if err := cbor.Unmarshal(tx.Body, &batch); err != nil {
+		return fmt.Errorf("%w: malformed batch: %s", consensus.ErrInvalidArgument, err)
+	}
+	if err := batch.ValidateBasic(); err != nil {
+		return err
+	}
+
+	batchCtx := ctx.NewTransaction()
+	defer batchCtx.Close()
+
+	for i, call := range batch.Calls {
+		app := mux.appsByMethod[call.Method]
+		if app == nil {
+			return fmt.Errorf("mux: unknown method in batch call %d: %s", i, call.Method)
+		}
+
+		ctx.Logger().Debug("dispatching batch call",
+			"app", app.Name(),
+			"index", i,
+			"method", call.Method,
+		)
+
+		gasBefore := batchCtx.Gas().GasUsed()
+		callCtx := batchCtx.NewChild()
+		err := app.ExecuteTx(callCtx, &transaction.Transaction{
+			Nonce:  tx.Nonce,
+			Fee:    tx.Fee,
+			Method: call.Method,
+			Body:   call.Body,
+		})
+		if err != nil {
+			callCtx.Close()
+			return fmt.Errorf("mux: batch call %d (%s) failed: %w", i, call.Method, err)
+		}
+
+		// Emit the call event before closing the call context so that it precedes any events
+		// emitted by the call itself.
+		batchCtx.EmitEvent(api.NewEventBuilder(consensus.ModuleName).TypedAttribute(&results.BatchCallEvent{
+			Index:   uint32(i),
+			Method:  call.Method,
+			GasUsed: batchCtx.Gas().GasUsed() - gasBefore,
+		}))
+		callCtx.Close()
+	}
+
+	batchCtx.Commit()
+	return nil
+}
@@ -0,0 +1,254 @@
+package abci
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	eventsAPI "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	storageDB "github.com/oasisprotocol/oasis-core/go/storage/database"
+)
+
+const (
+	batchTestAppName = "batchtest"
+
+	batchTestGasOpWrite transaction.Op = "write"
+)
+
+var (
+	methodBatchTestWrite = transaction.NewMethodName(batchTestAppName, "Write", batchTestWrite{})
+
+	batchTestGasCosts = transaction.Costs{
+		batchTestGasOpWrite: 10,
+	}
+
+	errBatchTest = fmt.Errorf("batch test: write failed")
+
+	batchTestFeeKey = []byte("fee")
+)
+
+type batchTestWrite struct {
+	Key  []byte `json:"key"`
+	Fail bool   `json:"fail,omitempty"`
+}
+
+type batchTestWriteEvent struct {
+	Key []byte `json:"key"`
+}
+
+func (e *batchTestWriteEvent) EventKind() string {
+	return "write"
+}
+
+// batchTestApp is an application which writes the given keys to state.
+type batchTestApp struct{}
+
+func (app *batchTestApp) Name() string {
+	return batchTestAppName
+}
+
+func (app *batchTestApp) ID() uint8 {
+	return 0xff
+}
+
+func (app *batchTestApp) Methods() []transaction.MethodName {
+	return []transaction.MethodName{methodBatchTestWrite}
+}
+
+func (app *batchTestApp) Blessed() bool {
+	return false
+}
+
+func (app *batchTestApp) Dependencies() []string {
+	return nil
+}
+
+func (app *batchTestApp) QueryFactory() interface{} {
+	return nil
+}
+
+func (app *batchTestApp) OnRegister(state api.ApplicationState, md api.MessageDispatcher) {
+}
+
+func (app *batchTestApp) OnCleanup() {
+}
+
+func (app *batchTestApp) ExecuteMessage(ctx *api.Context, kind, msg interface{}) (interface{}, error) {
+	return nil, fmt.Errorf("batch test: unexpected message")
+}
+
+func (app *batchTestApp) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	var w batchTestWrite
+	if err := cbor.Unmarshal(tx.Body, &w); err != nil {
+		return consensus.ErrInvalidArgument
+	}
+	if err := ctx.Gas().UseGas(1, batchTestGasOpWrite, batchTestGasCosts); err != nil {
+		return err
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+	if w.Fail {
+		return errBatchTest
+	}
+
+	ctx.EmitEvent(api.NewEventBuilder(batchTestAppName).TypedAttribute(&batchTestWriteEvent{Key: w.Key}))
+	return ctx.State().Insert(ctx, w.Key, []byte("written"))
+}
+
+func (app *batchTestApp) InitChain(*api.Context, types.RequestInitChain, *genesis.Document) error {
+	return nil
+}
+
+func (app *batchTestApp) BeginBlock(*api.Context, types.RequestBeginBlock) error {
+	return nil
+}
+
+func (app *batchTestApp) EndBlock(*api.Context, types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	return types.ResponseEndBlock{}, nil
+}
+
+// batchTestAuthHandler is a transaction auth handler which pays fees by writing to state.
+type batchTestAuthHandler struct{}
+
+func (h *batchTestAuthHandler) GetSignerNonce(ctx context.Context, req *consensus.GetSignerNonceRequest) (uint64, error) {
+	return 0, nil
+}
+
+func (h *batchTestAuthHandler) AuthenticateTx(ctx *api.Context, tx *transaction.Transaction) error {
+	ctx.SetGasAccountant(api.NewGasAccountant(tx.Fee.Gas))
+	return ctx.State().Insert(ctx, batchTestFeeKey, []byte("paid"))
+}
+
+func (h *batchTestAuthHandler) PostExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	return nil
+}
+
+func TestExecuteBatch(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	signature.SetChainContext("test: abci")
+	signer := memorySigner.NewTestSigner("abci batch test signer")
+
+	mux, err := newABCIMux(ctx, nil, &ApplicationConfig{
+		StorageBackend:      storageDB.BackendNameBadgerDB,
+		MemoryOnlyStorage:   true,
+		DisableCheckpointer: true,
+		InitialHeight:       1,
+		Pruning: PruneConfig{
+			Strategy:      PruneNone,
+			PruneInterval: time.Second,
+		},
+	})
+	require.NoError(err, "newABCIMux")
+	err = mux.state.startPruner()
+	require.NoError(err, "startPruner")
+	defer mux.doCleanup()
+
+	err = mux.doRegister(&batchTestApp{})
+	require.NoError(err, "doRegister")
+	mux.state.txAuthHandler = &batchTestAuthHandler{}
+	mux.state.blockParams = &consensusGenesis.Parameters{
+		EnableBatchTransactions: true,
+	}
+
+	newBatchTx := func(writes ...batchTestWrite) []byte {
+		var batch consensus.Batch
+		for _, w := range writes {
+			batch.Calls = append(batch.Calls, consensus.NewBatchCall(methodBatchTestWrite, &w))
+		}
+		tx := consensus.NewBatchTx(0, &transaction.Fee{Gas: 1000}, &batch)
+		sigTx, err := transaction.Sign(signer, tx)
+		require.NoError(err, "Sign")
+		return cbor.Marshal(sigTx)
+	}
+	getState := func(key []byte) []byte {
+		value, err := mux.state.deliverTxTree.Get(ctx, key)
+		require.NoError(err, "Get")
+		return value
+	}
+
+	// A failing call should roll back all of the batch, but the fee should still be paid.
+	rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: newBatchTx(
+		batchTestWrite{Key: []byte("a")},
+		batchTestWrite{Key: []byte("b"), Fail: true},
+	)})
+	require.NotEqualValues(types.CodeTypeOK, rsp.Code, "DeliverTx should fail in case a call fails")
+	require.Contains(rsp.Log, "batch call 1", "error should refer to the failed call")
+	require.Empty(rsp.Events, "no events should be emitted by a failed batch")
+	require.Nil(getState([]byte("a")), "state updates of a failed batch should be discarded")
+	require.EqualValues([]byte("paid"), getState(batchTestFeeKey), "fee should be paid for a failed batch")
+
+	// A successful batch should apply all calls and emit events in order.
+	err = mux.state.deliverTxTree.Remove(ctx, batchTestFeeKey)
+	require.NoError(err, "Remove")
+	rsp = mux.DeliverTx(types.RequestDeliverTx{Tx: newBatchTx(
+		batchTestWrite{Key: []byte("a")},
+		batchTestWrite{Key: []byte("b")},
+	)})
+	require.EqualValues(types.CodeTypeOK, rsp.Code, "DeliverTx should succeed")
+	require.EqualValues(20, rsp.GasUsed, "gas used should include all calls")
+	require.EqualValues([]byte("written"), getState([]byte("a")), "first call should be applied")
+	require.EqualValues([]byte("written"), getState([]byte("b")), "second call should be applied")
+	require.EqualValues([]byte("paid"), getState(batchTestFeeKey), "fee should be paid")
+
+	require.Len(rsp.Events, 4, "each call should emit a batch call event followed by its events")
+	for i, key := range []string{"a", "b"} {
+		callEv := rsp.Events[2*i]
+		require.EqualValues(api.EventTypeForApp(consensus.ModuleName), callEv.Type, "batch call event should come first")
+		require.Len(callEv.Attributes, 1)
+		require.True(eventsAPI.IsAttributeKind(callEv.Attributes[0].Key, &results.BatchCallEvent{}))
+		var bce results.BatchCallEvent
+		err = eventsAPI.DecodeValue(string(callEv.Attributes[0].Value), &bce)
+		require.NoError(err, "DecodeValue")
+		require.EqualValues(i, bce.Index, "batch call event should have the correct index")
+		require.EqualValues(methodBatchTestWrite, bce.Method, "batch call event should have the correct method")
+		require.EqualValues(10, bce.GasUsed, "batch call event should report per-call gas used")
+
+		writeEv := rsp.Events[2*i+1]
+		require.EqualValues(api.EventTypeForApp(batchTestAppName), writeEv.Type, "call events should follow the batch call event")
+		var we batchTestWriteEvent
+		err = eventsAPI.DecodeValue(string(writeEv.Attributes[0].Value), &we)
+		require.NoError(err, "DecodeValue")
+		require.EqualValues(key, we.Key, "call events should be in call order")
+	}
+
+	// CheckTx should accept valid batches without applying them to the block state.
+	err = mux.state.deliverTxTree.Remove(ctx, []byte("a"))
+	require.NoError(err, "Remove")
+	checkRsp := mux.CheckTx(types.RequestCheckTx{Tx: newBatchTx(batchTestWrite{Key: []byte("a")})})
+	require.EqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should succeed")
+	require.EqualValues(10, checkRsp.GasUsed, "CheckTx should charge gas for all calls")
+	require.Nil(getState([]byte("a")), "CheckTx should not modify block state")
+
+	// CheckTx should reject invalid batches.
+	checkRsp = mux.CheckTx(types.RequestCheckTx{Tx: newBatchTx()})
+	require.NotEqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should reject empty batches")
+	invalidCall := consensus.Batch{Calls: []consensus.BatchCall{{Method: methodBatchTestWrite, Body: []byte("invalid")}}}
+	sigTx, err := transaction.Sign(signer, consensus.NewBatchTx(0, &transaction.Fee{Gas: 1000}, &invalidCall))
+	require.NoError(err, "Sign")
+	checkRsp = mux.CheckTx(types.RequestCheckTx{Tx: cbor.Marshal(sigTx)})
+	require.NotEqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should reject batches with invalid calls")
+
+	// Batches should be rejected unless enabled.
+	mux.state.blockParams = &consensusGenesis.Parameters{}
+	checkRsp = mux.CheckTx(types.RequestCheckTx{Tx: newBatchTx(batchTestWrite{Key: []byte("a")})})
+	require.NotEqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should reject batches when disabled")
+	rsp = mux.DeliverTx(types.RequestDeliverTx{Tx: newBatchTx(batchTestWrite{Key: []byte("a")})})
+	require.NotEqualValues(types.CodeTypeOK, rsp.Code, "DeliverTx should reject batches when disabled")
+	require.Nil(getState([]byte("a")), "disabled batches should not be applied")
+}
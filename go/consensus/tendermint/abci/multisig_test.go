@@ -0,0 +1,87 @@
+package abci
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	storageDB "github.com/oasisprotocol/oasis-core/go/storage/database"
+)
+
+func TestMultisigTransactions(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	signature.SetChainContext("test: abci")
+	signers := []signature.Signer{
+		memorySigner.NewTestSigner("abci multisig test signer 1"),
+		memorySigner.NewTestSigner("abci multisig test signer 2"),
+	}
+	account := multisig.Account{
+		Signers:   []signature.PublicKey{signers[0].Public(), signers[1].Public()},
+		Threshold: 2,
+	}
+
+	mux, err := newABCIMux(ctx, nil, &ApplicationConfig{
+		StorageBackend:      storageDB.BackendNameBadgerDB,
+		MemoryOnlyStorage:   true,
+		DisableCheckpointer: true,
+		InitialHeight:       1,
+		Pruning: PruneConfig{
+			Strategy:      PruneNone,
+			PruneInterval: time.Second,
+		},
+	})
+	require.NoError(err, "newABCIMux")
+	err = mux.state.startPruner()
+	require.NoError(err, "startPruner")
+	defer mux.doCleanup()
+
+	err = mux.doRegister(&batchTestApp{})
+	require.NoError(err, "doRegister")
+	mux.state.txAuthHandler = &batchTestAuthHandler{}
+
+	newMultisigTx := func(key string) []byte {
+		tx := transaction.NewTransaction(0, &transaction.Fee{Gas: 1000}, methodBatchTestWrite, &batchTestWrite{Key: []byte(key)})
+		multiSigTx, err := transaction.NewMultiSignedTransaction(&account, tx)
+		require.NoError(err, "NewMultiSignedTransaction")
+		for _, signer := range signers {
+			err = multiSigTx.Sign(signer)
+			require.NoError(err, "Sign")
+		}
+		return cbor.Marshal(multiSigTx)
+	}
+	getState := func(key string) []byte {
+		value, err := mux.state.deliverTxTree.Get(ctx, []byte(key))
+		require.NoError(err, "Get")
+		return value
+	}
+
+	// Multisig transactions should be rejected unless enabled.
+	mux.state.blockParams = &consensusGenesis.Parameters{}
+	checkRsp := mux.CheckTx(types.RequestCheckTx{Tx: newMultisigTx("a")})
+	require.NotEqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should reject multisig transactions when disabled")
+	rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: newMultisigTx("a")})
+	require.NotEqualValues(types.CodeTypeOK, rsp.Code, "DeliverTx should reject multisig transactions when disabled")
+	require.Contains(rsp.Log, "multisig transactions are disabled")
+	require.Nil(getState("a"), "disabled multisig transactions should not be applied")
+
+	// Multisig transactions should be accepted when enabled.
+	mux.state.blockParams = &consensusGenesis.Parameters{
+		EnableMultisigTransactions: true,
+	}
+	checkRsp = mux.CheckTx(types.RequestCheckTx{Tx: newMultisigTx("a")})
+	require.EqualValues(types.CodeTypeOK, checkRsp.Code, "CheckTx should accept multisig transactions when enabled")
+	rsp = mux.DeliverTx(types.RequestDeliverTx{Tx: newMultisigTx("a")})
+	require.EqualValues(types.CodeTypeOK, rsp.Code, "DeliverTx should accept multisig transactions when enabled")
+	require.EqualValues([]byte("written"), getState("a"), "enabled multisig transactions should be applied")
+}
@@ -31,6 +31,7 @@ import (
 	abciState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci/state"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -187,6 +188,20 @@ func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transact
 	return a.mux.EstimateGas(caller, tx)
 }
 
+// DryRunTx executes the given transaction against a separate copy of the state at the given
+// block height, as if it was included in the following block with the given block time. No
+// changes are committed.
+//
+// The caller must close the returned result after it is done inspecting it.
+func (a *ApplicationServer) DryRunTx(
+	caller signature.PublicKey,
+	tx *transaction.Transaction,
+	height int64,
+	blockTime time.Time,
+) (*DryRunTxResult, error) {
+	return a.mux.DryRunTx(caller, tx, height, blockTime)
+}
+
 // State returns the application state.
 func (a *ApplicationServer) State() api.ApplicationQueryState {
 	return a.mux.state
@@ -497,10 +512,15 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 	return response
 }
 
-func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Transaction, *transaction.SignedTransaction, error) {
+// decodeTx decodes and verifies the given raw transaction and sets the
+// authenticated transaction signer in the context.
+//
+// Both single-signed and multisig (threshold-signed) envelopes are supported,
+// the latter only in case they have been enabled.
+func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Transaction, error) {
 	if mux.state.haltMode {
 		ctx.Logger().Debug("executeTx: in halt, rejecting all transactions")
-		return nil, nil, fmt.Errorf("halt mode, rejecting all transactions")
+		return nil, fmt.Errorf("halt mode, rejecting all transactions")
 	}
 
 	params := mux.state.ConsensusParameters()
@@ -510,38 +530,63 @@ func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Trans
 		ctx.Logger().Error("received oversized transaction",
 			"tx_size", len(rawTx),
 		)
-		return nil, nil, consensus.ErrOversizedTx
+		return nil, consensus.ErrOversizedTx
 	}
 
 	// Unmarshal envelope and verify transaction.
-	var sigTx transaction.SignedTransaction
-	if err := cbor.Unmarshal(rawTx, &sigTx); err != nil {
-		ctx.Logger().Error("failed to unmarshal signed transaction",
-			"tx", base64.StdEncoding.EncodeToString(rawTx),
-		)
-		return nil, nil, err
-	}
 	var tx transaction.Transaction
-	if err := sigTx.Open(&tx); err != nil {
-		ctx.Logger().Error("failed to verify transaction signature",
+	sigTx, multiSigTx, err := transaction.UnmarshalSignedTransaction(rawTx)
+	switch {
+	case err != nil:
+		ctx.Logger().Error("failed to unmarshal signed transaction",
 			"tx", base64.StdEncoding.EncodeToString(rawTx),
+			"err", err,
 		)
-		return nil, nil, err
+		return nil, fmt.Errorf("mux: %w", err)
+	case sigTx != nil:
+		if err = sigTx.Open(&tx); err != nil {
+			ctx.Logger().Error("failed to verify transaction signature",
+				"tx", base64.StdEncoding.EncodeToString(rawTx),
+			)
+			return nil, err
+		}
+		ctx.SetTxSigner(sigTx.Signature.PublicKey)
+	default:
+		if !params.EnableMultisigTransactions {
+			ctx.Logger().Debug("rejecting multisig transaction as multisig transactions are disabled")
+			return nil, fmt.Errorf("mux: multisig transactions are disabled")
+		}
+		if err = multiSigTx.Open(&tx); err != nil {
+			ctx.Logger().Error("failed to verify multisig transaction signatures",
+				"tx", base64.StdEncoding.EncodeToString(rawTx),
+				"err", err,
+			)
+			return nil, err
+		}
+		ctx.SetMultisigTxSigner(&multiSigTx.Account)
 	}
-	if err := tx.SanityCheck(); err != nil {
+	if err = tx.SanityCheck(); err != nil {
 		ctx.Logger().Error("bad transaction",
 			"tx", base64.StdEncoding.EncodeToString(rawTx),
 		)
-		return nil, nil, err
+		return nil, err
 	}
 
-	return &tx, &sigTx, nil
+	return &tx, nil
 }
 
 func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txSize int) error {
-	// Lookup method handler.
+	params := mux.state.ConsensusParameters()
+	if ctx.IsDryRun() {
+		// Dry runs may be executed against past state with different parameters.
+		params = ctx.AppState().ConsensusParameters()
+	}
+
+	// Lookup method handler. Batches are handled by the multiplexer itself, but only in case they
+	// have been enabled.
 	app := mux.appsByMethod[tx.Method]
-	if app == nil {
+	isBatch := tx.Method == consensus.MethodBatch && params.EnableBatchTransactions
+	if app == nil && !isBatch {
 		ctx.Logger().Error("unknown method",
 			"tx", tx,
 			"method", tx.Method,
@@ -566,19 +611,25 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 	}
 
 	// Charge gas based on the size of the transaction.
-	params := mux.state.ConsensusParameters()
 	if err := ctx.Gas().UseGas(txSize, consensusGenesis.GasOpTxByte, params.GasCosts); err != nil {
 		return err
 	}
 
-	// Route to correct handler.
-	ctx.Logger().Debug("dispatching",
-		"app", app.Name(),
-		"tx", tx,
-	)
+	switch isBatch {
+	case true:
+		if err := mux.executeBatch(ctx, tx); err != nil {
+			return err
+		}
+	case false:
+		// Route to correct handler.
+		ctx.Logger().Debug("dispatching",
+			"app", app.Name(),
+			"tx", tx,
+		)
 
-	if err := app.ExecuteTx(ctx, tx); err != nil {
-		return err
+		if err := app.ExecuteTx(ctx, tx); err != nil {
+			return err
+		}
 	}
 
 	//  Pass the transaction through the PostExecuteTx handler if configured.
@@ -598,14 +649,11 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 }
 
 func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) error {
-	tx, sigTx, err := mux.decodeTx(ctx, rawTx)
+	tx, err := mux.decodeTx(ctx, rawTx)
 	if err != nil {
 		return err
 	}
 
-	// Set authenticated transaction signer.
-	ctx.SetTxSigner(sigTx.Signature.PublicKey)
-
 	// If we are in CheckTx mode and there is a pending upgrade in this block, make sure to reject
 	// any transactions before processing as they may potentially query incompatible state.
 	if upgrader := mux.state.Upgrader(); upgrader != nil && ctx.IsCheckOnly() {
@@ -663,6 +711,79 @@ func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Tran
 	return ctx.Gas().GasUsed(), nil
 }
 
+// DryRunTxResult is the result of a transaction dry run.
+type DryRunTxResult struct {
+	// Error is the error returned by transaction execution, if any.
+	Error error
+	// Events are the events emitted by transaction execution.
+	Events []types.Event
+	// GasUsed is the amount of gas used by transaction execution.
+	GasUsed transaction.Gas
+
+	ctx *api.Context
+}
+
+// State returns the state after transaction execution.
+//
+// The state is only valid until the result is closed.
+func (r *DryRunTxResult) State() mkvs.KeyValueTree {
+	return r.ctx.State()
+}
+
+// Close releases all resources associated with the dry run.
+func (r *DryRunTxResult) Close() {
+	r.ctx.Close()
+}
+
+func (mux *abciMux) DryRunTx(
+	caller signature.PublicKey,
+	tx *transaction.Transaction,
+	height int64,
+	blockTime time.Time,
+) (*DryRunTxResult, error) {
+	if tx == nil {
+		return nil, consensus.ErrInvalidArgument
+	}
+	if err := tx.SanityCheck(); err != nil {
+		return nil, consensus.ErrInvalidArgument
+	}
+
+	// Certain modules, in particular the beacon require InitChain or BeginBlock
+	// to have completed before initialization is complete.
+	if atomic.LoadInt64(&mux.lastBeginBlock) == blockHeightInvalid {
+		return nil, consensus.ErrNoCommittedBlocks
+	}
+
+	// As opposed to other transaction dispatch entry points (CheckTx/DeliverTx), this method can
+	// be called in parallel to the consensus layer and to other invocations.
+	ctx, err := mux.state.NewDryRunContext(height, blockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.SetTxSigner(caller)
+	mockSignedTx := transaction.SignedTransaction{
+		Signed: signature.Signed{
+			Blob: cbor.Marshal(tx),
+			// Signature is fixed-size, so we can leave it as default.
+		},
+	}
+	txSize := len(cbor.Marshal(mockSignedTx))
+
+	txErr := mux.processTx(ctx, tx, txSize)
+	if api.IsUnavailableStateError(txErr) {
+		ctx.Close()
+		return nil, txErr
+	}
+
+	return &DryRunTxResult{
+		Error:   txErr,
+		Events:  ctx.GetEvents(),
+		GasUsed: ctx.Gas().GasUsed(),
+		ctx:     ctx,
+	}, nil
+}
+
 func (mux *abciMux) notifyInvalidatedCheckTx(txHash hash.Hash, err error) {
 	if item, exists := mux.invalidatedTxs.Load(txHash); exists {
 		// Notify subscriber.
@@ -17,6 +17,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	abciState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci/state"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
@@ -29,7 +30,10 @@ import (
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
-var _ api.ApplicationState = (*applicationState)(nil)
+var (
+	_ api.ApplicationState = (*applicationState)(nil)
+	_ api.ApplicationState = (*dryRunState)(nil)
+)
 
 // appStateDir is the subdirectory which contains ABCI state.
 const appStateDir = "abci-state"
@@ -113,6 +117,97 @@ func (s *applicationState) NewContext(mode api.ContextMode, now time.Time) *api.
 	)
 }
 
+// NewDryRunContext creates a new dry run context operating on a separate in-memory copy of the
+// state at the given block height. Transactions are executed as if they were included in the
+// following block with the given block time.
+func (s *applicationState) NewDryRunContext(blockHeight int64, now time.Time) (*api.Context, error) {
+	if blockHeight < int64(s.initialHeight) || blockHeight > s.BlockHeight() {
+		return nil, consensus.ErrVersionNotFound
+	}
+
+	ndb := s.storage.NodeDB()
+	roots, err := ndb.GetRootsForVersion(s.ctx, uint64(blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	switch len(roots) {
+	case 0:
+		// No roots for that state -- it may have been pruned.
+		return nil, consensus.ErrVersionNotFound
+	case 1:
+		// A single root.
+	default:
+		// Unexpected number of roots.
+		return nil, fmt.Errorf("state: incorrect number of roots (%d): %+v", blockHeight, roots)
+	}
+
+	// Since the dry run is running in parallel to any changes to the database, we make sure to
+	// create a separate in-memory tree at the given block height.
+	tree := mkvs.NewWithRoot(nil, ndb, roots[0], mkvs.WithoutWriteLog())
+
+	// Consensus parameters may have changed since the given height.
+	params, err := abciState.NewMutableState(tree).ConsensusParameters(s.ctx)
+	if err != nil {
+		tree.Close()
+		return nil, fmt.Errorf("state: failed to load consensus parameters: %w", err)
+	}
+
+	drs := &dryRunState{
+		applicationState: s,
+		blockHeight:      blockHeight,
+		blockCtx:         api.NewBlockContext(),
+		blockParams:      params,
+	}
+	if params.MaxBlockGas > 0 {
+		drs.blockCtx.Set(api.GasAccountantKey{}, api.NewGasAccountant(params.MaxBlockGas))
+	} else {
+		drs.blockCtx.Set(api.GasAccountantKey{}, api.NewNopGasAccountant())
+	}
+
+	return api.NewContext(
+		s.ctx,
+		api.ContextDryRunTx,
+		now,
+		api.NewNopGasAccountant(),
+		drs,
+		tree,
+		blockHeight,
+		drs.blockCtx,
+		int64(s.initialHeight),
+	), nil
+}
+
+// dryRunState is the application state as seen by a dry run context. It reports the block
+// height, consensus parameters and block context of the dry run instead of the latest block.
+type dryRunState struct {
+	*applicationState
+
+	blockHeight int64
+	blockCtx    *api.BlockContext
+	blockParams *consensusGenesis.Parameters
+}
+
+func (s *dryRunState) BlockHeight() int64 {
+	return s.blockHeight
+}
+
+func (s *dryRunState) ConsensusParameters() *consensusGenesis.Parameters {
+	return s.blockParams
+}
+
+func (s *dryRunState) BlockContext() *api.BlockContext {
+	return s.blockCtx
+}
+
+func (s *dryRunState) GetCurrentEpoch(ctx context.Context) (beacon.EpochTime, error) {
+	return s.getCurrentEpochAt(ctx, s.blockHeight)
+}
+
+func (s *dryRunState) EpochChanged(ctx *api.Context) (bool, beacon.EpochTime) {
+	// Epoch transitions only happen in BeginBlock.
+	return false, beacon.EpochInvalid
+}
+
 func (s *applicationState) LastRetainedVersion() (int64, error) {
 	return int64(s.statePruner.GetLastRetainedVersion()), nil
 }
@@ -171,7 +266,10 @@ func (s *applicationState) GetEpoch(ctx context.Context, blockHeight int64) (bea
 }
 
 func (s *applicationState) GetCurrentEpoch(ctx context.Context) (beacon.EpochTime, error) {
-	blockHeight := s.BlockHeight()
+	return s.getCurrentEpochAt(ctx, s.BlockHeight())
+}
+
+func (s *applicationState) getCurrentEpochAt(ctx context.Context, blockHeight int64) (beacon.EpochTime, error) {
 	if blockHeight == 0 {
 		return beacon.EpochInvalid, nil
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/tendermint/tendermint/abci/types"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
@@ -35,6 +36,11 @@ const (
 	ContextBeginBlock
 	// ContextEndBlock is EndBlock context.
 	ContextEndBlock
+	// ContextDryRunTx is DryRunTx context.
+	//
+	// In contrast to SimulateTx, transactions are fully executed as in DeliverTx, but against a
+	// separate in-memory copy of state which is discarded afterwards.
+	ContextDryRunTx
 )
 
 // String returns a string representation of the context mode.
@@ -52,6 +58,8 @@ func (m ContextMode) String() string {
 		return "begin block"
 	case ContextEndBlock:
 		return "end block"
+	case ContextDryRunTx:
+		return "dry run tx"
 	default:
 		return "[invalid]"
 	}
@@ -126,7 +134,7 @@ func (c *Context) Close() {
 	switch c.parent {
 	case nil:
 		// This is the top-level context.
-		if c.IsSimulation() {
+		if c.IsSimulation() || c.IsDryRun() {
 			if tree, ok := c.state.(mkvs.ClosableTree); ok {
 				tree.Close()
 			}
@@ -174,7 +182,7 @@ func (c *Context) Data() interface{} {
 // will panic.
 func (c *Context) TxSigner() signature.PublicKey {
 	switch c.mode {
-	case ContextCheckTx, ContextDeliverTx, ContextSimulateTx:
+	case ContextCheckTx, ContextDeliverTx, ContextSimulateTx, ContextDryRunTx:
 		return c.txSigner
 	default:
 		panic("context: only available in transaction context")
@@ -189,7 +197,7 @@ func (c *Context) TxSigner() signature.PublicKey {
 // will panic.
 func (c *Context) SetTxSigner(txSigner signature.PublicKey) {
 	switch c.mode {
-	case ContextCheckTx, ContextDeliverTx, ContextSimulateTx:
+	case ContextCheckTx, ContextDeliverTx, ContextSimulateTx, ContextDryRunTx:
 		c.txSigner = txSigner
 		// By default, the caller is the transaction signer.
 		c.callerAddress = staking.NewAddress(txSigner)
@@ -198,6 +206,27 @@ func (c *Context) SetTxSigner(txSigner signature.PublicKey) {
 	}
 }
 
+// SetMultisigTxSigner sets the authenticated multisig account as the
+// transaction signer.
+//
+// Since there is no single signing public key, TxSigner will return an
+// empty public key and the caller address will be derived from the
+// multisig account descriptor.
+//
+// This must only be done after verifying the transaction signatures.
+//
+// In case the method is called on a non-transaction context, this method
+// will panic.
+func (c *Context) SetMultisigTxSigner(account *multisig.Account) {
+	switch c.mode {
+	case ContextCheckTx, ContextDeliverTx, ContextSimulateTx, ContextDryRunTx:
+		c.txSigner = signature.PublicKey{}
+		c.callerAddress = staking.NewMultisigAddress(account)
+	default:
+		panic("context: only available in transaction context")
+	}
+}
+
 // CallerAddress returns the authenticated address representing the caller.
 func (c *Context) CallerAddress() staking.Address {
 	return c.callerAddress
@@ -303,6 +332,11 @@ func (c *Context) IsSimulation() bool {
 	return c.mode == ContextSimulateTx
 }
 
+// IsDryRun returns true if this is a dry run context.
+func (c *Context) IsDryRun() bool {
+	return c.mode == ContextDryRunTx
+}
+
 // IsMessageExecution returns true if this is a message execution context.
 func (c *Context) IsMessageExecution() bool {
 	return c.isMessageExecution
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
@@ -254,3 +255,46 @@ func TestNestedTransactionContext(t *testing.T) {
 	require.NoError(err, "Get")
 	require.EqualValues([]byte("value2"), value, "child2 state should be committed")
 }
+
+func TestMultisigTxSigner(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := NewMockApplicationState(&MockApplicationStateConfig{})
+	ctx := appState.NewContext(ContextDeliverTx, now)
+	defer ctx.Close()
+
+	account := multisig.Account{
+		Signers: []signature.PublicKey{
+			signature.NewPublicKey("1234567890000000000000000000000000000000000000000000000000000000"),
+			signature.NewPublicKey("abcdef0000000000000000000000000000000000000000000000000000000000"),
+		},
+		Threshold: 2,
+	}
+	ctx.SetMultisigTxSigner(&account)
+	require.Equal(staking.NewMultisigAddress(&account), ctx.CallerAddress(), "CallerAddress should correspond to multisig account")
+	require.Equal(signature.PublicKey{}, ctx.TxSigner(), "TxSigner should be empty for multisig transactions")
+
+	child := ctx.NewChild()
+	defer child.Close()
+	require.Equal(ctx.CallerAddress(), child.CallerAddress(), "child CallerAddress should be inherited")
+}
+
+func TestDryRunContext(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := NewMockApplicationState(&MockApplicationStateConfig{})
+	ctx := appState.NewContext(ContextDryRunTx, now)
+	defer ctx.Close()
+
+	require.True(ctx.IsDryRun(), "context should be a dry run context")
+	require.False(ctx.IsSimulation(), "dry run context should not be a simulation context")
+	require.False(ctx.IsCheckOnly(), "dry run context should not be a check-only context")
+	require.NotNil(ctx.AppState(), "application state should be available in dry run mode")
+	require.Equal("dry run tx", ctx.Mode().String())
+
+	var pk signature.PublicKey
+	ctx.SetTxSigner(pk)
+	require.Equal(pk, ctx.TxSigner(), "TxSigner should be available in dry run mode")
+}
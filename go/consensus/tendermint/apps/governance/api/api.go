@@ -0,0 +1,21 @@
+// Package api defines the governance application API for other applications.
+package api
+
+type messageKind uint8
+
+var (
+	// MessageValidateParameterChanges is the message kind for validating consensus parameter
+	// changes. The message is the change parameters proposal that is being submitted.
+	//
+	// Subscribers must ignore proposals targeting other modules and return a non-nil result iff
+	// the proposal targets their module and the changes are valid. Any errors returned from the
+	// handler will prevent the proposal from being submitted.
+	MessageValidateParameterChanges = messageKind(0)
+
+	// MessageChangeParameters is the message kind for applying consensus parameter changes. The
+	// message is the change parameters proposal that has passed.
+	//
+	// Subscribers must ignore proposals targeting other modules and return a non-nil result iff
+	// the proposal targets their module and the changes have been applied.
+	MessageChangeParameters = messageKind(1)
+)
@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryapp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
@@ -26,6 +27,7 @@ var _ api.Application = (*governanceApplication)(nil)
 
 type governanceApplication struct {
 	state api.ApplicationState
+	md    api.MessageDispatcher
 }
 
 func (app *governanceApplication) Name() string {
@@ -50,6 +52,7 @@ func (app *governanceApplication) Dependencies() []string {
 
 func (app *governanceApplication) OnRegister(state api.ApplicationState, md api.MessageDispatcher) {
 	app.state = state
+	app.md = md
 
 	// Subscribe to messages emitted by other apps.
 	md.Subscribe(api.MessageStateSyncCompleted, app)
@@ -261,6 +264,28 @@ func (app *governanceApplication) executeProposal(ctx *api.Context, state *gover
 				)
 			}
 		}
+	case proposal.Content.ChangeParameters != nil:
+		// Make sure that change parameters proposals have not been disabled while the proposal
+		// was being voted on.
+		params, err := state.ConsensusParameters(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query consensus parameters: %w", err)
+		}
+		if !params.EnableChangeParametersProposal {
+			return fmt.Errorf("%w: change parameters proposals are disabled", governance.ErrInvalidArgument)
+		}
+
+		// Dispatch the changes to the target module which validates them again as the state
+		// may have changed since the proposal was submitted.
+		res, err := app.md.Publish(ctx, governanceApi.MessageChangeParameters, proposal.Content.ChangeParameters)
+		if err != nil {
+			return fmt.Errorf("failed to change consensus parameters: %w", err)
+		}
+		if res == nil {
+			return fmt.Errorf("%w: unknown module: %s", governance.ErrInvalidParameterChanges, proposal.Content.ChangeParameters.Module)
+		}
+	case proposal.Content.Signaling != nil:
+		// Signaling proposals are non-binding, there is nothing to execute.
 	default:
 		return governance.ErrInvalidArgument
 	}
@@ -315,6 +340,47 @@ func (app *governanceApplication) validatorsEscrow(
 	return totalVotingStake, validatorEntitiesEscrow, nil
 }
 
+// delegatorVotingStake returns the total stake that the given delegator has delegated to current
+// validator entities together with the delegated stake for each of those entities.
+//
+// Self-delegations are not included as they are accounted for by the validator's own vote.
+func delegatorVotingStake(
+	ctx *api.Context,
+	stakingState *stakingState.MutableState,
+	validatorEntitiesEscrow map[stakingAPI.Address]*quantity.Quantity,
+	delegator stakingAPI.Address,
+) (*quantity.Quantity, map[stakingAPI.Address]*quantity.Quantity, error) {
+	delegations, err := stakingState.DelegationsFor(ctx, delegator)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query delegations: %w", err)
+	}
+
+	totalStake := quantity.NewQuantity()
+	delegatedStake := make(map[stakingAPI.Address]*quantity.Quantity)
+	for escrowAddr, delegation := range delegations {
+		if escrowAddr.Equal(delegator) {
+			continue
+		}
+		if _, ok := validatorEntitiesEscrow[escrowAddr]; !ok {
+			// Delegations to entities not in the current validator set carry no votes.
+			continue
+		}
+		validator, err := stakingState.Account(ctx, escrowAddr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query validator account: %w", err)
+		}
+		stake, err := validator.Escrow.Active.StakeForShares(&delegation.Shares)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compute delegated stake: %w", err)
+		}
+		if err = totalStake.Add(stake); err != nil {
+			return nil, nil, fmt.Errorf("failed to add delegated stake: %w", err)
+		}
+		delegatedStake[escrowAddr] = stake
+	}
+	return totalStake, delegatedStake, nil
+}
+
 // closeProposal closes an active proposal.
 //
 // This method modifies the passed proposal.
@@ -342,21 +408,70 @@ func (app *governanceApplication) closeProposal(
 		"validator_entities_escrow", validatorEntitiesEscrow,
 		"votes", votes,
 	)
-	// Tally the votes.
+	addVotes := func(vote governance.Vote, stake *quantity.Quantity) error {
+		currentVotes := proposal.Results[vote]
+		newVotes := stake.Clone()
+		if err := newVotes.Add(&currentVotes); err != nil {
+			return fmt.Errorf("failed to add votes: %w", err)
+		}
+		proposal.Results[vote] = *newVotes
+		return nil
+	}
+
+	// Tally the votes. Delegators to validator entities can override the validator's vote for
+	// their share of the validator's escrow pool (if enabled) so first account for the delegator
+	// votes.
+	stakingState := stakingState.NewMutableState(ctx.State())
+	overriddenEscrow := make(map[stakingAPI.Address]*quantity.Quantity)
+	delegatorVoters := make(map[stakingAPI.Address]bool)
+	for _, vote := range votes {
+		if !params.EnableDelegatorVotes {
+			break
+		}
+
+		totalStake, delegatedStake, err := delegatorVotingStake(ctx, stakingState, validatorEntitiesEscrow, vote.Voter)
+		if err != nil {
+			return err
+		}
+		if totalStake.IsZero() || totalStake.Cmp(&params.MinDelegatorVoteStake) < 0 {
+			// Delegations below the minimum delegator vote stake carry no votes.
+			continue
+		}
+		for escrowAddr, stake := range delegatedStake {
+			if err = addVotes(vote.Vote, stake); err != nil {
+				return err
+			}
+
+			if overriddenEscrow[escrowAddr] == nil {
+				overriddenEscrow[escrowAddr] = quantity.NewQuantity()
+			}
+			if err = overriddenEscrow[escrowAddr].Add(stake); err != nil {
+				return fmt.Errorf("failed to add overridden escrow: %w", err)
+			}
+		}
+		delegatorVoters[vote.Voter] = true
+	}
+
+	// Then account for the validator votes, excluding any stake overridden by delegators.
 	for _, vote := range votes {
 		escrow, ok := validatorEntitiesEscrow[vote.Voter]
 		if !ok {
-			// Voter not in current validator set - invalid vote.
-			proposal.InvalidVotes++
+			if !delegatorVoters[vote.Voter] {
+				// Voter neither in current validator set nor delegating to it - invalid vote.
+				proposal.InvalidVotes++
+			}
 			continue
 		}
 
-		currentVotes := proposal.Results[vote.Vote]
-		newVotes := escrow.Clone()
-		if err := newVotes.Add(&currentVotes); err != nil {
-			return fmt.Errorf("failed to add votes: %w", err)
+		stake := escrow.Clone()
+		if overridden := overriddenEscrow[vote.Voter]; overridden != nil {
+			if _, err := stake.SubUpTo(overridden); err != nil {
+				return fmt.Errorf("failed to subtract overridden escrow: %w", err)
+			}
+		}
+		if err := addVotes(vote.Vote, stake); err != nil {
+			return err
 		}
-		proposal.Results[vote.Vote] = *newVotes
 	}
 
 	ctx.Logger().Debug("close proposal",
@@ -171,6 +171,24 @@ func TestCloseProposal(t *testing.T) {
 	addr3 := staking.NewAddress(pk3)
 	pk4 := signature.NewPublicKey("dddfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
 	addr4 := staking.NewAddress(pk4)
+	pk5 := signature.NewPublicKey("eeefffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr5 := staking.NewAddress(pk5)
+
+	// Setup staking state. Half of addr2's escrow is delegated by addr5.
+	stakeState := stakingState.NewMutableState(ctx.State())
+	err = stakeState.SetAccount(ctx, addr2, &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     *quantity.NewFromUint64(60),
+				TotalShares: *quantity.NewFromUint64(60),
+			},
+		},
+	})
+	require.NoError(err, "SetAccount")
+	err = stakeState.SetDelegation(ctx, addr2, addr2, &staking.Delegation{Shares: *quantity.NewFromUint64(30)})
+	require.NoError(err, "SetDelegation")
+	err = stakeState.SetDelegation(ctx, addr5, addr2, &staking.Delegation{Shares: *quantity.NewFromUint64(30)})
+	require.NoError(err, "SetDelegation")
 
 	// Setup governance state.
 	state := governanceState.NewMutableState(ctx.State())
@@ -187,6 +205,12 @@ func TestCloseProposal(t *testing.T) {
 		UpgradeMinEpochDiff:       beacon.EpochTime(100),
 		VotingPeriod:              beacon.EpochTime(50),
 	}
+	delegatorConsParams := *baseConsParams
+	delegatorConsParams.EnableDelegatorVotes = true
+	delegatorConsParams.MinDelegatorVoteStake = *quantity.NewFromUint64(10)
+	delegatorConsParams.MaxDelegatorVotesPerProposal = 10
+	highMinStakeConsParams := delegatorConsParams
+	highMinStakeConsParams.MinDelegatorVoteStake = *quantity.NewFromUint64(31)
 
 	baseValidatorEntitiesEscrow := map[staking.Address]*quantity.Quantity{
 		addr1: quantity.NewFromUint64(5),
@@ -300,6 +324,112 @@ func TestCloseProposal(t *testing.T) {
 				governance.VoteNo:  *quantity.NewFromUint64(5),
 			},
 		},
+		{
+			"should be rejected if delegator overrides validator vote",
+			&delegatorConsParams,
+			quantity.NewFromUint64(100),
+			baseValidatorEntitiesEscrow,
+			&governance.Proposal{
+				ID:    6,
+				State: governance.StateActive,
+			},
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteYes},
+				{Voter: addr2, Vote: governance.VoteYes},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr5, Vote: governance.VoteNo},
+			},
+			governance.StateRejected,
+			0,
+			map[governance.Vote]quantity.Quantity{
+				governance.VoteYes: *quantity.NewFromUint64(70),
+				governance.VoteNo:  *quantity.NewFromUint64(30),
+			},
+		},
+		{
+			"should count delegator vote without validator vote",
+			&delegatorConsParams,
+			quantity.NewFromUint64(100),
+			baseValidatorEntitiesEscrow,
+			&governance.Proposal{
+				ID:    7,
+				State: governance.StateActive,
+			},
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteYes},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr5, Vote: governance.VoteYes},
+			},
+			governance.StateRejected,
+			0,
+			map[governance.Vote]quantity.Quantity{
+				governance.VoteYes: *quantity.NewFromUint64(70),
+			},
+		},
+		{
+			"should pass with delegator and validator votes",
+			&delegatorConsParams,
+			quantity.NewFromUint64(100),
+			baseValidatorEntitiesEscrow,
+			&governance.Proposal{
+				ID:    8,
+				State: governance.StateActive,
+			},
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteNo},
+				{Voter: addr2, Vote: governance.VoteYes},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr5, Vote: governance.VoteYes},
+			},
+			governance.StatePassed,
+			0,
+			map[governance.Vote]quantity.Quantity{
+				governance.VoteYes: *quantity.NewFromUint64(95),
+				governance.VoteNo:  *quantity.NewFromUint64(5),
+			},
+		},
+		{
+			"should ignore delegator votes when disabled",
+			baseConsParams,
+			quantity.NewFromUint64(100),
+			baseValidatorEntitiesEscrow,
+			&governance.Proposal{
+				ID:    9,
+				State: governance.StateActive,
+			},
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteYes},
+				{Voter: addr2, Vote: governance.VoteYes},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr5, Vote: governance.VoteNo},
+			},
+			governance.StatePassed,
+			1,
+			map[governance.Vote]quantity.Quantity{
+				governance.VoteYes: *quantity.NewFromUint64(100),
+			},
+		},
+		{
+			"should ignore delegator votes below the minimum delegator vote stake",
+			&highMinStakeConsParams,
+			quantity.NewFromUint64(100),
+			baseValidatorEntitiesEscrow,
+			&governance.Proposal{
+				ID:    10,
+				State: governance.StateActive,
+			},
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteYes},
+				{Voter: addr2, Vote: governance.VoteYes},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr5, Vote: governance.VoteNo},
+			},
+			governance.StatePassed,
+			1,
+			map[governance.Vote]quantity.Quantity{
+				governance.VoteYes: *quantity.NewFromUint64(100),
+			},
+		},
 	} {
 		err = state.SetConsensusParameters(ctx, tc.params)
 		require.NoError(err, "setting governance consensus parameters should not error")
@@ -345,14 +475,17 @@ func TestExecuteProposal(t *testing.T) {
 	state := governanceState.NewMutableState(ctx.State())
 	app := &governanceApplication{
 		state: appState,
+		md:    &testMsgDispatcher{},
 	}
 	// Consensus parameters.
-	err = state.SetConsensusParameters(ctx, &governance.ConsensusParameters{
-		MinProposalDeposit:        *quantity.NewFromUint64(100),
-		StakeThreshold:            90,
-		UpgradeMinEpochDiff:       10,
-		UpgradeCancelMinEpochDiff: 10,
-	})
+	params := &governance.ConsensusParameters{
+		MinProposalDeposit:             *quantity.NewFromUint64(100),
+		StakeThreshold:                 90,
+		UpgradeMinEpochDiff:            10,
+		UpgradeCancelMinEpochDiff:      10,
+		EnableChangeParametersProposal: true,
+	}
+	err = state.SetConsensusParameters(ctx, params)
 	require.NoError(err, "setting governance consensus parameters should not error")
 	// Prepare proposals.
 	err = state.SetProposal(ctx,
@@ -471,6 +604,28 @@ func TestExecuteProposal(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"executing change parameters proposal should fail for unknown module",
+			&governance.Proposal{
+				ID: 13,
+				Content: governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+					Module:  "unknown",
+					Changes: cbor.Marshal("changes"),
+				}},
+			},
+			governance.ErrInvalidParameterChanges,
+		},
+		{
+			"executing change parameters proposal should work",
+			&governance.Proposal{
+				ID: 14,
+				Content: governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+					Module:  "test",
+					Changes: cbor.Marshal("changes"),
+				}},
+			},
+			nil,
+		},
 	} {
 		err = app.executeProposal(ctx, state, tc.proposal)
 		if tc.err != nil {
@@ -489,6 +644,21 @@ func TestExecuteProposal(t *testing.T) {
 		err = state.SetProposal(ctx, tc.proposal)
 		require.NoError(err, "SetProposal")
 	}
+
+	// Change parameters proposals should fail in case they were disabled in the meantime.
+	params.EnableChangeParametersProposal = false
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "setting governance consensus parameters should not error")
+	proposal := &governance.Proposal{
+		ID: 15,
+		Content: governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+			Module:  "test",
+			Changes: cbor.Marshal("changes"),
+		}},
+	}
+	err = app.executeProposal(ctx, state, proposal)
+	require.ErrorIs(err, governance.ErrInvalidArgument, "executing disabled change parameters proposal should fail")
+	require.Equal(governance.StateFailed, proposal.State, "disabled change parameters proposal should fail")
 }
 
 func TestBeginBlock(t *testing.T) {
@@ -47,6 +47,13 @@ var (
 	// Key format is: 0x85.
 	// Value is CBOR-serialized governance.ConsensusParameters.
 	parametersKeyFmt = keyformat.New(0x85)
+
+	// delegatorVotesKeyFmt is the key format used for storing the number of delegator votes cast
+	// for proposals.
+	//
+	// Key format is: 0x86 <proposal-id (uint64)>.
+	// Value is a CBOR-serialized uint64.
+	delegatorVotesKeyFmt = keyformat.New(0x86, uint64(0))
 )
 
 // ImmutableState is the immutable consensus state wrapper.
@@ -174,6 +181,32 @@ func (s *ImmutableState) Votes(ctx context.Context, id uint64) ([]*governance.Vo
 	return voteEntries, nil
 }
 
+// HasVote checks whether the given voter has already voted for a proposal.
+func (s *ImmutableState) HasVote(ctx context.Context, id uint64, voter staking.Address) (bool, error) {
+	data, err := s.is.Get(ctx, votesKeyFmt.Encode(id, voter))
+	if err != nil {
+		return false, api.UnavailableStateError(err)
+	}
+	return data != nil, nil
+}
+
+// DelegatorVotes returns the number of delegator votes cast for a proposal.
+func (s *ImmutableState) DelegatorVotes(ctx context.Context, id uint64) (uint64, error) {
+	data, err := s.is.Get(ctx, delegatorVotesKeyFmt.Encode(id))
+	if err != nil {
+		return 0, api.UnavailableStateError(err)
+	}
+	if data == nil {
+		return 0, nil
+	}
+
+	var n uint64
+	if err = cbor.Unmarshal(data, &n); err != nil {
+		return 0, api.UnavailableStateError(err)
+	}
+	return n, nil
+}
+
 func (s *ImmutableState) isProposalPendingUpgrade(ctx context.Context, proposal *governance.Proposal) (bool, error) {
 	if proposal.Content.Upgrade == nil {
 		return false, nil
@@ -349,6 +382,12 @@ func (s *MutableState) SetVote(
 	return api.UnavailableStateError(err)
 }
 
+// SetDelegatorVotes sets the number of delegator votes cast for a proposal.
+func (s *MutableState) SetDelegatorVotes(ctx context.Context, id uint64, n uint64) error {
+	err := s.ms.Insert(ctx, delegatorVotesKeyFmt.Encode(id), cbor.Marshal(n))
+	return api.UnavailableStateError(err)
+}
+
 // SetConsensusParameters sets governance consensus parameters.
 //
 // NOTE: This method must only be called from InitChain/EndBlock contexts.
@@ -3,14 +3,14 @@ package governance
 import (
 	"fmt"
 
-	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
-	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
 	stakingAPI "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgradeAPI "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -48,7 +48,7 @@ func (app *governanceApplication) submitProposal(
 	}
 
 	// Load submitter account.
-	submitterAddr := stakingAPI.NewAddress(ctx.TxSigner())
+	submitterAddr := ctx.CallerAddress()
 	if !submitterAddr.IsValid() {
 		return stakingAPI.ErrForbidden
 	}
@@ -127,6 +127,23 @@ func (app *governanceApplication) submitProposal(
 		if upgrade.Descriptor.Epoch < params.UpgradeCancelMinEpochDiff+epoch {
 			return governance.ErrUpgradeTooSoon
 		}
+
+	case proposalContent.ChangeParameters != nil:
+		if !params.EnableChangeParametersProposal {
+			ctx.Logger().Error("governance: change parameters proposals are disabled",
+				"submitter", submitterAddr,
+			)
+			return fmt.Errorf("%w: change parameters proposals are disabled", governance.ErrInvalidArgument)
+		}
+
+		// Ask the target module to validate the proposed changes.
+		if err = app.validateParameterChanges(ctx, proposalContent.ChangeParameters); err != nil {
+			ctx.Logger().Error("governance: invalid consensus parameter changes",
+				"module", proposalContent.ChangeParameters.Module,
+				"err", err,
+			)
+			return err
+		}
 	}
 
 	// Deposit proposal funds.
@@ -184,6 +201,23 @@ func (app *governanceApplication) submitProposal(
 	return nil
 }
 
+// validateParameterChanges dispatches the change parameters proposal to the
+// target module for validation.
+func (app *governanceApplication) validateParameterChanges(
+	ctx *api.Context,
+	proposal *governance.ChangeParametersProposal,
+) error {
+	res, err := app.md.Publish(ctx, governanceApi.MessageValidateParameterChanges, proposal)
+	if err != nil {
+		return fmt.Errorf("%w: %s", governance.ErrInvalidParameterChanges, err)
+	}
+	if res == nil {
+		// No module recognized the proposal.
+		return fmt.Errorf("%w: unknown module: %s", governance.ErrInvalidParameterChanges, proposal.Module)
+	}
+	return nil
+}
+
 func (app *governanceApplication) castVote(
 	ctx *api.Context,
 	state *governanceState.MutableState,
@@ -203,42 +237,38 @@ func (app *governanceApplication) castVote(
 		return err
 	}
 
-	submitterAddr := stakingAPI.NewAddress(ctx.TxSigner())
+	submitterAddr := ctx.CallerAddress()
 	if !submitterAddr.IsValid() {
 		return stakingAPI.ErrForbidden
 	}
 
-	// Query signer entity descriptor.
-	registryState := registryState.NewMutableState(ctx.State())
-	submitterEntity, err := registryState.Entity(ctx, ctx.TxSigner())
-	switch err {
-	case nil:
-	case registryAPI.ErrNoSuchEntity:
-		return governance.ErrNotEligible
-	default:
-		return fmt.Errorf("governance: failed to query entity: %w", err)
-	}
-	schedulerState := schedulerState.NewMutableState(ctx.State())
-	currentValidators, err := schedulerState.CurrentValidators(ctx)
+	// Submitter is eligible if it is an entity with any of its nodes being part of the current
+	// validator committee or, if delegator votes are enabled, if it delegates at least the minimum
+	// delegator vote stake to such entities, in which case its vote overrides the validator's vote
+	// for the delegated stake.
+	stakingState := stakingState.NewMutableState(ctx.State())
+	_, validatorEntitiesEscrow, err := app.validatorsEscrow(
+		ctx,
+		stakingState,
+		registryState.NewMutableState(ctx.State()),
+		schedulerState.NewMutableState(ctx.State()),
+	)
 	if err != nil {
-		return fmt.Errorf("governance: failed to query current validators: %w", err)
+		return fmt.Errorf("governance: failed to compute validators escrow: %w", err)
 	}
-	// Submitter is eligible if any of its nodes is part of the current validator committee.
-	var eligible bool
-	for _, nID := range submitterEntity.Nodes {
-		var node *node.Node
-		node, err = registryState.Node(ctx, nID)
+	isValidator := validatorEntitiesEscrow[submitterAddr] != nil
+	eligible := isValidator
+	if !eligible && params.EnableDelegatorVotes {
+		var delegatedStake *quantity.Quantity
+		delegatedStake, _, err = delegatorVotingStake(ctx, stakingState, validatorEntitiesEscrow, submitterAddr)
 		if err != nil {
-			return fmt.Errorf("governance: failed to query entity node: %w", err)
-		}
-		if _, ok := currentValidators[node.Consensus.ID]; ok {
-			eligible = true
-			break
+			return fmt.Errorf("governance: %w", err)
 		}
+		eligible = !delegatedStake.IsZero() && delegatedStake.Cmp(&params.MinDelegatorVoteStake) >= 0
 	}
 	if !eligible {
 		ctx.Logger().Error("governance: submitter not eligible to vote",
-			"submitter", ctx.TxSigner(),
+			"submitter", submitterAddr,
 		)
 		return governance.ErrNotEligible
 	}
@@ -269,6 +299,32 @@ func (app *governanceApplication) castVote(
 		return governance.ErrVotingIsClosed
 	}
 
+	// Limit the number of delegator votes per proposal as each of them needs to be accounted for
+	// when tallying the votes.
+	if !isValidator {
+		var voted bool
+		if voted, err = state.HasVote(ctx, proposal.ID, submitterAddr); err != nil {
+			return fmt.Errorf("governance: failed to query vote: %w", err)
+		}
+		if !voted {
+			var numVotes uint64
+			if numVotes, err = state.DelegatorVotes(ctx, proposal.ID); err != nil {
+				return fmt.Errorf("governance: failed to query delegator votes: %w", err)
+			}
+			if numVotes >= params.MaxDelegatorVotesPerProposal {
+				ctx.Logger().Error("governance: too many delegator votes",
+					"proposal_id", proposal.ID,
+					"submitter", submitterAddr,
+					"max_delegator_votes_per_proposal", params.MaxDelegatorVotesPerProposal,
+				)
+				return governance.ErrTooManyVotes
+			}
+			if err = state.SetDelegatorVotes(ctx, proposal.ID, numVotes+1); err != nil {
+				return fmt.Errorf("governance: failed to set delegator votes: %w", err)
+			}
+		}
+	}
+
 	// Save the vote.
 	if err := state.SetVote(ctx, proposal.ID, submitterAddr, proposalVote.Vote); err != nil {
 		return fmt.Errorf("governance: failed to save the vote: %w", err)
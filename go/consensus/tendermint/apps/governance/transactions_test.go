@@ -2,15 +2,18 @@ package governance
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
@@ -19,6 +22,30 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
+type testMsgDispatcher struct{}
+
+// Implements MessageDispatcher.
+func (nd *testMsgDispatcher) Subscribe(interface{}, abciAPI.MessageSubscriber) {
+}
+
+// Implements MessageDispatcher.
+func (nd *testMsgDispatcher) Publish(ctx *abciAPI.Context, kind, msg interface{}) (interface{}, error) {
+	switch kind {
+	case governanceApi.MessageValidateParameterChanges, governanceApi.MessageChangeParameters:
+		proposal := msg.(*governance.ChangeParametersProposal)
+		switch proposal.Module {
+		case "test":
+			return struct{}{}, nil
+		case "invalid":
+			return nil, fmt.Errorf("invalid changes")
+		default:
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("unexpected message kind: %v", kind)
+	}
+}
+
 func TestSubmitProposal(t *testing.T) {
 	require := require.New(t)
 	var err error
@@ -48,6 +75,7 @@ func TestSubmitProposal(t *testing.T) {
 	state := governanceState.NewMutableState(ctx.State())
 	app := &governanceApplication{
 		state: appState,
+		md:    &testMsgDispatcher{},
 	}
 
 	minProposalDeposit := quantity.NewFromUint64(100)
@@ -59,6 +87,8 @@ func TestSubmitProposal(t *testing.T) {
 		UpgradeMinEpochDiff:       beacon.EpochTime(100),
 		VotingPeriod:              beacon.EpochTime(50),
 	}
+	changeParamsConsParams := *baseConsParams
+	changeParamsConsParams.EnableChangeParametersProposal = true
 
 	for _, tc := range []struct {
 		msg             string
@@ -225,6 +255,50 @@ func TestSubmitProposal(t *testing.T) {
 			},
 			governance.ErrUpgradeAlreadyPending,
 		},
+		{
+			"should fail change parameters proposal when disabled",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+				Module:  "test",
+				Changes: cbor.Marshal("changes"),
+			}},
+			func() {},
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should fail change parameters proposal for unknown module",
+			&changeParamsConsParams,
+			pk1,
+			&governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+				Module:  "unknown",
+				Changes: cbor.Marshal("changes"),
+			}},
+			func() {},
+			governance.ErrInvalidParameterChanges,
+		},
+		{
+			"should fail change parameters proposal with invalid changes",
+			&changeParamsConsParams,
+			pk1,
+			&governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+				Module:  "invalid",
+				Changes: cbor.Marshal("changes"),
+			}},
+			func() {},
+			governance.ErrInvalidParameterChanges,
+		},
+		{
+			"should work with valid change parameters proposal",
+			&changeParamsConsParams,
+			pk1,
+			&governance.ProposalContent{ChangeParameters: &governance.ChangeParametersProposal{
+				Module:  "test",
+				Changes: cbor.Marshal("changes"),
+			}},
+			func() {},
+			nil,
+		},
 	} {
 		err = state.SetConsensusParameters(ctx, tc.params)
 		require.NoError(err, "setting governance consensus parameters should not error")
@@ -272,6 +346,21 @@ func TestCastVote(t *testing.T) {
 	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
 	reservedPK := signature.NewPublicKey("badbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
 	_ = staking.NewReservedAddress(reservedPK)
+	delegatorPK := signature.NewPublicKey("dddfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	delegatorAddr := staking.NewAddress(delegatorPK)
+	nonValidatorDelegatorPK := signature.NewPublicKey("eeefffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	smallDelegatorPK := signature.NewPublicKey("abcfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	otherDelegatorPK := signature.NewPublicKey("bcdfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	// Setup delegations to a validator and to a non-validator entity.
+	err = stakeState.SetDelegation(ctx, delegatorAddr, addresses[1], &staking.Delegation{Shares: *quantity.NewFromUint64(10)})
+	require.NoError(err, "SetDelegation")
+	err = stakeState.SetDelegation(ctx, staking.NewAddress(nonValidatorDelegatorPK), addresses[0], &staking.Delegation{Shares: *quantity.NewFromUint64(10)})
+	require.NoError(err, "SetDelegation")
+	err = stakeState.SetDelegation(ctx, staking.NewAddress(smallDelegatorPK), addresses[1], &staking.Delegation{Shares: *quantity.NewFromUint64(1)})
+	require.NoError(err, "SetDelegation")
+	err = stakeState.SetDelegation(ctx, staking.NewAddress(otherDelegatorPK), addresses[2], &staking.Delegation{Shares: *quantity.NewFromUint64(10)})
+	require.NoError(err, "SetDelegation")
 
 	// Setup governance state.
 	state := governanceState.NewMutableState(ctx.State())
@@ -285,6 +374,10 @@ func TestCastVote(t *testing.T) {
 		UpgradeCancelMinEpochDiff: beacon.EpochTime(100),
 		UpgradeMinEpochDiff:       beacon.EpochTime(100),
 		VotingPeriod:              beacon.EpochTime(50),
+
+		EnableDelegatorVotes:         true,
+		MinDelegatorVoteStake:        *quantity.NewFromUint64(5),
+		MaxDelegatorVotesPerProposal: 1,
 	}
 	err = state.SetConsensusParameters(ctx, params)
 	require.NoError(err, "setting governance consensus parameters should not error")
@@ -340,6 +433,26 @@ func TestCastVote(t *testing.T) {
 			governance.ErrNotEligible,
 			func() {},
 		},
+		{
+			"should fail if submitter delegates only to a non-validator",
+			nonValidatorDelegatorPK,
+			&governance.ProposalVote{
+				ID:   p1.ID,
+				Vote: governance.VoteYes,
+			},
+			governance.ErrNotEligible,
+			func() {},
+		},
+		{
+			"should fail if submitter delegates less than the minimum delegator vote stake",
+			smallDelegatorPK,
+			&governance.ProposalVote{
+				ID:   p1.ID,
+				Vote: governance.VoteYes,
+			},
+			governance.ErrNotEligible,
+			func() {},
+		},
 		{
 			"should fail for missing proposals",
 			signers[1].Public(),
@@ -418,6 +531,55 @@ func TestCastVote(t *testing.T) {
 				t.Fatal("expected vote not found")
 			},
 		},
+		{
+			"delegator vote should work",
+			delegatorPK,
+			&governance.ProposalVote{
+				ID:   p1.ID,
+				Vote: governance.VoteNo,
+			},
+			nil,
+			func() {
+				// Ensure vote exists.
+				var votes []*governance.VoteEntry
+				votes, err = state.Votes(ctx, p1.ID)
+				require.NoError(err, "Votes()")
+				require.Len(votes, 3, "three votes should exist")
+				for _, v := range votes {
+					if !v.Voter.Equal(delegatorAddr) {
+						continue
+					}
+					require.EqualValues(governance.VoteNo, v.Vote, "vote should match submitted vote")
+					return
+				}
+				t.Fatal("expected vote not found")
+			},
+		},
+		{
+			"delegator vote change should work",
+			delegatorPK,
+			&governance.ProposalVote{
+				ID:   p1.ID,
+				Vote: governance.VoteYes,
+			},
+			nil,
+			func() {
+				var numVotes uint64
+				numVotes, err = state.DelegatorVotes(ctx, p1.ID)
+				require.NoError(err, "DelegatorVotes()")
+				require.EqualValues(1, numVotes, "changing a vote should not count as a new delegator vote")
+			},
+		},
+		{
+			"should fail if there are too many delegator votes",
+			otherDelegatorPK,
+			&governance.ProposalVote{
+				ID:   p1.ID,
+				Vote: governance.VoteYes,
+			},
+			governance.ErrTooManyVotes,
+			func() {},
+		},
 	} {
 		txCtx := appState.NewContext(abciAPI.ContextDeliverTx, now)
 		defer txCtx.Close()
@@ -428,4 +590,15 @@ func TestCastVote(t *testing.T) {
 
 		tc.check()
 	}
+
+	// Delegators should not be able to vote when delegator votes are disabled.
+	params.EnableDelegatorVotes = false
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "setting governance consensus parameters should not error")
+
+	txCtx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer txCtx.Close()
+	txCtx.SetTxSigner(delegatorPK)
+	err = app.castVote(txCtx, state, &governance.ProposalVote{ID: p1.ID, Vote: governance.VoteNo})
+	require.Equal(governance.ErrNotEligible, err, "delegator vote should fail when disabled")
 }
@@ -0,0 +1,63 @@
+package roothash
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// changeParameters validates and optionally applies consensus parameter changes.
+func (app *rootHashApplication) changeParameters(ctx *tmapi.Context, msg interface{}, apply bool) (interface{}, error) {
+	proposal, ok := msg.(*governance.ChangeParametersProposal)
+	if !ok {
+		return nil, fmt.Errorf("roothash: failed to type assert change parameters proposal")
+	}
+
+	if proposal.Module != roothash.ModuleName {
+		return nil, nil
+	}
+
+	// Validate changes against current parameters.
+	var changes roothash.ConsensusParameterChanges
+	if err := cbor.Unmarshal(proposal.Changes, &changes); err != nil {
+		return nil, fmt.Errorf("roothash: failed to unmarshal consensus parameter changes: %w", err)
+	}
+	if err := changes.SanityCheck(); err != nil {
+		return nil, fmt.Errorf("roothash: failed to validate consensus parameter changes: %w", err)
+	}
+	state := roothashState.NewMutableState(ctx.State())
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("roothash: failed to load consensus parameters: %w", err)
+	}
+	if err = changes.Apply(params); err != nil {
+		return nil, fmt.Errorf("roothash: failed to apply consensus parameter changes: %w", err)
+	}
+
+	// Make sure that all registered runtimes remain valid under the new parameters.
+	regState := registryState.NewMutableState(ctx.State())
+	runtimes, err := regState.AllRuntimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("roothash: failed to load runtimes: %w", err)
+	}
+	for _, rt := range runtimes {
+		if err = roothash.VerifyRuntimeParameters(ctx.Logger(), rt, params); err != nil {
+			return nil, fmt.Errorf("roothash: runtime %s invalid under new consensus parameters: %w", rt.ID, err)
+		}
+	}
+
+	// Apply changes.
+	if apply {
+		if err = state.SetConsensusParameters(ctx, params); err != nil {
+			return nil, fmt.Errorf("roothash: failed to update consensus parameters: %w", err)
+		}
+	}
+
+	// Non-nil response signals that changes are valid and were successfully applied (if required).
+	return struct{}{}, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	registryApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/api"
@@ -69,6 +70,8 @@ func (app *rootHashApplication) OnRegister(state tmapi.ApplicationState, md tmap
 	md.Subscribe(registryApi.MessageRuntimeResumed, app)
 	md.Subscribe(roothashApi.RuntimeMessageNoop, app)
 	md.Subscribe(schedulerApi.MessageBeforeSchedule, app)
+	md.Subscribe(governanceApi.MessageValidateParameterChanges, app)
+	md.Subscribe(governanceApi.MessageChangeParameters, app)
 }
 
 func (app *rootHashApplication) OnCleanup() {
@@ -357,6 +360,13 @@ func (app *rootHashApplication) ExecuteMessage(ctx *tmapi.Context, kind, msg int
 			}
 		}
 		return nil, nil
+	case governanceApi.MessageValidateParameterChanges:
+		// A change parameters proposal is about to be submitted. Validate changes.
+		return app.changeParameters(ctx, msg, false)
+	case governanceApi.MessageChangeParameters:
+		// A change parameters proposal has just been accepted and closed. Validate and apply
+		// changes.
+		return app.changeParameters(ctx, msg, true)
 	default:
 		return nil, roothash.ErrInvalidArgument
 	}
@@ -8,7 +8,6 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
-	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 var _ api.TransactionAuthHandler = (*stakingApplication)(nil)
@@ -29,7 +28,7 @@ func (app *stakingApplication) GetSignerNonce(ctx context.Context, req *consensu
 
 // Implements api.TransactionAuthHandler.
 func (app *stakingApplication) AuthenticateTx(ctx *api.Context, tx *transaction.Transaction) error {
-	return stakingState.AuthenticateAndPayFees(ctx, ctx.TxSigner(), tx.Nonce, tx.Fee)
+	return stakingState.AuthenticateAndPayFees(ctx, ctx.CallerAddress(), tx.Nonce, tx.Fee)
 }
 
 // Implements api.TransactionAuthHandler.
@@ -50,7 +49,7 @@ func (app *stakingApplication) PostExecuteTx(ctx *api.Context, tx *transaction.T
 		fee = &transaction.Fee{}
 	}
 
-	addr := staking.NewAddress(ctx.TxSigner())
+	addr := ctx.CallerAddress()
 
 	account, err := state.Account(ctx, addr)
 	if err != nil {
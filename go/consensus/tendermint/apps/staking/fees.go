@@ -2,14 +2,157 @@ package staking
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
+// collectBaseFees moves the base portion of the fees collected in the block (the base gas price
+// times the gas paid for) to the common pool.
+//
+// In case of errors the state may be inconsistent.
+func (app *stakingApplication) collectBaseFees(
+	ctx *abciAPI.Context,
+	stakeState *stakingState.MutableState,
+	totalFees *quantity.Quantity,
+	blockGas transaction.Gas,
+) error {
+	baseGasPrice, err := stakeState.BaseGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query base gas price: %w", err)
+	}
+	if baseGasPrice.IsZero() || blockGas == 0 || totalFees.IsZero() {
+		return nil
+	}
+
+	var gasQ quantity.Quantity
+	if err = gasQ.FromUint64(uint64(blockGas)); err != nil {
+		return fmt.Errorf("import blockGas %d: %w", blockGas, err)
+	}
+	baseFees := baseGasPrice.Clone()
+	if err = baseFees.Mul(&gasQ); err != nil {
+		return fmt.Errorf("multiply baseFees: %w", err)
+	}
+	// All transactions paid at least the base gas price, but be defensive in case the base gas
+	// price changed during the block.
+	if baseFees.Cmp(totalFees) > 0 {
+		baseFees = totalFees.Clone()
+	}
+
+	ctx.Logger().Debug("collecting base fees",
+		"base_gas_price", baseGasPrice,
+		"block_gas", blockGas,
+		"amount", baseFees,
+	)
+
+	commonPool, err := stakeState.CommonPool(ctx)
+	if err != nil {
+		return fmt.Errorf("CommonPool: %w", err)
+	}
+	if err = quantity.Move(commonPool, totalFees, baseFees); err != nil {
+		return fmt.Errorf("move baseFees: %w", err)
+	}
+	if err = stakeState.SetCommonPool(ctx, commonPool); err != nil {
+		return fmt.Errorf("failed to set common pool: %w", err)
+	}
+
+	// Emit transfer event.
+	ctx.EmitEvent(abciAPI.NewEventBuilder(app.Name()).TypedAttribute(&staking.TransferEvent{
+		From:   staking.FeeAccumulatorAddress,
+		To:     staking.CommonPoolAddress,
+		Amount: *baseFees,
+	}))
+
+	return nil
+}
+
+// updateBaseGasPrice adjusts the base gas price based on the amount of gas used in the block.
+//
+// In case the block used more gas than the target, the base gas price increases and in case it
+// used less, the base gas price decreases. The change is proportional to the difference from the
+// target and is bounded by the base gas price divided by the change denominator.
+func (app *stakingApplication) updateBaseGasPrice(
+	ctx *abciAPI.Context,
+	stakeState *stakingState.MutableState,
+	blockGas transaction.Gas,
+) error {
+	params, err := stakeState.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("ConsensusParameters: %w", err)
+	}
+	if params.FeeMarket == nil {
+		return nil
+	}
+	baseGasPrice, err := stakeState.BaseGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query base gas price: %w", err)
+	}
+
+	newPrice := computeBaseGasPrice(baseGasPrice, blockGas, params.FeeMarket)
+	if newPrice.Cmp(baseGasPrice) == 0 {
+		return nil
+	}
+
+	ctx.Logger().Debug("updating base gas price",
+		"block_gas", blockGas,
+		"old_base_gas_price", baseGasPrice,
+		"new_base_gas_price", newPrice,
+	)
+	if err = stakeState.SetBaseGasPrice(ctx, newPrice); err != nil {
+		return fmt.Errorf("failed to set base gas price: %w", err)
+	}
+	return nil
+}
+
+// computeBaseGasPrice computes the base gas price for the next block.
+func computeBaseGasPrice(
+	baseGasPrice *quantity.Quantity,
+	blockGas transaction.Gas,
+	params *staking.FeeMarketParameters,
+) *quantity.Quantity {
+	target := new(big.Int).SetUint64(uint64(params.TargetBlockGas))
+	used := new(big.Int).SetUint64(uint64(blockGas))
+	// Bound the used gas to twice the target so that the base gas price can increase by at most
+	// price / denominator in a single block.
+	if maxUsed := new(big.Int).Lsh(target, 1); used.Cmp(maxUsed) > 0 {
+		used = maxUsed
+	}
+
+	// delta = price * |used - target| / target / denominator
+	delta := new(big.Int).Sub(used, target)
+	delta.Abs(delta)
+	delta.Mul(delta, baseGasPrice.ToBigInt())
+	delta.Quo(delta, target)
+	delta.Quo(delta, new(big.Int).SetUint64(params.BaseGasPriceChangeDenominator))
+
+	newPrice := new(big.Int).Set(baseGasPrice.ToBigInt())
+	switch used.Cmp(target) {
+	case 1:
+		// Always increase by at least one so that the price can grow from small values.
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		newPrice.Add(newPrice, delta)
+	case -1:
+		newPrice.Sub(newPrice, delta)
+	}
+	if newPrice.Cmp(params.MinGasPrice.ToBigInt()) < 0 {
+		newPrice.Set(params.MinGasPrice.ToBigInt())
+	}
+
+	var q quantity.Quantity
+	if err := q.FromBigInt(newPrice); err != nil {
+		// Should never happen as the price is never negative.
+		panic(err)
+	}
+	return &q
+}
+
 // disburseFeesP disburses fees to the proposer and persists the voters' and next proposer's shares of the fees.
 //
 // In case of errors the state may be inconsistent.
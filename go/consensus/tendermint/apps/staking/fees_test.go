@@ -0,0 +1,124 @@
+package staking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestComputeBaseGasPrice(t *testing.T) {
+	params := &staking.FeeMarketParameters{
+		MinGasPrice:                   *quantity.NewFromUint64(10),
+		TargetBlockGas:                1000,
+		BaseGasPriceChangeDenominator: 8,
+	}
+
+	for _, tt := range []struct {
+		msg      string
+		price    uint64
+		blockGas transaction.Gas
+		expected uint64
+	}{
+		{"on target", 800, 1000, 800},
+		{"full block", 800, 2000, 900},
+		{"over full block", 800, 100_000, 900},
+		{"half full block", 800, 1500, 850},
+		{"empty block", 800, 0, 700},
+		{"minimum increase", 10, 1001, 11},
+		{"clamped to minimum", 10, 0, 10},
+	} {
+		price := computeBaseGasPrice(quantity.NewFromUint64(tt.price), tt.blockGas, params)
+		require.Zero(t, price.Cmp(quantity.NewFromUint64(tt.expected)), "%s: expected %d got %s", tt.msg, tt.expected, price)
+	}
+}
+
+func TestFeeMarket(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	params := &staking.ConsensusParameters{
+		FeeSplitWeightPropose: *quantity.NewFromUint64(1),
+	}
+	err := stakeState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	// Without a fee market there should be no base gas price.
+	price, err := stakeState.BaseGasPrice(ctx)
+	require.NoError(err, "BaseGasPrice")
+	require.True(price.IsZero(), "base gas price should be zero without a fee market")
+
+	params.FeeMarket = &staking.FeeMarketParameters{
+		MinGasPrice:                   *quantity.NewFromUint64(10),
+		TargetBlockGas:                1000,
+		BaseGasPriceChangeDenominator: 8,
+	}
+	err = stakeState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	price, err = stakeState.BaseGasPrice(ctx)
+	require.NoError(err, "BaseGasPrice")
+	require.Zero(price.Cmp(quantity.NewFromUint64(10)), "base gas price should initially be the minimum gas price")
+
+	pk := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr := staking.NewAddress(pk)
+	err = stakeState.SetAccount(ctx, addr, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(1_000_000),
+		},
+	})
+	require.NoError(err, "SetAccount")
+
+	txCtx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer txCtx.Close()
+
+	// Transactions paying less than the base gas price should be rejected.
+	err = stakingState.AuthenticateAndPayFees(txCtx, addr, 0, &transaction.Fee{
+		Amount: *quantity.NewFromUint64(9_000),
+		Gas:    1000,
+	})
+	require.ErrorIs(err, transaction.ErrGasPriceTooLow, "transaction below base gas price should be rejected")
+	err = stakingState.AuthenticateAndPayFees(txCtx, addr, 0, nil)
+	require.ErrorIs(err, transaction.ErrGasPriceTooLow, "transaction without fee should be rejected")
+
+	// Transactions paying at least the base gas price should be accepted.
+	err = stakingState.AuthenticateAndPayFees(txCtx, addr, 0, &transaction.Fee{
+		Amount: *quantity.NewFromUint64(30_000),
+		Gas:    2000,
+	})
+	require.NoError(err, "transaction at or above base gas price should be accepted")
+	require.EqualValues(2000, stakingState.BlockGas(txCtx), "BlockGas")
+
+	// The base portion of the fees should go to the common pool.
+	fees := stakingState.BlockFees(txCtx)
+	require.Zero(fees.Cmp(quantity.NewFromUint64(30_000)), "BlockFees")
+	err = app.collectBaseFees(txCtx, stakeState, &fees, stakingState.BlockGas(txCtx))
+	require.NoError(err, "collectBaseFees")
+	require.Zero(fees.Cmp(quantity.NewFromUint64(10_000)), "remaining fees should exclude the base portion")
+	commonPool, err := stakeState.CommonPool(ctx)
+	require.NoError(err, "CommonPool")
+	require.Zero(commonPool.Cmp(quantity.NewFromUint64(20_000)), "base portion should be moved to the common pool")
+
+	// The base gas price should increase after a full block.
+	err = app.updateBaseGasPrice(txCtx, stakeState, stakingState.BlockGas(txCtx))
+	require.NoError(err, "updateBaseGasPrice")
+	price, err = stakeState.BaseGasPrice(ctx)
+	require.NoError(err, "BaseGasPrice")
+	require.Zero(price.Cmp(quantity.NewFromUint64(11)), "base gas price should increase after a full block")
+}
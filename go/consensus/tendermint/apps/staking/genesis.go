@@ -114,6 +114,13 @@ func (app *stakingApplication) initLedger(
 			)
 			return fmt.Errorf("tendermint/staking: invalid genesis debonding escrow balance for account %s", addr)
 		}
+		if !acct.Vesting.Balance.IsValid() {
+			ctx.Logger().Error("InitChain: invalid genesis vesting balance",
+				"address", addr,
+				"vesting_balance", acct.Vesting.Balance,
+			)
+			return fmt.Errorf("tendermint/staking: invalid genesis vesting balance for account %s", addr)
+		}
 
 		// Make sure that the stake accumulator is empty as otherwise it could be inconsistent with
 		// what is registered in the genesis block.
@@ -142,6 +149,12 @@ func (app *stakingApplication) initLedger(
 			)
 			return fmt.Errorf("tendermint/staking: failed to add debonding escrow balance: %w", err)
 		}
+		if err := totalSupply.Add(&acct.Vesting.Balance); err != nil {
+			ctx.Logger().Error("InitChain: failed to add vesting balance",
+				"err", err,
+			)
+			return fmt.Errorf("tendermint/staking: failed to add vesting balance: %w", err)
+		}
 
 		if err := state.SetAccount(ctx, addr, acct); err != nil {
 			return fmt.Errorf("tendermint/staking: failed to set account %s: %w", addr, err)
@@ -15,6 +15,7 @@ type Query interface {
 	TotalSupply(context.Context) (*quantity.Quantity, error)
 	CommonPool(context.Context) (*quantity.Quantity, error)
 	LastBlockFees(context.Context) (*quantity.Quantity, error)
+	BaseGasPrice(context.Context) (*quantity.Quantity, error)
 	GovernanceDeposits(context.Context) (*quantity.Quantity, error)
 	Threshold(context.Context, staking.ThresholdKind) (*quantity.Quantity, error)
 	DebondingInterval(context.Context) (beacon.EpochTime, error)
@@ -60,6 +61,10 @@ func (sq *stakingQuerier) LastBlockFees(ctx context.Context) (*quantity.Quantity
 	return sq.state.LastBlockFees(ctx)
 }
 
+func (sq *stakingQuerier) BaseGasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	return sq.state.BaseGasPrice(ctx)
+}
+
 func (sq *stakingQuerier) GovernanceDeposits(ctx context.Context) (*quantity.Quantity, error) {
 	return sq.state.GovernanceDeposits(ctx)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
@@ -49,6 +50,8 @@ func (app *stakingApplication) OnRegister(state api.ApplicationState, md api.Mes
 
 	// Subscribe to messages emitted by other apps.
 	md.Subscribe(roothashApi.RuntimeMessageStaking, app)
+	md.Subscribe(governanceApi.MessageValidateParameterChanges, app)
+	md.Subscribe(governanceApi.MessageChangeParameters, app)
 }
 
 func (app *stakingApplication) OnCleanup() {
@@ -133,6 +136,13 @@ func (app *stakingApplication) ExecuteMessage(ctx *api.Context, kind, msg interf
 		default:
 			return nil, staking.ErrInvalidArgument
 		}
+	case governanceApi.MessageValidateParameterChanges:
+		// A change parameters proposal is about to be submitted. Validate changes.
+		return app.changeParameters(ctx, msg, false)
+	case governanceApi.MessageChangeParameters:
+		// A change parameters proposal has just been accepted and closed. Validate and apply
+		// changes.
+		return app.changeParameters(ctx, msg, true)
 	default:
 		return nil, staking.ErrInvalidArgument
 	}
@@ -195,16 +205,31 @@ func (app *stakingApplication) ExecuteTx(ctx *api.Context, tx *transaction.Trans
 
 		_, err := app.withdraw(ctx, state, &withdraw)
 		return err
+	case staking.MethodScheduleTransfer:
+		var xfer staking.ScheduleTransfer
+		if err := cbor.Unmarshal(tx.Body, &xfer); err != nil {
+			return err
+		}
+
+		return app.scheduleTransfer(ctx, state, &xfer)
 	default:
 		return staking.ErrInvalidArgument
 	}
 }
 
 func (app *stakingApplication) EndBlock(ctx *api.Context, request types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	state := stakingState.NewMutableState(ctx.State())
 	fees := stakingState.BlockFees(ctx)
-	if err := app.disburseFeesP(ctx, stakingState.NewMutableState(ctx.State()), stakingState.BlockProposer(ctx), &fees); err != nil {
+	blockGas := stakingState.BlockGas(ctx)
+	if err := app.collectBaseFees(ctx, state, &fees, blockGas); err != nil {
+		return types.ResponseEndBlock{}, fmt.Errorf("collect base fees: %w", err)
+	}
+	if err := app.disburseFeesP(ctx, state, stakingState.BlockProposer(ctx), &fees); err != nil {
 		return types.ResponseEndBlock{}, fmt.Errorf("disburse fees proposer: %w", err)
 	}
+	if err := app.updateBaseGasPrice(ctx, state, blockGas); err != nil {
+		return types.ResponseEndBlock{}, fmt.Errorf("update base gas price: %w", err)
+	}
 
 	if changed, epoch := app.state.EpochChanged(ctx); changed {
 		return types.ResponseEndBlock{}, app.onEpochChange(ctx, epoch)
@@ -293,6 +318,11 @@ func (app *stakingApplication) onEpochChange(ctx *api.Context, epoch beacon.Epoc
 		}))
 	}
 
+	// Release vested stake.
+	if err = app.releaseVestings(ctx, state, epoch); err != nil {
+		return fmt.Errorf("staking/tendermint: failed to release vested stake: %w", err)
+	}
+
 	// Add signing rewards.
 	if err := app.rewardEpochSigning(ctx, epoch); err != nil {
 		ctx.Logger().Error("failed to add signing rewards",
@@ -304,6 +334,58 @@ func (app *stakingApplication) onEpochChange(ctx *api.Context, epoch beacon.Epoc
 	return nil
 }
 
+func (app *stakingApplication) releaseVestings(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	epoch beacon.EpochTime,
+) error {
+	addresses, err := state.VestingAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query vesting accounts: %w", err)
+	}
+	for _, addr := range addresses {
+		acct, err := state.Account(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("failed to query vesting account: %w", err)
+		}
+
+		released, err := acct.Vesting.Release(&acct.General.Balance, epoch)
+		if err != nil {
+			ctx.Logger().Error("failed to release vested stake",
+				"err", err,
+				"addr", addr,
+			)
+			return err
+		}
+		if len(released) == 0 {
+			continue
+		}
+
+		if err = state.SetAccount(ctx, addr, acct); err != nil {
+			return fmt.Errorf("failed to set vesting (%s) account: %w", addr, err)
+		}
+
+		for _, v := range released {
+			ctx.Logger().Debug("released vested stake",
+				"from", v.From,
+				"to", addr,
+				"base_units", v.Released,
+			)
+
+			ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.VestingEvent{
+				From:     v.From,
+				To:       addr,
+				Amount:   v.Released,
+				Start:    v.Start,
+				End:      v.End,
+				Released: true,
+			}))
+		}
+	}
+
+	return nil
+}
+
 // New constructs a new staking application instance.
 func New() api.Application {
 	return &stakingApplication{}
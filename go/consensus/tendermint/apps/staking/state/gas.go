@@ -22,6 +22,7 @@ func (fak feeAccumulatorKey) NewDefault() interface{} {
 // in a block.
 type feeAccumulator struct {
 	balance quantity.Quantity
+	gas     transaction.Gas
 }
 
 // AuthenticateAndPayFees authenticates the message signer and makes sure that
@@ -31,7 +32,7 @@ type feeAccumulator struct {
 // persisted at the end of the block.
 func AuthenticateAndPayFees(
 	ctx *abciAPI.Context,
-	signer signature.PublicKey,
+	addr staking.Address,
 	nonce uint64,
 	fee *transaction.Fee,
 ) error {
@@ -45,8 +46,6 @@ func AuthenticateAndPayFees(
 		return nil
 	}
 
-	// Convert signer's public key to account address.
-	addr := staking.NewAddress(signer)
 	if addr.IsReserved() {
 		return fmt.Errorf("using reserved account address %s is prohibited", addr)
 	}
@@ -79,6 +78,21 @@ func AuthenticateAndPayFees(
 		return fmt.Errorf("adding MinTransactBalance to fee: %w", err)
 	}
 
+	// Check fee against the consensus-enforced base gas price. Unlike the local minimum gas price
+	// this also applies to own transactions and is checked in both CheckTx and DeliverTx.
+	baseGasPrice, err := state.BaseGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch base gas price: %w", err)
+	}
+	if !baseGasPrice.IsZero() && fee.GasPrice().Cmp(baseGasPrice) < 0 {
+		logger.Debug("gas price below base gas price",
+			"account_addr", addr,
+			"gas_price", fee.GasPrice(),
+			"base_gas_price", baseGasPrice,
+		)
+		return transaction.ErrGasPriceTooLow
+	}
+
 	// Check against minimum balance plus fee.
 	if account.General.Balance.Cmp(needed) < 0 {
 		logger.Error("account balance too low",
@@ -112,6 +126,11 @@ func AuthenticateAndPayFees(
 	if err = quantity.Move(&feeAcc.balance, &account.General.Balance, &fee.Amount); err != nil {
 		return fmt.Errorf("staking: failed to pay fees: %w", err)
 	}
+	if feeAcc.gas+fee.Gas < feeAcc.gas {
+		feeAcc.gas = transaction.Gas(math.MaxUint64)
+	} else {
+		feeAcc.gas += fee.Gas
+	}
 
 	account.General.Nonce++
 	if err := state.SetAccount(ctx, addr, account); err != nil {
@@ -142,6 +161,11 @@ func BlockFees(ctx *abciAPI.Context) quantity.Quantity {
 	return ctx.BlockContext().Get(feeAccumulatorKey{}).(*feeAccumulator).balance
 }
 
+// BlockGas returns the total amount of gas paid for by transactions in the current block.
+func BlockGas(ctx *abciAPI.Context) transaction.Gas {
+	return ctx.BlockContext().Get(feeAccumulatorKey{}).(*feeAccumulator).gas
+}
+
 // proposerKey is the block context key.
 type proposerKey struct{}
 
@@ -66,6 +66,15 @@ var (
 	//
 	// Value is a CBOR-serialized quantity.
 	governanceDepositsKeyFmt = keyformat.New(0x59)
+	// vestingAccountKeyFmt is the key format used for accounts with pending
+	// vesting schedules (account address).
+	//
+	// Value is empty.
+	vestingAccountKeyFmt = keyformat.New(0x5a, &staking.Address{})
+	// baseGasPriceKeyFmt is the key format used for the fee market base gas price.
+	//
+	// Value is a CBOR-serialized quantity.
+	baseGasPriceKeyFmt = keyformat.New(0x5b)
 
 	logger = logging.GetLogger("tendermint/staking")
 )
@@ -430,6 +439,27 @@ func (s *ImmutableState) DebondingDelegationsTo(
 	return delegations, nil
 }
 
+// VestingAccounts returns the addresses of accounts with pending vesting
+// schedules.
+func (s *ImmutableState) VestingAccounts(ctx context.Context) ([]staking.Address, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var addresses []staking.Address
+	for it.Seek(vestingAccountKeyFmt.Encode()); it.Valid(); it.Next() {
+		var addr staking.Address
+		if !vestingAccountKeyFmt.Decode(it.Key(), &addr) {
+			break
+		}
+
+		addresses = append(addresses, addr)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return addresses, nil
+}
+
 type DebondingQueueEntry struct {
 	Epoch         beacon.EpochTime
 	DelegatorAddr staking.Address
@@ -484,6 +514,29 @@ func (s *ImmutableState) LastBlockFees(ctx context.Context) (*quantity.Quantity,
 	return s.loadStoredBalance(ctx, lastBlockFeesKeyFmt)
 }
 
+// BaseGasPrice returns the current fee market base gas price.
+//
+// In case the fee market is disabled, zero is returned. The base gas price is never lower than
+// the configured minimum gas price.
+func (s *ImmutableState) BaseGasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	params, err := s.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.FeeMarket == nil {
+		return quantity.NewQuantity(), nil
+	}
+
+	price, err := s.loadStoredBalance(ctx, baseGasPriceKeyFmt)
+	if err != nil {
+		return nil, err
+	}
+	if price.Cmp(&params.FeeMarket.MinGasPrice) < 0 {
+		return params.FeeMarket.MinGasPrice.Clone(), nil
+	}
+	return price, nil
+}
+
 // GovernanceDeposits returns the governance deposits balance.
 func (s *ImmutableState) GovernanceDeposits(ctx context.Context) (*quantity.Quantity, error) {
 	return s.loadStoredBalance(ctx, governanceDepositsKeyFmt)
@@ -569,7 +622,19 @@ type MutableState struct {
 }
 
 func (s *MutableState) SetAccount(ctx context.Context, addr staking.Address, account *staking.Account) error {
-	err := s.ms.Insert(ctx, accountKeyFmt.Encode(&addr), cbor.Marshal(account))
+	if err := s.ms.Insert(ctx, accountKeyFmt.Encode(&addr), cbor.Marshal(account)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+
+	// Keep track of accounts with pending vesting schedules so they can be
+	// processed on epoch transitions.
+	var err error
+	switch len(account.Vesting.Schedules) {
+	case 0:
+		err = s.ms.Remove(ctx, vestingAccountKeyFmt.Encode(&addr))
+	default:
+		err = s.ms.Insert(ctx, vestingAccountKeyFmt.Encode(&addr), []byte{})
+	}
 	return abciAPI.UnavailableStateError(err)
 }
 
@@ -678,6 +743,11 @@ func (s *MutableState) SetLastBlockFees(ctx context.Context, q *quantity.Quantit
 	return abciAPI.UnavailableStateError(err)
 }
 
+func (s *MutableState) SetBaseGasPrice(ctx context.Context, q *quantity.Quantity) error {
+	err := s.ms.Insert(ctx, baseGasPriceKeyFmt.Encode(), cbor.Marshal(q))
+	return abciAPI.UnavailableStateError(err)
+}
+
 func (s *MutableState) SetEpochSigning(ctx context.Context, es *EpochSigning) error {
 	err := s.ms.Insert(ctx, epochSigningKeyFmt.Encode(), cbor.Marshal(es))
 	return abciAPI.UnavailableStateError(err)
@@ -749,3 +749,130 @@ func (app *stakingApplication) withdraw(
 		AmountChange: withdraw.Amount,
 	}, nil
 }
+
+func (app *stakingApplication) scheduleTransfer(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	xfer *staking.ScheduleTransfer,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpScheduleTransfer, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Return early for simulation as we only need gas accounting.
+	if ctx.IsSimulation() {
+		return nil
+	}
+
+	// Scheduled transfers are disabled in case max vestings is zero.
+	if params.MaxVestings == 0 {
+		return staking.ErrForbidden
+	}
+
+	// Check if sender provided at least a minimum amount.
+	if xfer.Amount.Cmp(&params.MinTransferAmount) < 0 {
+		return staking.ErrUnderMinTransferAmount
+	}
+	if xfer.Amount.IsZero() {
+		return staking.ErrInvalidArgument
+	}
+
+	fromAddr := ctx.CallerAddress()
+	if fromAddr.IsReserved() || xfer.To.IsReserved() || !isTransferPermitted(params, fromAddr) {
+		return staking.ErrForbidden
+	}
+
+	// The schedule must end in the future as otherwise a regular transfer should be used.
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+	if xfer.End < xfer.Start || xfer.End <= epoch {
+		return staking.ErrInvalidArgument
+	}
+
+	from, err := state.Account(ctx, fromAddr)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	// NOTE: Could be the same account, so make sure to not have two duplicate
+	//       copies of it and overwrite it later.
+	var to *staking.Account
+	if fromAddr.Equal(xfer.To) {
+		to = from
+	} else {
+		to, err = state.Account(ctx, xfer.To)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+	}
+
+	// If adding the vesting schedule would go past the maximum number of vestings, fail.
+	if uint32(len(to.Vesting.Schedules)) >= params.MaxVestings {
+		return staking.ErrTooManyVestings
+	}
+
+	if err = quantity.Move(&to.Vesting.Balance, &from.General.Balance, &xfer.Amount); err != nil {
+		ctx.Logger().Error("ScheduleTransfer: failed to move balance",
+			"err", err,
+			"from", fromAddr,
+			"to", xfer.To,
+			"amount", xfer.Amount,
+		)
+		return staking.ErrInsufficientBalance
+	}
+	to.Vesting.Schedules = append(to.Vesting.Schedules, staking.Vesting{
+		From:   fromAddr,
+		Amount: xfer.Amount,
+		Start:  xfer.Start,
+		End:    xfer.End,
+	})
+
+	// Check against minimum balance.
+	if from.General.Balance.Cmp(&params.MinTransactBalance) < 0 {
+		ctx.Logger().Error("after scheduled transfer source account balance too low",
+			"account_addr", fromAddr,
+			"account_balance", from.General.Balance,
+			"min_transact_balance", params.MinTransactBalance,
+		)
+		return errors.WithContext(staking.ErrBalanceTooLow, "source account")
+	}
+
+	// Commit accounts.
+	if err = state.SetAccount(ctx, fromAddr, from); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+	if !fromAddr.Equal(xfer.To) {
+		if err = state.SetAccount(ctx, xfer.To, to); err != nil {
+			return fmt.Errorf("failed to set account: %w", err)
+		}
+	}
+
+	ctx.Logger().Debug("ScheduleTransfer: scheduled transfer",
+		"from", fromAddr,
+		"to", xfer.To,
+		"amount", xfer.Amount,
+		"start", xfer.Start,
+		"end", xfer.End,
+	)
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.VestingEvent{
+		From:   fromAddr,
+		To:     xfer.To,
+		Amount: xfer.Amount,
+		Start:  xfer.Start,
+		End:    xfer.End,
+	}))
+
+	return nil
+}
@@ -75,6 +75,7 @@ func TestReservedAddresses(t *testing.T) {
 
 	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
 		MaxAllowances: 1,
+		MaxVestings:   1,
 	})
 	require.NoError(err, "setting staking consensus parameters should not error")
 
@@ -120,6 +121,10 @@ func TestReservedAddresses(t *testing.T) {
 	withdrawResult, err := app.withdraw(txCtx, stakeState, &staking.Withdraw{})
 	require.EqualError(err, "staking: forbidden by policy", "withdraw for reserved address should error")
 	require.Nil(withdrawResult, "withdraw result should be nil on error")
+
+	// NOTE: We need to specify the amount since that is checked before the check for reserved address.
+	err = app.scheduleTransfer(txCtx, stakeState, &staking.ScheduleTransfer{Amount: *q.Clone()})
+	require.EqualError(err, "staking: forbidden by policy", "schedule transfer for reserved address should error")
 }
 
 func TestAllow(t *testing.T) {
@@ -1044,3 +1049,157 @@ func TestBurn(t *testing.T) {
 		require.ErrorIs(err, tc.err, tc.msg)
 	}
 }
+
+func TestScheduleTransfer(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	err = stakeState.SetAccount(ctx, addr1, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(1_000),
+		},
+	})
+	require.NoError(err, "SetAccount")
+
+	for _, tc := range []struct {
+		msg    string
+		params *staking.ConsensusParameters
+		xfer   *staking.ScheduleTransfer
+		err    error
+	}{
+		{
+			"should fail with disabled vestings",
+			&staking.ConsensusParameters{},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 10, End: 20},
+			staking.ErrForbidden,
+		},
+		{
+			"should fail with disabled transfers",
+			&staking.ConsensusParameters{MaxVestings: 1, DisableTransfers: true},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 10, End: 20},
+			staking.ErrForbidden,
+		},
+		{
+			"should fail with amount under min transfer amount",
+			&staking.ConsensusParameters{MaxVestings: 1, MinTransferAmount: *quantity.NewFromUint64(200)},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 10, End: 20},
+			staking.ErrUnderMinTransferAmount,
+		},
+		{
+			"should fail with zero amount",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Start: 10, End: 20},
+			staking.ErrInvalidArgument,
+		},
+		{
+			"should fail with end before start",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 20, End: 15},
+			staking.ErrInvalidArgument,
+		},
+		{
+			"should fail with end not in the future",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 5, End: 10},
+			staking.ErrInvalidArgument,
+		},
+		{
+			"should fail with insufficient balance",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(10_000), Start: 10, End: 20},
+			staking.ErrInsufficientBalance,
+		},
+		{
+			"should fail with balance under min transact balance",
+			&staking.ConsensusParameters{MaxVestings: 1, MinTransactBalance: *quantity.NewFromUint64(950)},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 10, End: 20},
+			staking.ErrBalanceTooLow,
+		},
+		{
+			"should succeed with linear vesting",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 10, End: 20},
+			nil,
+		},
+		{
+			"should fail with too many vestings",
+			&staking.ConsensusParameters{MaxVestings: 1},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 15, End: 15},
+			staking.ErrTooManyVestings,
+		},
+		{
+			"should succeed with cliff vesting",
+			&staking.ConsensusParameters{MaxVestings: 2},
+			&staking.ScheduleTransfer{To: addr2, Amount: *quantity.NewFromUint64(100), Start: 15, End: 15},
+			nil,
+		},
+	} {
+		err = stakeState.SetConsensusParameters(ctx, tc.params)
+		require.NoError(err, "setting staking consensus parameters should not error")
+
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(pk1)
+
+		err = app.scheduleTransfer(txCtx, stakeState, tc.xfer)
+		require.ErrorIs(err, tc.err, tc.msg)
+	}
+
+	acct1, err := stakeState.Account(ctx, addr1)
+	require.NoError(err, "Account")
+	require.Equal(*quantity.NewFromUint64(800), acct1.General.Balance, "source general balance should be reduced")
+
+	acct2, err := stakeState.Account(ctx, addr2)
+	require.NoError(err, "Account")
+	require.True(acct2.General.Balance.IsZero(), "destination general balance should be zero")
+	require.Equal(*quantity.NewFromUint64(200), acct2.Vesting.Balance, "destination vesting balance should be correct")
+	require.Len(acct2.Vesting.Schedules, 2, "destination should have two vesting schedules")
+
+	vestingAccounts, err := stakeState.VestingAccounts(ctx)
+	require.NoError(err, "VestingAccounts")
+	require.EqualValues([]staking.Address{addr2}, vestingAccounts, "destination should be tracked as a vesting account")
+
+	for _, step := range []struct {
+		epoch     beacon.EpochTime
+		general   uint64
+		vesting   uint64
+		schedules int
+	}{
+		{10, 0, 200, 2},
+		{12, 20, 180, 2},
+		{15, 150, 50, 1},
+		{19, 190, 10, 1},
+		{25, 200, 0, 0},
+	} {
+		err = app.releaseVestings(ctx, stakeState, step.epoch)
+		require.NoError(err, "releaseVestings(%d)", step.epoch)
+
+		acct2, err = stakeState.Account(ctx, addr2)
+		require.NoError(err, "Account")
+		require.Equal(*quantity.NewFromUint64(step.general), acct2.General.Balance, "general balance at epoch %d", step.epoch)
+		require.Equal(*quantity.NewFromUint64(step.vesting), acct2.Vesting.Balance, "vesting balance at epoch %d", step.epoch)
+		require.Len(acct2.Vesting.Schedules, step.schedules, "vesting schedules at epoch %d", step.epoch)
+	}
+
+	vestingAccounts, err = stakeState.VestingAccounts(ctx)
+	require.NoError(err, "VestingAccounts")
+	require.Empty(vestingAccounts, "fully released accounts should no longer be tracked")
+}
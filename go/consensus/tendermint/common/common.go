@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 )
 
 const (
@@ -40,6 +41,23 @@ const (
 	CfgSubmissionGasPrice = "consensus.tendermint.submission.gas_price"
 	// CfgSubmissionMaxFee configures the maximum fee that can be set.
 	CfgSubmissionMaxFee = "consensus.tendermint.submission.max_fee"
+	// CfgSubmissionPriceDiscoveryStrategy configures the gas price discovery mechanism.
+	CfgSubmissionPriceDiscoveryStrategy = "consensus.tendermint.submission.price_discovery.strategy"
+	// CfgSubmissionPriceDiscoveryWindow configures the number of recent blocks sampled by the
+	// dynamic price discovery mechanism.
+	CfgSubmissionPriceDiscoveryWindow = "consensus.tendermint.submission.price_discovery.window"
+	// CfgSubmissionPriceDiscoveryPercentile configures the percentile of sampled gas prices used
+	// by the dynamic price discovery mechanism.
+	CfgSubmissionPriceDiscoveryPercentile = "consensus.tendermint.submission.price_discovery.percentile"
+	// CfgSubmissionPriceDiscoveryCongestedPercentile configures the percentile of sampled gas
+	// prices used by the dynamic price discovery mechanism when the mempool is congested.
+	CfgSubmissionPriceDiscoveryCongestedPercentile = "consensus.tendermint.submission.price_discovery.congested_percentile"
+	// CfgSubmissionPriceDiscoveryCongestionThreshold configures the number of unconfirmed
+	// transactions at which the mempool is considered congested.
+	CfgSubmissionPriceDiscoveryCongestionThreshold = "consensus.tendermint.submission.price_discovery.congestion_threshold"
+	// CfgSubmissionPriceDiscoveryMaxGasPrice configures the maximum gas price returned by the
+	// dynamic price discovery mechanism.
+	CfgSubmissionPriceDiscoveryMaxGasPrice = "consensus.tendermint.submission.price_discovery.max_gas_price"
 
 	// CfgP2PSeed configures tendermint's seed node(s).
 	CfgP2PSeed = "consensus.tendermint.p2p.seed"
@@ -128,6 +146,12 @@ func init() {
 
 	Flags.Uint64(CfgSubmissionGasPrice, 0, "gas price used when submitting consensus transactions")
 	Flags.Uint64(CfgSubmissionMaxFee, 0, "maximum transaction fee when submitting consensus transactions")
+	Flags.String(CfgSubmissionPriceDiscoveryStrategy, consensus.PriceDiscoveryStatic, "gas price discovery mechanism (static, dynamic)")
+	Flags.Uint64(CfgSubmissionPriceDiscoveryWindow, 10, "number of recent blocks sampled by dynamic price discovery")
+	Flags.Uint64(CfgSubmissionPriceDiscoveryPercentile, 60, "percentile of sampled gas prices used by dynamic price discovery")
+	Flags.Uint64(CfgSubmissionPriceDiscoveryCongestedPercentile, 90, "percentile of sampled gas prices used by dynamic price discovery when the mempool is congested")
+	Flags.Uint64(CfgSubmissionPriceDiscoveryCongestionThreshold, 1000, "number of unconfirmed transactions at which the mempool is considered congested (0 disables)")
+	Flags.Uint64(CfgSubmissionPriceDiscoveryMaxGasPrice, 0, "maximum gas price returned by dynamic price discovery (0 means no limit)")
 
 	Flags.Bool(CfgLogDebug, false, "enable tendermint debug logs (very verbose)")
 
@@ -33,6 +33,10 @@ var (
 	// a tendermint node.
 	DBProvider node.DBProvider = badgerDBProvider
 
+	// ReadOnlyDBProvider is a DBProvider that opens existing databases
+	// in read-only mode.
+	ReadOnlyDBProvider node.DBProvider = badgerReadOnlyDBProvider
+
 	dbVersionStart = []byte{dbVersion}
 	dbVersionEnd   = []byte{dbVersion + 1}
 )
@@ -42,6 +46,10 @@ func badgerDBProvider(ctx *node.DBContext) (dbm.DB, error) {
 	return New(filepath.Join(ctx.Config.DBDir(), ctx.ID), false)
 }
 
+func badgerReadOnlyDBProvider(ctx *node.DBContext) (dbm.DB, error) {
+	return NewReadOnly(filepath.Join(ctx.Config.DBDir(), ctx.ID), false)
+}
+
 type badgerDBImpl struct {
 	logger *logging.Logger
 
@@ -57,6 +65,19 @@ type badgerDBImpl struct {
 // Note: This should only be used by tendermint, all other places
 // that need a K/V store should favor using BadgerDB directly.
 func New(fn string, noSuffix bool) (dbm.DB, error) {
+	return open(fn, noSuffix, false)
+}
+
+// NewReadOnly opens an existing tendermint DB, backed by a Badger
+// database at the provided path, in read-only mode.
+//
+// Note: Badger refuses to open databases that were not closed cleanly
+// in read-only mode.
+func NewReadOnly(fn string, noSuffix bool) (dbm.DB, error) {
+	return open(fn, noSuffix, true)
+}
+
+func open(fn string, noSuffix, readOnly bool) (dbm.DB, error) {
 	if !noSuffix && !strings.HasSuffix(fn, dbSuffix) {
 		fn = fn + dbSuffix
 	}
@@ -68,6 +89,7 @@ func New(fn string, noSuffix bool) (dbm.DB, error) {
 	opts = opts.WithSyncWrites(false)
 	opts = opts.WithCompression(options.Snappy)
 	opts = opts.WithBlockCacheSize(64 * 1024 * 1024)
+	opts = opts.WithReadOnly(readOnly)
 
 	db, err := badger.Open(opts)
 	if err != nil {
@@ -77,7 +99,10 @@ func New(fn string, noSuffix bool) (dbm.DB, error) {
 	impl := &badgerDBImpl{
 		logger: logger,
 		db:     db,
-		gc:     cmnBadger.NewGCWorker(logger, db),
+	}
+	if !readOnly {
+		// There is nothing to garbage collect in a read-only database.
+		impl.gc = cmnBadger.NewGCWorker(logger, db)
 	}
 
 	return impl, nil
@@ -211,7 +236,9 @@ func (d *badgerDBImpl) ReverseIterator(start, end []byte) (dbm.Iterator, error)
 func (d *badgerDBImpl) Close() error {
 	err := os.ErrClosed
 	d.closeOnce.Do(func() {
-		d.gc.Close()
+		if d.gc != nil {
+			d.gc.Close()
+		}
 
 		if err = d.db.Close(); err != nil {
 			d.logger.Error("Close failed",
@@ -24,3 +24,41 @@ func TestBadgerTendermintDB(t *testing.T) {
 
 	tests.TestTendermintDB(t, db)
 }
+
+func TestBadgerTendermintDBReadOnly(t *testing.T) {
+	require := require.New(t)
+
+	// Create a temporary directory to store the test database.
+	tmpDir, err := ioutil.TempDir("", "oasis-go-tendermint-db-test")
+	require.NoError(err, "Failed to create temporary directory.")
+	defer os.RemoveAll(tmpDir)
+
+	fn := filepath.Join(tmpDir, "test")
+
+	// Read-only access requires an existing database.
+	_, err = NewReadOnly(fn, false)
+	require.Error(err, "NewReadOnly should fail for a non-existent database")
+
+	// Create the database and populate it.
+	db, err := New(fn, false)
+	require.NoError(err, "New")
+	err = db.Set([]byte("key"), []byte("value"))
+	require.NoError(err, "Set")
+	err = db.Close()
+	require.NoError(err, "Close")
+
+	// Reopen the database in read-only mode.
+	db, err = NewReadOnly(fn, false)
+	require.NoError(err, "NewReadOnly")
+	defer db.Close()
+
+	value, err := db.Get([]byte("key"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value"), value, "Get should return the stored value")
+
+	// Writes must not be applied to a read-only database.
+	_ = db.Set([]byte("key"), []byte("other value"))
+	value, err = db.Get([]byte("key"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value"), value, "Set should not modify a read-only database")
+}
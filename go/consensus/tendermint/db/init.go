@@ -36,6 +36,19 @@ func GetProvider() (node.DBProvider, error) {
 	}
 }
 
+// GetReadOnlyProvider returns the currently configured Tendermint DBProvider
+// which opens existing databases in read-only mode.
+func GetReadOnlyProvider() (node.DBProvider, error) {
+	backend := viper.GetString(cfgBackend)
+
+	switch strings.ToLower(backend) {
+	case badger.BackendName:
+		return badger.ReadOnlyDBProvider, nil
+	default:
+		return nil, fmt.Errorf("tendermint/db: unsupported backend: '%v'", backend)
+	}
+}
+
 // New constructs a new tendermint DB with the configured backend.
 func New(fn string, noSuffix bool) (dbm.DB, error) {
 	backend := viper.GetString(cfgBackend)
@@ -0,0 +1,140 @@
+package full
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	tmconfig "github.com/tendermint/tendermint/config"
+	tmnode "github.com/tendermint/tendermint/node"
+	tmcli "github.com/tendermint/tendermint/rpc/client/local"
+	tmcore "github.com/tendermint/tendermint/rpc/core"
+	tmstate "github.com/tendermint/tendermint/state"
+	tmstore "github.com/tendermint/tendermint/store"
+
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/db"
+	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
+)
+
+// NewArchive creates a new archive-only Tendermint consensus backend.
+//
+// An archive node does not participate in consensus. Instead it serves the
+// blocks and the full history of the consensus state stored in an existing
+// data directory (e.g., the one of a node of a previous network after a
+// dump-restore upgrade), which is opened in read-only mode.
+func NewArchive(
+	ctx context.Context,
+	dataDir string,
+	identity *identity.Identity,
+	genesisProvider genesisAPI.Provider,
+) (consensusAPI.Backend, error) {
+	return newFullService(ctx, dataDir, identity, nil, genesisProvider, true)
+}
+
+func (t *fullService) lazyInitArchive() error {
+	var err error
+
+	// Create Tendermint application mux. As the state is read-only, there
+	// is nothing to prune or checkpoint.
+	appConfig := &abci.ApplicationConfig{
+		DataDir:        filepath.Join(t.dataDir, tmcommon.StateDir),
+		StorageBackend: db.GetBackendName(),
+		Pruning: abci.PruneConfig{
+			Strategy:      abci.PruneNone,
+			PruneInterval: minPruneInterval,
+		},
+		HaltEpochHeight:     t.genesis.HaltEpoch,
+		OwnTxSigner:         t.identity.NodeSigner.Public(),
+		DisableCheckpointer: true,
+		InitialHeight:       uint64(t.genesis.Height),
+		ReadOnlyStorage:     true,
+	}
+	t.mux, err = abci.NewApplicationServer(t.ctx, nil, appConfig)
+	if err != nil {
+		return err
+	}
+
+	// Open the Tendermint block store and state databases. The DB context
+	// requires a full Tendermint configuration, but only the data directory
+	// is actually used.
+	tenderConfig := tmconfig.DefaultConfig()
+	_ = viper.Unmarshal(&tenderConfig)
+	tenderConfig.SetRoot(filepath.Join(t.dataDir, tmcommon.StateDir))
+
+	dbProvider, err := db.GetReadOnlyProvider()
+	if err != nil {
+		t.Logger.Error("failed to obtain database provider",
+			"err", err,
+		)
+		return err
+	}
+	if t.blockStoreDB, err = dbProvider(&tmnode.DBContext{ID: "blockstore", Config: tenderConfig}); err != nil {
+		return fmt.Errorf("tendermint: failed to open block store database: %w", err)
+	}
+	t.blockStore = tmstore.NewBlockStore(t.blockStoreDB)
+
+	stateDB, err := dbProvider(&tmnode.DBContext{ID: "state", Config: tenderConfig})
+	if err != nil {
+		_ = t.blockStoreDB.Close()
+		return fmt.Errorf("tendermint: failed to open state database: %w", err)
+	}
+	t.stateStore = tmstate.NewStore(stateDB)
+
+	tmGenDoc, err := api.GetTendermintGenesisDocument(t.genesisProvider)
+	if err != nil {
+		t.Logger.Error("failed to obtain genesis document",
+			"err", err,
+		)
+		t.closeArchiveStores()
+		return err
+	}
+
+	// Set up the minimal Tendermint RPC environment required by the in-process
+	// client to serve block and state queries without a running node.
+	tmcore.SetEnvironment(&tmcore.Environment{
+		StateStore: t.stateStore,
+		BlockStore: t.blockStore,
+		GenDoc:     tmGenDoc,
+		Logger:     tmcommon.NewLogAdapter(!viper.GetBool(tmcommon.CfgLogDebug)),
+		Config:     *tenderConfig.RPC,
+	})
+	t.client = &tmcli.Local{}
+
+	t.isInitialized = true
+
+	return nil
+}
+
+func (t *fullService) closeArchiveStores() {
+	if err := t.stateStore.Close(); err != nil {
+		t.Logger.Error("failed to close state database",
+			"err", err,
+		)
+	}
+	if err := t.blockStoreDB.Close(); err != nil {
+		t.Logger.Error("failed to close block store database",
+			"err", err,
+		)
+	}
+}
+
+// archiveIndexHistory schedules indexing of all transactions in the stored history in case the
+// transaction indexer is enabled. This is needed as archive nodes never receive any new blocks.
+func (t *fullService) archiveIndexHistory() {
+	height := t.blockStore.Height()
+	t.Logger.Info("indexing archived transactions in the background",
+		"height", height,
+	)
+
+	if err := t.txIndexer.DeliverBlock(t.ctx, height); err != nil {
+		t.Logger.Error("failed to index archived transactions",
+			"err", err,
+		)
+	}
+}
@@ -0,0 +1,145 @@
+package full
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/db"
+	tmTestGenesis "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/tests/genesis"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade"
+)
+
+const archiveTestHeight = 5
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dataDir, err := ioutil.TempDir("", "oasis-tendermint-archive-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	require.NoError(viper.BindPFlags(Flags), "BindPFlags")
+	require.NoError(viper.BindPFlags(tmcommon.Flags), "BindPFlags")
+	require.NoError(viper.BindPFlags(db.Flags), "BindPFlags")
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	viper.Set(tmcommon.CfgCoreListenAddress, "tcp://127.0.0.1:27566")
+	viper.Set(CfgIndexerEnabled, true)
+	viper.Set(CfgSupplementarySanityEnabled, false)
+
+	// Generate a single validator network.
+	entSignerFactory, err := fileSigner.NewFactory(dataDir, signature.SignerEntity)
+	require.NoError(err, "NewFactory")
+	ent, entSigner, err := entity.Generate(dataDir, entSignerFactory, nil)
+	require.NoError(err, "entity.Generate")
+	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+	ident, err := identity.LoadOrGenerate(dataDir, nodeSignerFactory, false)
+	require.NoError(err, "identity.LoadOrGenerate")
+	ent.Nodes = append(ent.Nodes, ident.NodeSigner.Public())
+
+	genesis, err := tmTestGenesis.NewTestNodeGenesisProvider(ident, ent, entSigner)
+	require.NoError(err, "NewTestNodeGenesisProvider")
+	doc, err := genesis.GetGenesisDocument()
+	require.NoError(err, "GetGenesisDocument")
+	doc.SetChainContext()
+
+	// Run a node for a few blocks and then stop it cleanly.
+	nodeCtx, nodeCancel := context.WithCancel(ctx)
+	defer nodeCancel()
+	node, err := New(nodeCtx, dataDir, ident, upgrade.NewDummyUpgradeManager(), genesis)
+	require.NoError(err, "New")
+	require.NoError(node.Start(), "Start")
+
+	blkCh, blkSub, err := node.WatchBlocks(ctx)
+	require.NoError(err, "WatchBlocks")
+	for {
+		select {
+		case blk := <-blkCh:
+			if blk.Height < archiveTestHeight {
+				continue
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("failed to wait for blocks")
+		}
+		break
+	}
+	blkSub.Close()
+
+	expectedBlk, err := node.GetBlock(ctx, 2)
+	require.NoError(err, "GetBlock")
+	expectedSupply, err := node.Staking().TotalSupply(ctx, 2)
+	require.NoError(err, "TotalSupply")
+
+	node.Stop()
+	<-node.Quit()
+	nodeCancel()
+	node.Cleanup()
+	// Regular nodes leave the ABCI state open until the process exits, so close it explicitly to
+	// allow the archive node to open it.
+	node.(*fullService).mux.Cleanup()
+
+	// Serve the stored history from an archive node.
+	archive, err := NewArchive(ctx, dataDir, ident, genesis)
+	require.NoError(err, "NewArchive")
+	require.NoError(archive.Start(), "Start")
+	defer func() {
+		archive.Stop()
+		<-archive.Quit()
+		archive.Cleanup()
+	}()
+
+	select {
+	case <-archive.Synced():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("failed to wait for archive node to become ready")
+	}
+
+	blk, err := archive.GetBlock(ctx, 2)
+	require.NoError(err, "GetBlock")
+	require.EqualValues(expectedBlk, blk, "archived blocks should be served")
+
+	blk, err = archive.GetBlock(ctx, consensusAPI.HeightLatest)
+	require.NoError(err, "GetBlock(HeightLatest)")
+	require.GreaterOrEqual(blk.Height, int64(archiveTestHeight), "latest block should be the last stored block")
+
+	supply, err := archive.Staking().TotalSupply(ctx, 2)
+	require.NoError(err, "TotalSupply")
+	require.Zero(supply.Cmp(expectedSupply), "archived state should be served")
+
+	stateDoc, err := archive.StateToGenesis(ctx, 2)
+	require.NoError(err, "StateToGenesis")
+	require.EqualValues(2, stateDoc.Height, "StateToGenesis should use the requested height")
+	require.Zero(stateDoc.Staking.TotalSupply.Cmp(expectedSupply), "StateToGenesis should use archived state")
+
+	// The stored history should be indexed on startup.
+	var rsp *consensusAPI.GetTransactionsByAddressResponse
+	for i := 0; i < 100; i++ {
+		rsp, err = archive.GetTransactionsByAddress(ctx, &consensusAPI.GetTransactionsByAddressRequest{
+			Address: staking.NewAddress(ident.NodeSigner.Public()),
+		})
+		require.NoError(err, "GetTransactionsByAddress")
+		if rsp.IndexedHeightMax >= blk.Height {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.EqualValues(blk.Height, rsp.IndexedHeightMax, "stored history should be indexed")
+
+	err = archive.SubmitTx(ctx, nil)
+	require.ErrorIs(err, consensusAPI.ErrUnsupported, "SubmitTx should not be supported")
+}
@@ -28,6 +28,7 @@ import (
 	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
 	tmstate "github.com/tendermint/tendermint/state"
 	tmstatesync "github.com/tendermint/tendermint/statesync"
+	tmstore "github.com/tendermint/tendermint/store"
 	tmtypes "github.com/tendermint/tendermint/types"
 	tmdb "github.com/tendermint/tm-db"
 
@@ -40,20 +41,24 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	cmservice "github.com/oasisprotocol/oasis-core/go/common/service"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	eventsAPI "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
 	"github.com/oasisprotocol/oasis-core/go/consensus/metrics"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/supplementarysanity"
 	tmbeacon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/beacon"
 	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/db"
 	tmgovernance "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/governance"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/indexer"
 	tmkeymanager "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/keymanager"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/light"
 	tmregistry "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/registry"
@@ -89,6 +94,9 @@ const (
 	// CfgCheckpointerCheckInterval configures the ABCI state checkpointing check interval.
 	CfgCheckpointerCheckInterval = "consensus.tendermint.checkpointer.check_interval"
 
+	// CfgIndexerEnabled enables the consensus transaction indexer.
+	CfgIndexerEnabled = "consensus.tendermint.indexer.enabled"
+
 	// CfgSentryUpstreamAddress defines nodes for which we act as a sentry for.
 	CfgSentryUpstreamAddress = "consensus.tendermint.sentry.upstream_address"
 
@@ -138,6 +146,8 @@ const (
 
 	minUpgradeStopWaitPeriod = 5 * time.Second
 
+	minPruneInterval = 1 * time.Second
+
 	// tmSubscriberID is the subscriber identifier used for all internal Tendermint pubsub
 	// subscriptions. If any other subscriber IDs need to be derived they will be under this prefix.
 	tmSubscriberID = "oasis-core"
@@ -153,6 +163,9 @@ var (
 )
 
 // fullService implements a full Tendermint node.
+//
+// In archive mode no Tendermint node is created and existing state is only
+// served in read-only mode.
 type fullService struct { // nolint: maligned
 	sync.Mutex
 	cmservice.BaseBackgroundService
@@ -167,6 +180,9 @@ type fullService struct { // nolint: maligned
 	failMonitor   *failMonitor
 
 	stateStore tmstate.Store
+	blockStore *tmstore.BlockStore
+	// blockStoreDB is only set in archive mode where there is no Tendermint node to manage it.
+	blockStoreDB tmdb.DB
 
 	beacon        beaconAPI.Backend
 	governance    governanceAPI.Backend
@@ -176,6 +192,7 @@ type fullService struct { // nolint: maligned
 	scheduler     schedulerAPI.Backend
 	staking       stakingAPI.Backend
 	submissionMgr consensusAPI.SubmissionManager
+	txIndexer     indexer.ServiceClient
 
 	serviceClients   []api.ServiceClient
 	serviceClientsWg sync.WaitGroup
@@ -184,6 +201,7 @@ type fullService struct { // nolint: maligned
 	genesisProvider          genesisAPI.Provider
 	identity                 *identity.Identity
 	dataDir                  string
+	archive                  bool
 	isInitialized, isStarted bool
 	startedCh                chan struct{}
 	syncedCh                 chan struct{}
@@ -220,6 +238,15 @@ func (t *fullService) Start() error {
 		if err := t.mux.Start(); err != nil {
 			return err
 		}
+		if t.archive {
+			// Archive nodes only serve existing state, so they are always synced.
+			close(t.syncedCh)
+			// As no blocks will ever be delivered, index the stored history once.
+			if t.txIndexer != nil {
+				t.archiveIndexHistory()
+			}
+			break
+		}
 		if err := t.startFn(); err != nil {
 			return err
 		}
@@ -275,6 +302,15 @@ func (t *fullService) Quit() <-chan struct{} {
 func (t *fullService) Cleanup() {
 	t.serviceClientsWg.Wait()
 	t.svcMgr.Cleanup()
+
+	// Archive nodes own the mux and the Tendermint stores, so they need to clean them up.
+	if t.archive && t.initialized() {
+		// The mux workers are only running after the mux has been started.
+		if t.started() {
+			t.mux.Cleanup()
+		}
+		t.closeArchiveStores()
+	}
 }
 
 // Implements service.BackgroundService.
@@ -284,6 +320,13 @@ func (t *fullService) Stop() {
 	}
 
 	t.stopOnce.Do(func() {
+		if t.archive {
+			t.svcMgr.Stop()
+			t.mux.Stop()
+			close(t.quitCh)
+			return
+		}
+
 		t.failMonitor.markCleanShutdown()
 		if err := t.node.Stop(); err != nil {
 			t.Logger.Error("Error on stopping node", err)
@@ -299,6 +342,9 @@ func (t *fullService) Started() <-chan struct{} {
 }
 
 func (t *fullService) SupportedFeatures() consensusAPI.FeatureMask {
+	if t.archive {
+		return consensusAPI.FeatureServices | consensusAPI.FeatureArchiveNode
+	}
 	return consensusAPI.FeatureServices | consensusAPI.FeatureFullNode
 }
 
@@ -307,6 +353,10 @@ func (t *fullService) Synced() <-chan struct{} {
 }
 
 func (t *fullService) GetAddresses() ([]node.ConsensusAddress, error) {
+	if t.archive {
+		return nil, consensusAPI.ErrUnsupported
+	}
+
 	u, err := tmcommon.GetExternalAddress()
 	if err != nil {
 		return nil, err
@@ -445,8 +495,19 @@ func (t *fullService) RegisterHaltHook(hook consensusAPI.HaltHook) {
 }
 
 func (t *fullService) SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	return t.submitTxRaw(ctx, cbor.Marshal(tx))
+}
+
+func (t *fullService) SubmitMultiSignedTx(ctx context.Context, tx *transaction.MultiSignedTransaction) error {
+	return t.submitTxRaw(ctx, cbor.Marshal(tx))
+}
+
+func (t *fullService) submitTxRaw(ctx context.Context, data []byte) error {
+	if t.archive {
+		return consensusAPI.ErrUnsupported
+	}
+
 	// Subscribe to the transaction being included in a block.
-	data := cbor.Marshal(tx)
 	query := tmtypes.EventQueryTxFor(data)
 	subID := t.newSubscriberID()
 	txSub, err := t.subscribe(subID, query)
@@ -492,6 +553,10 @@ func (t *fullService) SubmitTx(ctx context.Context, tx *transaction.SignedTransa
 }
 
 func (t *fullService) broadcastTxRaw(data []byte) error {
+	if t.archive {
+		return consensusAPI.ErrUnsupported
+	}
+
 	// We could use t.client.BroadcastTxSync but that is annoying as it
 	// doesn't give you the right fields when CheckTx fails.
 	mp := t.node.Mempool()
@@ -524,6 +589,10 @@ func (t *fullService) newSubscriberID() string {
 }
 
 func (t *fullService) SubmitEvidence(ctx context.Context, evidence *consensusAPI.Evidence) error {
+	if t.archive {
+		return consensusAPI.ErrUnsupported
+	}
+
 	var protoEv tmproto.Evidence
 	if err := protoEv.Unmarshal(evidence.Meta); err != nil {
 		return fmt.Errorf("tendermint: malformed evidence while unmarshalling: %w", err)
@@ -545,6 +614,90 @@ func (t *fullService) EstimateGas(ctx context.Context, req *consensusAPI.Estimat
 	return t.mux.EstimateGas(req.Signer, req.Transaction)
 }
 
+func (t *fullService) SimulateTx(ctx context.Context, req *consensusAPI.SimulateTxRequest) (*consensusAPI.SimulateTxResponse, error) {
+	height := req.Height
+	if height == consensusAPI.HeightLatest {
+		// Use the latest committed state instead of the latest block as the state for the latest
+		// block may not have been committed yet.
+		height = t.mux.State().BlockHeight()
+	}
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+
+	res, err := t.mux.DryRunTx(req.Signer, req.Transaction, blk.Height, blk.Time)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// The transaction is executed as if it was included in the following block.
+	rs := &tmabcitypes.ResponseDeliverTx{
+		Events: res.Events,
+	}
+	if res.Error != nil {
+		rs.Codespace, rs.Code = errors.Code(res.Error)
+		rs.Log = res.Error.Error()
+	}
+	result, err := resultFromTendermint(nil, blk.Height+1, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute balance changes of all involved accounts.
+	involved := make(map[stakingAPI.Address]bool)
+	addrs := []stakingAPI.Address{stakingAPI.NewAddress(req.Signer)}
+	for _, ev := range result.Events {
+		if ev.Staking != nil {
+			addrs = append(addrs, (&consensusAPI.Event{Staking: ev.Staking}).Addresses()...)
+		}
+	}
+
+	before, err := stakingState.NewImmutableState(ctx, t.mux.State(), blk.Height)
+	if err != nil {
+		return nil, err
+	}
+	after := stakingState.NewMutableState(res.State())
+
+	var balanceChanges []*consensusAPI.BalanceChange
+	for _, addr := range addrs {
+		if involved[addr] {
+			continue
+		}
+		involved[addr] = true
+
+		acctBefore, err := before.Account(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		acctAfter, err := after.Account(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		change := consensusAPI.BalanceChange{
+			Address: addr,
+			Before:  consensusAPI.NewAccountBalances(acctBefore),
+			After:   consensusAPI.NewAccountBalances(acctAfter),
+		}
+		if change.Before.Equal(&change.After) {
+			continue
+		}
+		balanceChanges = append(balanceChanges, &change)
+	}
+
+	return &consensusAPI.SimulateTxResponse{
+		Height:         blk.Height,
+		Result:         result,
+		GasUsed:        res.GasUsed,
+		BalanceChanges: balanceChanges,
+	}, nil
+}
+
 func (t *fullService) subscribe(subscriber string, query tmpubsub.Query) (tmtypes.Subscription, error) {
 	// Note: The tendermint documentation claims using SubscribeUnbuffered can
 	// freeze the server, however, the buffered Subscribe can drop events, and
@@ -688,73 +841,144 @@ func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int
 		return nil, err
 	}
 	for txIdx, rs := range res.TxsResults {
-		// Transaction result.
-		result := &results.Result{
-			Error: results.Error{
-				Module:  rs.GetCodespace(),
-				Code:    rs.GetCode(),
-				Message: rs.GetLog(),
-			},
+		result, err := resultFromTendermint(txsWithResults.Transactions[txIdx], blk.Height, rs)
+		if err != nil {
+			return nil, err
 		}
+		txsWithResults.Results = append(txsWithResults.Results, result)
+	}
+	return &txsWithResults, nil
+}
 
-		// Transaction staking events.
-		stakingEvents, err := tmstaking.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
+// resultFromTendermint converts a tendermint transaction result into a transaction result.
+//
+// The events of the result are kept in the order in which they were emitted.
+func resultFromTendermint(tx tmtypes.Tx, height int64, rs *tmabcitypes.ResponseDeliverTx) (*results.Result, error) {
+	events, err := eventsFromTendermint(tx, height, rs.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	return &results.Result{
+		Error: results.Error{
+			Module:  rs.GetCodespace(),
+			Code:    rs.GetCode(),
+			Message: rs.GetLog(),
+		},
+		Events: events,
+	}, nil
+}
+
+// eventsFromTendermint converts tendermint events into consensus service events, keeping them in
+// the order in which they were emitted.
+func eventsFromTendermint(tx tmtypes.Tx, height int64, tmEvents []tmabcitypes.Event) ([]*results.Event, error) {
+	var events []*results.Event
+
+	// Each service only decodes its own events, so converting them one by one preserves the
+	// emission order.
+	for _, tmEv := range tmEvents {
+		tmEvents := []tmabcitypes.Event{tmEv}
+
+		consensusEvents, err := consensusEventsFromTendermint(tx, height, tmEvents)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range consensusEvents {
+			events = append(events, &results.Event{Consensus: e})
+		}
+
+		stakingEvents, err := tmstaking.EventsFromTendermint(tx, height, tmEvents)
 		if err != nil {
 			return nil, err
 		}
 		for _, e := range stakingEvents {
-			result.Events = append(result.Events, &results.Event{Staking: e})
+			events = append(events, &results.Event{Staking: e})
 		}
 
-		// Transaction registry events.
-		registryEvents, _, err := tmregistry.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
+		registryEvents, _, err := tmregistry.EventsFromTendermint(tx, height, tmEvents)
 		if err != nil {
 			return nil, err
 		}
 		for _, e := range registryEvents {
-			result.Events = append(result.Events, &results.Event{Registry: e})
+			events = append(events, &results.Event{Registry: e})
 		}
 
-		// Transaction roothash events.
-		roothashEvents, err := tmroothash.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
+		roothashEvents, err := tmroothash.EventsFromTendermint(tx, height, tmEvents)
 		if err != nil {
 			return nil, err
 		}
 		for _, e := range roothashEvents {
-			result.Events = append(result.Events, &results.Event{RootHash: e})
+			events = append(events, &results.Event{RootHash: e})
 		}
 
-		// Transaction governance events.
-		governanceEvents, err := tmgovernance.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
+		governanceEvents, err := tmgovernance.EventsFromTendermint(tx, height, tmEvents)
 		if err != nil {
 			return nil, err
 		}
 		for _, e := range governanceEvents {
-			result.Events = append(result.Events, &results.Event{Governance: e})
+			events = append(events, &results.Event{Governance: e})
 		}
+	}
 
-		txsWithResults.Results = append(txsWithResults.Results, result)
+	return events, nil
+}
+
+// consensusEventsFromTendermint extracts events emitted by the consensus layer itself (e.g., by
+// the multiplexer when executing batches) from tendermint events.
+func consensusEventsFromTendermint(
+	tx tmtypes.Tx,
+	height int64,
+	tmEvents []tmabcitypes.Event,
+) ([]*results.ConsensusEvent, error) {
+	var txHash hash.Hash
+	switch tx {
+	case nil:
+		txHash.Empty()
+	default:
+		txHash = hash.NewFromBytes(tx)
 	}
-	return &txsWithResults, nil
+
+	var events []*results.ConsensusEvent
+	for _, tmEv := range tmEvents {
+		// Ignore events that don't relate to the consensus layer.
+		if tmEv.GetType() != api.EventTypeForApp(consensusAPI.ModuleName) {
+			continue
+		}
+
+		for _, pair := range tmEv.GetAttributes() {
+			key := pair.GetKey()
+			val := pair.GetValue()
+
+			switch {
+			case eventsAPI.IsAttributeKind(key, &results.BatchCallEvent{}):
+				// Batch call event.
+				var e results.BatchCallEvent
+				if err := eventsAPI.DecodeValue(string(val), &e); err != nil {
+					return nil, fmt.Errorf("consensus: corrupt BatchCall event: %w", err)
+				}
+
+				events = append(events, &results.ConsensusEvent{Height: height, TxHash: txHash, BatchCall: &e})
+			default:
+				return nil, fmt.Errorf("consensus: unknown event type: key: %s, val: %s", key, val)
+			}
+		}
+	}
+	return events, nil
+}
+
+func (t *fullService) GetTransactionsByAddress(ctx context.Context, request *consensusAPI.GetTransactionsByAddressRequest) (*consensusAPI.GetTransactionsByAddressResponse, error) {
+	if t.txIndexer == nil {
+		return nil, consensusAPI.ErrUnsupported
+	}
+	return t.txIndexer.GetTransactionsByAddress(ctx, request)
 }
 
 func (t *fullService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
+	if t.archive {
+		// Archive nodes have no mempool.
+		return [][]byte{}, nil
+	}
+
 	mempoolTxs := t.node.Mempool().ReapMaxTxs(-1)
 	txs := make([][]byte, 0, len(mempoolTxs))
 	for _, v := range mempoolTxs {
@@ -763,6 +987,14 @@ func (t *fullService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte,
 	return txs, nil
 }
 
+func (t *fullService) GetUnconfirmedTransactionCount(ctx context.Context) (uint64, error) {
+	if t.archive {
+		// Archive nodes have no mempool.
+		return 0, nil
+	}
+	return uint64(t.node.Mempool().Size()), nil
+}
+
 func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, error) {
 	status := &consensusAPI.Status{
 		Version:  version.ConsensusProtocol,
@@ -821,14 +1053,16 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 			return nil, fmt.Errorf("failed to fetch current block: %w", err)
 		}
 
-		// List of consensus peers.
-		tmpeers := t.node.Switch().Peers().List()
-		peers := make([]string, 0, len(tmpeers))
-		for _, tmpeer := range tmpeers {
-			p := string(tmpeer.ID()) + "@" + tmpeer.RemoteAddr().String()
-			peers = append(peers, p)
+		// List of consensus peers (archive nodes have no peers).
+		if !t.archive {
+			tmpeers := t.node.Switch().Peers().List()
+			peers := make([]string, 0, len(tmpeers))
+			for _, tmpeer := range tmpeers {
+				p := string(tmpeer.ID()) + "@" + tmpeer.RemoteAddr().String()
+				peers = append(peers, p)
+			}
+			status.NodePeers = peers
 		}
-		status.NodePeers = peers
 
 		// Check if the local node is in the validator set for the latest (uncommitted) block.
 		valSetHeight := status.LatestHeight + 1
@@ -850,6 +1084,9 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 }
 
 func (t *fullService) GetNextBlockState(ctx context.Context) (*consensusAPI.NextBlockState, error) {
+	if t.archive {
+		return nil, consensusAPI.ErrUnsupported
+	}
 	if !t.started() {
 		return nil, fmt.Errorf("tendermint: not yet started")
 	}
@@ -890,6 +1127,67 @@ func (t *fullService) GetNextBlockState(ctx context.Context) (*consensusAPI.Next
 	return nbs, nil
 }
 
+func (t *fullService) GasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	return t.submissionMgr.PriceDiscovery().GasPrice(ctx)
+}
+
+func (t *fullService) WatchAllEvents(ctx context.Context, request *consensusAPI.WatchAllEventsRequest) (<-chan *consensusAPI.Event, pubsub.ClosableSubscription, error) {
+	return consensusAPI.WatchAllEvents(ctx, t, t.getAllEvents, request)
+}
+
+// getAllEvents returns the events emitted by all consensus services at the given height in
+// emission order.
+func (t *fullService) getAllEvents(ctx context.Context, height int64) ([]*consensusAPI.Event, error) {
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+	res, err := t.GetBlockResults(ctx, blk.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block events are emitted before (BeginBlock) and after (EndBlock) all transaction events.
+	var evs []*results.Event
+	blockEvs, err := eventsFromTendermint(nil, blk.Height, res.BeginBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+	evs = append(evs, blockEvs...)
+	for txIdx, rs := range res.TxsResults {
+		txEvs, err := eventsFromTendermint(blk.Data.Txs[txIdx], blk.Height, rs.Events)
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, txEvs...)
+	}
+	blockEvs, err = eventsFromTendermint(nil, blk.Height, res.EndBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+	evs = append(evs, blockEvs...)
+
+	allEvs := make([]*consensusAPI.Event, 0, len(evs))
+	for _, ev := range evs {
+		switch {
+		case ev.Staking != nil:
+			allEvs = append(allEvs, &consensusAPI.Event{Height: blk.Height, TxHash: ev.Staking.TxHash, Staking: ev.Staking})
+		case ev.Registry != nil:
+			allEvs = append(allEvs, &consensusAPI.Event{Height: blk.Height, TxHash: ev.Registry.TxHash, Registry: ev.Registry})
+		case ev.RootHash != nil:
+			allEvs = append(allEvs, &consensusAPI.Event{Height: blk.Height, TxHash: ev.RootHash.TxHash, RootHash: ev.RootHash})
+		case ev.Governance != nil:
+			allEvs = append(allEvs, &consensusAPI.Event{Height: blk.Height, TxHash: ev.Governance.TxHash, Governance: ev.Governance})
+		default:
+			// Events emitted by the consensus layer itself are not service events.
+		}
+	}
+	return allEvs, nil
+}
+
 func (t *fullService) WatchBlocks(ctx context.Context) (<-chan *consensusAPI.Block, pubsub.ClosableSubscription, error) {
 	ch, sub := t.WatchTendermintBlocks()
 	mapCh := make(chan *consensusAPI.Block)
@@ -1037,6 +1335,20 @@ func (t *fullService) initialize() error {
 	t.serviceClients = append(t.serviceClients, scGovernance)
 	t.svcMgr.RegisterCleanupOnly(t.governance, "governance backend")
 
+	// Optionally enable the consensus transaction indexer.
+	if viper.GetBool(CfgIndexerEnabled) {
+		var scIndexer indexer.ServiceClient
+		if scIndexer, err = indexer.New(t.ctx, filepath.Join(t.dataDir, tmcommon.StateDir), t); err != nil {
+			t.Logger.Error("indexer: failed to initialize transaction indexer",
+				"err", err,
+			)
+			return err
+		}
+		t.txIndexer = scIndexer
+		t.serviceClients = append(t.serviceClients, scIndexer)
+		t.svcMgr.RegisterCleanupOnly(t.txIndexer, "transaction indexer")
+	}
+
 	// Enable supplementary sanity checks when enabled.
 	if viper.GetBool(CfgSupplementarySanityEnabled) {
 		ssa := supplementarysanity.New(viper.GetUint64(CfgSupplementarySanityInterval))
@@ -1052,7 +1364,7 @@ func (t *fullService) GetLastRetainedVersion(ctx context.Context) (int64, error)
 	if err := t.ensureStarted(ctx); err != nil {
 		return -1, err
 	}
-	return t.node.BlockStore().Base(), nil
+	return t.blockStore.Base(), nil
 }
 
 func (t *fullService) heightToTendermintHeight(height int64) (int64, error) {
@@ -1135,6 +1447,9 @@ func (t *fullService) lazyInit() error {
 	if t.isInitialized {
 		return nil
 	}
+	if t.archive {
+		return t.lazyInitArchive()
+	}
 
 	var err error
 
@@ -1146,7 +1461,6 @@ func (t *fullService) lazyInit() error {
 	}
 	pruneCfg.NumKept = viper.GetUint64(CfgABCIPruneNumKept)
 	pruneCfg.PruneInterval = viper.GetDuration(CfgABCIPruneInterval)
-	const minPruneInterval = 1 * time.Second
 	if pruneCfg.PruneInterval < minPruneInterval {
 		pruneCfg.PruneInterval = minPruneInterval
 	}
@@ -1362,6 +1676,7 @@ func (t *fullService) lazyInit() error {
 			return fmt.Errorf("tendermint: internal error: state database not set")
 		}
 		t.client = tmcli.New(t.node)
+		t.blockStore = t.node.BlockStore()
 		t.failMonitor = newFailMonitor(t.ctx, t.Logger, t.node.ConsensusState().Wait)
 
 		// Register a halt hook that handles upgrades gracefully.
@@ -1534,6 +1849,17 @@ func New(
 	identity *identity.Identity,
 	upgrader upgradeAPI.Backend,
 	genesisProvider genesisAPI.Provider,
+) (consensusAPI.Backend, error) {
+	return newFullService(ctx, dataDir, identity, upgrader, genesisProvider, false)
+}
+
+func newFullService(
+	ctx context.Context,
+	dataDir string,
+	identity *identity.Identity,
+	upgrader upgradeAPI.Backend,
+	genesisProvider genesisAPI.Provider,
+	archive bool,
 ) (consensusAPI.Backend, error) {
 	// Retrieve the genesis document early so that it is possible to
 	// use it while initializing other things.
@@ -1560,27 +1886,57 @@ func New(
 		genesisProvider:       genesisProvider,
 		ctx:                   ctx,
 		dataDir:               dataDir,
+		archive:               archive,
 		startedCh:             make(chan struct{}),
 		syncedCh:              make(chan struct{}),
 		quitCh:                make(chan struct{}),
 	}
 
-	t.Logger.Info("starting a full consensus node")
+	switch archive {
+	case true:
+		t.Logger.Info("starting an archive consensus node")
+	case false:
+		t.Logger.Info("starting a full consensus node")
+	}
+
+	if err = t.initialize(); err != nil {
+		return t, err
+	}
 
-	// Create the submission manager.
-	pd, err := consensusAPI.NewStaticPriceDiscovery(viper.GetUint64(tmcommon.CfgSubmissionGasPrice))
+	// Create the submission manager. This requires the staking backend to be initialized so that
+	// the consensus-enforced minimum gas price can be taken into account.
+	pd, err := newPriceDiscovery(t)
 	if err != nil {
 		return nil, fmt.Errorf("tendermint: failed to create submission manager: %w", err)
 	}
-	t.submissionMgr = consensusAPI.NewSubmissionManager(t, pd, viper.GetUint64(tmcommon.CfgSubmissionMaxFee))
+	t.submissionMgr = consensusAPI.NewSubmissionManager(t, pd, t.staking, viper.GetUint64(tmcommon.CfgSubmissionMaxFee))
 
-	return t, t.initialize()
+	return t, nil
+}
+
+func newPriceDiscovery(backend consensusAPI.PriceDiscoveryBackend) (consensusAPI.PriceDiscovery, error) {
+	switch strategy := viper.GetString(tmcommon.CfgSubmissionPriceDiscoveryStrategy); strategy {
+	case consensusAPI.PriceDiscoveryStatic:
+		return consensusAPI.NewStaticPriceDiscovery(viper.GetUint64(tmcommon.CfgSubmissionGasPrice))
+	case consensusAPI.PriceDiscoveryDynamic:
+		return consensusAPI.NewDynamicPriceDiscovery(backend, &consensusAPI.DynamicPriceDiscoveryConfig{
+			Window:              viper.GetUint64(tmcommon.CfgSubmissionPriceDiscoveryWindow),
+			Percentile:          viper.GetUint64(tmcommon.CfgSubmissionPriceDiscoveryPercentile),
+			CongestedPercentile: viper.GetUint64(tmcommon.CfgSubmissionPriceDiscoveryCongestedPercentile),
+			CongestionThreshold: viper.GetUint64(tmcommon.CfgSubmissionPriceDiscoveryCongestionThreshold),
+			MinPrice:            viper.GetUint64(tmcommon.CfgSubmissionGasPrice),
+			MaxPrice:            viper.GetUint64(tmcommon.CfgSubmissionPriceDiscoveryMaxGasPrice),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported price discovery mechanism: %s", strategy)
+	}
 }
 
 func init() {
 	Flags.String(CfgABCIPruneStrategy, abci.PruneDefault, "ABCI state pruning strategy")
 	Flags.Uint64(CfgABCIPruneNumKept, 3600, "ABCI state versions kept (when applicable)")
 	Flags.Duration(CfgABCIPruneInterval, 2*time.Minute, "ABCI state pruning interval")
+	Flags.Bool(CfgIndexerEnabled, false, "Enable the consensus transaction indexer")
 	Flags.Bool(CfgCheckpointerDisabled, false, "Disable the ABCI state checkpointer")
 	Flags.Duration(CfgCheckpointerCheckInterval, 1*time.Minute, "ABCI state checkpointer check interval")
 	Flags.StringSlice(CfgSentryUpstreamAddress, []string{}, "Tendermint nodes for which we act as sentry of the form ID@ip:port")
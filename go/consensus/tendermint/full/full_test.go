@@ -0,0 +1,50 @@
+package full
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+
+	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	stakingApp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestResultFromTendermint(t *testing.T) {
+	require := require.New(t)
+
+	tx := []byte("test transaction")
+	var tmEvents []tmabcitypes.Event
+	for i := 0; i < 2; i++ {
+		tmEvents = append(tmEvents,
+			api.NewEventBuilder(consensusAPI.ModuleName).TypedAttribute(&results.BatchCallEvent{
+				Index:   uint32(i),
+				Method:  staking.MethodTransfer,
+				GasUsed: 10,
+			}).Event(),
+			api.NewEventBuilder(stakingApp.AppName).TypedAttribute(&staking.TransferEvent{
+				From: staking.CommonPoolAddress,
+				To:   staking.FeeAccumulatorAddress,
+			}).Event(),
+		)
+	}
+
+	result, err := resultFromTendermint(tx, 42, &tmabcitypes.ResponseDeliverTx{Events: tmEvents})
+	require.NoError(err, "resultFromTendermint")
+	require.True(result.IsSuccess(), "result should be successful")
+	require.Len(result.Events, 4, "all events should be decoded")
+	for i := 0; i < 2; i++ {
+		callEv := result.Events[2*i].Consensus
+		require.NotNil(callEv, "batch call events should be decoded")
+		require.NotNil(callEv.BatchCall, "batch call events should be decoded")
+		require.EqualValues(i, callEv.BatchCall.Index, "events should be in emission order")
+		require.EqualValues(42, callEv.Height, "event height should be set")
+
+		transferEv := result.Events[2*i+1].Staking
+		require.NotNil(transferEv, "staking events should follow their batch call event")
+		require.NotNil(transferEv.Transfer, "staking events should be decoded")
+	}
+}
@@ -0,0 +1,382 @@
+// Package indexer implements the consensus transaction indexer.
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v3"
+
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	// DbFilename is the filename of the indexer database.
+	DbFilename = "indexer.db"
+
+	dbVersion = 1
+)
+
+var (
+	// metadataKeyFmt is the metadata key format.
+	//
+	// Value is CBOR-serialized dbMetadata.
+	metadataKeyFmt = keyformat.New(0x01)
+	// addressTxKeyFmt is the address transaction index key format.
+	//
+	// Value is CBOR-serialized consensus.AddressTransaction.
+	addressTxKeyFmt = keyformat.New(0x02, &staking.Address{}, uint64(0), uint32(0))
+	// heightAddressKeyFmt is the per-height address index key format. It is used to find the
+	// address transaction index entries to remove when pruning.
+	//
+	// Value is empty.
+	heightAddressKeyFmt = keyformat.New(0x03, uint64(0), &staking.Address{}, uint32(0))
+)
+
+type dbMetadata struct {
+	// ChainContext is the chain domain separation context of the indexed chain.
+	ChainContext string `json:"chain_context"`
+	// Version is the database schema version.
+	Version uint64 `json:"version"`
+	// FirstHeight is the first height that has been indexed and not yet pruned.
+	FirstHeight int64 `json:"first_height"`
+	// LastHeight is the last indexed height.
+	LastHeight int64 `json:"last_height"`
+}
+
+// txIndex is the badger-backed consensus transaction index.
+type txIndex struct {
+	logger *logging.Logger
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+}
+
+// decodeTransaction decodes a raw consensus transaction, returning the transaction and the address
+// of its signer. Both are nil in case the transaction is malformed or its signature(s) are invalid.
+func decodeTransaction(rawTx []byte) (*transaction.Transaction, *staking.Address) {
+	sigTx, multiSigTx, err := transaction.UnmarshalSignedTransaction(rawTx)
+	if err != nil {
+		return nil, nil
+	}
+
+	var (
+		tx     transaction.Transaction
+		signer staking.Address
+	)
+	switch {
+	case sigTx != nil:
+		if err = sigTx.Open(&tx); err != nil {
+			return nil, nil
+		}
+		signer = staking.NewAddress(sigTx.Signature.PublicKey)
+	default:
+		if err = multiSigTx.Open(&tx); err != nil {
+			return nil, nil
+		}
+		signer = staking.NewMultisigAddress(&multiSigTx.Account)
+	}
+	return &tx, &signer
+}
+
+// batchCalls returns the methods of the calls in case the given transaction is a batch.
+func batchCalls(tx *transaction.Transaction) []transaction.MethodName {
+	if tx.Method != consensus.MethodBatch {
+		return nil
+	}
+
+	var batch consensus.Batch
+	if err := cbor.Unmarshal(tx.Body, &batch); err != nil {
+		return nil
+	}
+	calls := make([]transaction.MethodName, 0, len(batch.Calls))
+	for _, call := range batch.Calls {
+		calls = append(calls, call.Method)
+	}
+	return calls
+}
+
+// addressTransactions returns the address transaction index entries for the given block.
+func addressTransactions(height int64, txs *consensus.TransactionsWithResults) map[staking.Address][]*consensus.AddressTransaction {
+	entries := make(map[staking.Address][]*consensus.AddressTransaction)
+	for i, rawTx := range txs.Transactions {
+		var (
+			method transaction.MethodName
+			calls  []transaction.MethodName
+		)
+		tx, signer := decodeTransaction(rawTx)
+		if tx != nil {
+			method = tx.Method
+			calls = batchCalls(tx)
+		}
+
+		var success bool
+		if i < len(txs.Results) {
+			success = txs.Results[i].IsSuccess()
+		}
+
+		// Collect all addresses involved in the transaction together with their roles.
+		involved := make(map[staking.Address]*consensus.AddressTransaction)
+		entryFor := func(addr staking.Address) *consensus.AddressTransaction {
+			if at, ok := involved[addr]; ok {
+				return at
+			}
+			at := &consensus.AddressTransaction{
+				TransactionPosition: consensus.TransactionPosition{
+					Height: height,
+					Index:  uint32(i),
+				},
+				TxHash:  hash.NewFromBytes(rawTx),
+				Method:  method,
+				Calls:   calls,
+				Success: success,
+			}
+			involved[addr] = at
+			entries[addr] = append(entries[addr], at)
+			return at
+		}
+
+		if signer != nil {
+			entryFor(*signer).Signer = true
+		}
+		if i < len(txs.Results) {
+			for _, ev := range txs.Results[i].Events {
+				if ev.Staking == nil {
+					continue
+				}
+				for _, addr := range (&consensus.Event{Staking: ev.Staking}).Addresses() {
+					entryFor(addr).EventParticipant = true
+				}
+			}
+		}
+	}
+	return entries
+}
+
+func (ti *txIndex) getMetadata(tx *badger.Txn) (*dbMetadata, error) {
+	item, err := tx.Get(metadataKeyFmt.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var meta dbMetadata
+	if err = item.Value(func(val []byte) error {
+		return cbor.Unmarshal(val, &meta)
+	}); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// lastHeight returns the last indexed height or zero in case nothing has been indexed yet.
+func (ti *txIndex) lastHeight() (int64, error) {
+	var height int64
+	txErr := ti.db.View(func(tx *badger.Txn) error {
+		meta, err := ti.getMetadata(tx)
+		if err != nil {
+			return err
+		}
+		height = meta.LastHeight
+		return nil
+	})
+	if txErr != nil {
+		return 0, txErr
+	}
+	return height, nil
+}
+
+// index indexes the transactions included in the block at the given height.
+//
+// Heights must be indexed in order, but skipping heights is allowed.
+func (ti *txIndex) index(height int64, txs *consensus.TransactionsWithResults) error {
+	return ti.db.Update(func(tx *badger.Txn) error {
+		meta, err := ti.getMetadata(tx)
+		if err != nil {
+			return err
+		}
+		if height <= meta.LastHeight {
+			return fmt.Errorf("consensus/indexer: height %d already indexed (last indexed: %d)", height, meta.LastHeight)
+		}
+
+		for addr, ats := range addressTransactions(height, txs) {
+			addr := addr
+			for _, at := range ats {
+				if err = tx.Set(addressTxKeyFmt.Encode(&addr, uint64(height), at.Index), cbor.Marshal(at)); err != nil {
+					return err
+				}
+				if err = tx.Set(heightAddressKeyFmt.Encode(uint64(height), &addr, at.Index), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if meta.FirstHeight == 0 {
+			meta.FirstHeight = height
+		}
+		meta.LastHeight = height
+		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+	})
+}
+
+// query returns the indexed transactions matching the given request. The request must have been
+// validated before.
+func (ti *txIndex) query(ctx context.Context, request *consensus.GetTransactionsByAddressRequest) (*consensus.GetTransactionsByAddressResponse, error) {
+	rsp := consensus.GetTransactionsByAddressResponse{
+		Transactions: []*consensus.AddressTransaction{},
+	}
+	txErr := ti.db.View(func(tx *badger.Txn) error {
+		meta, err := ti.getMetadata(tx)
+		if err != nil {
+			return err
+		}
+		rsp.IndexedHeightMin = meta.FirstHeight
+		rsp.IndexedHeightMax = meta.LastHeight
+
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix: addressTxKeyFmt.Encode(&request.Address),
+		})
+		defer it.Close()
+
+		start := addressTxKeyFmt.Encode(&request.Address, uint64(request.HeightMin))
+		if after := request.After; after != nil && after.Height >= request.HeightMin {
+			start = addressTxKeyFmt.Encode(&request.Address, uint64(after.Height), after.Index)
+		}
+
+		for it.Seek(start); it.Valid(); it.Next() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			var (
+				addr   staking.Address
+				height uint64
+				index  uint32
+			)
+			if !addressTxKeyFmt.Decode(it.Item().Key(), &addr, &height, &index) {
+				// This should not happen as the Badger iterator should take care of it.
+				panic("consensus/indexer: bad iterator")
+			}
+			if request.HeightMax != 0 && height > uint64(request.HeightMax) {
+				break
+			}
+			if after := request.After; after != nil && int64(height) == after.Height && index <= after.Index {
+				continue
+			}
+
+			var at consensus.AddressTransaction
+			if err = it.Item().Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &at)
+			}); err != nil {
+				return err
+			}
+			if !request.MatchesTransaction(&at) {
+				continue
+			}
+
+			if uint64(len(rsp.Transactions)) >= request.Limit {
+				// There are more results, so the caller should continue after the last one.
+				last := rsp.Transactions[len(rsp.Transactions)-1].TransactionPosition
+				rsp.Next = &last
+				break
+			}
+			rsp.Transactions = append(rsp.Transactions, &at)
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return &rsp, nil
+}
+
+// prune removes the index entries for the given height.
+func (ti *txIndex) prune(height int64) error {
+	return ti.db.Update(func(tx *badger.Txn) error {
+		err := cmnBadger.DeleteIndexed(tx, heightAddressKeyFmt.Encode(uint64(height)), func(key []byte) ([]byte, bool) {
+			var (
+				decHeight uint64
+				addr      staking.Address
+				index     uint32
+			)
+			if !heightAddressKeyFmt.Decode(key, &decHeight, &addr, &index) {
+				return nil, false
+			}
+			return addressTxKeyFmt.Encode(&addr, decHeight, index), true
+		})
+		if err != nil {
+			return err
+		}
+
+		meta, err := ti.getMetadata(tx)
+		if err != nil {
+			return err
+		}
+		if height < meta.FirstHeight {
+			return nil
+		}
+		meta.FirstHeight = height + 1
+		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+	})
+}
+
+func (ti *txIndex) close() {
+	ti.gc.Close()
+	ti.db.Close()
+}
+
+func (ti *txIndex) ensureMetadata(chainContext string) error {
+	initial := dbMetadata{
+		ChainContext: chainContext,
+		Version:      dbVersion,
+	}
+	return cmnBadger.EnsureMetadata(ti.db, metadataKeyFmt.Encode(), &initial, func(raw []byte) error {
+		var meta dbMetadata
+		if err := cbor.Unmarshal(raw, &meta); err != nil {
+			return err
+		}
+
+		// Verify metadata section.
+		if meta.Version != dbVersion {
+			return fmt.Errorf("consensus/indexer: incompatible database version (expected: %d got: %d)",
+				dbVersion,
+				meta.Version,
+			)
+		}
+		if meta.ChainContext != chainContext {
+			return fmt.Errorf("consensus/indexer: database for different chain (expected: %s got: %s)",
+				chainContext,
+				meta.ChainContext,
+			)
+		}
+		return nil
+	})
+}
+
+func openTxIndex(fn, chainContext string) (*txIndex, error) {
+	logger := logging.GetLogger("consensus/indexer").With("path", fn)
+
+	db, gc, err := cmnBadger.OpenIndex(fn, logger)
+	if err != nil {
+		return nil, fmt.Errorf("consensus/indexer: %w", err)
+	}
+
+	ti := &txIndex{
+		logger: logger,
+		db:     db,
+		gc:     gc,
+	}
+
+	if err = ti.ensureMetadata(chainContext); err != nil {
+		ti.close()
+		return nil, err
+	}
+
+	return ti, nil
+}
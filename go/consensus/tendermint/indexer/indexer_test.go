@@ -0,0 +1,180 @@
+package indexer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const testChainContext = "test: consensus indexer"
+
+func TestTxIndex(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext(testChainContext)
+
+	dataDir, err := ioutil.TempDir("", "oasis-consensus-indexer-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	ti, err := openTxIndex(filepath.Join(dataDir, DbFilename), testChainContext)
+	require.NoError(err, "openTxIndex")
+
+	signer1 := memorySigner.NewTestSigner("consensus indexer test signer 1")
+	signer2 := memorySigner.NewTestSigner("consensus indexer test signer 2")
+	addr1 := staking.NewAddress(signer1.Public())
+	addr2 := staking.NewAddress(signer2.Public())
+	account := &multisig.Account{
+		Signers:   []signature.PublicKey{signer1.Public(), signer2.Public()},
+		Threshold: 1,
+	}
+	addrMultisig := staking.NewMultisigAddress(account)
+
+	transfer := func(nonce uint64, to staking.Address) *transaction.Transaction {
+		return staking.NewTransferTx(nonce, nil, &staking.Transfer{To: to})
+	}
+	signTx := func(signer signature.Signer, tx *transaction.Transaction) []byte {
+		sigTx, err := transaction.Sign(signer, tx)
+		require.NoError(err, "Sign")
+		return cbor.Marshal(sigTx)
+	}
+	transferResult := func(from, to staking.Address) *results.Result {
+		return &results.Result{Events: []*results.Event{
+			{Staking: &staking.Event{Transfer: &staking.TransferEvent{From: from, To: to}}},
+		}}
+	}
+
+	// Height 10: addr1 -> addr2 transfer and a failed addr2 burn.
+	err = ti.index(10, &consensus.TransactionsWithResults{
+		Transactions: [][]byte{
+			signTx(signer1, transfer(0, addr2)),
+			signTx(signer2, staking.NewBurnTx(0, nil, &staking.Burn{})),
+		},
+		Results: []*results.Result{
+			transferResult(addr1, addr2),
+			{Error: results.Error{Module: staking.ModuleName, Code: 1}},
+		},
+	})
+	require.NoError(err, "index")
+
+	// Height 11: multisig -> addr1 transfer and a malformed transaction.
+	msTx, err := transaction.NewMultiSignedTransaction(account, transfer(0, addr1))
+	require.NoError(err, "NewMultiSignedTransaction")
+	require.NoError(msTx.Sign(signer2), "Sign")
+	err = ti.index(11, &consensus.TransactionsWithResults{
+		Transactions: [][]byte{cbor.Marshal(msTx), []byte("malformed")},
+		Results:      []*results.Result{transferResult(addrMultisig, addr1), {}},
+	})
+	require.NoError(err, "index")
+
+	err = ti.index(11, &consensus.TransactionsWithResults{})
+	require.Error(err, "indexing the same height twice should fail")
+
+	lastHeight, err := ti.lastHeight()
+	require.NoError(err, "lastHeight")
+	require.EqualValues(11, lastHeight)
+
+	query := func(rq consensus.GetTransactionsByAddressRequest) *consensus.GetTransactionsByAddressResponse {
+		require.NoError(rq.ValidateBasic(), "ValidateBasic")
+		rsp, err := ti.query(context.Background(), &rq)
+		require.NoError(err, "query")
+		return rsp
+	}
+
+	rsp := query(consensus.GetTransactionsByAddressRequest{Address: addr1})
+	require.Len(rsp.Transactions, 2)
+	require.Nil(rsp.Next)
+	require.EqualValues(10, rsp.IndexedHeightMin, "response should include the indexed range")
+	require.EqualValues(11, rsp.IndexedHeightMax, "response should include the indexed range")
+	require.EqualValues(consensus.TransactionPosition{Height: 10, Index: 0}, rsp.Transactions[0].TransactionPosition)
+	require.EqualValues(staking.MethodTransfer, rsp.Transactions[0].Method)
+	require.True(rsp.Transactions[0].Signer)
+	require.True(rsp.Transactions[0].EventParticipant)
+	require.True(rsp.Transactions[0].Success)
+	require.EqualValues(consensus.TransactionPosition{Height: 11, Index: 0}, rsp.Transactions[1].TransactionPosition)
+	require.False(rsp.Transactions[1].Signer, "multisig signers should not be indexed as signers")
+	require.True(rsp.Transactions[1].EventParticipant)
+
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr2})
+	require.Len(rsp.Transactions, 2)
+	require.False(rsp.Transactions[0].Signer)
+	require.True(rsp.Transactions[0].EventParticipant)
+	require.EqualValues(staking.MethodBurn, rsp.Transactions[1].Method)
+	require.True(rsp.Transactions[1].Signer)
+	require.False(rsp.Transactions[1].Success)
+
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addrMultisig})
+	require.Len(rsp.Transactions, 1)
+	require.True(rsp.Transactions[0].Signer)
+	require.EqualValues(msTx.Hash(), rsp.Transactions[0].TxHash)
+
+	// Method filter.
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr2, Methods: []transaction.MethodName{staking.MethodBurn}})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(staking.MethodBurn, rsp.Transactions[0].Method)
+
+	// Height range.
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr1, HeightMin: 11})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(11, rsp.Transactions[0].Height)
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr1, HeightMax: 10})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(10, rsp.Transactions[0].Height)
+
+	// Pagination.
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr1, Limit: 1})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(10, rsp.Transactions[0].Height)
+	require.NotNil(rsp.Next)
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr1, Limit: 1, After: rsp.Next})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(11, rsp.Transactions[0].Height)
+	require.Nil(rsp.Next)
+
+	// Pruning.
+	require.NoError(ti.prune(10), "prune")
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr2})
+	require.Empty(rsp.Transactions, "pruned transactions should be removed")
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr1})
+	require.Len(rsp.Transactions, 1)
+	require.EqualValues(11, rsp.Transactions[0].Height)
+	require.EqualValues(11, rsp.IndexedHeightMin, "pruning should advance the indexed range")
+	require.EqualValues(11, rsp.IndexedHeightMax)
+
+	// Batch transactions should also match method filters by their calls.
+	batchTx := transaction.NewTransaction(1, nil, consensus.MethodBatch, &consensus.Batch{
+		Calls: []consensus.BatchCall{
+			{Method: staking.MethodTransfer, Body: cbor.Marshal(&staking.Transfer{To: addr1})},
+			{Method: staking.MethodBurn, Body: cbor.Marshal(&staking.Burn{})},
+		},
+	})
+	err = ti.index(12, &consensus.TransactionsWithResults{
+		Transactions: [][]byte{signTx(signer2, batchTx)},
+		Results:      []*results.Result{transferResult(addr2, addr1)},
+	})
+	require.NoError(err, "index")
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr2, Methods: []transaction.MethodName{staking.MethodBurn}})
+	require.Len(rsp.Transactions, 1, "method filter should match batch calls")
+	require.EqualValues(consensus.MethodBatch, rsp.Transactions[0].Method)
+	require.EqualValues([]transaction.MethodName{staking.MethodTransfer, staking.MethodBurn}, rsp.Transactions[0].Calls)
+	rsp = query(consensus.GetTransactionsByAddressRequest{Address: addr2, Methods: []transaction.MethodName{staking.MethodAddEscrow}})
+	require.Empty(rsp.Transactions, "method filter should not match other batch calls")
+
+	// Reopening the index for a different chain should fail.
+	ti.close()
+	_, err = openTxIndex(filepath.Join(dataDir, DbFilename), "test: other chain")
+	require.Error(err, "openTxIndex should fail for a different chain")
+}
@@ -0,0 +1,275 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+)
+
+const (
+	serviceName = "indexer"
+
+	// retryInterval is the interval after which indexing is retried in case it fails.
+	retryInterval = 5 * time.Second
+	// maxPruneVetoDuration is the maximum duration for which pruning of not yet indexed blocks is
+	// vetoed. After that the unindexed blocks are pruned anyway to bound the consensus state size.
+	maxPruneVetoDuration = 10 * time.Minute
+)
+
+var (
+	_ ServiceClient           = (*serviceClient)(nil)
+	_ tmapi.StatePruneHandler = (*serviceClient)(nil)
+)
+
+// ServiceClient is the consensus transaction indexer service client.
+//
+// The indexer follows the chain and indexes the signer addresses, the staking event participants
+// and the methods of all transactions in each block. Index entries are pruned together with the
+// consensus state.
+type ServiceClient interface {
+	tmapi.ServiceClient
+
+	// GetTransactionsByAddress returns indexed transactions that involve the given address.
+	GetTransactionsByAddress(ctx context.Context, request *consensus.GetTransactionsByAddressRequest) (*consensus.GetTransactionsByAddressResponse, error)
+
+	// Cleanup closes the index database.
+	Cleanup()
+}
+
+type serviceClient struct {
+	tmapi.BaseServiceClient
+
+	// indexLock serializes indexing and pruning.
+	indexLock sync.Mutex
+	// pruneVetoStart is the time when pruning was first vetoed as the indexer was lagging behind.
+	pruneVetoStart time.Time
+
+	// targetLock protects targetHeight.
+	targetLock sync.Mutex
+	// targetHeight is the latest height that should be indexed.
+	targetHeight int64
+
+	logger *logging.Logger
+
+	backend tmapi.Backend
+	idx     *txIndex
+
+	cancelCtx context.CancelFunc
+	notifyCh  chan struct{}
+	quitCh    chan struct{}
+}
+
+// Implements ServiceClient.
+func (sc *serviceClient) GetTransactionsByAddress(ctx context.Context, request *consensus.GetTransactionsByAddressRequest) (*consensus.GetTransactionsByAddressResponse, error) {
+	rq := *request
+	if err := rq.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return sc.idx.query(ctx, &rq)
+}
+
+// Implements ServiceClient.
+func (sc *serviceClient) Cleanup() {
+	sc.cancelCtx()
+	<-sc.quitCh
+
+	sc.idx.close()
+}
+
+// Implements api.ServiceClient.
+func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
+	// The indexer only needs block notifications.
+	return tmapi.NewStaticServiceDescriptor(serviceName, "", nil)
+}
+
+// Implements api.ServiceClient.
+//
+// Indexing happens in the background so that catching up with the chain does not block the
+// delivery of blocks to other services.
+func (sc *serviceClient) DeliverBlock(ctx context.Context, height int64) error {
+	sc.targetLock.Lock()
+	if height > sc.targetHeight {
+		sc.targetHeight = height
+	}
+	sc.targetLock.Unlock()
+
+	select {
+	case sc.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (sc *serviceClient) worker(ctx context.Context) {
+	defer close(sc.quitCh)
+
+	var retryCh <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sc.notifyCh:
+		case <-retryCh:
+		}
+
+		sc.targetLock.Lock()
+		height := sc.targetHeight
+		sc.targetLock.Unlock()
+
+		retryCh = nil
+		if err := sc.catchUp(ctx, height); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			sc.logger.Error("failed to index transactions",
+				"err", err,
+				"height", height,
+			)
+			retryCh = time.After(retryInterval)
+		}
+	}
+}
+
+// catchUp indexes all heights after the last indexed height up to and including the given height.
+func (sc *serviceClient) catchUp(ctx context.Context, height int64) error {
+	lastHeight, err := sc.lastHeight()
+	if err != nil {
+		return err
+	}
+
+	// Start after the last indexed height, but never before the earliest retained height as
+	// earlier blocks may not be available (e.g., after state sync or pruning).
+	fromHeight := lastHeight + 1
+	lastRetainedHeight, err := sc.backend.GetLastRetainedVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("indexer: failed to get last retained height: %w", err)
+	}
+	if fromHeight < lastRetainedHeight {
+		fromHeight = lastRetainedHeight
+	}
+	genesis, err := sc.backend.GetGenesisDocument(ctx)
+	if err != nil {
+		return fmt.Errorf("indexer: failed to get genesis document: %w", err)
+	}
+	if fromHeight < genesis.Height {
+		fromHeight = genesis.Height
+	}
+
+	if height-fromHeight > 1 {
+		sc.logger.Info("catching up with the chain",
+			"from_height", fromHeight,
+			"to_height", height,
+		)
+	}
+
+	for h := fromHeight; h <= height; h++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		txs, err := sc.backend.GetTransactionsWithResults(ctx, h)
+		if err != nil {
+			return fmt.Errorf("indexer: failed to get transactions at height %d: %w", h, err)
+		}
+		if err = sc.indexHeight(h, txs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *serviceClient) lastHeight() (int64, error) {
+	sc.indexLock.Lock()
+	defer sc.indexLock.Unlock()
+
+	lastHeight, err := sc.idx.lastHeight()
+	if err != nil {
+		return 0, fmt.Errorf("indexer: failed to get last indexed height: %w", err)
+	}
+	return lastHeight, nil
+}
+
+func (sc *serviceClient) indexHeight(height int64, txs *consensus.TransactionsWithResults) error {
+	// Only hold the lock for a single height so that pruning can proceed while catching up.
+	sc.indexLock.Lock()
+	defer sc.indexLock.Unlock()
+
+	lastHeight, err := sc.idx.lastHeight()
+	if err != nil {
+		return fmt.Errorf("indexer: failed to get last indexed height: %w", err)
+	}
+	if height <= lastHeight {
+		// Already indexed.
+		return nil
+	}
+
+	if err = sc.idx.index(height, txs); err != nil {
+		return fmt.Errorf("indexer: failed to index height %d: %w", height, err)
+	}
+	return nil
+}
+
+// Implements api.StatePruneHandler.
+func (sc *serviceClient) Prune(ctx context.Context, version uint64) error {
+	sc.indexLock.Lock()
+	defer sc.indexLock.Unlock()
+
+	lastHeight, err := sc.idx.lastHeight()
+	if err != nil {
+		return fmt.Errorf("indexer: failed to get last indexed height: %w", err)
+	}
+	// Make sure that blocks are not pruned before they are indexed, unless the indexer has been
+	// lagging behind for too long in which case the unindexed blocks are skipped.
+	switch {
+	case int64(version) <= lastHeight:
+		sc.pruneVetoStart = time.Time{}
+	case sc.pruneVetoStart.IsZero():
+		sc.pruneVetoStart = time.Now()
+		return fmt.Errorf("indexer: version %d not yet indexed", version)
+	case time.Since(sc.pruneVetoStart) < maxPruneVetoDuration:
+		return fmt.Errorf("indexer: version %d not yet indexed", version)
+	default:
+		sc.logger.Warn("indexer lagging behind for too long, allowing unindexed blocks to be pruned",
+			"version", version,
+			"last_indexed_height", lastHeight,
+		)
+	}
+
+	return sc.idx.prune(int64(version))
+}
+
+// New creates a new consensus transaction indexer storing its database in the given directory.
+func New(ctx context.Context, dataDir string, backend tmapi.Backend) (ServiceClient, error) {
+	chainContext, err := backend.GetChainContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("indexer: failed to get chain context: %w", err)
+	}
+
+	idx, err := openTxIndex(filepath.Join(dataDir, DbFilename), chainContext)
+	if err != nil {
+		return nil, err
+	}
+
+	workerCtx, cancelCtx := context.WithCancel(ctx)
+	sc := &serviceClient{
+		logger:    logging.GetLogger("consensus/tendermint/indexer"),
+		backend:   backend,
+		idx:       idx,
+		cancelCtx: cancelCtx,
+		notifyCh:  make(chan struct{}, 1),
+		quitCh:    make(chan struct{}),
+	}
+	go sc.worker(workerCtx)
+
+	// Register a consensus state prune handler so that index entries are pruned together with
+	// the blocks they refer to.
+	backend.Pruner().RegisterHandler(sc)
+
+	return sc, nil
+}
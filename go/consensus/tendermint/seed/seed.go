@@ -22,6 +22,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -153,6 +154,11 @@ func (srv *seedService) GetNextBlockState(ctx context.Context) (*consensus.NextB
 	return nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GasPrice(ctx context.Context) (*quantity.Quantity, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetGenesisDocument(ctx context.Context) (*genesis.Document, error) {
 	return srv.doc, nil
@@ -194,6 +200,11 @@ func (srv *seedService) SubmitTx(ctx context.Context, tx *transaction.SignedTran
 	return consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) SubmitMultiSignedTx(ctx context.Context, tx *transaction.MultiSignedTransaction) error {
+	return consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	return nil, consensus.ErrUnsupported
@@ -204,6 +215,11 @@ func (srv *seedService) EstimateGas(ctx context.Context, req *consensus.Estimate
 	return 0, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) SimulateTx(ctx context.Context, req *consensus.SimulateTxRequest) (*consensus.SimulateTxResponse, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetBlock(ctx context.Context, height int64) (*consensus.Block, error) {
 	return nil, consensus.ErrUnsupported
@@ -219,6 +235,11 @@ func (srv *seedService) GetTransactionsWithResults(ctx context.Context, height i
 	return nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) GetTransactionsByAddress(ctx context.Context, request *consensus.GetTransactionsByAddressRequest) (*consensus.GetTransactionsByAddressResponse, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
 	return nil, consensus.ErrUnsupported
@@ -229,6 +250,11 @@ func (srv *seedService) WatchBlocks(ctx context.Context) (<-chan *consensus.Bloc
 	return nil, nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) WatchAllEvents(ctx context.Context, request *consensus.WatchAllEventsRequest) (<-chan *consensus.Event, pubsub.ClosableSubscription, error) {
+	return nil, nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetSignerNonce(ctx context.Context, req *consensus.GetSignerNonceRequest) (uint64, error) {
 	return 0, consensus.ErrUnsupported
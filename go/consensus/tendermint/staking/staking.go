@@ -83,6 +83,15 @@ func (sc *serviceClient) LastBlockFees(ctx context.Context, height int64) (*quan
 	return q.LastBlockFees(ctx)
 }
 
+func (sc *serviceClient) BaseGasPrice(ctx context.Context, height int64) (*quantity.Quantity, error) {
+	q, err := sc.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.BaseGasPrice(ctx)
+}
+
 func (sc *serviceClient) GovernanceDeposits(ctx context.Context, height int64) (*quantity.Quantity, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -186,6 +195,19 @@ func (sc *serviceClient) Allowance(ctx context.Context, query *api.AllowanceQuer
 	return &allowance, nil
 }
 
+func (sc *serviceClient) Vestings(ctx context.Context, query *api.OwnerQuery) ([]*api.Vesting, error) {
+	acct, err := sc.Account(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	vestings := make([]*api.Vesting, 0, len(acct.Vesting.Schedules))
+	for i := range acct.Vesting.Schedules {
+		vestings = append(vestings, &acct.Vesting.Schedules[i])
+	}
+	return vestings, nil
+}
+
 func (sc *serviceClient) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	// Query the staking genesis state.
 	q, err := sc.querier.QueryAt(ctx, height)
@@ -398,6 +420,16 @@ func EventsFromTendermint(
 
 				evt := &api.Event{Height: height, TxHash: txHash, AllowanceChange: &e}
 				events = append(events, evt)
+			case eventsAPI.IsAttributeKind(key, &api.VestingEvent{}):
+				// Vesting event.
+				var e api.VestingEvent
+				if err := eventsAPI.DecodeValue(string(val), &e); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("staking: corrupt Vesting event: %w", err))
+					continue
+				}
+
+				evt := &api.Event{Height: height, TxHash: txHash, Vesting: &e}
+				events = append(events, evt)
 			default:
 				errs = multierror.Append(errs, fmt.Errorf("staking: unknown event type: key: %s, val: %s", key, val))
 			}
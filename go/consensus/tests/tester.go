@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -75,9 +76,23 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 		"GetTransactionsWithResults.Results length mismatch",
 	)
 
+	// The transaction indexer is optional so the method may not be supported.
+	_, err = backend.GetTransactionsByAddress(ctx, &consensus.GetTransactionsByAddressRequest{
+		Limit: consensus.MaxTransactionsByAddressLimit + 1,
+	})
+	require.Error(err, "GetTransactionsByAddress with an invalid limit should fail")
+	require.True(
+		errors.Is(err, consensus.ErrInvalidArgument) || errors.Is(err, consensus.ErrUnsupported),
+		"GetTransactionsByAddress with an invalid limit should fail with the correct error",
+	)
+
 	_, err = backend.GetUnconfirmedTransactions(ctx)
 	require.NoError(err, "GetUnconfirmedTransactions")
 
+	gasPrice, err := backend.GasPrice(ctx)
+	require.NoError(err, "GasPrice")
+	require.True(gasPrice.IsValid(), "GasPrice should return a valid gas price")
+
 	blockCh, blockSub, err := backend.WatchBlocks(ctx)
 	require.NoError(err, "WatchBlocks")
 	defer blockSub.Close()
@@ -94,6 +109,38 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 		}
 	}
 
+	_, _, err = backend.WatchAllEvents(ctx, &consensus.WatchAllEventsRequest{FromHeight: -1})
+	require.ErrorIs(err, consensus.ErrInvalidArgument, "WatchAllEvents with negative height should fail")
+
+	// Replay events from a past height, which should only include events from the selected module.
+	evCh, evSub, err := backend.WatchAllEvents(ctx, &consensus.WatchAllEventsRequest{
+		Filter:     consensus.EventFilter{Modules: []string{staking.ModuleName}},
+		FromHeight: status.LastRetainedHeight,
+	})
+	require.NoError(err, "WatchAllEvents")
+	defer evSub.Close()
+
+	lastEvHeight := status.LastRetainedHeight
+	lastEndHeight := status.LastRetainedHeight - 1
+	for done := false; !done; {
+		select {
+		case ev := <-evCh:
+			require.NotNil(ev, "returned event should not be nil")
+			require.True(ev.Height >= lastEvHeight, "event heights should not decrease")
+			lastEvHeight = ev.Height
+			if ev.EndOfHeight {
+				require.EqualValues(lastEndHeight+1, ev.Height, "each height should be terminated by a marker")
+				lastEndHeight = ev.Height
+				continue
+			}
+			require.EqualValues(staking.ModuleName, ev.Module(), "returned event should pass the filter")
+			require.True(ev.Height > lastEndHeight, "events should precede their end of height marker")
+		case <-time.After(recvTimeout):
+			done = true
+		}
+	}
+	require.True(lastEndHeight >= status.LastRetainedHeight, "end of height markers should be emitted")
+
 	_, err = backend.EstimateGas(ctx, &consensus.EstimateGasRequest{})
 	require.ErrorIs(err, consensus.ErrInvalidArgument, "EstimateGas with nil transaction should fail")
 
@@ -103,6 +150,18 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	})
 	require.NoError(err, "EstimateGas")
 
+	_, err = backend.SimulateTx(ctx, &consensus.SimulateTxRequest{Height: consensus.HeightLatest})
+	require.ErrorIs(err, consensus.ErrInvalidArgument, "SimulateTx with nil transaction should fail")
+
+	simRsp, err := backend.SimulateTx(ctx, &consensus.SimulateTxRequest{
+		Signer:      memorySigner.NewTestSigner("simulate tx signer").Public(),
+		Transaction: transaction.NewTransaction(0, nil, staking.MethodTransfer, &staking.Transfer{}),
+		Height:      consensus.HeightLatest,
+	})
+	require.NoError(err, "SimulateTx")
+	require.NotNil(simRsp.Result, "SimulateTx should return a transaction result")
+	require.True(simRsp.Height > 0, "SimulateTx should return the simulation height")
+
 	nonce, err := backend.GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
 		AccountAddress: staking.NewAddress(
 			signature.NewPublicKey("badfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
@@ -144,6 +203,15 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	err = backend.SubmitTxNoWait(ctx, testSigTx)
 	require.NoError(err, "SubmitTxNoWait")
 
+	testMultisigAccount := multisig.Account{
+		Signers:   []signature.PublicKey{testSigner.Public()},
+		Threshold: 1,
+	}
+	testMultiSigTx, err := transaction.NewMultiSignedTransaction(&testMultisigAccount, testTx)
+	require.NoError(err, "transaction.NewMultiSignedTransaction")
+	err = backend.SubmitMultiSignedTx(ctx, testMultiSigTx)
+	require.Error(err, "SubmitMultiSignedTx should fail with insufficient signatures")
+
 	err = backend.SubmitEvidence(ctx, &consensus.Evidence{})
 	require.Error(err, "SubmitEvidence should fail with invalid evidence")
 
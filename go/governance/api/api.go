@@ -2,11 +2,13 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
@@ -24,6 +26,15 @@ const ModuleName = "governance"
 // ProposalContent.
 const ProposalContentInvalidText = "(invalid)"
 
+const (
+	// MaxSignalingTitleLength is the maximum length of a signaling proposal
+	// title.
+	MaxSignalingTitleLength = 256
+	// MaxSignalingURLLength is the maximum length of a signaling proposal
+	// URL.
+	MaxSignalingURLLength = 1024
+)
+
 var (
 	// ErrInvalidArgument is the error returned on malformed argument(s).
 	ErrInvalidArgument = errors.New(ModuleName, 1, "governance: invalid argument")
@@ -39,6 +50,12 @@ var (
 	ErrNotEligible = errors.New(ModuleName, 6, "governance: not eligible")
 	// ErrVotingIsClosed is the error returned when a vote is cast for a non-active proposal.
 	ErrVotingIsClosed = errors.New(ModuleName, 7, "governance: voting is closed")
+	// ErrInvalidParameterChanges is the error returned when the consensus parameter changes
+	// proposed by a change parameters proposal are not valid for the target module.
+	ErrInvalidParameterChanges = errors.New(ModuleName, 8, "governance: invalid consensus parameter changes")
+	// ErrTooManyVotes is the error returned when the maximum number of delegator votes for a
+	// proposal has been reached.
+	ErrTooManyVotes = errors.New(ModuleName, 9, "governance: too many votes")
 
 	// MethodSubmitProposal submits a new consensus layer governance proposal.
 	MethodSubmitProposal = transaction.NewMethodName(ModuleName, "SubmitProposal", ProposalContent{})
@@ -54,25 +71,51 @@ var (
 	_ prettyprint.PrettyPrinter = (*ProposalContent)(nil)
 	_ prettyprint.PrettyPrinter = (*UpgradeProposal)(nil)
 	_ prettyprint.PrettyPrinter = (*CancelUpgradeProposal)(nil)
+	_ prettyprint.PrettyPrinter = (*ChangeParametersProposal)(nil)
+	_ prettyprint.PrettyPrinter = (*SignalingProposal)(nil)
 	_ prettyprint.PrettyPrinter = (*ProposalVote)(nil)
 )
 
 // ProposalContent is a consensus layer governance proposal content.
 type ProposalContent struct {
-	Upgrade       *UpgradeProposal       `json:"upgrade,omitempty"`
-	CancelUpgrade *CancelUpgradeProposal `json:"cancel_upgrade,omitempty"`
+	Upgrade          *UpgradeProposal          `json:"upgrade,omitempty"`
+	CancelUpgrade    *CancelUpgradeProposal    `json:"cancel_upgrade,omitempty"`
+	ChangeParameters *ChangeParametersProposal `json:"change_parameters,omitempty"`
+	Signaling        *SignalingProposal        `json:"signaling,omitempty"`
+}
+
+// numFieldsSet returns the number of proposal content fields that are set.
+func (p *ProposalContent) numFieldsSet() int {
+	var n int
+	if p.Upgrade != nil {
+		n++
+	}
+	if p.CancelUpgrade != nil {
+		n++
+	}
+	if p.ChangeParameters != nil {
+		n++
+	}
+	if p.Signaling != nil {
+		n++
+	}
+	return n
 }
 
 // ValidateBasic performs basic proposal content validity checks.
 func (p *ProposalContent) ValidateBasic() error {
 	switch {
-	case p.Upgrade != nil && p.CancelUpgrade != nil:
+	case p.numFieldsSet() > 1:
 		return fmt.Errorf("proposal content has multiple fields set")
 	case p.Upgrade != nil:
 		return p.Upgrade.ValidateBasic()
 	case p.CancelUpgrade != nil:
 		// No validation at this time.
 		return nil
+	case p.ChangeParameters != nil:
+		return p.ChangeParameters.ValidateBasic()
+	case p.Signaling != nil:
+		return p.Signaling.ValidateBasic()
 	default:
 		return fmt.Errorf("proposal content has no fields set")
 	}
@@ -88,6 +131,10 @@ func (p *ProposalContent) Equals(other *ProposalContent) bool {
 		return p.CancelUpgrade.ProposalID == other.CancelUpgrade.ProposalID
 	case p.Upgrade != nil && other.Upgrade != nil:
 		return p.Upgrade.Descriptor.Equals(&other.Upgrade.Descriptor)
+	case p.ChangeParameters != nil && other.ChangeParameters != nil:
+		return p.ChangeParameters.Equals(other.ChangeParameters)
+	case p.Signaling != nil && other.Signaling != nil:
+		return p.Signaling.Equals(other.Signaling)
 	default:
 		return false
 	}
@@ -97,12 +144,20 @@ func (p *ProposalContent) Equals(other *ProposalContent) bool {
 // given writer.
 func (p ProposalContent) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 	switch {
-	case p.Upgrade != nil && p.CancelUpgrade == nil:
+	case p.numFieldsSet() != 1:
+		fmt.Fprintf(w, "%s%s\n", prefix, ProposalContentInvalidText)
+	case p.Upgrade != nil:
 		fmt.Fprintf(w, "%sUpgrade:\n", prefix)
 		p.Upgrade.PrettyPrint(ctx, prefix+"  ", w)
-	case p.CancelUpgrade != nil && p.Upgrade == nil:
+	case p.CancelUpgrade != nil:
 		fmt.Fprintf(w, "%sCancel Upgrade:\n", prefix)
 		p.CancelUpgrade.PrettyPrint(ctx, prefix+"  ", w)
+	case p.ChangeParameters != nil:
+		fmt.Fprintf(w, "%sChange Parameters:\n", prefix)
+		p.ChangeParameters.PrettyPrint(ctx, prefix+"  ", w)
+	case p.Signaling != nil:
+		fmt.Fprintf(w, "%sSignaling:\n", prefix)
+		p.Signaling.PrettyPrint(ctx, prefix+"  ", w)
 	default:
 		fmt.Fprintf(w, "%s%s\n", prefix, ProposalContentInvalidText)
 	}
@@ -149,6 +204,96 @@ func (cu CancelUpgradeProposal) PrettyType() (interface{}, error) {
 	return cu, nil
 }
 
+// ChangeParametersProposal is a consensus parameters change proposal.
+type ChangeParametersProposal struct {
+	// Module identifies the consensus backend module to which the changes
+	// should be applied.
+	Module string `json:"module"`
+	// Changes are CBOR-encoded module-specific consensus parameter changes.
+	Changes cbor.RawMessage `json:"changes"`
+}
+
+// ValidateBasic performs basic change parameters proposal validity checks.
+//
+// Note: The changes themselves are validated by the target module.
+func (p *ChangeParametersProposal) ValidateBasic() error {
+	if p.Module == "" {
+		return fmt.Errorf("change parameters proposal module not set")
+	}
+	if len(p.Changes) == 0 {
+		return fmt.Errorf("change parameters proposal changes not set")
+	}
+	return nil
+}
+
+// Equals checks if change parameters proposals are equal.
+func (p *ChangeParametersProposal) Equals(other *ChangeParametersProposal) bool {
+	return p.Module == other.Module && bytes.Equal(p.Changes, other.Changes)
+}
+
+// PrettyPrint writes a pretty-printed representation of ChangeParametersProposal
+// to the given writer.
+func (p ChangeParametersProposal) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sModule: %s\n", prefix, p.Module)
+	fmt.Fprintf(w, "%sChanges: %X\n", prefix, []byte(p.Changes))
+}
+
+// PrettyType returns a representation of ChangeParametersProposal that can be
+// used for pretty printing.
+func (p ChangeParametersProposal) PrettyType() (interface{}, error) {
+	return p, nil
+}
+
+// SignalingProposal is a non-binding signaling proposal.
+//
+// Passing a signaling proposal has no effect on the consensus layer state
+// apart from recording the voting outcome.
+type SignalingProposal struct {
+	// Title is the proposal title.
+	Title string `json:"title"`
+	// DescriptionHash is the hash of the off-chain proposal description.
+	DescriptionHash hash.Hash `json:"description_hash"`
+	// URL is an optional location of the off-chain proposal description.
+	URL string `json:"url,omitempty"`
+}
+
+// ValidateBasic performs basic signaling proposal validity checks.
+func (p *SignalingProposal) ValidateBasic() error {
+	switch {
+	case p.Title == "":
+		return fmt.Errorf("signaling proposal title not set")
+	case len(p.Title) > MaxSignalingTitleLength:
+		return fmt.Errorf("signaling proposal title too long (max: %d)", MaxSignalingTitleLength)
+	case p.DescriptionHash == hash.Hash{} || p.DescriptionHash.IsEmpty():
+		return fmt.Errorf("signaling proposal description hash not set")
+	case len(p.URL) > MaxSignalingURLLength:
+		return fmt.Errorf("signaling proposal URL too long (max: %d)", MaxSignalingURLLength)
+	default:
+		return nil
+	}
+}
+
+// Equals checks if signaling proposals are equal.
+func (p *SignalingProposal) Equals(other *SignalingProposal) bool {
+	return p.Title == other.Title && p.DescriptionHash.Equal(&other.DescriptionHash) && p.URL == other.URL
+}
+
+// PrettyPrint writes a pretty-printed representation of SignalingProposal to
+// the given writer.
+func (p SignalingProposal) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sTitle:            %s\n", prefix, p.Title)
+	fmt.Fprintf(w, "%sDescription Hash: %s\n", prefix, p.DescriptionHash)
+	if p.URL != "" {
+		fmt.Fprintf(w, "%sURL:              %s\n", prefix, p.URL)
+	}
+}
+
+// PrettyType returns a representation of SignalingProposal that can be used
+// for pretty printing.
+func (p SignalingProposal) PrettyType() (interface{}, error) {
+	return p, nil
+}
+
 // ProposalVote is a vote for a proposal.
 type ProposalVote struct {
 	// ID is the unique identifier of a proposal.
@@ -256,6 +401,22 @@ type ConsensusParameters struct {
 	// UpgradeCancelMinEpochDiff is the minimum number of epochs between the current
 	// epoch and the proposed upgrade epoch for the upgrade cancellation proposal to be valid.
 	UpgradeCancelMinEpochDiff beacon.EpochTime `json:"upgrade_cancel_min_epoch_diff,omitempty"`
+
+	// EnableChangeParametersProposal specifies whether consensus parameter change proposals
+	// are allowed.
+	EnableChangeParametersProposal bool `json:"enable_change_parameters_proposal,omitempty"`
+
+	// EnableDelegatorVotes specifies whether accounts delegating to validator entities can vote,
+	// overriding the validator's vote for their delegated stake.
+	EnableDelegatorVotes bool `json:"enable_delegator_votes,omitempty"`
+
+	// MinDelegatorVoteStake is the minimum amount of stake (in base units) that an account needs
+	// to have delegated to validator entities in order for its delegator vote to be counted.
+	MinDelegatorVoteStake quantity.Quantity `json:"min_delegator_vote_stake,omitempty"`
+
+	// MaxDelegatorVotesPerProposal is the maximum number of delegator votes that can be cast on
+	// a single proposal.
+	MaxDelegatorVotesPerProposal uint64 `json:"max_delegator_votes_per_proposal,omitempty"`
 }
 
 // Event signifies a governance event, returned via GetEvents.
@@ -3,11 +3,13 @@ package api
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -59,6 +61,84 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			msg: "change parameters proposal without module should fail",
+			p: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{
+					Changes: cbor.Marshal("changes"),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "change parameters proposal without changes should fail",
+			p: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{
+					Module: "test",
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "only one of Upgrade/ChangeParameters fields should be set",
+			p: &ProposalContent{
+				Upgrade: &UpgradeProposal{},
+				ChangeParameters: &ChangeParametersProposal{
+					Module:  "test",
+					Changes: cbor.Marshal("changes"),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "signaling proposal without title should fail",
+			p: &ProposalContent{
+				Signaling: &SignalingProposal{
+					DescriptionHash: hash.NewFromBytes([]byte("description")),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "signaling proposal without description hash should fail",
+			p: &ProposalContent{
+				Signaling: &SignalingProposal{
+					Title: "title",
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "signaling proposal with too long title should fail",
+			p: &ProposalContent{
+				Signaling: &SignalingProposal{
+					Title:           strings.Repeat("a", MaxSignalingTitleLength+1),
+					DescriptionHash: hash.NewFromBytes([]byte("description")),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "signaling proposal content should not fail",
+			p: &ProposalContent{
+				Signaling: &SignalingProposal{
+					Title:           "title",
+					DescriptionHash: hash.NewFromBytes([]byte("description")),
+					URL:             "https://example.com/proposal",
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			msg: "change parameters proposal content should not fail",
+			p: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{
+					Module:  "test",
+					Changes: cbor.Marshal("changes"),
+				},
+			},
+			shouldErr: false,
+		},
 	} {
 		err := tc.p.ValidateBasic()
 		if tc.shouldErr {
@@ -136,6 +216,56 @@ func TestProposalContentEquals(t *testing.T) {
 			},
 			equals: false,
 		},
+		{
+			msg: "signaling proposals should be equal",
+			p1: &ProposalContent{
+				Signaling: &SignalingProposal{Title: "test", DescriptionHash: hash.NewFromBytes([]byte("test"))},
+			},
+			p2: &ProposalContent{
+				Signaling: &SignalingProposal{Title: "test", DescriptionHash: hash.NewFromBytes([]byte("test"))},
+			},
+			equals: true,
+		},
+		{
+			msg: "signaling proposals should not be equal",
+			p1: &ProposalContent{
+				Signaling: &SignalingProposal{Title: "test", DescriptionHash: hash.NewFromBytes([]byte("test"))},
+			},
+			p2: &ProposalContent{
+				Signaling: &SignalingProposal{Title: "test", DescriptionHash: hash.NewFromBytes([]byte("test2"))},
+			},
+			equals: false,
+		},
+		{
+			msg: "change parameters proposals should be equal",
+			p1: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes")},
+			},
+			p2: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes")},
+			},
+			equals: true,
+		},
+		{
+			msg: "change parameters proposals with different modules should not be equal",
+			p1: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes")},
+			},
+			p2: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test2", Changes: cbor.Marshal("changes")},
+			},
+			equals: false,
+		},
+		{
+			msg: "change parameters proposals with different changes should not be equal",
+			p1: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes")},
+			},
+			p2: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes2")},
+			},
+			equals: false,
+		},
 	} {
 		require.Equal(t, tc.equals, tc.p1.Equals(tc.p2), tc.msg)
 	}
@@ -160,6 +290,18 @@ func TestProposalContentPrettyPrint(t *testing.T) {
 				CancelUpgrade: &CancelUpgradeProposal{ProposalID: 42},
 			},
 		},
+		{
+			expRegex: "^Signaling:",
+			p: &ProposalContent{
+				Signaling: &SignalingProposal{Title: "test", DescriptionHash: hash.NewFromBytes([]byte("test"))},
+			},
+		},
+		{
+			expRegex: "^Change Parameters:",
+			p: &ProposalContent{
+				ChangeParameters: &ChangeParametersProposal{Module: "test", Changes: cbor.Marshal("changes")},
+			},
+		},
 		{
 			expRegex: ProposalContentInvalidText,
 			p:        &ProposalContent{},
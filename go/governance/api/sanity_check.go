@@ -30,6 +30,13 @@ func (p *ConsensusParameters) SanityCheck() error {
 	if p.VotingPeriod >= p.UpgradeCancelMinEpochDiff {
 		return fmt.Errorf("voting_period should be less than upgrade_cancel_min_epoch_diff")
 	}
+	if !p.MinDelegatorVoteStake.IsValid() {
+		return fmt.Errorf("min_delegator_vote_stake has invalid value")
+	}
+	// If delegator votes are enabled, at least one delegator vote must be allowed.
+	if p.EnableDelegatorVotes && p.MaxDelegatorVotesPerProposal == 0 {
+		return fmt.Errorf("max_delegator_votes_per_proposal must be greater than zero when delegator votes are enabled")
+	}
 	return nil
 }
 
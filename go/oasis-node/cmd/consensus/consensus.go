@@ -19,6 +19,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
+	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 )
@@ -26,10 +27,14 @@ import (
 const (
 	// CfgSignerPub is the public key of the account that will sign an unsigned transaction in estimate gas.
 	CfgSignerPub = "consensus.signer_pub"
+
+	// CfgBatchCallFile configures the unsigned transaction files used as batch calls.
+	CfgBatchCallFile = "consensus.batch.call_file"
 )
 
 var (
-	signerPub string
+	signerPub      string
+	batchCallFiles []string
 
 	consensusCmd = &cobra.Command{
 		Use:   "consensus",
@@ -54,6 +59,18 @@ var (
 		Run:   doEstimateGas,
 	}
 
+	gasPriceCmd = &cobra.Command{
+		Use:   "gas_price",
+		Short: "Show the gas price suggested by the node",
+		Run:   doGasPrice,
+	}
+
+	genBatchCmd = &cobra.Command{
+		Use:   "gen_batch",
+		Short: "Generate a batch transaction executing unsigned transactions atomically",
+		Run:   doGenBatch,
+	}
+
 	nextBlockStateCmd = &cobra.Command{
 		Use: "next_block_state",
 		Run: doNextBlockState,
@@ -96,7 +113,11 @@ func loadTx() *transaction.SignedTransaction {
 }
 
 func loadUnsignedTx() *transaction.Transaction {
-	rawUnsignedTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
+	return loadUnsignedTxFile(viper.GetString(cmdConsensus.CfgTxFile))
+}
+
+func loadUnsignedTxFile(fn string) *transaction.Transaction {
+	rawUnsignedTx, err := ioutil.ReadFile(fn)
 	if err != nil {
 		logger.Error("failed to read raw serialized unsigned transaction",
 			"err", err,
@@ -177,6 +198,55 @@ func doEstimateGas(cmd *cobra.Command, args []string) {
 	fmt.Println(gas)
 }
 
+func doGasPrice(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	gasPrice, err := client.GasPrice(context.Background())
+	if err != nil {
+		logger.Error("failed to query gas price",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(gasPrice)
+}
+
+func doGenBatch(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	genesis := cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	// Only the methods and bodies of the given transactions are used, their nonces and fees are
+	// replaced by the ones of the batch transaction.
+	var batch consensus.Batch
+	for _, fn := range batchCallFiles {
+		tx := loadUnsignedTxFile(fn)
+		batch.Calls = append(batch.Calls, consensus.BatchCall{
+			Method: tx.Method,
+			Body:   tx.Body,
+		})
+	}
+	if err := batch.ValidateBasic(); err != nil {
+		logger.Error("invalid batch",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := consensus.NewBatchTx(nonce, fee, &batch)
+
+	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+}
+
 func doNextBlockState(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -209,6 +279,8 @@ func Register(parentCmd *cobra.Command) {
 		submitTxCmd,
 		showTxCmd,
 		estimateGasCmd,
+		gasPriceCmd,
+		genBatchCmd,
 		nextBlockStateCmd,
 	} {
 		consensusCmd.AddCommand(v)
@@ -224,6 +296,11 @@ func Register(parentCmd *cobra.Command) {
 	estimateGasCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	estimateGasCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	gasPriceCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	genBatchCmd.Flags().StringSliceVar(&batchCallFiles, CfgBatchCallFile, nil, "path to an unsigned transaction to include as a batch call (can be repeated)")
+	genBatchCmd.Flags().AddFlagSet(cmdConsensus.TxFlags)
+
 	nextBlockStateCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
 	parentCmd.AddCommand(consensusCmd)
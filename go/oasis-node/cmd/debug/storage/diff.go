@@ -0,0 +1,573 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	storageDatabase "github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage"
+)
+
+const (
+	cfgDiffRootType   = "storage.diff.root_type"
+	cfgDiffPrefix     = "storage.diff.prefix"
+	cfgDiffFormat     = "storage.diff.format"
+	cfgDiffOutput     = "storage.diff.output"
+	cfgDiffDecodeKeys = "storage.diff.decode_keys"
+
+	diffRootTypeState = "state"
+	diffRootTypeIO    = "io"
+
+	diffFormatText = "text"
+	diffFormatJSON = "json"
+	diffFormatCBOR = "cbor"
+
+	diffOpInsert = "insert"
+	diffOpUpdate = "update"
+	diffOpDelete = "delete"
+)
+
+var (
+	storageDiffCmd = &cobra.Command{
+		Use:   "diff <runtime-id> <round-a> <round-b>",
+		Short: "show state changes between two runtime rounds",
+		Long: "Show the keys that were inserted, updated or deleted between two rounds retained in the\n" +
+			"local node database.\n\n" +
+			"Changes are reconstructed from the stored write logs when they are available for all\n" +
+			"intermediate rounds, otherwise both trees are iterated and compared.",
+		Args: cobra.ExactArgs(3),
+		Run:  doDiff,
+	}
+
+	storageDiffFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	keyDecoders = make(map[node.RootType][]KeyDecoderFunc)
+)
+
+// KeyDecoderFunc decodes a key in a known format into a human-readable description.
+//
+// It returns false in case the key is not in a format known to the decoder.
+type KeyDecoderFunc func(key []byte) (string, bool)
+
+// RegisterKeyDecoder registers a key decoder for keys of trees with the given root type.
+//
+// Decoders are tried in registration order and the first successful description is used.
+func RegisterKeyDecoder(rootType node.RootType, fn KeyDecoderFunc) {
+	keyDecoders[rootType] = append(keyDecoders[rootType], fn)
+}
+
+func describeKey(rootType node.RootType, key []byte) string {
+	for _, fn := range keyDecoders[rootType] {
+		if description, ok := fn(key); ok {
+			return description
+		}
+	}
+	return ""
+}
+
+// diffHeader is the first item written when exporting a diff.
+type diffHeader struct {
+	OldRoot node.Root `json:"old_root"`
+	NewRoot node.Root `json:"new_root"`
+}
+
+// diffEntry is a single changed key.
+type diffEntry struct {
+	Op       string `json:"op"`
+	Key      []byte `json:"key"`
+	KeyInfo  string `json:"key_info,omitempty"`
+	OldValue []byte `json:"old_value,omitempty"`
+	NewValue []byte `json:"new_value,omitempty"`
+}
+
+func newDiffEntry(key, oldValue, newValue []byte) *diffEntry {
+	switch {
+	case oldValue == nil && newValue == nil:
+		return nil
+	case oldValue == nil:
+		return &diffEntry{Op: diffOpInsert, Key: key, NewValue: newValue}
+	case newValue == nil:
+		return &diffEntry{Op: diffOpDelete, Key: key, OldValue: oldValue}
+	case bytes.Equal(oldValue, newValue):
+		return nil
+	default:
+		return &diffEntry{Op: diffOpUpdate, Key: key, OldValue: oldValue, NewValue: newValue}
+	}
+}
+
+// normalizePrefixes sorts the given prefixes and removes any prefixes covered by other prefixes.
+func normalizePrefixes(prefixes [][]byte) [][]byte {
+	if len(prefixes) == 0 {
+		return [][]byte{nil}
+	}
+
+	sorted := append([][]byte{}, prefixes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	var result [][]byte
+	for _, prefix := range sorted {
+		if len(result) > 0 && bytes.HasPrefix(prefix, result[len(result)-1]) {
+			continue
+		}
+		result = append(result, prefix)
+	}
+	return result
+}
+
+func matchesPrefixes(key []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffWriteLogs computes the changes between the first and the last root by combining the write
+// logs between all consecutive roots.
+func diffWriteLogs(
+	ctx context.Context,
+	ndb db.NodeDB,
+	roots []node.Root,
+	prefixes [][]byte,
+	fn func(*diffEntry) error,
+) error {
+	// Merge write logs starting with the most recent one so that the latest value of each key is
+	// kept.
+	updates := make(map[string][]byte)
+	for i := len(roots) - 1; i > 0; i-- {
+		if roots[i-1].Hash.Equal(&roots[i].Hash) {
+			continue
+		}
+
+		it, err := ndb.GetWriteLog(ctx, roots[i-1], roots[i])
+		if err != nil {
+			return err
+		}
+		if _, err = storageDatabase.MergeWriteLog(updates, it); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		if !matchesPrefixes([]byte(key), prefixes) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	oldTree := mkvs.NewWithRoot(nil, ndb, roots[0])
+	defer oldTree.Close()
+
+	for _, key := range keys {
+		oldValue, err := oldTree.Get(ctx, []byte(key))
+		if err != nil {
+			return fmt.Errorf("failed to get old value: %w", err)
+		}
+		if entry := newDiffEntry([]byte(key), oldValue, updates[key]); entry != nil {
+			if err = fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffIterators computes the changes between two roots by iterating over both trees.
+func diffIterators(
+	ctx context.Context,
+	ndb db.NodeDB,
+	oldRoot, newRoot node.Root,
+	prefixes [][]byte,
+	fn func(*diffEntry) error,
+) error {
+	oldTree := mkvs.NewWithRoot(nil, ndb, oldRoot)
+	defer oldTree.Close()
+	newTree := mkvs.NewWithRoot(nil, ndb, newRoot)
+	defer newTree.Close()
+
+	for _, prefix := range prefixes {
+		if err := func() error {
+			oldIt := oldTree.NewIterator(ctx, mkvs.IteratorPrefetch(10_000))
+			defer oldIt.Close()
+			newIt := newTree.NewIterator(ctx, mkvs.IteratorPrefetch(10_000))
+			defer newIt.Close()
+
+			valid := func(it mkvs.Iterator) bool {
+				return it.Valid() && bytes.HasPrefix(it.Key(), prefix)
+			}
+
+			oldIt.Seek(prefix)
+			newIt.Seek(prefix)
+			for valid(oldIt) || valid(newIt) {
+				var (
+					entry *diffEntry
+					cmp   int
+				)
+				switch {
+				case !valid(oldIt):
+					cmp = 1
+				case !valid(newIt):
+					cmp = -1
+				default:
+					cmp = bytes.Compare(oldIt.Key(), newIt.Key())
+				}
+
+				switch {
+				case cmp < 0:
+					entry = newDiffEntry(oldIt.Key(), oldIt.Value(), nil)
+					oldIt.Next()
+				case cmp > 0:
+					entry = newDiffEntry(newIt.Key(), nil, newIt.Value())
+					newIt.Next()
+				default:
+					entry = newDiffEntry(newIt.Key(), oldIt.Value(), newIt.Value())
+					oldIt.Next()
+					newIt.Next()
+				}
+				if entry != nil {
+					if err := fn(entry); err != nil {
+						return err
+					}
+				}
+			}
+			if err := oldIt.Err(); err != nil {
+				return fmt.Errorf("failed to iterate old tree: %w", err)
+			}
+			if err := newIt.Err(); err != nil {
+				return fmt.Errorf("failed to iterate new tree: %w", err)
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffRoots computes the changes between the first and the last of the given roots.
+//
+// If the roots are consecutive and write logs are available for all of them, the changes are
+// reconstructed from the write logs. Otherwise the first and the last tree are iterated and
+// compared instead.
+func diffRoots(
+	ctx context.Context,
+	ndb db.NodeDB,
+	roots []node.Root,
+	prefixes [][]byte,
+	fn func(*diffEntry) error,
+) error {
+	prefixes = normalizePrefixes(prefixes)
+
+	oldRoot, newRoot := roots[0], roots[len(roots)-1]
+	if oldRoot.Hash.Equal(&newRoot.Hash) {
+		return nil
+	}
+
+	useWriteLogs := true
+	for i := 1; i < len(roots); i++ {
+		useWriteLogs = useWriteLogs && roots[i].Follows(&roots[i-1])
+	}
+	if useWriteLogs {
+		// Write log entries need to be deduplicated before they can be emitted, so nothing is
+		// emitted until all write logs have been successfully retrieved.
+		var entries []*diffEntry
+		err := diffWriteLogs(ctx, ndb, roots, prefixes, func(entry *diffEntry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		switch {
+		case err == nil:
+			for _, entry := range entries {
+				if err = fn(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		case errors.Is(err, db.ErrWriteLogNotFound):
+			logger.Info("write logs not available, comparing trees")
+		default:
+			return err
+		}
+	}
+
+	return diffIterators(ctx, ndb, oldRoot, newRoot, prefixes, fn)
+}
+
+// getRoot returns the finalized root of the given type stored under the given version.
+func getRoot(ctx context.Context, ndb db.NodeDB, version uint64, rootType node.RootType) (*node.Root, error) {
+	roots, err := ndb.GetRootsForVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		if root.Type == rootType {
+			return &root, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s root for round %d", rootType, version)
+}
+
+type diffWriter interface {
+	WriteHeader(header *diffHeader) error
+	WriteEntry(entry *diffEntry) error
+}
+
+type textDiffWriter struct {
+	w io.Writer
+}
+
+func (tw *textDiffWriter) WriteHeader(header *diffHeader) error {
+	_, err := fmt.Fprintf(tw.w, "--- %s\n+++ %s\n", header.OldRoot, header.NewRoot)
+	return err
+}
+
+func (tw *textDiffWriter) WriteEntry(entry *diffEntry) error {
+	key := hex.EncodeToString(entry.Key)
+	if entry.KeyInfo != "" {
+		key = fmt.Sprintf("%s (%s)", key, entry.KeyInfo)
+	}
+
+	var err error
+	switch entry.Op {
+	case diffOpInsert:
+		_, err = fmt.Fprintf(tw.w, "+ %s: %X\n", key, entry.NewValue)
+	case diffOpUpdate:
+		_, err = fmt.Fprintf(tw.w, "~ %s: %X -> %X\n", key, entry.OldValue, entry.NewValue)
+	case diffOpDelete:
+		_, err = fmt.Fprintf(tw.w, "- %s: %X\n", key, entry.OldValue)
+	}
+	return err
+}
+
+type encoderDiffWriter struct {
+	encode func(v interface{}) error
+}
+
+func (ew *encoderDiffWriter) WriteHeader(header *diffHeader) error {
+	return ew.encode(header)
+}
+
+func (ew *encoderDiffWriter) WriteEntry(entry *diffEntry) error {
+	return ew.encode(entry)
+}
+
+func newDiffWriter(format string, w io.Writer) (diffWriter, error) {
+	switch strings.ToLower(format) {
+	case diffFormatText:
+		return &textDiffWriter{w: w}, nil
+	case diffFormatJSON:
+		return &encoderDiffWriter{encode: json.NewEncoder(w).Encode}, nil
+	case diffFormatCBOR:
+		return &encoderDiffWriter{encode: cbor.NewEncoder(w).Encode}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: '%s'", format)
+	}
+}
+
+func doDiff(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	ctx := context.Background()
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		return
+	}
+
+	var id common.Namespace
+	if err := id.UnmarshalHex(args[0]); err != nil {
+		logger.Error("malformed runtime id",
+			"err", err,
+		)
+		return
+	}
+	var rounds [2]uint64
+	for i, arg := range args[1:] {
+		round, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			logger.Error("malformed round",
+				"err", err,
+				"round", arg,
+			)
+			return
+		}
+		rounds[i] = round
+	}
+	if rounds[0] > rounds[1] {
+		logger.Error("the first round must not be after the second round")
+		return
+	}
+
+	var rootType node.RootType
+	switch rt := strings.ToLower(viper.GetString(cfgDiffRootType)); rt {
+	case diffRootTypeState:
+		rootType = node.RootTypeState
+	case diffRootTypeIO:
+		rootType = node.RootTypeIO
+	default:
+		logger.Error("unsupported root type",
+			"root_type", rt,
+		)
+		return
+	}
+	var prefixes [][]byte
+	for _, prefixHex := range viper.GetStringSlice(cfgDiffPrefix) {
+		prefix, err := hex.DecodeString(prefixHex)
+		if err != nil {
+			logger.Error("malformed key prefix",
+				"err", err,
+				"prefix", prefixHex,
+			)
+			return
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	decodeKeys := viper.GetBool(cfgDiffDecodeKeys)
+
+	var out io.Writer = os.Stdout
+	if fn := viper.GetString(cfgDiffOutput); fn != "" {
+		f, err := os.Create(fn)
+		if err != nil {
+			logger.Error("failed to create output file",
+				"err", err,
+				"fn", fn,
+			)
+			return
+		}
+		defer f.Close()
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+	defer bw.Flush()
+
+	w, err := newDiffWriter(viper.GetString(cfgDiffFormat), bw)
+	if err != nil {
+		logger.Error("failed to create diff writer",
+			"err", err,
+		)
+		return
+	}
+
+	runtimeDir := runtimeRegistry.GetRuntimeStateDir(dataDir, id)
+	backend := strings.ToLower(viper.GetString(storage.CfgBackend))
+	ndb, err := storageDatabase.NewNodeDB(backend, &db.Config{
+		DB:        storage.GetLocalBackendDBDir(runtimeDir, backend),
+		Namespace: id,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		logger.Error("failed to open node database",
+			"err", err,
+		)
+		return
+	}
+	defer ndb.Close()
+
+	// Collect roots for all rounds so that write logs can be used when available. In case any of
+	// the intermediate roots is not available, only the first and the last root are compared.
+	var endRoots [2]node.Root
+	for i, round := range rounds {
+		root, rerr := getRoot(ctx, ndb, round, rootType)
+		if rerr != nil {
+			logger.Error("failed to get root",
+				"err", rerr,
+				"round", round,
+			)
+			return
+		}
+		endRoots[i] = *root
+	}
+	roots := []node.Root{endRoots[0]}
+	for round := rounds[0] + 1; round < rounds[1]; round++ {
+		root, rerr := getRoot(ctx, ndb, round, rootType)
+		if rerr != nil {
+			logger.Info("intermediate root not available, comparing trees",
+				"err", rerr,
+				"round", round,
+			)
+			roots = roots[:1]
+			break
+		}
+		roots = append(roots, *root)
+	}
+	if rounds[1] > rounds[0] {
+		roots = append(roots, endRoots[1])
+	}
+
+	if err = w.WriteHeader(&diffHeader{OldRoot: roots[0], NewRoot: roots[len(roots)-1]}); err != nil {
+		logger.Error("failed to write diff header",
+			"err", err,
+		)
+		return
+	}
+
+	var numChanges int
+	err = diffRoots(ctx, ndb, roots, prefixes, func(entry *diffEntry) error {
+		if decodeKeys {
+			entry.KeyInfo = describeKey(rootType, entry.Key)
+		}
+		numChanges++
+		return w.WriteEntry(entry)
+	})
+	if err != nil {
+		logger.Error("failed to compute diff",
+			"err", err,
+		)
+		return
+	}
+
+	logger.Info("computed diff",
+		"old_root", roots[0],
+		"new_root", roots[len(roots)-1],
+		"changes", numChanges,
+	)
+
+	ok = true
+}
+
+func init() {
+	RegisterKeyDecoder(node.RootTypeIO, transaction.DescribeIOKey)
+
+	storageDiffFlags.String(cfgDiffRootType, diffRootTypeState, "root type to compare (state, io)")
+	storageDiffFlags.StringSlice(cfgDiffPrefix, nil, "only include keys with the given hex-encoded prefix (can be repeated)")
+	storageDiffFlags.String(cfgDiffFormat, diffFormatText, "output format (text, json, cbor)")
+	storageDiffFlags.String(cfgDiffOutput, "", "output file (default standard output)")
+	storageDiffFlags.Bool(cfgDiffDecodeKeys, true, "describe keys in known formats")
+	_ = viper.BindPFlags(storageDiffFlags)
+}
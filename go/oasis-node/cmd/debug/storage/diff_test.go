@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestDiffRoots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ns := common.NewTestNamespaceFromSeed([]byte("debug storage diff test ns"), 0)
+	ndb, err := badgerDb.New(&db.Config{
+		Namespace:    ns,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+		MemoryOnly:   true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	// Each round applies the given changes, a nil value removes the key.
+	rounds := []map[string][]byte{
+		{"a/1": []byte("1"), "a/2": []byte("2"), "b/1": []byte("1"), "c/1": []byte("1")},
+		{"a/1": []byte("1'"), "a/3": []byte("3"), "b/1": nil},
+		{"a/3": nil, "b/2": []byte("2")},
+		{},
+		{"c/1": []byte("1'"), "c/2": []byte("2"), "a/2": nil},
+	}
+	var roots []node.Root
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	for round, changes := range rounds {
+		for key, value := range changes {
+			if value == nil {
+				err = tree.Remove(ctx, []byte(key))
+			} else {
+				err = tree.Insert(ctx, []byte(key), value)
+			}
+			require.NoError(err, "Insert/Remove")
+		}
+		_, rootHash, err := tree.Commit(ctx, ns, uint64(round))
+		require.NoError(err, "Commit")
+		root := node.Root{Namespace: ns, Version: uint64(round), Type: node.RootTypeState, Hash: rootHash}
+		err = ndb.Finalize(ctx, []node.Root{root})
+		require.NoError(err, "Finalize")
+		roots = append(roots, root)
+	}
+	tree.Close()
+
+	collect := func(roots []node.Root, prefixes [][]byte) []string {
+		var result []string
+		err := diffRoots(ctx, ndb, roots, prefixes, func(entry *diffEntry) error {
+			result = append(result, fmt.Sprintf("%s %s %s %s", entry.Op, entry.Key, entry.OldValue, entry.NewValue))
+			return nil
+		})
+		require.NoError(err, "diffRoots")
+		return result
+	}
+
+	for _, tc := range []struct {
+		from, to uint64
+		prefixes [][]byte
+		expected []string
+	}{
+		{0, 0, nil, nil},
+		{3, 2, nil, nil},
+		{0, 1, nil, []string{"update a/1 1 1'", "insert a/3  3", "delete b/1 1 "}},
+		{0, 2, nil, []string{"update a/1 1 1'", "delete b/1 1 ", "insert b/2  2"}},
+		{1, 4, nil, []string{"delete a/2 2 ", "delete a/3 3 ", "insert b/2  2", "update c/1 1 1'", "insert c/2  2"}},
+		{0, 4, [][]byte{[]byte("c/"), []byte("a/")}, []string{"update a/1 1 1'", "delete a/2 2 ", "update c/1 1 1'", "insert c/2  2"}},
+		{0, 4, [][]byte{[]byte("a/"), []byte("a/1")}, []string{"update a/1 1 1'", "delete a/2 2 "}},
+		{0, 4, [][]byte{[]byte("d")}, nil},
+	} {
+		if tc.from > tc.to {
+			continue
+		}
+		// Compare using write logs.
+		result := collect(roots[tc.from:tc.to+1], tc.prefixes)
+		require.EqualValues(tc.expected, result, "diff using write logs (%d, %d)", tc.from, tc.to)
+
+		// Compare using iterators.
+		result = collect([]node.Root{roots[tc.from], roots[tc.to]}, tc.prefixes)
+		require.EqualValues(tc.expected, result, "diff using iterators (%d, %d)", tc.from, tc.to)
+	}
+}
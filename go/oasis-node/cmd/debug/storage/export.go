@@ -167,7 +167,7 @@ func newDirectStorageBackend(dataDir string, namespace common.Namespace) (storag
 
 	b := strings.ToLower(viper.GetString(storage.CfgBackend))
 	switch b {
-	case storageDatabase.BackendNameBadgerDB:
+	case storageDatabase.BackendNameBadgerDB, storageDatabase.BackendNameBBoltDB:
 		cfg.DB = filepath.Join(cfg.DB, storageDatabase.DefaultFileName(cfg.Backend))
 		return storageDatabase.New(cfg)
 	default:
@@ -224,8 +224,12 @@ func Register(parentCmd *cobra.Command) {
 
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
+	storageDiffCmd.Flags().AddFlagSet(storage.Flags)
+	storageDiffCmd.Flags().AddFlagSet(storageDiffFlags)
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageExportCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
+	storageCmd.AddCommand(storageDiffCmd)
 	parentCmd.AddCommand(storageCmd)
 }
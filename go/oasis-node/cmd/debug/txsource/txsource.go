@@ -24,6 +24,7 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/txsource/workload"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 const (
@@ -101,7 +102,7 @@ func doRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create submission manager: %w", err)
 	}
-	sm := consensus.NewSubmissionManager(cnsc, pd, 0)
+	sm := consensus.NewSubmissionManager(cnsc, pd, staking.NewStakingClient(conn), 0)
 
 	// Wait for sync before transferring control to the workload.
 	ncc := api.NewNodeControllerClient(conn)
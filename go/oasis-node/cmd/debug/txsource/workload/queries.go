@@ -530,6 +530,7 @@ func (q *queries) doStakingQueries(ctx context.Context, rng *rand.Rand, height i
 		_ = accSum.Add(&acc.General.Balance)
 		_ = accSum.Add(&acc.Escrow.Active.Balance)
 		_ = accSum.Add(&acc.Escrow.Debonding.Balance)
+		_ = accSum.Add(&acc.Vesting.Balance)
 
 		for beneficiary, allowance := range acc.General.Allowances {
 			aw, err := q.staking.Allowance(ctx, &staking.AllowanceQuery{
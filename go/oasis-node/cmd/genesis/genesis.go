@@ -78,6 +78,10 @@ const (
 	CfgGovernanceUpgradeCancelMinEpochDiff = "governance.upgrade_cancel_min_epoch_diff"
 	CfgGovernanceUpgradeMinEpochDiff       = "governance.upgrade_min_epoch_diff"
 	CfgGovernanceVotingPeriod              = "governance.voting_period"
+	CfgGovernanceEnableChangeParameters    = "governance.enable_change_parameters_proposal"
+	CfgGovernanceEnableDelegatorVotes      = "governance.enable_delegator_votes"
+	CfgGovernanceMinDelegatorVoteStake     = "governance.min_delegator_vote_stake"
+	CfgGovernanceMaxDelegatorVotes         = "governance.max_delegator_votes_per_proposal"
 
 	// Beacon config flags.
 	CfgBeaconBackend                    = "beacon.backend"
@@ -110,6 +114,8 @@ const (
 	CfgConsensusStateCheckpointChunkSize = "consensus.state_checkpoint.chunk_size"
 	CfgConsensusGasCostsTxByte           = "consensus.gas_costs.tx_byte"
 	cfgConsensusBlacklistPublicKey       = "consensus.blacklist_public_key"
+	cfgConsensusEnableBatchTransactions  = "consensus.enable_batch_transactions"
+	cfgConsensusEnableMultisigTxs        = "consensus.enable_multisig_transactions"
 
 	// Consensus backend config flag.
 	cfgConsensusBackend = "consensus.backend"
@@ -294,12 +300,16 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 
 	doc.Governance = governance.Genesis{
 		Parameters: governance.ConsensusParameters{
-			GasCosts:                  governance.DefaultGasCosts, // TODO: configurable.
-			MinProposalDeposit:        *quantity.NewFromUint64(viper.GetUint64(CfgGovernanceMinProposalDeposit)),
-			StakeThreshold:            uint8(viper.GetInt(CfgGovernanceStakeThreshold)),
-			UpgradeCancelMinEpochDiff: beacon.EpochTime(viper.GetUint64(CfgGovernanceUpgradeCancelMinEpochDiff)),
-			UpgradeMinEpochDiff:       beacon.EpochTime(viper.GetUint64(CfgGovernanceUpgradeMinEpochDiff)),
-			VotingPeriod:              beacon.EpochTime(viper.GetUint64(CfgGovernanceVotingPeriod)),
+			GasCosts:                       governance.DefaultGasCosts, // TODO: configurable.
+			MinProposalDeposit:             *quantity.NewFromUint64(viper.GetUint64(CfgGovernanceMinProposalDeposit)),
+			StakeThreshold:                 uint8(viper.GetInt(CfgGovernanceStakeThreshold)),
+			UpgradeCancelMinEpochDiff:      beacon.EpochTime(viper.GetUint64(CfgGovernanceUpgradeCancelMinEpochDiff)),
+			UpgradeMinEpochDiff:            beacon.EpochTime(viper.GetUint64(CfgGovernanceUpgradeMinEpochDiff)),
+			VotingPeriod:                   beacon.EpochTime(viper.GetUint64(CfgGovernanceVotingPeriod)),
+			EnableChangeParametersProposal: viper.GetBool(CfgGovernanceEnableChangeParameters),
+			EnableDelegatorVotes:           viper.GetBool(CfgGovernanceEnableDelegatorVotes),
+			MinDelegatorVoteStake:          *quantity.NewFromUint64(viper.GetUint64(CfgGovernanceMinDelegatorVoteStake)),
+			MaxDelegatorVotesPerProposal:   viper.GetUint64(CfgGovernanceMaxDelegatorVotes),
 		},
 	}
 
@@ -352,7 +362,9 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 			GasCosts: transaction.Costs{
 				consensusGenesis.GasOpTxByte: transaction.Gas(viper.GetUint64(CfgConsensusGasCostsTxByte)),
 			},
-			PublicKeyBlacklist: pkBlacklist,
+			PublicKeyBlacklist:         pkBlacklist,
+			EnableBatchTransactions:    viper.GetBool(cfgConsensusEnableBatchTransactions),
+			EnableMultisigTransactions: viper.GetBool(cfgConsensusEnableMultisigTxs),
 		},
 	}
 
@@ -836,6 +848,10 @@ func init() {
 	initGenesisFlags.Uint64(CfgGovernanceUpgradeCancelMinEpochDiff, 300, "minimum number of epochs in advance for canceling proposals")
 	initGenesisFlags.Uint64(CfgGovernanceUpgradeMinEpochDiff, 300, "minimum number of epochs the upgrade needs to be scheduled in advance")
 	initGenesisFlags.Uint64(CfgGovernanceVotingPeriod, 100, "voting period (in epochs)")
+	initGenesisFlags.Bool(CfgGovernanceEnableChangeParameters, false, "enable consensus parameter change proposals")
+	initGenesisFlags.Bool(CfgGovernanceEnableDelegatorVotes, false, "enable delegator votes overriding validator votes")
+	initGenesisFlags.Uint64(CfgGovernanceMinDelegatorVoteStake, 0, "minimum delegated stake required for delegator votes")
+	initGenesisFlags.Uint64(CfgGovernanceMaxDelegatorVotes, 1000, "maximum number of delegator votes per proposal")
 
 	// Beacon config flags.
 	initGenesisFlags.String(CfgBeaconBackend, "insecure", "beacon backend")
@@ -871,6 +887,8 @@ func init() {
 	initGenesisFlags.String(CfgConsensusStateCheckpointChunkSize, "8mb", "consensus state checkpoint chunk size (in bytes)")
 	initGenesisFlags.Uint64(CfgConsensusGasCostsTxByte, 1, "consensus gas costs: each transaction byte")
 	initGenesisFlags.StringSlice(cfgConsensusBlacklistPublicKey, nil, "blacklist public key")
+	initGenesisFlags.Bool(cfgConsensusEnableBatchTransactions, false, "enable batch transactions")
+	initGenesisFlags.Bool(cfgConsensusEnableMultisigTxs, false, "enable multisig transactions")
 
 	// Consensus backend flag.
 	initGenesisFlags.String(cfgConsensusBackend, tendermint.BackendName, "consensus backend")
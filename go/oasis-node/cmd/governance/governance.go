@@ -13,6 +13,8 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -23,12 +25,20 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 const (
-	cfgProposalCancelUpgradeID   = "proposal.cancel_upgrade.id"
-	cfgProposalUpgradeDescriptor = "proposal.upgrade.descriptor"
+	cfgProposalCancelUpgradeID         = "proposal.cancel_upgrade.id"
+	cfgProposalUpgradeDescriptor       = "proposal.upgrade.descriptor"
+	cfgProposalChangeParametersModule  = "proposal.change_parameters.module"
+	cfgProposalChangeParametersChanges = "proposal.change_parameters.changes"
+	cfgProposalSignalingTitle          = "proposal.signaling.title"
+	cfgProposalSignalingDescription    = "proposal.signaling.description"
+	cfgProposalSignalingURL            = "proposal.signaling.url"
 
 	cfgVote           = "vote"
 	cfgVoteProposalID = "vote.proposal.id"
@@ -81,6 +91,14 @@ var (
 	}
 
 	logger = logging.GetLogger("cmd/governance")
+
+	// parameterChanges are the supported consensus parameter changes types
+	// keyed by module name.
+	parameterChanges = map[string]func() interface{}{
+		roothash.ModuleName:  func() interface{} { return &roothash.ConsensusParameterChanges{} },
+		scheduler.ModuleName: func() interface{} { return &scheduler.ConsensusParameterChanges{} },
+		staking.ModuleName:   func() interface{} { return &staking.ConsensusParameterChanges{} },
+	}
 )
 
 func doConnect(cmd *cobra.Command) (*grpc.ClientConn, governance.Backend) {
@@ -143,9 +161,66 @@ func doGenSubmitProposal(cmd *cobra.Command, args []string) {
 				ProposalID: viper.GetUint64(cfgProposalCancelUpgradeID),
 			},
 		})
+	case viper.GetString(cfgProposalChangeParametersModule) != "":
+		module := viper.GetString(cfgProposalChangeParametersModule)
+		newChanges, ok := parameterChanges[module]
+		if !ok {
+			logger.Error("consensus parameter changes not supported for module",
+				"module", module,
+			)
+			os.Exit(1)
+		}
+
+		changesBytes, err := ioutil.ReadFile(viper.GetString(cfgProposalChangeParametersChanges))
+		if err != nil {
+			logger.Error("failed to read consensus parameter changes",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		changes := newChanges()
+		if err = json.Unmarshal(changesBytes, changes); err != nil {
+			logger.Error("can't parse consensus parameter changes",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		tx = governance.NewSubmitProposalTx(nonce, fee, &governance.ProposalContent{
+			ChangeParameters: &governance.ChangeParametersProposal{
+				Module:  module,
+				Changes: cbor.Marshal(changes),
+			},
+		})
+	case viper.GetString(cfgProposalSignalingTitle) != "":
+		description, err := ioutil.ReadFile(viper.GetString(cfgProposalSignalingDescription))
+		if err != nil {
+			logger.Error("failed to read signaling proposal description",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		signaling := &governance.SignalingProposal{
+			Title:           viper.GetString(cfgProposalSignalingTitle),
+			DescriptionHash: hash.NewFromBytes(description),
+			URL:             viper.GetString(cfgProposalSignalingURL),
+		}
+		if err = signaling.ValidateBasic(); err != nil {
+			logger.Error("signaling proposal is not valid",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		tx = governance.NewSubmitProposalTx(nonce, fee, &governance.ProposalContent{
+			Signaling: signaling,
+		})
 	default:
-		logger.Error(fmt.Sprintf("missing required arguments: either '%v' or '%v' required",
-			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID,
+		logger.Error(fmt.Sprintf("missing required arguments: either '%v', '%v', '%v' or '%v' required",
+			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID, cfgProposalChangeParametersModule,
+			cfgProposalSignalingTitle,
 		))
 		os.Exit(1)
 	}
@@ -312,6 +387,11 @@ func init() {
 
 	submitProposalFlags.String(cfgProposalUpgradeDescriptor, "", "Path to the proposal upgrade descriptor")
 	submitProposalFlags.Uint64(cfgProposalCancelUpgradeID, 0, "Cancel upgrade proposal ID")
+	submitProposalFlags.String(cfgProposalChangeParametersModule, "", "Change parameters proposal module name")
+	submitProposalFlags.String(cfgProposalChangeParametersChanges, "", "Path to the change parameters proposal consensus parameter changes")
+	submitProposalFlags.String(cfgProposalSignalingTitle, "", "Signaling proposal title")
+	submitProposalFlags.String(cfgProposalSignalingDescription, "", "Path to the signaling proposal description")
+	submitProposalFlags.String(cfgProposalSignalingURL, "", "Signaling proposal description URL")
 	_ = viper.BindPFlags(submitProposalFlags)
 	submitProposalFlags.AddFlagSet(cmdConsensus.TxFlags)
 	submitProposalFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
@@ -750,6 +750,7 @@ func init() {
 		p2p.Flags,
 		registration.Flags,
 		workerCommon.Flags,
+		workerClient.Flags,
 		workerStorage.Flags,
 		workerSentry.Flags,
 		workerConsensusRPC.Flags,
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
@@ -49,6 +53,24 @@ const (
 
 	// CfgWithdrawSource configures the withdrawal source address.
 	CfgWithdrawSource = "stake.withdraw.source"
+
+	// CfgScheduleTransferStart configures the epoch when a scheduled transfer starts releasing stake.
+	CfgScheduleTransferStart = "stake.schedule_transfer.start"
+
+	// CfgScheduleTransferEnd configures the epoch when a scheduled transfer has released all stake.
+	CfgScheduleTransferEnd = "stake.schedule_transfer.end"
+
+	// CfgHistoryHeightMin configures the minimum height of listed account transactions.
+	CfgHistoryHeightMin = "stake.history.height_min"
+
+	// CfgHistoryHeightMax configures the maximum height of listed account transactions.
+	CfgHistoryHeightMax = "stake.history.height_max"
+
+	// CfgHistoryMethods configures the methods of listed account transactions.
+	CfgHistoryMethods = "stake.history.methods"
+
+	// CfgHistoryLimit configures the maximum number of listed account transactions.
+	CfgHistoryLimit = "stake.history.limit"
 )
 
 var (
@@ -61,6 +83,8 @@ var (
 	accountBurnFlags        = flag.NewFlagSet("", flag.ContinueOnError)
 	accountAllowFlags       = flag.NewFlagSet("", flag.ContinueOnError)
 	accountWithdrawFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	accountScheduleFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	accountHistoryFlags     = flag.NewFlagSet("", flag.ContinueOnError)
 
 	accountCmd = &cobra.Command{
 		Use:   "account",
@@ -79,6 +103,12 @@ var (
 		Run:   doAccountNonce,
 	}
 
+	accountHistoryCmd = &cobra.Command{
+		Use:   "history",
+		Short: "list consensus transactions involving an account (requires an indexer-enabled node)",
+		Run:   doAccountHistory,
+	}
+
 	accountValidateAddressCmd = &cobra.Command{
 		Use:   "validate_address",
 		Short: "validate account address",
@@ -126,6 +156,12 @@ var (
 		Short: "generate a withdraw transaction",
 		Run:   doAccountWithdraw,
 	}
+
+	accountScheduleTransferCmd = &cobra.Command{
+		Use:   "gen_schedule_transfer",
+		Short: "generate a scheduled transfer transaction",
+		Run:   doAccountScheduleTransfer,
+	}
 )
 
 func doAccountInfo(cmd *cobra.Command, args []string) {
@@ -215,6 +251,84 @@ func doAccountNonce(cmd *cobra.Command, args []string) {
 	fmt.Println(acct.General.Nonce)
 }
 
+func doAccountHistory(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	request := consensus.GetTransactionsByAddressRequest{
+		HeightMin: viper.GetInt64(CfgHistoryHeightMin),
+		HeightMax: viper.GetInt64(CfgHistoryHeightMax),
+	}
+	if err := request.Address.UnmarshalText([]byte(viper.GetString(CfgAccountAddr))); err != nil {
+		logger.Error("failed to parse account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	for _, method := range viper.GetStringSlice(CfgHistoryMethods) {
+		request.Methods = append(request.Methods, transaction.MethodName(method))
+	}
+	limit := viper.GetUint64(CfgHistoryLimit)
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	client := consensus.NewConsensusClient(conn)
+	ctx := context.Background()
+
+	var count uint64
+	for {
+		request.Limit = consensus.MaxTransactionsByAddressLimit
+		if limit > 0 && limit-count < request.Limit {
+			request.Limit = limit - count
+		}
+
+		rsp, err := client.GetTransactionsByAddress(ctx, &request)
+		if err != nil {
+			logger.Error("failed to query account transactions",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		if request.After == nil {
+			fmt.Printf("Indexed heights: %d-%d\n", rsp.IndexedHeightMin, rsp.IndexedHeightMax)
+		}
+		for _, at := range rsp.Transactions {
+			var roles []string
+			if at.Signer {
+				roles = append(roles, "signer")
+			}
+			if at.EventParticipant {
+				roles = append(roles, "event participant")
+			}
+			method := string(at.Method)
+			if method == "" {
+				method = "<malformed>"
+			}
+
+			fmt.Printf("Height %d, transaction %d:\n", at.Height, at.Index)
+			fmt.Printf("  Hash:    %s\n", at.TxHash)
+			fmt.Printf("  Method:  %s\n", method)
+			fmt.Printf("  Roles:   %s\n", strings.Join(roles, ", "))
+			fmt.Printf("  Success: %t\n", at.Success)
+		}
+
+		count += uint64(len(rsp.Transactions))
+		if rsp.Next == nil || (limit > 0 && count >= limit) {
+			break
+		}
+		request.After = rsp.Next
+	}
+}
+
 func doValidateAddress(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -487,11 +601,46 @@ func doAccountWithdraw(cmd *cobra.Command, args []string) {
 	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
 }
 
+func doAccountScheduleTransfer(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	genesis := cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	var xfer api.ScheduleTransfer
+	if err := xfer.To.UnmarshalText([]byte(viper.GetString(CfgTransferDestination))); err != nil {
+		logger.Error("failed to parse transfer destination account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err := xfer.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+		logger.Error("failed to parse transfer amount",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	xfer.Start = beacon.EpochTime(viper.GetUint64(CfgScheduleTransferStart))
+	xfer.End = beacon.EpochTime(viper.GetUint64(CfgScheduleTransferEnd))
+	if xfer.End < xfer.Start {
+		logger.Error("scheduled transfer end epoch is before start epoch")
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := api.NewScheduleTransferTx(nonce, fee, &xfer)
+
+	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+}
+
 func registerAccountCmd() {
 	for _, v := range []*cobra.Command{
 		accountInfoCmd,
 		accountNonceCmd,
 		accountValidateAddressCmd,
+		accountHistoryCmd,
 		accountTransferCmd,
 		accountBurnCmd,
 		accountEscrowCmd,
@@ -499,6 +648,7 @@ func registerAccountCmd() {
 		accountAmendCommissionScheduleCmd,
 		accountAllowCmd,
 		accountWithdrawCmd,
+		accountScheduleTransferCmd,
 	} {
 		accountCmd.AddCommand(v)
 	}
@@ -507,6 +657,7 @@ func registerAccountCmd() {
 	accountNonceCmd.Flags().AddFlagSet(commonAccountFlags)
 	accountValidateAddressCmd.Flags().AddFlagSet(commonAccountFlags)
 	accountValidateAddressCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	accountHistoryCmd.Flags().AddFlagSet(accountHistoryFlags)
 	accountTransferCmd.Flags().AddFlagSet(accountTransferFlags)
 	accountBurnCmd.Flags().AddFlagSet(accountBurnFlags)
 	accountEscrowCmd.Flags().AddFlagSet(commonEscrowFlags)
@@ -516,6 +667,7 @@ func registerAccountCmd() {
 	accountAmendCommissionScheduleCmd.Flags().AddFlagSet(commissionScheduleFlags)
 	accountAllowCmd.Flags().AddFlagSet(accountAllowFlags)
 	accountWithdrawCmd.Flags().AddFlagSet(accountWithdrawFlags)
+	accountScheduleTransferCmd.Flags().AddFlagSet(accountScheduleFlags)
 }
 
 func init() {
@@ -529,6 +681,13 @@ func init() {
 	sharesFlags.String(CfgShares, "0", "amount of shares for the transaction")
 	_ = viper.BindPFlags(sharesFlags)
 
+	accountHistoryFlags.Int64(CfgHistoryHeightMin, 0, "minimum height of listed transactions")
+	accountHistoryFlags.Int64(CfgHistoryHeightMax, 0, "maximum height of listed transactions (0 means latest)")
+	accountHistoryFlags.StringSlice(CfgHistoryMethods, nil, "only list transactions with the given method(s)")
+	accountHistoryFlags.Uint64(CfgHistoryLimit, 0, "maximum number of listed transactions (0 means unlimited)")
+	_ = viper.BindPFlags(accountHistoryFlags)
+	accountHistoryFlags.AddFlagSet(commonAccountFlags)
+
 	accountTransferFlags.String(CfgTransferDestination, "", "transfer destination account address")
 	_ = viper.BindPFlags(accountTransferFlags)
 	accountTransferFlags.AddFlagSet(cmdConsensus.TxFlags)
@@ -570,4 +729,9 @@ func init() {
 	accountWithdrawFlags.AddFlagSet(cmdConsensus.TxFlags)
 	accountWithdrawFlags.AddFlagSet(amountFlags)
 	accountWithdrawFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
+	accountScheduleFlags.Uint64(CfgScheduleTransferStart, 0, "epoch when the release of stake starts")
+	accountScheduleFlags.Uint64(CfgScheduleTransferEnd, 0, "epoch when all stake has been released")
+	_ = viper.BindPFlags(accountScheduleFlags)
+	accountScheduleFlags.AddFlagSet(accountTransferFlags)
 }
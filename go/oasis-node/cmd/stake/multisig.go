@@ -0,0 +1,358 @@
+package stake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	signerFile "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	signerPlugin "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/plugin"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
+	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	"github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	// CfgMultisigSigners configures the multisig account signers.
+	CfgMultisigSigners = "stake.multisig.signers"
+
+	// CfgMultisigThreshold configures the multisig account threshold.
+	CfgMultisigThreshold = "stake.multisig.threshold"
+
+	// CfgMultisigAccountFile configures the multisig account descriptor file.
+	CfgMultisigAccountFile = "stake.multisig.account_file"
+
+	// CfgMultisigOutputFile configures the output file for the multi-signed transaction.
+	CfgMultisigOutputFile = "stake.multisig.output_file"
+)
+
+var (
+	multisigAccountFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	multisigGenAccountFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	multisigSignFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	multisigSubmitFlags      = flag.NewFlagSet("", flag.ContinueOnError)
+
+	multisigCmd = &cobra.Command{
+		Use:   "multisig",
+		Short: "multisig account commands",
+	}
+
+	multisigGenAccountCmd = &cobra.Command{
+		Use:   "gen_account",
+		Short: "generate a multisig account descriptor and print its address",
+		Run:   doMultisigGenAccount,
+	}
+
+	multisigAddressCmd = &cobra.Command{
+		Use:   "address",
+		Short: "print the address of a multisig account descriptor",
+		Run:   doMultisigAddress,
+	}
+
+	multisigSignCmd = &cobra.Command{
+		Use:   "sign",
+		Short: "sign (or co-sign) a transaction on behalf of a multisig account",
+		Run:   doMultisigSign,
+	}
+
+	multisigSubmitCmd = &cobra.Command{
+		Use:   "submit",
+		Short: "submit a multi-signed transaction",
+		Run:   doMultisigSubmit,
+	}
+)
+
+func loadMultisigAccount() *multisig.Account {
+	rawAccount, err := ioutil.ReadFile(viper.GetString(CfgMultisigAccountFile))
+	if err != nil {
+		logger.Error("failed to read multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var account multisig.Account
+	if err = json.Unmarshal(rawAccount, &account); err != nil {
+		logger.Error("failed to parse multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = account.ValidateBasic(); err != nil {
+		logger.Error("invalid multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	return &account
+}
+
+func loadMultiSignedTx() *transaction.MultiSignedTransaction {
+	rawTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
+	if err != nil {
+		logger.Error("failed to read multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var tx transaction.MultiSignedTransaction
+	if err = json.Unmarshal(rawTx, &tx); err != nil {
+		logger.Error("failed to parse multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	return &tx
+}
+
+// loadTxForMultisig loads either a previously (partially) multi-signed
+// transaction or an unsigned transaction, in which case a new multi-signed
+// transaction for the configured multisig account is created.
+func loadTxForMultisig() *transaction.MultiSignedTransaction {
+	rawTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
+	if err != nil {
+		logger.Error("failed to read transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var multiSigTx transaction.MultiSignedTransaction
+	if err = json.Unmarshal(rawTx, &multiSigTx); err == nil {
+		if err = multiSigTx.Account.ValidateBasic(); err != nil {
+			logger.Error("invalid multisig account descriptor in transaction",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		if viper.GetString(CfgMultisigAccountFile) != "" {
+			accountHash, txAccountHash := loadMultisigAccount().Hash(), multiSigTx.Account.Hash()
+			if !txAccountHash.Equal(&accountHash) {
+				logger.Error("multisig account descriptor does not match the one in transaction")
+				os.Exit(1)
+			}
+		}
+		return &multiSigTx
+	}
+
+	var tx transaction.Transaction
+	if err = cbor.Unmarshal(rawTx, &tx); err != nil {
+		logger.Error("failed to parse transaction as either unsigned or multi-signed",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if viper.GetString(CfgMultisigAccountFile) == "" {
+		logger.Error("multisig account descriptor is required for signing unsigned transactions")
+		os.Exit(1)
+	}
+	newTx, err := transaction.NewMultiSignedTransaction(loadMultisigAccount(), &tx)
+	if err != nil {
+		logger.Error("failed to create multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return newTx
+}
+
+func doMultisigGenAccount(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	account := multisig.Account{
+		Threshold: uint8(viper.GetUint(CfgMultisigThreshold)),
+	}
+	for _, v := range viper.GetStringSlice(CfgMultisigSigners) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(v)); err != nil {
+			logger.Error("failed to parse multisig signer public key",
+				"err", err,
+				"public_key", v,
+			)
+			os.Exit(1)
+		}
+		account.Signers = append(account.Signers, pk)
+	}
+	if err := account.ValidateBasic(); err != nil {
+		logger.Error("invalid multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyAccount, err := cmdCommon.PrettyJSONMarshal(account)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(viper.GetString(CfgMultisigAccountFile), prettyAccount, 0o600); err != nil {
+		logger.Error("failed to save multisig account descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%v\n", api.NewMultisigAddress(&account))
+}
+
+func doMultisigAddress(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	fmt.Printf("%v\n", api.NewMultisigAddress(loadMultisigAccount()))
+}
+
+func doMultisigSign(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	genesis := cmdConsensus.InitGenesis()
+	ctx := cmdContext.GetCtxWithGenesisInfo(genesis)
+
+	outputFile := viper.GetString(CfgMultisigOutputFile)
+	if outputFile == "" {
+		logger.Error("failed to determine output file")
+		os.Exit(1)
+	}
+
+	multiSigTx := loadTxForMultisig()
+
+	_, signer, err := cmdCommon.LoadEntitySigner()
+	if err != nil {
+		logger.Error("failed to load signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	fmt.Printf("You are about to sign the following transaction on behalf of multisig account %s:\n",
+		api.NewMultisigAddress(&multiSigTx.Account),
+	)
+	multiSigTx.PrettyPrint(ctx, "  ", os.Stdout)
+
+	switch cmdSigner.Backend() {
+	case signerFile.SignerName:
+		if !cmdFlags.AssumeYes() {
+			if !cmdCommon.GetUserConfirmation("\nAre you sure you want to continue? (y)es/(n)o: ") {
+				os.Exit(1)
+			}
+		}
+	case signerPlugin.SignerName:
+		if cmdCommon.Isatty(os.Stdin.Fd()) {
+			fmt.Println("\nYou may need to review the transaction on your device if you use a hardware-based signer plugin...")
+		}
+	}
+
+	if err = multiSigTx.Sign(signer); err != nil {
+		logger.Error("failed to sign transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettySigTx, err := cmdCommon.PrettyJSONMarshal(multiSigTx)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(outputFile, prettySigTx, 0o600); err != nil {
+		logger.Error("failed to save multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Signatures: %d of %d required\n", len(multiSigTx.Signatures), multiSigTx.Account.Threshold)
+}
+
+func doMultisigSubmit(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	tx := loadMultiSignedTx()
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	client := consensus.NewConsensusClient(conn)
+	if err = client.SubmitMultiSignedTx(context.Background(), tx); err != nil {
+		logger.Error("failed to submit multi-signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func registerMultisigCmd() {
+	for _, v := range []*cobra.Command{
+		multisigGenAccountCmd,
+		multisigAddressCmd,
+		multisigSignCmd,
+		multisigSubmitCmd,
+	} {
+		multisigCmd.AddCommand(v)
+	}
+
+	multisigGenAccountCmd.Flags().AddFlagSet(multisigGenAccountFlags)
+	multisigAddressCmd.Flags().AddFlagSet(multisigAccountFileFlags)
+	multisigSignCmd.Flags().AddFlagSet(multisigSignFlags)
+	multisigSubmitCmd.Flags().AddFlagSet(multisigSubmitFlags)
+}
+
+func init() {
+	multisigAccountFileFlags.String(CfgMultisigAccountFile, "", "path to the multisig account descriptor")
+	_ = viper.BindPFlags(multisigAccountFileFlags)
+
+	multisigGenAccountFlags.StringSlice(CfgMultisigSigners, nil, "multisig account signer public key (Base64-encoded). Multiple of this flag is allowed")
+	multisigGenAccountFlags.Uint(CfgMultisigThreshold, 1, "number of signers required to authorize a transaction")
+	_ = viper.BindPFlags(multisigGenAccountFlags)
+	multisigGenAccountFlags.AddFlagSet(multisigAccountFileFlags)
+
+	multisigSignFlags.String(CfgMultisigOutputFile, "", "path to the output multi-signed transaction")
+	_ = viper.BindPFlags(multisigSignFlags)
+	multisigSignFlags.AddFlagSet(multisigAccountFileFlags)
+	multisigSignFlags.AddFlagSet(cmdConsensus.TxFileFlags)
+	multisigSignFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	multisigSignFlags.AddFlagSet(cmdSigner.Flags)
+	multisigSignFlags.AddFlagSet(cmdSigner.CLIFlags)
+	multisigSignFlags.AddFlagSet(cmdFlags.GenesisFileFlags)
+	multisigSignFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
+	multisigSubmitFlags.AddFlagSet(cmdConsensus.TxFileFlags)
+	multisigSubmitFlags.AddFlagSet(cmdGrpc.ClientFlags)
+}
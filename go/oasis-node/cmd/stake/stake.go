@@ -224,6 +224,15 @@ func doInfo(cmd *cobra.Command, args []string) {
 	token.PrettyPrintAmount(ctx, *lastBlockFees, os.Stdout)
 	fmt.Println()
 
+	baseGasPrice, err := client.BaseGasPrice(ctx, consensus.HeightLatest)
+	if err != nil {
+		logger.Error("failed to query base gas price",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("Base gas price: %s\n", baseGasPrice)
+
 	governanceDeposits, err := client.GovernanceDeposits(ctx, consensus.HeightLatest)
 	if err != nil {
 		logger.Error("failed to query governance deposits",
@@ -330,11 +339,13 @@ func doPubkey2Address(cmd *cobra.Command, args []string) {
 // Register registers the stake sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	registerAccountCmd()
+	registerMultisigCmd()
 	for _, v := range []*cobra.Command{
 		infoCmd,
 		listCmd,
 		pubkey2AddressCmd,
 		accountCmd,
+		multisigCmd,
 	} {
 		stakeCmd.AddCommand(v)
 	}
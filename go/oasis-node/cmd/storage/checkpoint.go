@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage"
+)
+
+const cfgCheckpointExportChunkSize = "storage.checkpoint.export.chunk_size"
+
+var (
+	storageCheckpointCmd = &cobra.Command{
+		Use:   "checkpoint",
+		Short: "checkpoint archive utilities",
+	}
+
+	storageCheckpointExportCmd = &cobra.Command{
+		Use:   "export <runtime> <round> <file>",
+		Args:  cobra.ExactArgs(3),
+		Short: "export runtime state at the given round into a checkpoint archive",
+		RunE:  doCheckpointExport,
+	}
+
+	storageCheckpointExportFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	storageCheckpointImportCmd = &cobra.Command{
+		Use:   "import <runtime> <file>",
+		Args:  cobra.ExactArgs(2),
+		Short: "import runtime state from a checkpoint archive",
+		Long: "Import runtime state from a checkpoint archive into an empty node database.\n\n" +
+			"The archive roots are verified against the runtime block for the archive round. The block\n" +
+			"is taken from the local runtime history or, if it is not available there, queried from the\n" +
+			"node at the given gRPC address.",
+		RunE: doCheckpointImport,
+	}
+)
+
+func parseRuntime(arg string) (common.Namespace, error) {
+	runtimes, err := parseRuntimes([]string{arg})
+	if err != nil {
+		return common.Namespace{}, err
+	}
+	return runtimes[0], nil
+}
+
+func doCheckpointExport(cmd *cobra.Command, args []string) error {
+	dataDir := cmdCommon.DataDir()
+	ctx := context.Background()
+
+	rt, err := parseRuntime(args[0])
+	if err != nil {
+		return err
+	}
+	round, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed round '%s': %w", args[1], err)
+	}
+	filename := args[2]
+	chunkSize := viper.GetUint64(cfgCheckpointExportChunkSize)
+	if chunkSize == 0 {
+		return fmt.Errorf("chunk size must be greater than zero")
+	}
+
+	runtimeDir := registry.GetRuntimeStateDir(dataDir, rt)
+	backend := strings.ToLower(viper.GetString(workerStorage.CfgBackend))
+	ndb, err := database.NewNodeDB(backend, &db.Config{
+		DB:        workerStorage.GetLocalBackendDBDir(runtimeDir, backend),
+		Namespace: rt,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open node database: %w", err)
+	}
+	defer ndb.Close()
+
+	earliestVersion, err := ndb.GetEarliestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get earliest version: %w", err)
+	}
+	latestVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if round < earliestVersion || round > latestVersion {
+		return fmt.Errorf("round %d not available in the node database (earliest: %d latest: %d)",
+			round,
+			earliestVersion,
+			latestVersion,
+		)
+	}
+	roots, err := ndb.GetRootsForVersion(ctx, round)
+	if err != nil {
+		return fmt.Errorf("failed to get roots for round %d: %w", round, err)
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("no roots for round %d in the node database", round)
+	}
+
+	// Create checkpoints in a scratch directory so that the checkpoints managed by the node are
+	// left untouched.
+	tmpDir, err := ioutil.TempDir(dataDir, "checkpoint-export-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	creator, err := checkpoint.NewFileCreator(tmpDir, ndb)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint creator: %w", err)
+	}
+
+	display := &displayHelper{}
+	var cps []*checkpoint.Metadata
+	for _, root := range roots {
+		display.DisplayStepBegin(fmt.Sprintf("creating checkpoint for %s root", root.Type))
+		cp, err := creator.CreateCheckpoint(ctx, root, chunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to create checkpoint for root %s: %w", root, err)
+		}
+		display.DisplayStepEnd(fmt.Sprintf("%d chunks", len(cp.Chunks)))
+		cps = append(cps, cp)
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	display.DisplayStepBegin("writing archive")
+	w := bufio.NewWriter(f)
+	err = checkpoint.WriteArchive(ctx, creator, cps, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	f.Close()
+	if err != nil {
+		// Do not leave a partially written archive around.
+		_ = os.Remove(filename)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	display.DisplayStepEnd("done")
+
+	logger.Info("exported checkpoint archive",
+		"rt", rt,
+		"round", round,
+		"roots", roots,
+		"file", filename,
+	)
+	return nil
+}
+
+// getBlock returns the runtime block for the given round, either from the local runtime history
+// or from a remote node.
+func getBlock(ctx context.Context, cmd *cobra.Command, runtimeDir string, rt common.Namespace, round uint64) (*block.Block, error) {
+	h, err := history.New(runtimeDir, rt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating history provider: %w", err)
+	}
+	blk, err := h.GetBlock(ctx, round)
+	h.Close()
+	switch {
+	case err == nil:
+		return blk, nil
+	case errors.Is(err, roothash.ErrNotFound):
+	default:
+		return nil, fmt.Errorf("failed to get block from local history: %w", err)
+	}
+
+	if !cmd.Flags().Changed(cmdGrpc.CfgAddress) {
+		return nil, fmt.Errorf("block for round %d not in local history, use --%s to query a node", round, cmdGrpc.CfgAddress)
+	}
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish connection with node: %w", err)
+	}
+	defer conn.Close()
+
+	blk, err = runtimeClient.NewRuntimeClient(conn).GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: rt,
+		Round:     round,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block from node: %w", err)
+	}
+	return blk, nil
+}
+
+func doCheckpointImport(cmd *cobra.Command, args []string) error {
+	dataDir := cmdCommon.DataDir()
+	ctx := context.Background()
+
+	rt, err := parseRuntime(args[0])
+	if err != nil {
+		return err
+	}
+	filename := args[1]
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	runtimeDir := registry.GetRuntimeStateDir(dataDir, rt)
+	if err = common.Mkdir(runtimeDir); err != nil {
+		return fmt.Errorf("failed to create runtime state directory: %w", err)
+	}
+	backend := strings.ToLower(viper.GetString(workerStorage.CfgBackend))
+	ndb, err := database.NewNodeDB(backend, &db.Config{
+		DB:        workerStorage.GetLocalBackendDBDir(runtimeDir, backend),
+		Namespace: rt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open node database: %w", err)
+	}
+	defer ndb.Close()
+
+	// Only import into an empty database.
+	latestVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	roots, err := ndb.GetRootsForVersion(ctx, latestVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get roots for version %d: %w", latestVersion, err)
+	}
+	if len(roots) > 0 {
+		return fmt.Errorf("node database is not empty (latest version: %d)", latestVersion)
+	}
+
+	display := &displayHelper{}
+	verifyFn := func(meta *checkpoint.ArchiveMetadata) error {
+		round := meta.Checkpoints[0].Root.Version
+
+		display.DisplayStepBegin(fmt.Sprintf("verifying archive roots for round %d", round))
+		blk, err := getBlock(ctx, cmd, runtimeDir, rt, round)
+		if err != nil {
+			return err
+		}
+		expectedRoots := blk.Header.StorageRoots()
+		containsRoot := func(roots []node.Root, root node.Root) bool {
+			for _, r := range roots {
+				if r.Equal(&root) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, root := range meta.Roots() {
+			if !containsRoot(expectedRoots, root) {
+				return fmt.Errorf("archive root %s does not match block for round %d", root, round)
+			}
+		}
+		for _, expected := range expectedRoots {
+			// Empty roots are implicitly present.
+			if !expected.Hash.IsEmpty() && !containsRoot(meta.Roots(), expected) {
+				return fmt.Errorf("archive is missing root %s", expected)
+			}
+		}
+		display.DisplayStepEnd("ok")
+		display.DisplayStepBegin("restoring checkpoints")
+		return nil
+	}
+
+	meta, err := checkpoint.RestoreArchive(ctx, ndb, bufio.NewReader(f), verifyFn)
+	if err != nil {
+		return fmt.Errorf("failed to import archive: %w", err)
+	}
+	if err = ndb.Sync(); err != nil {
+		return fmt.Errorf("failed to sync node database: %w", err)
+	}
+	display.DisplayStepEnd("done")
+
+	logger.Info("imported checkpoint archive",
+		"rt", rt,
+		"roots", meta.Roots(),
+		"file", filename,
+	)
+	return nil
+}
+
+func registerCheckpointCmd(parentCmd *cobra.Command) {
+	storageCheckpointExportCmd.Flags().AddFlagSet(storageCheckpointExportFlags)
+	storageCheckpointImportCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	storageCheckpointCmd.AddCommand(storageCheckpointExportCmd)
+	storageCheckpointCmd.AddCommand(storageCheckpointImportCmd)
+	parentCmd.AddCommand(storageCheckpointCmd)
+}
+
+func init() {
+	storageCheckpointExportFlags.Uint64(cfgCheckpointExportChunkSize, 8*1024*1024, "checkpoint chunk size in bytes")
+	_ = viper.BindPFlags(storageCheckpointExportFlags)
+}
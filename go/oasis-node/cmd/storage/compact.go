@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	storageWorkerAPI "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+var (
+	storageStatsCmd = &cobra.Command{
+		Use:   "stats <runtime>",
+		Args:  cobra.ExactArgs(1),
+		Short: "show node database statistics of a running node",
+		Long: "Show node database statistics of a running node, including per-version node counts,\n" +
+			"an estimate of orphaned nodes awaiting compaction, write log sizes and the on-disk size.",
+		RunE: doStats,
+	}
+
+	storageCompactCmd = &cobra.Command{
+		Use:   "compact <runtime>",
+		Args:  cobra.ExactArgs(1),
+		Short: "compact the node database of a running node",
+		Long: "Compact the node database of a running node, reclaiming space used by pruned data.\n\n" +
+			"The node's checkpointer is paused while compaction is in progress.",
+		RunE: doCompact,
+	}
+)
+
+func connectStorageWorker(cmd *cobra.Command) (storageWorkerAPI.StorageWorker, func(), error) {
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to establish connection with node: %w", err)
+	}
+	return storageWorkerAPI.NewStorageWorkerClient(conn), func() { conn.Close() }, nil
+}
+
+func doStats(cmd *cobra.Command, args []string) error {
+	rt, err := parseRuntime(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, closeFn, err := connectStorageWorker(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	stats, err := client.GetStats(context.Background(), &storageWorkerAPI.GetStatsRequest{
+		RuntimeID: rt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get storage statistics: %w", err)
+	}
+
+	prettyStats, err := cmdCommon.PrettyJSONMarshal(stats)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(prettyStats))
+	return nil
+}
+
+func doCompact(cmd *cobra.Command, args []string) error {
+	rt, err := parseRuntime(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, closeFn, err := connectStorageWorker(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	logger.Info("compacting node database",
+		"rt", rt,
+	)
+	if err = client.Compact(context.Background(), &storageWorkerAPI.CompactRequest{
+		RuntimeID: rt,
+	}); err != nil {
+		return fmt.Errorf("failed to compact node database: %w", err)
+	}
+	logger.Info("node database compaction completed",
+		"rt", rt,
+	)
+	return nil
+}
+
+func registerCompactCmds(parentCmd *cobra.Command) {
+	storageStatsCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	storageCompactCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	parentCmd.AddCommand(storageStatsCmd)
+	parentCmd.AddCommand(storageCompactCmd)
+}
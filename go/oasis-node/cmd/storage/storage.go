@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -19,12 +21,15 @@ import (
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/history"
 	"github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage"
 )
 
+const cfgMigrateBackendTarget = "storage.migrate_backend.target"
+
 var (
 	storageCmd = &cobra.Command{
 		Use:   "storage",
@@ -45,6 +50,15 @@ var (
 		RunE:  doCheck,
 	}
 
+	storageMigrateBackendCmd = &cobra.Command{
+		Use:   "migrate-backend <runtime...>",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "copy node databases to a different storage backend",
+		RunE:  doMigrateBackend,
+	}
+
+	storageMigrateBackendFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	storageRenameNsCmd = &cobra.Command{
 		Use:   "rename-ns <src-ns> <dst-ns>",
 		Args:  cobra.ExactArgs(2),
@@ -238,6 +252,104 @@ func doCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func doMigrateBackend(cmd *cobra.Command, args []string) error {
+	dataDir := cmdCommon.DataDir()
+	ctx := context.Background()
+
+	srcBackend := strings.ToLower(viper.GetString(workerStorage.CfgBackend))
+	dstBackend := strings.ToLower(viper.GetString(cfgMigrateBackendTarget))
+	for _, backend := range []string{srcBackend, dstBackend} {
+		switch backend {
+		case database.BackendNameBadgerDB, database.BackendNameBBoltDB:
+		default:
+			return fmt.Errorf("unsupported storage backend: '%s'", backend)
+		}
+	}
+	if srcBackend == dstBackend {
+		return fmt.Errorf("source and target storage backends must be different")
+	}
+
+	runtimes, err := parseRuntimes(args)
+	cobra.CheckErr(err)
+
+	for _, rt := range runtimes {
+		if pretty {
+			fmt.Printf(" ** Migrating storage database for runtime %v from %s to %s...\n", rt, srcBackend, dstBackend)
+		}
+		err := func() error {
+			runtimeDir := registry.GetRuntimeStateDir(dataDir, rt)
+			srcDir := workerStorage.GetLocalBackendDBDir(runtimeDir, srcBackend)
+			dstDir := workerStorage.GetLocalBackendDBDir(runtimeDir, dstBackend)
+
+			if _, err := os.Stat(dstDir); !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("target node database '%s' already exists", dstDir)
+			}
+
+			src, err := database.NewNodeDB(srcBackend, &db.Config{
+				DB:        srcDir,
+				Namespace: rt,
+				ReadOnly:  true,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to open source node database: %w", err)
+			}
+			defer src.Close()
+
+			dst, err := database.NewNodeDB(dstBackend, &db.Config{
+				DB:        dstDir,
+				Namespace: rt,
+				NoFsync:   true,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create target node database: %w", err)
+			}
+
+			display := &displayHelper{}
+			display.DisplayStepBegin("copying versions")
+			err = database.CopyNodeDB(ctx, rt, dst, src, func(version, earliestVersion, latestVersion uint64) {
+				display.DisplayProgress("copied versions", version-earliestVersion+1, latestVersion-earliestVersion+1)
+			})
+			if err == nil {
+				err = dst.Sync()
+			}
+			dst.Close()
+			if err != nil {
+				// Do not leave a partially copied database around.
+				if rmErr := os.RemoveAll(dstDir); rmErr != nil {
+					logger.Error("failed to remove partially copied node database",
+						"err", rmErr,
+						"dir", dstDir,
+					)
+				}
+				return fmt.Errorf("failed to copy node database: %w", err)
+			}
+			display.DisplayStepEnd("done")
+
+			logger.Info("successfully migrated node database",
+				"rt", rt,
+				"src_backend", srcBackend,
+				"dst_backend", dstBackend,
+			)
+			return nil
+		}()
+		if err != nil {
+			logger.Error("error migrating node database", "rt", rt, "err", err)
+			if pretty {
+				fmt.Printf("error migrating node database for runtime %v: %v\n", rt, err)
+			}
+			return fmt.Errorf("error migrating node database for runtime %v: %w", rt, err)
+		}
+	}
+
+	if pretty {
+		fmt.Printf("Node databases migrated, set --%s=%s to use them. The old databases can be removed.\n",
+			workerStorage.CfgBackend,
+			dstBackend,
+		)
+	}
+	return nil
+}
+
 func doRenameNs(cmd *cobra.Command, args []string) error {
 	dataDir := cmdCommon.DataDir()
 
@@ -285,8 +397,17 @@ func doRenameNs(cmd *cobra.Command, args []string) error {
 func Register(parentCmd *cobra.Command) {
 	storageMigrateCmd.Flags().AddFlagSet(registry.Flags)
 	storageCheckCmd.Flags().AddFlagSet(registry.Flags)
+	storageMigrateBackendCmd.Flags().AddFlagSet(storageMigrateBackendFlags)
 	storageCmd.AddCommand(storageMigrateCmd)
 	storageCmd.AddCommand(storageCheckCmd)
+	storageCmd.AddCommand(storageMigrateBackendCmd)
 	storageCmd.AddCommand(storageRenameNsCmd)
+	registerCheckpointCmd(storageCmd)
+	registerCompactCmds(storageCmd)
 	parentCmd.AddCommand(storageCmd)
 }
+
+func init() {
+	storageMigrateBackendFlags.String(cfgMigrateBackendTarget, database.BackendNameBBoltDB, "target storage backend")
+	_ = viper.BindPFlags(storageMigrateBackendFlags)
+}
@@ -569,6 +569,52 @@ type ConsensusParameters struct {
 	MaxEvidenceAge uint64 `json:"max_evidence_age"`
 }
 
+// ConsensusParameterChanges are allowed roothash consensus parameter changes.
+type ConsensusParameterChanges struct {
+	// GasCosts are the new gas costs.
+	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
+
+	// MaxRuntimeMessages is the new maximum number of emitted runtime messages.
+	MaxRuntimeMessages *uint32 `json:"max_runtime_messages,omitempty"`
+
+	// MaxInRuntimeMessages is the new maximum number of incoming queued runtime messages.
+	MaxInRuntimeMessages *uint32 `json:"max_in_runtime_messages,omitempty"`
+
+	// MaxEvidenceAge is the new maximum evidence age.
+	MaxEvidenceAge *uint64 `json:"max_evidence_age,omitempty"`
+}
+
+// SanityCheck performs a sanity check on the consensus parameter changes.
+func (c *ConsensusParameterChanges) SanityCheck() error {
+	if c.GasCosts == nil &&
+		c.MaxRuntimeMessages == nil &&
+		c.MaxInRuntimeMessages == nil &&
+		c.MaxEvidenceAge == nil {
+		return fmt.Errorf("consensus parameter changes should not be empty")
+	}
+	return nil
+}
+
+// Apply applies changes to the given consensus parameters.
+func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
+	if c.GasCosts != nil {
+		params.GasCosts = make(transaction.Costs, len(c.GasCosts))
+		for k, v := range c.GasCosts {
+			params.GasCosts[k] = v
+		}
+	}
+	if c.MaxRuntimeMessages != nil {
+		params.MaxRuntimeMessages = *c.MaxRuntimeMessages
+	}
+	if c.MaxInRuntimeMessages != nil {
+		params.MaxInRuntimeMessages = *c.MaxInRuntimeMessages
+	}
+	if c.MaxEvidenceAge != nil {
+		params.MaxEvidenceAge = *c.MaxEvidenceAge
+	}
+	return nil
+}
+
 const (
 	// GasOpComputeCommit is the gas operation identifier for compute commits.
 	GasOpComputeCommit transaction.Op = "compute_commit"
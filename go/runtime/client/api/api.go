@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -10,6 +11,7 @@ import (
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 const (
@@ -18,6 +20,17 @@ const (
 
 	// RoundLatest is a special round number always referring to the latest round.
 	RoundLatest = roothash.RoundLatest
+
+	// MaxGetRangeLimit is the maximum number of entries that can be requested in a single
+	// GetRange request.
+	MaxGetRangeLimit = 1000
+
+	// DefaultQueryEventsLimit is the number of events returned by a single QueryEvents request in
+	// case no limit is given.
+	DefaultQueryEventsLimit = 100
+	// MaxQueryEventsLimit is the maximum number of events that can be requested in a single
+	// QueryEvents request.
+	MaxQueryEventsLimit = 1000
 )
 
 var (
@@ -34,6 +47,10 @@ var (
 	ErrCheckTxFailed = errors.New(ModuleName, 5, "client: transaction check failed")
 	// ErrNoHostedRuntime is returned when the hosted runtime is not available locally.
 	ErrNoHostedRuntime = errors.New(ModuleName, 6, "client: no hosted runtime is available")
+	// ErrInvalidArgument is an error returned when a request contains invalid arguments.
+	ErrInvalidArgument = errors.New(ModuleName, 7, "client: invalid argument")
+	// ErrIndexerDisabled is an error returned when querying the indexer while it is disabled.
+	ErrIndexerDisabled = errors.New(ModuleName, 8, "client: indexer is disabled")
 )
 
 // RuntimeClient is the runtime client interface.
@@ -75,11 +92,34 @@ type RuntimeClient interface {
 	// GetEvents returns all events emitted in a given block.
 	GetEvents(ctx context.Context, request *GetEventsRequest) ([]*Event, error)
 
+	// GetTransactionByHash fetches an indexed runtime transaction together with its results and
+	// the location where it was included.
+	GetTransactionByHash(ctx context.Context, request *GetTransactionByHashRequest) (*GetTransactionByHashResponse, error)
+
+	// QueryEvents returns indexed events with the given key (and optionally value) emitted in the
+	// given round range.
+	QueryEvents(ctx context.Context, request *QueryEventsRequest) ([]*Event, error)
+
+	// GetRange fetches the entries in the given key range of a runtime storage root together with
+	// a proof that can be verified using syncer.ProofVerifier.VerifyRange.
+	//
+	// The limit must be set and must not exceed MaxGetRangeLimit.
+	GetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.GetRangeResponse, error)
+
 	// Query makes a runtime-specific query.
 	Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error)
 
+	// GetTransactionStatus returns the current status of a transaction.
+	GetTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (*TransactionStatus, error)
+
 	// WatchBlocks subscribes to blocks for a specific runtimes.
 	WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
+
+	// WatchTransaction subscribes to status changes of a transaction.
+	//
+	// The current status is emitted immediately. The channel is closed after the transaction
+	// reaches a final status (included or rejected).
+	WatchTransaction(ctx context.Context, request *GetTransactionStatusRequest) (<-chan *TransactionStatus, pubsub.ClosableSubscription, error)
 }
 
 // SubmitTxResult is the raw result of submitting a transaction for processing.
@@ -107,6 +147,75 @@ type SubmitTxMetaResponse struct {
 	CheckTxError *protocol.Error `json:"check_tx_error,omitempty"`
 }
 
+// TransactionStatusKind is the kind of the transaction status.
+type TransactionStatusKind uint8
+
+const (
+	// TransactionStatusUnknown means that the transaction is not known to the node.
+	TransactionStatusUnknown TransactionStatusKind = 0
+	// TransactionStatusPendingCheck means that the transaction is waiting to be checked.
+	TransactionStatusPendingCheck TransactionStatusKind = 1
+	// TransactionStatusInPool means that the transaction has passed checks and is waiting in the
+	// transaction pool to be scheduled.
+	TransactionStatusInPool TransactionStatusKind = 2
+	// TransactionStatusScheduled means that the transaction has been included in a batch by the
+	// local scheduler and is waiting for the batch to be finalized.
+	TransactionStatusScheduled TransactionStatusKind = 3
+	// TransactionStatusIncluded means that the transaction has been included in a block.
+	TransactionStatusIncluded TransactionStatusKind = 4
+	// TransactionStatusRejected means that the transaction has failed checks.
+	TransactionStatusRejected TransactionStatusKind = 5
+)
+
+// String returns a string representation of the transaction status kind.
+func (k TransactionStatusKind) String() string {
+	switch k {
+	case TransactionStatusUnknown:
+		return "unknown"
+	case TransactionStatusPendingCheck:
+		return "pending check"
+	case TransactionStatusInPool:
+		return "in pool"
+	case TransactionStatusScheduled:
+		return "scheduled"
+	case TransactionStatusIncluded:
+		return "included"
+	case TransactionStatusRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("[unknown: %d]", uint8(k))
+	}
+}
+
+// GetTransactionStatusRequest is a GetTransactionStatus/WatchTransaction request.
+type GetTransactionStatusRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	TxHash    hash.Hash        `json:"tx_hash"`
+}
+
+// TransactionStatus is the status of a transaction.
+type TransactionStatus struct {
+	// Kind is the kind of the transaction status.
+	Kind TransactionStatusKind `json:"kind"`
+
+	// Round is the runtime round in which the transaction was included.
+	//
+	// Only set when the transaction has been included.
+	Round uint64 `json:"round,omitempty"`
+	// BatchOrder is the order of the transaction in the execution batch.
+	//
+	// Only set when the transaction has been included.
+	BatchOrder uint32 `json:"batch_order,omitempty"`
+
+	// CheckTxError is the CheckTx error in case the transaction has been rejected.
+	CheckTxError *protocol.Error `json:"check_tx_error,omitempty"`
+}
+
+// IsFinal returns true iff the transaction status will not change anymore.
+func (s *TransactionStatus) IsFinal() bool {
+	return s.Kind == TransactionStatusIncluded || s.Kind == TransactionStatusRejected
+}
+
 // CheckTxRequest is a CheckTx request.
 type CheckTxRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
@@ -138,6 +247,38 @@ type GetEventsRequest struct {
 	Round     uint64           `json:"round"`
 }
 
+// GetTransactionByHashRequest is a GetTransactionByHash request.
+type GetTransactionByHashRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	TxHash    hash.Hash        `json:"tx_hash"`
+}
+
+// GetTransactionByHashResponse is the GetTransactionByHash response.
+type GetTransactionByHashResponse struct {
+	// Round is the runtime round in which the transaction was included.
+	Round uint64 `json:"round"`
+	// BatchOrder is the order of the transaction in the execution batch.
+	BatchOrder uint32 `json:"batch_order"`
+	// Transaction is the transaction together with its results.
+	Transaction *TransactionWithResults `json:"transaction"`
+}
+
+// QueryEventsRequest is a QueryEvents request.
+type QueryEventsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Key is the event key.
+	Key []byte `json:"key"`
+	// Value is the optional event value. If not set, events with any value are returned.
+	Value []byte `json:"value,omitempty"`
+	// RoundMin is the minimum round (inclusive).
+	RoundMin uint64 `json:"round_min"`
+	// RoundMax is the maximum round (inclusive). RoundLatest can be used to not limit the range.
+	RoundMax uint64 `json:"round_max"`
+	// Limit is the maximum number of returned events. Zero means that the default limit
+	// (DefaultQueryEventsLimit) is used and the limit must not exceed MaxQueryEventsLimit.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
 // Event is an event emitted by a runtime in the form of a runtime transaction tag.
 //
 // Key and value semantics are runtime-dependent.
@@ -145,6 +286,8 @@ type Event struct {
 	Key    []byte    `json:"key"`
 	Value  []byte    `json:"value"`
 	TxHash hash.Hash `json:"tx_hash"`
+	// Round is the runtime round in which the event was emitted.
+	Round uint64 `json:"round,omitempty"`
 }
 
 // PlainEvent is an event emitted by a runtime in the form of a runtime transaction tag. It
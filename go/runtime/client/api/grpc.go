@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 var (
@@ -39,11 +40,21 @@ var (
 	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{})
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{})
+	// methodGetTransactionByHash is the GetTransactionByHash method.
+	methodGetTransactionByHash = serviceName.NewMethod("GetTransactionByHash", GetTransactionByHashRequest{})
+	// methodQueryEvents is the QueryEvents method.
+	methodQueryEvents = serviceName.NewMethod("QueryEvents", QueryEventsRequest{})
+	// methodGetRange is the GetRange method.
+	methodGetRange = serviceName.NewMethod("GetRange", syncer.GetRangeRequest{})
 	// methodQuery is the Query method.
 	methodQuery = serviceName.NewMethod("Query", QueryRequest{})
+	// methodGetTransactionStatus is the GetTransactionStatus method.
+	methodGetTransactionStatus = serviceName.NewMethod("GetTransactionStatus", GetTransactionStatusRequest{})
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
+	// methodWatchTransaction is the WatchTransaction method.
+	methodWatchTransaction = serviceName.NewMethod("WatchTransaction", GetTransactionStatusRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -90,10 +101,26 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetTransactionByHash.ShortName(),
+				Handler:    handlerGetTransactionByHash,
+			},
+			{
+				MethodName: methodQueryEvents.ShortName(),
+				Handler:    handlerQueryEvents,
+			},
+			{
+				MethodName: methodGetRange.ShortName(),
+				Handler:    handlerGetRange,
+			},
 			{
 				MethodName: methodQuery.ShortName(),
 				Handler:    handlerQuery,
 			},
+			{
+				MethodName: methodGetTransactionStatus.ShortName(),
+				Handler:    handlerGetTransactionStatus,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -101,6 +128,11 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchTransaction.ShortName(),
+				Handler:       handlerWatchTransaction,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -368,6 +400,75 @@ func handlerGetEvents( // nolint: golint
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerGetTransactionByHash( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq GetTransactionByHashRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).GetTransactionByHash(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionByHash.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).GetTransactionByHash(ctx, req.(*GetTransactionByHashRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerQueryEvents( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq QueryEventsRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).QueryEvents(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodQueryEvents.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).QueryEvents(ctx, req.(*QueryEventsRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerGetRange( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq syncer.GetRangeRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).GetRange(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRange.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).GetRange(ctx, req.(*syncer.GetRangeRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerQuery( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -393,6 +494,29 @@ func handlerQuery( // nolint: golint
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerGetTransactionStatus( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq GetTransactionStatusRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).GetTransactionStatus(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionStatus.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).GetTransactionStatus(ctx, req.(*GetTransactionStatusRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	var runtimeID common.Namespace
 	if err := stream.RecvMsg(&runtimeID); err != nil {
@@ -422,6 +546,35 @@ func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchTransaction(srv interface{}, stream grpc.ServerStream) error {
+	var rq GetTransactionStatusRequest
+	if err := stream.RecvMsg(&rq); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(RuntimeClient).WatchTransaction(ctx, &rq)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case status, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(status); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new runtime client service with the given gRPC server.
 func RegisterService(server *grpc.Server, service RuntimeClient) {
 	server.RegisterService(&serviceDesc, service)
@@ -503,6 +656,30 @@ func (c *runtimeClient) GetEvents(ctx context.Context, request *GetEventsRequest
 	return rsp, nil
 }
 
+func (c *runtimeClient) GetTransactionByHash(ctx context.Context, request *GetTransactionByHashRequest) (*GetTransactionByHashResponse, error) {
+	var rsp GetTransactionByHashResponse
+	if err := c.conn.Invoke(ctx, methodGetTransactionByHash.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *runtimeClient) QueryEvents(ctx context.Context, request *QueryEventsRequest) ([]*Event, error) {
+	var rsp []*Event
+	if err := c.conn.Invoke(ctx, methodQueryEvents.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *runtimeClient) GetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.GetRangeResponse, error) {
+	var rsp syncer.GetRangeResponse
+	if err := c.conn.Invoke(ctx, methodGetRange.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *runtimeClient) Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error) {
 	var rsp QueryResponse
 	if err := c.conn.Invoke(ctx, methodQuery.FullName(), request, &rsp); err != nil {
@@ -511,6 +688,14 @@ func (c *runtimeClient) Query(ctx context.Context, request *QueryRequest) (*Quer
 	return &rsp, nil
 }
 
+func (c *runtimeClient) GetTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (*TransactionStatus, error) {
+	var rsp TransactionStatus
+	if err := c.conn.Invoke(ctx, methodGetTransactionStatus.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *runtimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -546,6 +731,41 @@ func (c *runtimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namesp
 	return ch, sub, nil
 }
 
+func (c *runtimeClient) WatchTransaction(ctx context.Context, request *GetTransactionStatusRequest) (<-chan *TransactionStatus, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchTransaction.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *TransactionStatus)
+	go func() {
+		defer close(ch)
+
+		for {
+			var status TransactionStatus
+			if serr := stream.RecvMsg(&status); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &status:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // NewRuntimeClient creates a new gRPC runtime client service.
 func NewRuntimeClient(c *grpc.ClientConn) RuntimeClient {
 	return &runtimeClient{
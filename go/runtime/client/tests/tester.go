@@ -11,6 +11,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/mock"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
@@ -69,6 +70,39 @@ func testSubmitTransaction(
 	require.Nil(t, resp.CheckTxError, "SubmitTxMeta check tx error")
 	require.EqualValues(t, testInput, resp.Output)
 	require.True(t, resp.Round > 0, "SubmitTxMeta round should be non zero")
+
+	// Transaction status should reflect that the transaction has been included.
+	txHash := hash.NewFromBytes(testInput)
+	status, err := c.GetTransactionStatus(ctx, &api.GetTransactionStatusRequest{RuntimeID: runtimeID, TxHash: txHash})
+	require.NoError(t, err, "GetTransactionStatus")
+	require.Equal(t, api.TransactionStatusIncluded, status.Kind, "transaction should be included")
+	require.EqualValues(t, resp.Round, status.Round, "transaction should be included in the same round")
+
+	// Transaction should be retrievable by its hash.
+	txByHash, err := c.GetTransactionByHash(ctx, &api.GetTransactionByHashRequest{RuntimeID: runtimeID, TxHash: txHash})
+	require.NoError(t, err, "GetTransactionByHash")
+	require.EqualValues(t, resp.Round, txByHash.Round, "GetTransactionByHash should return the correct round")
+	require.EqualValues(t, testInput, txByHash.Transaction.Tx, "GetTransactionByHash should return the correct transaction")
+	require.EqualValues(t, resp.Output, txByHash.Transaction.Result, "GetTransactionByHash should return the correct result")
+
+	ch, sub, err := c.WatchTransaction(ctx, &api.GetTransactionStatusRequest{RuntimeID: runtimeID, TxHash: txHash})
+	require.NoError(t, err, "WatchTransaction")
+	defer sub.Close()
+
+	select {
+	case status = <-ch:
+		require.Equal(t, api.TransactionStatusIncluded, status.Kind, "watched transaction should be included")
+	case <-ctx.Done():
+		t.Fatalf("failed to receive transaction status: %s", ctx.Err())
+	}
+
+	// Unknown transactions should be reported as such.
+	status, err = c.GetTransactionStatus(ctx, &api.GetTransactionStatusRequest{
+		RuntimeID: runtimeID,
+		TxHash:    hash.NewFromBytes([]byte("unknown transaction")),
+	})
+	require.NoError(t, err, "GetTransactionStatus")
+	require.Equal(t, api.TransactionStatusUnknown, status.Kind, "transaction should be unknown")
 }
 
 func testFailSubmitTransaction(
@@ -9,6 +9,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
@@ -29,6 +30,15 @@ var (
 	//
 	// Value is CBOR-serialized roothash.RoundResults.
 	roundResultsKeyFmt = keyformat.New(0x03, uint64(0))
+	// txLocationKeyFmt is the transaction location index key format.
+	//
+	// Value is CBOR-serialized TransactionLocation.
+	txLocationKeyFmt = keyformat.New(0x04, &hash.Hash{})
+	// roundTxKeyFmt is the per-round transaction index key format. It is used
+	// to find the transaction location index entries to remove when pruning.
+	//
+	// Value is empty.
+	roundTxKeyFmt = keyformat.New(0x05, uint64(0), &hash.Hash{})
 )
 
 type dbMetadata struct {
@@ -208,6 +218,104 @@ func (d *DB) commit(blk *roothash.AnnotatedBlock, roundResults *roothash.RoundRe
 	})
 }
 
+func (d *DB) commitTransactions(round uint64, txs []IndexedTransaction) error {
+	return d.db.Update(func(tx *badger.Txn) error {
+		// Only index transactions for rounds which are present in history.
+		_, err := tx.Get(blockKeyFmt.Encode(round))
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			return roothash.ErrNotFound
+		default:
+			return err
+		}
+
+		for i := range txs {
+			loc := TransactionLocation{
+				Round:      round,
+				BatchOrder: txs[i].BatchOrder,
+			}
+			if err = tx.Set(txLocationKeyFmt.Encode(&txs[i].TxHash), cbor.Marshal(loc)); err != nil {
+				return err
+			}
+			if err = tx.Set(roundTxKeyFmt.Encode(round, &txs[i].TxHash), []byte{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *DB) getTransactionLocation(txHash hash.Hash) (*TransactionLocation, error) {
+	var loc TransactionLocation
+	txErr := d.db.View(func(tx *badger.Txn) error {
+		item, err := tx.Get(txLocationKeyFmt.Encode(&txHash))
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			return roothash.ErrNotFound
+		default:
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			return cbor.UnmarshalTrusted(val, &loc)
+		})
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return &loc, nil
+}
+
+// pruneTransactions removes the transaction index entries for the given round.
+func (d *DB) pruneTransactions(tx *badger.Txn, round uint64) error {
+	// NOTE: Do not prefetch values as we are only looking at keys.
+	it := tx.NewIterator(badger.IteratorOptions{
+		Prefix: roundTxKeyFmt.Encode(round),
+	})
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		var (
+			decRound uint64
+			txHash   hash.Hash
+		)
+		if !roundTxKeyFmt.Decode(it.Item().Key(), &decRound, &txHash) {
+			// This should not happen as the Badger iterator should take care of it.
+			panic("runtime/history: bad iterator")
+		}
+
+		// Only remove the location entry in case it still points to the pruned round as the
+		// same transaction could have been included again in a later round.
+		item, err := tx.Get(txLocationKeyFmt.Encode(&txHash))
+		switch err {
+		case nil:
+			var loc TransactionLocation
+			if err = item.Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &loc)
+			}); err != nil {
+				return err
+			}
+			if loc.Round == round {
+				toDelete = append(toDelete, txLocationKeyFmt.Encode(&txHash))
+			}
+		case badger.ErrKeyNotFound:
+		default:
+			return err
+		}
+		toDelete = append(toDelete, it.Item().KeyCopy(nil))
+	}
+
+	for _, key := range toDelete {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *DB) getBlock(round uint64) (*roothash.AnnotatedBlock, error) {
 	var blk roothash.AnnotatedBlock
 	txErr := d.db.View(func(tx *badger.Txn) error {
@@ -10,6 +10,7 @@ import (
 	"github.com/eapache/channels"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
@@ -41,10 +42,35 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// IndexedTransaction is a transaction included in a runtime block.
+type IndexedTransaction struct {
+	// TxHash is the transaction hash.
+	TxHash hash.Hash
+	// BatchOrder is the order of the transaction in the execution batch.
+	BatchOrder uint32
+}
+
+// TransactionLocation is the location of a transaction included in a runtime block.
+type TransactionLocation struct {
+	// Round is the runtime round in which the transaction was included.
+	Round uint64 `json:"round"`
+	// BatchOrder is the order of the transaction in the execution batch.
+	BatchOrder uint32 `json:"batch_order"`
+}
+
 // History is the runtime history interface.
 type History interface {
 	roothash.BlockHistory
 
+	// CommitTransactions indexes the transactions included in the given (already committed)
+	// round so that they can later be looked up by hash.
+	CommitTransactions(round uint64, txs []IndexedTransaction) error
+
+	// GetTransactionLocation returns the location of a previously indexed transaction.
+	//
+	// In case the transaction has not been indexed, roothash.ErrNotFound is returned.
+	GetTransactionLocation(ctx context.Context, txHash hash.Hash) (*TransactionLocation, error)
+
 	// Pruner returns the history pruner.
 	Pruner() Pruner
 
@@ -88,6 +114,14 @@ func (h *nopHistory) GetRoundResults(ctx context.Context, round uint64) (*rootha
 	return nil, errNopHistory
 }
 
+func (h *nopHistory) CommitTransactions(round uint64, txs []IndexedTransaction) error {
+	return errNopHistory
+}
+
+func (h *nopHistory) GetTransactionLocation(ctx context.Context, txHash hash.Hash) (*TransactionLocation, error) {
+	return nil, errNopHistory
+}
+
 func (h *nopHistory) Pruner() Pruner {
 	pruner, _ := NewNonePruner()(nil)
 	return pruner
@@ -202,6 +236,17 @@ func (h *runtimeHistory) GetRoundResults(ctx context.Context, round uint64) (*ro
 	return h.db.getRoundResults(resolvedRound)
 }
 
+func (h *runtimeHistory) CommitTransactions(round uint64, txs []IndexedTransaction) error {
+	return h.db.commitTransactions(round, txs)
+}
+
+func (h *runtimeHistory) GetTransactionLocation(ctx context.Context, txHash hash.Hash) (*TransactionLocation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return h.db.getTransactionLocation(txHash)
+}
+
 func (h *runtimeHistory) Pruner() Pruner {
 	return h.pruner
 }
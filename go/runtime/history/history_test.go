@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
@@ -110,6 +111,20 @@ func TestHistory(t *testing.T) {
 	require.NoError(err, "GetRoundResults")
 	require.Equal(roundResults, gotResults, "GetRoundResults should return the correct results")
 
+	txHash := hash.NewFromBytes([]byte("history test tx"))
+	_, err = history.GetTransactionLocation(context.Background(), txHash)
+	require.Error(err, "GetTransactionLocation should fail for non-indexed transaction")
+	require.Equal(roothash.ErrNotFound, err)
+
+	err = history.CommitTransactions(11, []IndexedTransaction{{TxHash: txHash, BatchOrder: 3}})
+	require.Error(err, "CommitTransactions should fail for non-existent round")
+	err = history.CommitTransactions(10, []IndexedTransaction{{TxHash: txHash, BatchOrder: 3}})
+	require.NoError(err, "CommitTransactions")
+
+	txLoc, err := history.GetTransactionLocation(context.Background(), txHash)
+	require.NoError(err, "GetTransactionLocation")
+	require.Equal(&TransactionLocation{Round: 10, BatchOrder: 3}, txLoc, "GetTransactionLocation should return the correct location")
+
 	// Close history and try to reopen and continue.
 	history.Close()
 
@@ -142,6 +157,10 @@ func TestHistory(t *testing.T) {
 	gotResults, err = history.GetRoundResults(context.Background(), 10)
 	require.NoError(err, "GetRoundResults")
 	require.Equal(roundResults, gotResults, "GetRoundResults should return the correct results")
+
+	txLoc, err = history.GetTransactionLocation(context.Background(), txHash)
+	require.NoError(err, "GetTransactionLocation")
+	require.Equal(&TransactionLocation{Round: 10, BatchOrder: 3}, txLoc, "GetTransactionLocation should return the correct location")
 }
 
 type testPruneHandler struct {
@@ -214,6 +233,11 @@ func TestHistoryPrune(t *testing.T) {
 
 		err = history.Commit(&blk, roundResults)
 		require.NoError(err, "Commit")
+
+		err = history.CommitTransactions(uint64(i), []IndexedTransaction{
+			{TxHash: hash.NewFromBytes([]byte(fmt.Sprintf("tx %d", i))), BatchOrder: 0},
+		})
+		require.NoError(err, "CommitTransactions")
 	}
 
 	// No more blocks after this point.
@@ -259,6 +283,15 @@ func TestHistoryPrune(t *testing.T) {
 			require.NoError(err, "GetRoundResults(%d)", i)
 			require.NotEmpty(roundResults.Messages, "GetRoundResults should return correct results for block %d", i)
 		}
+
+		txLoc, err := history.GetTransactionLocation(context.Background(), hash.NewFromBytes([]byte(fmt.Sprintf("tx %d", i))))
+		if i <= 40 {
+			require.Error(err, "GetTransactionLocation should fail for pruned transaction %d", i)
+			require.Equal(roothash.ErrNotFound, err)
+		} else {
+			require.NoError(err, "GetTransactionLocation(%d)", i)
+			require.EqualValues(i, txLoc.Round, "GetTransactionLocation should return the correct round")
+		}
 	}
 
 	// Ensure the prune handler was called.
@@ -120,6 +120,14 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 				break
 			}
 
+			if err := p.db.pruneTransactions(tx, round); err != nil {
+				if err == badger.ErrTxnTooBig {
+					// We can't prune any more rounds in this transaction.
+					break
+				}
+				return err
+			}
+
 			if err := tx.Delete(roundResultsKeyFmt.Encode(round)); err != nil {
 				if err == badger.ErrTxnTooBig {
 					// We can't prune any more rounds in this transaction.
@@ -260,6 +260,15 @@ type CheckTxMetadata struct {
 
 	// Weight are runtime specific transaction weights.
 	Weights map[transaction.Weight]uint64 `json:"weights,omitempty"`
+
+	// Sender is the unique identifier of the transaction sender.
+	//
+	// If set, the transaction scheduler will make sure that transactions from the same sender are
+	// scheduled in SenderSeq order and that a pending transaction can be replaced by another one
+	// with the same SenderSeq and a higher priority.
+	Sender []byte `json:"sender,omitempty"`
+	// SenderSeq is the per-sender sequence number of the transaction (e.g., the nonce).
+	SenderSeq uint64 `json:"sender_seq,omitempty"`
 }
 
 // IsSuccess returns true if transaction execution was successful.
@@ -275,7 +284,13 @@ func (r *CheckTxResult) ToCheckedTransaction(rawTx []byte) *transaction.CheckedT
 	case nil:
 		return transaction.NewCheckedTransaction(rawTx, 0, nil)
 	default:
-		return transaction.NewCheckedTransaction(rawTx, r.Meta.Priority, r.Meta.Weights)
+		return transaction.NewCheckedTransactionWithSender(
+			rawTx,
+			r.Meta.Priority,
+			r.Meta.Weights,
+			r.Meta.Sender,
+			r.Meta.SenderSeq,
+		)
 	}
 }
 
@@ -0,0 +1,329 @@
+// Package indexer implements the runtime event indexer.
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v3"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+const (
+	// DbFilename is the filename of the indexer database.
+	DbFilename = "indexer.db"
+
+	dbVersion = 1
+)
+
+var (
+	// metadataKeyFmt is the metadata key format.
+	//
+	// Value is CBOR-serialized dbMetadata.
+	metadataKeyFmt = keyformat.New(0x01)
+	// lastRoundKeyFmt is the last indexed round key format.
+	//
+	// Value is the CBOR-serialized last indexed round.
+	lastRoundKeyFmt = keyformat.New(0x02)
+	// eventKeyFmt is the event index key format. Events are indexed by the hash
+	// of the tag key, the round and the index of the tag within the round.
+	//
+	// Value is CBOR-serialized Event.
+	eventKeyFmt = keyformat.New(0x04, &hash.Hash{}, uint64(0), uint32(0))
+	// roundEventKeyFmt is the per-round event index key format. It is used
+	// to find the event index entries to remove when pruning.
+	//
+	// Value is empty.
+	roundEventKeyFmt = keyformat.New(0x05, uint64(0), &hash.Hash{}, uint32(0))
+
+	_ Indexer              = (*indexer)(nil)
+	_ history.PruneHandler = (*indexer)(nil)
+)
+
+type dbMetadata struct {
+	// RuntimeID is the runtime ID this database is for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Version is the database schema version.
+	Version uint64 `json:"version"`
+}
+
+// Event is an indexed event emitted by a runtime in the form of a runtime transaction tag.
+type Event struct {
+	// Round is the runtime round in which the event was emitted.
+	Round uint64 `json:"round"`
+	// TxHash is the hash of the transaction that emitted the event.
+	TxHash hash.Hash `json:"tx_hash"`
+	// Key is the tag key.
+	Key []byte `json:"key"`
+	// Value is the tag value.
+	Value []byte `json:"value"`
+}
+
+// EventQuery is a query for indexed events.
+type EventQuery struct {
+	// Key is the tag key that the events must have.
+	Key []byte
+	// Value is the tag value that the events must have. If nil, events with any value match.
+	Value []byte
+	// RoundMin is the minimum round (inclusive).
+	RoundMin uint64
+	// RoundMax is the maximum round (inclusive).
+	RoundMax uint64
+	// Limit is the maximum number of returned events. Zero means no limit.
+	Limit uint64
+}
+
+// Indexer is the runtime event indexer interface.
+//
+// Transaction locations are indexed by the runtime block history. The indexer also implements
+// history.PruneHandler so that it can be pruned in lockstep with the runtime block history.
+type Indexer interface {
+	history.PruneHandler
+
+	// Index indexes the tags emitted in the given round.
+	//
+	// Indexing the same round multiple times is allowed.
+	Index(round uint64, tags transaction.Tags) error
+
+	// LastIndexedRound returns the highest round that has been indexed.
+	//
+	// In case no rounds have been indexed yet, roothash.ErrNotFound is returned.
+	LastIndexedRound(ctx context.Context) (uint64, error)
+
+	// QueryEvents returns indexed events matching the given query, ordered by round.
+	QueryEvents(ctx context.Context, query *EventQuery) ([]*Event, error)
+
+	// Close closes the indexer.
+	Close()
+}
+
+type indexer struct {
+	logger *logging.Logger
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+}
+
+func (idx *indexer) Index(round uint64, tags transaction.Tags) error {
+	return idx.db.Update(func(tx *badger.Txn) error {
+		for i, tag := range tags {
+			keyHash := hash.NewFromBytes(tag.Key)
+			ev := Event{
+				Round:  round,
+				TxHash: tag.TxHash,
+				Key:    tag.Key,
+				Value:  tag.Value,
+			}
+			if err := tx.Set(eventKeyFmt.Encode(&keyHash, round, uint32(i)), cbor.Marshal(ev)); err != nil {
+				return err
+			}
+			if err := tx.Set(roundEventKeyFmt.Encode(round, &keyHash, uint32(i)), []byte{}); err != nil {
+				return err
+			}
+		}
+
+		// Rounds may be indexed out of order while backfilling, so only ever advance the last
+		// indexed round.
+		lastRound, err := getLastRound(tx)
+		switch err {
+		case nil:
+			if round <= lastRound {
+				return nil
+			}
+		case roothash.ErrNotFound:
+		default:
+			return err
+		}
+		return tx.Set(lastRoundKeyFmt.Encode(), cbor.Marshal(round))
+	})
+}
+
+func (idx *indexer) LastIndexedRound(ctx context.Context) (uint64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	var round uint64
+	txErr := idx.db.View(func(tx *badger.Txn) error {
+		var err error
+		round, err = getLastRound(tx)
+		return err
+	})
+	if txErr != nil {
+		return 0, txErr
+	}
+	return round, nil
+}
+
+func getLastRound(tx *badger.Txn) (uint64, error) {
+	item, err := tx.Get(lastRoundKeyFmt.Encode())
+	switch err {
+	case nil:
+	case badger.ErrKeyNotFound:
+		return 0, roothash.ErrNotFound
+	default:
+		return 0, err
+	}
+
+	var round uint64
+	if err = item.Value(func(val []byte) error {
+		return cbor.UnmarshalTrusted(val, &round)
+	}); err != nil {
+		return 0, err
+	}
+	return round, nil
+}
+
+func (idx *indexer) QueryEvents(ctx context.Context, query *EventQuery) ([]*Event, error) {
+	if query.RoundMin > query.RoundMax {
+		return nil, fmt.Errorf("runtime/indexer: invalid round range")
+	}
+
+	keyHash := hash.NewFromBytes(query.Key)
+
+	var events []*Event
+	txErr := idx.db.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix: eventKeyFmt.Encode(&keyHash),
+		})
+		defer it.Close()
+
+		for it.Seek(eventKeyFmt.Encode(&keyHash, query.RoundMin)); it.Valid(); it.Next() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			var (
+				decKeyHash hash.Hash
+				round      uint64
+				index      uint32
+			)
+			if !eventKeyFmt.Decode(it.Item().Key(), &decKeyHash, &round, &index) {
+				// This should not happen as the Badger iterator should take care of it.
+				panic("runtime/indexer: bad iterator")
+			}
+			if round > query.RoundMax {
+				break
+			}
+
+			var ev Event
+			if err := it.Item().Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &ev)
+			}); err != nil {
+				return err
+			}
+
+			// Make sure to skip any events with colliding key hashes.
+			if !bytes.Equal(ev.Key, query.Key) {
+				continue
+			}
+			if query.Value != nil && !bytes.Equal(ev.Value, query.Value) {
+				continue
+			}
+
+			events = append(events, &ev)
+			if query.Limit > 0 && uint64(len(events)) >= query.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return events, nil
+}
+
+// Implements history.PruneHandler.
+func (idx *indexer) Prune(ctx context.Context, rounds []uint64) error {
+	for _, round := range rounds {
+		if err := idx.pruneRound(round); err != nil {
+			return fmt.Errorf("runtime/indexer: failed to prune round %d: %w", round, err)
+		}
+	}
+	return nil
+}
+
+func (idx *indexer) pruneRound(round uint64) error {
+	return idx.db.Update(func(tx *badger.Txn) error {
+		return cmnBadger.DeleteIndexed(tx, roundEventKeyFmt.Encode(round), func(key []byte) ([]byte, bool) {
+			var (
+				decRound uint64
+				keyHash  hash.Hash
+				index    uint32
+			)
+			if !roundEventKeyFmt.Decode(key, &decRound, &keyHash, &index) {
+				return nil, false
+			}
+			return eventKeyFmt.Encode(&keyHash, round, index), true
+		})
+	})
+}
+
+func (idx *indexer) Close() {
+	idx.gc.Close()
+	idx.db.Close()
+}
+
+func (idx *indexer) ensureMetadata(runtimeID common.Namespace) error {
+	initial := dbMetadata{
+		RuntimeID: runtimeID,
+		Version:   dbVersion,
+	}
+	return cmnBadger.EnsureMetadata(idx.db, metadataKeyFmt.Encode(), &initial, func(raw []byte) error {
+		var meta dbMetadata
+		if err := cbor.Unmarshal(raw, &meta); err != nil {
+			return err
+		}
+
+		// Verify metadata section.
+		if meta.Version != dbVersion {
+			return fmt.Errorf("runtime/indexer: incompatible database version (expected: %d got: %d)",
+				dbVersion,
+				meta.Version,
+			)
+		}
+		if !meta.RuntimeID.Equal(&runtimeID) {
+			return fmt.Errorf("runtime/indexer: database for different runtime (expected: %s got: %s)",
+				runtimeID,
+				meta.RuntimeID,
+			)
+		}
+		return nil
+	})
+}
+
+// New creates a new runtime indexer.
+func New(dataDir string, runtimeID common.Namespace) (Indexer, error) {
+	fn := filepath.Join(dataDir, DbFilename)
+	logger := logging.GetLogger("runtime/indexer").With("path", fn)
+
+	db, gc, err := cmnBadger.OpenIndex(fn, logger)
+	if err != nil {
+		return nil, fmt.Errorf("runtime/indexer: %w", err)
+	}
+
+	idx := &indexer{
+		logger: logger,
+		db:     db,
+		gc:     gc,
+	}
+
+	if err = idx.ensureMetadata(runtimeID); err != nil {
+		idx.Close()
+		return nil, err
+	}
+
+	return idx, nil
+}
@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+func TestIndexer(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-indexer-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("indexer test ns 1"), 0)
+	runtimeID2 := common.NewTestNamespaceFromSeed([]byte("indexer test ns 2"), 0)
+
+	idx, err := New(dataDir, runtimeID)
+	require.NoError(err, "New")
+
+	tx1 := &transaction.Transaction{Input: []byte("tx 1"), BatchOrder: 0}
+	tx2 := &transaction.Transaction{Input: []byte("tx 2"), BatchOrder: 1}
+	tx3 := &transaction.Transaction{Input: []byte("tx 3"), BatchOrder: 0}
+
+	_, err = idx.LastIndexedRound(ctx)
+	require.Error(err, "LastIndexedRound should fail when nothing has been indexed")
+	require.Equal(roothash.ErrNotFound, err)
+
+	err = idx.Index(10, transaction.Tags{
+		{Key: []byte("transfer"), Value: []byte("alice"), TxHash: tx1.Hash()},
+		{Key: []byte("transfer"), Value: []byte("bob"), TxHash: tx2.Hash()},
+		{Key: []byte("burn"), Value: []byte("alice"), TxHash: tx2.Hash()},
+	})
+	require.NoError(err, "Index")
+	err = idx.Index(11, transaction.Tags{
+		{Key: []byte("transfer"), Value: []byte("alice"), TxHash: tx3.Hash()},
+	})
+	require.NoError(err, "Index")
+
+	lastRound, err := idx.LastIndexedRound(ctx)
+	require.NoError(err, "LastIndexedRound")
+	require.EqualValues(11, lastRound, "LastIndexedRound should return the highest indexed round")
+
+	events, err := idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 0, RoundMax: 100})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 3, "QueryEvents should return all matching events")
+	require.EqualValues(10, events[0].Round)
+	require.EqualValues(10, events[1].Round)
+	require.EqualValues(11, events[2].Round)
+
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), Value: []byte("alice"), RoundMin: 0, RoundMax: 100})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 2, "QueryEvents should filter by value")
+	require.Equal(tx1.Hash(), events[0].TxHash)
+	require.Equal(tx3.Hash(), events[1].TxHash)
+
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 11, RoundMax: 11})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 1, "QueryEvents should filter by round range")
+	require.Equal(&Event{Round: 11, TxHash: tx3.Hash(), Key: []byte("transfer"), Value: []byte("alice")}, events[0])
+
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 0, RoundMax: 100, Limit: 1})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 1, "QueryEvents should respect the limit")
+
+	_, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 11, RoundMax: 10})
+	require.Error(err, "QueryEvents should fail for an invalid round range")
+
+	// Indexing an earlier round (e.g., when backfilling) should not move the last indexed round.
+	err = idx.Index(5, nil)
+	require.NoError(err, "Index")
+	lastRound, err = idx.LastIndexedRound(ctx)
+	require.NoError(err, "LastIndexedRound")
+	require.EqualValues(11, lastRound, "LastIndexedRound should not decrease")
+
+	// Pruning should remove all entries for the given rounds.
+	err = idx.Prune(ctx, []uint64{10})
+	require.NoError(err, "Prune")
+
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 0, RoundMax: 100})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 1, "QueryEvents should not return pruned events")
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("burn"), RoundMin: 0, RoundMax: 100})
+	require.NoError(err, "QueryEvents")
+	require.Empty(events, "QueryEvents should not return pruned events")
+
+	// Reopening the indexer should retain all entries.
+	idx.Close()
+	idx, err = New(dataDir, runtimeID)
+	require.NoError(err, "New")
+
+	events, err = idx.QueryEvents(ctx, &EventQuery{Key: []byte("transfer"), RoundMin: 0, RoundMax: 100})
+	require.NoError(err, "QueryEvents")
+	require.Len(events, 1, "QueryEvents should return events after reopening")
+	lastRound, err = idx.LastIndexedRound(ctx)
+	require.NoError(err, "LastIndexedRound")
+	require.EqualValues(11, lastRound, "LastIndexedRound should be retained after reopening")
+	idx.Close()
+
+	// Opening an indexer for a different runtime should fail.
+	_, err = New(dataDir, runtimeID2)
+	require.Error(err, "New should fail for a different runtime")
+}
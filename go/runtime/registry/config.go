@@ -100,6 +100,10 @@ const (
 	// stateless client for all the configured runtimes. No state is kept locally and the node must
 	// connect to remote nodes to perform any runtime queries.
 	RuntimeModeClientStateless RuntimeMode = "client-stateless"
+	// RuntimeModeStorageReplica is the runtime mode where the node does not register, does not host
+	// any runtimes and only follows finalized state of all the configured runtimes, serving state
+	// queries. It never participates in committees or creates checkpoints.
+	RuntimeModeStorageReplica RuntimeMode = "storage-replica"
 )
 
 // UnmarshalText decodes a text marshaled runtime mode.
@@ -115,6 +119,8 @@ func (m *RuntimeMode) UnmarshalText(text []byte) error {
 		*m = RuntimeModeClient
 	case string(RuntimeModeClientStateless):
 		*m = RuntimeModeClientStateless
+	case string(RuntimeModeStorageReplica):
+		*m = RuntimeModeStorageReplica
 	default:
 		return fmt.Errorf("invalid mode: %s", string(text))
 	}
@@ -125,12 +131,22 @@ func (m *RuntimeMode) UnmarshalText(text []byte) error {
 // as a client for all configured runtimes.
 func (m RuntimeMode) IsClientOnly() bool {
 	switch m {
-	case RuntimeModeClient, RuntimeModeClientStateless:
+	case RuntimeModeClient, RuntimeModeClientStateless, RuntimeModeStorageReplica:
 		return true
 	}
 	return false
 }
 
+// HostsRuntimes returns true iff the mode is one that has the node hosting the configured
+// runtimes (assuming any are configured).
+func (m RuntimeMode) HostsRuntimes() bool {
+	switch m {
+	case RuntimeModeNone, RuntimeModeStorageReplica:
+		return false
+	}
+	return true
+}
+
 // RuntimeConfig is the node runtime configuration.
 type RuntimeConfig struct {
 	// Mode is the runtime mode for this node.
@@ -229,7 +245,7 @@ func newConfig(dataDir string, consensus consensus.Backend, ias ias.Endpoint) (*
 
 			fallthrough
 		case RuntimeProvisionerSandboxed:
-			if !insecureNoSandbox {
+			if !insecureNoSandbox && cfg.Mode.HostsRuntimes() {
 				if _, err = os.Stat(sandboxBinary); err != nil {
 					return nil, fmt.Errorf("failed to stat sandbox binary: %w", err)
 				}
@@ -389,7 +405,7 @@ func init() {
 	Flags.Duration(CfgHistoryPrunerInterval, 2*time.Minute, "History pruning interval")
 	Flags.Uint64(CfgHistoryPrunerKeepLastNum, 600, "Keep last history pruner: number of last rounds to keep")
 
-	Flags.String(CfgRuntimeMode, string(RuntimeModeNone), "Runtime mode (none, compute, keymanager, client, client-stateless)")
+	Flags.String(CfgRuntimeMode, string(RuntimeModeNone), "Runtime mode (none, compute, keymanager, client, client-stateless, storage-replica)")
 
 	Flags.StringSlice(CfgDebugMockIDs, nil, "Mock runtime IDs (format: <path>,<path>,...)")
 	Flags.Bool(CfgDebugForceELF, false, "Force the use of the ELF image over any TEE images")
@@ -0,0 +1,35 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeMode(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		mode          RuntimeMode
+		isClientOnly  bool
+		hostsRuntimes bool
+	}{
+		{RuntimeModeNone, false, false},
+		{RuntimeModeCompute, false, true},
+		{RuntimeModeKeymanager, false, true},
+		{RuntimeModeClient, true, true},
+		{RuntimeModeClientStateless, true, true},
+		{RuntimeModeStorageReplica, true, false},
+	} {
+		var mode RuntimeMode
+		err := mode.UnmarshalText([]byte(tc.mode))
+		require.NoError(err, "UnmarshalText(%s)", tc.mode)
+		require.Equal(tc.mode, mode, "UnmarshalText(%s)", tc.mode)
+		require.Equal(tc.isClientOnly, mode.IsClientOnly(), "IsClientOnly(%s)", tc.mode)
+		require.Equal(tc.hostsRuntimes, mode.HostsRuntimes(), "HostsRuntimes(%s)", tc.mode)
+	}
+
+	var mode RuntimeMode
+	err := mode.UnmarshalText([]byte("storage"))
+	require.Error(err, "UnmarshalText should fail for invalid modes")
+}
@@ -557,7 +557,7 @@ func newRuntime(
 	go rt.watchUpdates(watchCtx)
 
 	// Configure runtime host if needed.
-	if cfg.Host != nil {
+	if cfg.Host != nil && cfg.Mode.HostsRuntimes() {
 		rt.hostProvisioners = cfg.Host.Provisioners
 		rt.hostConfig = cfg.Host.Runtimes[id]
 	}
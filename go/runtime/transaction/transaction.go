@@ -85,6 +85,29 @@ var (
 	tagKeyFmt = keyformat.New('E', []byte{}, &hash.Hash{})
 )
 
+// DescribeIOKey returns a human-readable description of the given IO tree key.
+//
+// It returns false in case the key is not a valid IO tree key.
+func DescribeIOKey(key []byte) (string, bool) {
+	var (
+		txHash hash.Hash
+		kind   artifactKind
+		tagKey []byte
+	)
+	switch {
+	case len(key) == txnKeyFmt.Size() && txnKeyFmt.Decode(key, &txHash, &kind):
+		switch kind {
+		case kindInput:
+			return fmt.Sprintf("transaction %s input", txHash), true
+		case kindOutput:
+			return fmt.Sprintf("transaction %s output", txHash), true
+		}
+	case len(key) >= tagKeyFmt.Size() && tagKeyFmt.Decode(key, &tagKey, &txHash):
+		return fmt.Sprintf("tag %X of transaction %s", tagKey, txHash), true
+	}
+	return "", false
+}
+
 // ValidateIOWriteLog validates the writelog for IO storage.
 func ValidateIOWriteLog(writeLog writelog.WriteLog, maxBatchSize, maxBatchSizeBytes uint64) error {
 	var (
@@ -183,11 +206,23 @@ type CheckedTransaction struct {
 	// in the CheckTx response.
 	weights map[Weight]uint64
 
+	// sender is the optional transaction sender identifier as specified by
+	// the runtime in the CheckTx response.
+	sender []byte
+	// senderSeq is the per-sender sequence number as specified by the runtime
+	// in the CheckTx response.
+	senderSeq uint64
+
 	hash hash.Hash
 }
 
 // String returns string representation of the raw transaction data.
 func (t *CheckedTransaction) String() string {
+	if len(t.sender) > 0 {
+		return fmt.Sprintf("CheckedTransaction{hash: %v, priority: %v, weights: %v, sender: %X, sender_seq: %v}",
+			t.hash, t.priority, t.weights, t.sender, t.senderSeq,
+		)
+	}
 	return fmt.Sprintf("CheckedTransaction{hash: %v, priority: %v, weights: %v}", t.hash, t.priority, t.weights)
 }
 
@@ -199,14 +234,28 @@ func RawCheckedTransaction(raw []byte) *CheckedTransaction {
 // NewCheckedTransaction creates a new CheckedTransactions from the provided
 // bytes, priority and weights.
 func NewCheckedTransaction(tx []byte, priority uint64, weights map[Weight]uint64) *CheckedTransaction {
+	return NewCheckedTransactionWithSender(tx, priority, weights, nil, 0)
+}
+
+// NewCheckedTransactionWithSender creates a new CheckedTransactions from the
+// provided bytes, priority, weights and sender information.
+func NewCheckedTransactionWithSender(
+	tx []byte,
+	priority uint64,
+	weights map[Weight]uint64,
+	sender []byte,
+	senderSeq uint64,
+) *CheckedTransaction {
 	if weights == nil {
 		weights = make(map[Weight]uint64)
 	}
 	checkedTx := &CheckedTransaction{
-		tx:       tx,
-		priority: priority,
-		weights:  weights,
-		hash:     hash.NewFromBytes(tx),
+		tx:        tx,
+		priority:  priority,
+		weights:   weights,
+		sender:    sender,
+		senderSeq: senderSeq,
+		hash:      hash.NewFromBytes(tx),
 	}
 	checkedTx.weights[WeightSizeBytes] = checkedTx.Size()
 	checkedTx.weights[WeightCount] = 1
@@ -232,6 +281,20 @@ func (t *CheckedTransaction) Weights() map[Weight]uint64 {
 	return t.weights
 }
 
+// Sender returns the transaction sender identifier (if any).
+//
+// The caller should not modify the returned slice.
+func (t *CheckedTransaction) Sender() []byte {
+	return t.sender
+}
+
+// SenderSeq returns the per-sender sequence number of the transaction.
+//
+// This is only meaningful in case Sender is set.
+func (t *CheckedTransaction) SenderSeq() uint64 {
+	return t.senderSeq
+}
+
 // Hash returns the hash of the transaction binary data.
 func (t *CheckedTransaction) Hash() hash.Hash {
 	return t.hash
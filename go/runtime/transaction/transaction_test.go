@@ -264,3 +264,29 @@ func TestIOWriteLogValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestDescribeIOKey(t *testing.T) {
+	require := require.New(t)
+
+	txHash := hash.NewFromBytes([]byte("transaction"))
+	for _, tc := range []struct {
+		key         []byte
+		description string
+		ok          bool
+	}{
+		{txnKeyFmt.Encode(&txHash, kindInput), fmt.Sprintf("transaction %s input", txHash), true},
+		{txnKeyFmt.Encode(&txHash, kindOutput), fmt.Sprintf("transaction %s output", txHash), true},
+		{tagKeyFmt.Encode([]byte("tag"), &txHash), fmt.Sprintf("tag 746167 of transaction %s", txHash), true},
+		{tagKeyFmt.Encode([]byte{}, &txHash), fmt.Sprintf("tag  of transaction %s", txHash), true},
+		{append(txnKeyFmt.Encode(&txHash), 0x03), "", false},
+		{txnKeyFmt.Encode(&txHash), "", false},
+		{[]byte("T"), "", false},
+		{[]byte("E"), "", false},
+		{[]byte("foo"), "", false},
+		{[]byte{}, "", false},
+	} {
+		description, ok := DescribeIOKey(tc.key)
+		require.Equal(tc.ok, ok, "DescribeIOKey(%X)", tc.key)
+		require.Equal(tc.description, description, "DescribeIOKey(%X)", tc.key)
+	}
+}
@@ -0,0 +1,348 @@
+package txpool
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+const (
+	// JournalDbFilename is the filename of the local transaction journal database.
+	JournalDbFilename = "txpool-journal.badger.db"
+
+	journalDbVersion = 1
+)
+
+var (
+	// journalMetadataKeyFmt is the metadata key format.
+	//
+	// Value is CBOR-serialized journalMetadata.
+	journalMetadataKeyFmt = keyformat.New(0x01)
+	// journalTxKeyFmt is the journaled transaction key format.
+	//
+	// Value is CBOR-serialized journalEntry.
+	journalTxKeyFmt = keyformat.New(0x02, &hash.Hash{})
+)
+
+type journalMetadata struct {
+	// RuntimeID is the runtime ID this journal is for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Version is the database schema version.
+	Version uint64 `json:"version"`
+}
+
+type journalEntry struct {
+	// Tx is the raw transaction.
+	Tx []byte `json:"tx"`
+	// Timestamp is the UNIX timestamp (in seconds) when the transaction was first journaled.
+	Timestamp int64 `json:"timestamp"`
+}
+
+func (e *journalEntry) isExpired(now time.Time, expiry time.Duration) bool {
+	if expiry == 0 {
+		return false
+	}
+	return now.Sub(time.Unix(e.Timestamp, 0)) >= expiry
+}
+
+// journal is an on-disk journal of locally submitted transactions which makes it possible to
+// resubmit them after the node restarts.
+type journal struct {
+	sync.Mutex
+
+	logger *logging.Logger
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+
+	maxSize uint64
+	expiry  time.Duration
+	size    uint64
+}
+
+// Add adds the given transactions to the journal in a single database transaction. Transactions
+// that are already journaled retain their original timestamp so that resubmitted transactions
+// still expire.
+//
+// In case the journal is full, any transactions that do not fit are not journaled and an error is
+// returned.
+func (j *journal) Add(txs []*transaction.CheckedTransaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	// In case the journal would become full, try to make room by removing any expired transactions.
+	if j.size+uint64(len(txs)) > j.maxSize {
+		if err := j.pruneExpiredLocked(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	var added, dropped uint64
+	now := time.Now().Unix()
+	err := j.db.Update(func(txn *badger.Txn) error {
+		for _, tx := range txs {
+			txHash := tx.Hash()
+			key := journalTxKeyFmt.Encode(&txHash)
+			_, err := txn.Get(key)
+			switch err {
+			case nil:
+				// Already journaled.
+				continue
+			case badger.ErrKeyNotFound:
+			default:
+				return err
+			}
+
+			if j.size+added >= j.maxSize {
+				dropped++
+				continue
+			}
+
+			entry := journalEntry{
+				Tx:        tx.Raw(),
+				Timestamp: now,
+			}
+			if err = txn.Set(key, cbor.Marshal(&entry)); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("txpool/journal: failed to add transactions: %w", err)
+	}
+	j.size += added
+
+	if dropped > 0 {
+		return fmt.Errorf("txpool/journal: journal is full (%d transactions not journaled)", dropped)
+	}
+	return nil
+}
+
+// Remove removes the given transactions from the journal. Transactions that are not journaled
+// are ignored.
+func (j *journal) Remove(txHashes []hash.Hash) error {
+	j.Lock()
+	defer j.Unlock()
+
+	var removed uint64
+	err := j.db.Update(func(txn *badger.Txn) error {
+		for i := range txHashes {
+			key := journalTxKeyFmt.Encode(&txHashes[i])
+			_, err := txn.Get(key)
+			switch err {
+			case nil:
+			case badger.ErrKeyNotFound:
+				continue
+			default:
+				return err
+			}
+
+			if err = txn.Delete(key); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("txpool/journal: failed to remove transactions: %w", err)
+	}
+	j.size -= removed
+	return nil
+}
+
+// Load removes any expired transactions from the journal and returns the remaining ones.
+func (j *journal) Load() ([][]byte, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	if err := j.pruneExpiredLocked(time.Now()); err != nil {
+		return nil, err
+	}
+
+	var txs [][]byte
+	err := j.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: journalTxKeyFmt.Encode()})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var entry journalEntry
+			if err := it.Item().Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &entry)
+			}); err != nil {
+				return err
+			}
+			txs = append(txs, entry.Tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("txpool/journal: failed to load transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// Size returns the number of journaled transactions.
+func (j *journal) Size() uint64 {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.size
+}
+
+// Close closes the journal.
+func (j *journal) Close() {
+	j.Lock()
+	defer j.Unlock()
+
+	j.gc.Close()
+	j.db.Close()
+}
+
+// NOTE: Assumes lock is held.
+func (j *journal) pruneExpiredLocked(now time.Time) error {
+	if j.expiry == 0 {
+		return nil
+	}
+
+	var pruned uint64
+	err := j.db.Update(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: journalTxKeyFmt.Encode()})
+		defer it.Close()
+
+		var toDelete [][]byte
+		for it.Rewind(); it.Valid(); it.Next() {
+			var entry journalEntry
+			if err := it.Item().Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &entry)
+			}); err != nil {
+				return err
+			}
+			if entry.isExpired(now, j.expiry) {
+				toDelete = append(toDelete, it.Item().KeyCopy(nil))
+			}
+		}
+
+		for _, key := range toDelete {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		pruned = uint64(len(toDelete))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("txpool/journal: failed to prune expired transactions: %w", err)
+	}
+	if pruned > 0 {
+		j.logger.Debug("pruned expired transactions",
+			"num_txs", pruned,
+		)
+	}
+	j.size -= pruned
+	return nil
+}
+
+func (j *journal) ensureMetadata(runtimeID common.Namespace) error {
+	return j.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(journalMetadataKeyFmt.Encode())
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			// Create new metadata section.
+			meta := journalMetadata{
+				RuntimeID: runtimeID,
+				Version:   journalDbVersion,
+			}
+			return txn.Set(journalMetadataKeyFmt.Encode(), cbor.Marshal(meta))
+		default:
+			return err
+		}
+
+		var meta journalMetadata
+		if err = item.Value(func(val []byte) error {
+			return cbor.Unmarshal(val, &meta)
+		}); err != nil {
+			return err
+		}
+
+		// Verify metadata section.
+		if meta.Version != journalDbVersion {
+			return fmt.Errorf("txpool/journal: unsupported database version (expected: %d got: %d)",
+				journalDbVersion,
+				meta.Version,
+			)
+		}
+		if !meta.RuntimeID.Equal(&runtimeID) {
+			return fmt.Errorf("txpool/journal: database for different runtime (expected: %s got: %s)",
+				runtimeID,
+				meta.RuntimeID,
+			)
+		}
+		return nil
+	})
+}
+
+func (j *journal) countEntries() error {
+	return j.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{
+			Prefix:         journalTxKeyFmt.Encode(),
+			PrefetchValues: false,
+		})
+		defer it.Close()
+
+		j.size = 0
+		for it.Rewind(); it.Valid(); it.Next() {
+			j.size++
+		}
+		return nil
+	})
+}
+
+func openJournal(dataDir string, runtimeID common.Namespace, maxSize uint64, expiry time.Duration) (*journal, error) {
+	fn := filepath.Join(dataDir, JournalDbFilename)
+	logger := logging.GetLogger("runtime/txpool/journal").With("path", fn)
+
+	opts := badger.DefaultOptions(fn)
+	opts = opts.WithLogger(cmnBadger.NewLogAdapter(logger))
+	opts = opts.WithSyncWrites(true)
+	opts = opts.WithCompression(options.None)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("txpool/journal: failed to open database: %w", err)
+	}
+
+	j := &journal{
+		logger:  logger,
+		db:      db,
+		gc:      cmnBadger.NewGCWorker(logger, db),
+		maxSize: maxSize,
+		expiry:  expiry,
+	}
+
+	if err = j.ensureMetadata(runtimeID); err != nil {
+		j.Close()
+		return nil, err
+	}
+	if err = j.countEntries(); err != nil {
+		j.Close()
+		return nil, fmt.Errorf("txpool/journal: failed to count entries: %w", err)
+	}
+
+	return j, nil
+}
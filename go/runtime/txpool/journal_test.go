@@ -0,0 +1,77 @@
+package txpool
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-txpool-journal-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("txpool journal test ns 1"), 0)
+	runtimeID2 := common.NewTestNamespaceFromSeed([]byte("txpool journal test ns 2"), 0)
+
+	j, err := openJournal(dataDir, runtimeID, 3, 0)
+	require.NoError(err, "openJournal")
+
+	txs, err := j.Load()
+	require.NoError(err, "Load")
+	require.Empty(txs, "journal should initially be empty")
+
+	tx1, tx2, tx3, tx4 := []byte("tx 1"), []byte("tx 2"), []byte("tx 3"), []byte("tx 4")
+	checkedTx := func(raw []byte) *transaction.CheckedTransaction {
+		return transaction.RawCheckedTransaction(raw)
+	}
+	err = j.Add([]*transaction.CheckedTransaction{checkedTx(tx1), checkedTx(tx2), checkedTx(tx1)})
+	require.NoError(err, "Add")
+	require.EqualValues(2, j.Size(), "Add should ignore duplicate transactions in a batch")
+	err = j.Add([]*transaction.CheckedTransaction{checkedTx(tx1), checkedTx(tx3)})
+	require.NoError(err, "Add should ignore already journaled transactions")
+	require.EqualValues(3, j.Size(), "Size")
+
+	err = j.Add([]*transaction.CheckedTransaction{checkedTx(tx4)})
+	require.Error(err, "Add should fail when the journal is full")
+	require.EqualValues(3, j.Size(), "Size")
+
+	err = j.Remove([]hash.Hash{hash.NewFromBytes(tx2), hash.NewFromBytes(tx4)})
+	require.NoError(err, "Remove")
+	require.EqualValues(2, j.Size(), "Size")
+
+	// Reopening the journal should retain all transactions.
+	j.Close()
+	j, err = openJournal(dataDir, runtimeID, 3, 0)
+	require.NoError(err, "openJournal")
+	require.EqualValues(2, j.Size(), "Size after reopen")
+
+	txs, err = j.Load()
+	require.NoError(err, "Load")
+	require.ElementsMatch([][]byte{tx1, tx3}, txs, "journaled transactions should be loaded")
+	j.Close()
+
+	// Opening a journal for a different runtime should fail.
+	_, err = openJournal(dataDir, runtimeID2, 3, 0)
+	require.Error(err, "openJournal should fail for a different runtime")
+
+	// Expired transactions should be discarded on load.
+	j, err = openJournal(dataDir, runtimeID, 3, time.Nanosecond)
+	require.NoError(err, "openJournal")
+	defer j.Close()
+
+	txs, err = j.Load()
+	require.NoError(err, "Load")
+	require.Empty(txs, "expired transactions should be discarded")
+	require.EqualValues(0, j.Size(), "Size")
+}
@@ -3,25 +3,68 @@ package txpool
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 
 	"github.com/google/btree"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	p2pError "github.com/oasisprotocol/oasis-core/go/worker/common/p2p/error"
 )
 
+// maxLastSenderSeqs is the maximum number of senders for which the sequence number of the last
+// processed transaction is remembered.
+const maxLastSenderSeqs = 100_000
+
 type item struct {
 	tx *transaction.CheckedTransaction
+
+	// effectivePriority is the priority used for ordering the transaction. For transactions
+	// without a sender this is the same as the transaction priority. For transactions with a
+	// sender this is the lowest priority of the transaction itself and any pending transactions
+	// from the same sender with a lower sequence number. This makes sure that transactions from
+	// the same sender are always ordered by their sequence numbers.
+	effectivePriority uint64
+
+	// scheduled is a flag indicating that the transaction has already been returned as part of
+	// a batch by GetBatch or GetPrioritizedBatch.
+	scheduled bool
+}
+
+func newItem(tx *transaction.CheckedTransaction) *item {
+	return &item{
+		tx:                tx,
+		effectivePriority: tx.Priority(),
+	}
+}
+
+// senderKey returns the key used to group transactions by sender. Transactions without a sender
+// are each treated as a separate sender.
+func (i item) senderKey() []byte {
+	if sender := i.tx.Sender(); len(sender) > 0 {
+		return sender
+	}
+	h := i.tx.Hash()
+	return h[:]
 }
 
 func (i item) Less(other btree.Item) bool {
 	i2 := other.(*item)
-	if p1, p2 := i.tx.Priority(), i2.tx.Priority(); p1 != p2 {
+	if p1, p2 := i.effectivePriority, i2.effectivePriority; p1 != p2 {
 		return p1 < p2
 	}
-	// If transactions have same priority, sort arbitrary.
+	// If transactions have same priority, sort arbitrary but make sure that transactions from the
+	// same sender are ordered by sequence number (lowest sequence number is considered greater as
+	// the index is traversed in descending order).
+	if cmp := bytes.Compare(i.senderKey(), i2.senderKey()); cmp != 0 {
+		return cmp < 0
+	}
+	if s1, s2 := i.tx.SenderSeq(), i2.tx.SenderSeq(); s1 != s2 {
+		return s1 > s2
+	}
 	h1 := i.tx.Hash()
 	h2 := i2.tx.Hash()
 	return bytes.Compare(h1[:], h2[:]) < 0
@@ -32,8 +75,14 @@ type priorityQueue struct {
 
 	priorityIndex *btree.BTree
 	transactions  map[hash.Hash]*item
+	// senders contains pending transactions of each sender, ordered by sequence number.
+	senders map[string][]*item
+	// lastSenderSeqs maps senders to the sequence number of their last transaction that has been
+	// removed from the queue after being scheduled.
+	lastSenderSeqs *lru.Cache
 
-	maxTxPoolSize uint64
+	maxTxPoolSize          uint64
+	maxTxPoolSizePerSender uint64
 
 	poolWeights  map[transaction.Weight]uint64
 	weightLimits map[transaction.Weight]uint64
@@ -45,9 +94,19 @@ func (q *priorityQueue) Add(tx *transaction.CheckedTransaction) error {
 	q.Lock()
 	defer q.Unlock()
 
-	// Check if there is room in the queue.
+	if err := q.checkTxLocked(tx); err != nil {
+		return err
+	}
+
+	// Check if the transaction replaces a pending transaction from the same sender.
+	replaced, err := q.checkSenderLocked(tx)
+	if err != nil {
+		return err
+	}
+
+	// Check if there is room in the queue. Replacements do not change the queue size.
 	var needsPop bool
-	if q.poolWeights[transaction.WeightCount] >= q.maxTxPoolSize {
+	if replaced == nil && q.poolWeights[transaction.WeightCount] >= q.maxTxPoolSize {
 		needsPop = true
 
 		if tx.Priority() <= q.lowestPriority {
@@ -55,27 +114,28 @@ func (q *priorityQueue) Add(tx *transaction.CheckedTransaction) error {
 		}
 	}
 
-	if err := q.checkTxLocked(tx); err != nil {
-		return err
-	}
-
-	// Remove the lowest priority transaction when queue is full.
-	if needsPop {
+	switch {
+	case replaced != nil:
+		// Remove the transaction being replaced.
+		q.removeTxsLocked([]*item{replaced})
+	case needsPop:
+		// Remove the lowest priority transaction when queue is full.
 		lpi := q.priorityIndex.Min()
 		if lpi != nil {
 			q.removeTxsLocked([]*item{lpi.(*item)})
 		}
 	}
 
-	item := &item{tx: tx}
+	item := newItem(tx)
 	q.priorityIndex.ReplaceOrInsert(item)
 	q.transactions[tx.Hash()] = item
 	for k, v := range tx.Weights() {
 		q.poolWeights[k] += v
 	}
-	if tx.Priority() < q.lowestPriority {
-		q.lowestPriority = tx.Priority()
+	if sender := tx.Sender(); len(sender) > 0 {
+		q.insertSenderItemLocked(string(sender), item)
 	}
+	q.updateLowestPriorityLocked()
 
 	if mlen, qlen := len(q.transactions), q.priorityIndex.Len(); mlen != qlen {
 		panic(fmt.Errorf("inconsistent sizes of the underlying index (%v) and map (%v) after Add", mlen, qlen))
@@ -115,9 +175,21 @@ func (q *priorityQueue) GetBatch(force bool) []*transaction.CheckedTransaction {
 		batchWeights[w] = 0
 	}
 	toRemove := []*item{}
+	// Senders for which a transaction has been skipped as it didn't fit into the batch. Any
+	// subsequent transactions from these senders must also be skipped to preserve ordering.
+	skippedSenders := make(map[string]struct{})
+	nextSeqs := make(map[string]uint64)
 	q.priorityIndex.Descend(func(i btree.Item) bool {
 		item := i.(*item)
 
+		sender := string(item.tx.Sender())
+		if _, skipped := skippedSenders[sender]; skipped {
+			return true
+		}
+		if !q.isReadyLocked(item, nextSeqs) {
+			return true
+		}
+
 		// Check if the call fits into the batch.
 		for w, limit := range q.weightLimits {
 			batchWeight := batchWeights[w]
@@ -136,12 +208,16 @@ func (q *priorityQueue) GetBatch(force bool) []*transaction.CheckedTransaction {
 
 			// This transaction would overflow the batch.
 			if batchWeight+txW > limit {
+				if sender != "" {
+					skippedSenders[sender] = struct{}{}
+				}
 				return true
 			}
 		}
 
 		// Add the tx to the batch.
 		batch = append(batch, item.tx)
+		item.scheduled = true
 		for w, val := range item.tx.Weights() {
 			if _, ok := batchWeights[w]; ok {
 				batchWeights[w] += val
@@ -160,6 +236,7 @@ func (q *priorityQueue) GetBatch(force bool) []*transaction.CheckedTransaction {
 }
 
 func (q *priorityQueue) removeTxsLocked(items []*item) {
+	affectedSenders := make(map[string]struct{})
 	for _, item := range items {
 		// Skip already removed items to avoid corrupting the list in case of duplicates.
 		if _, exists := q.transactions[item.tx.Hash()]; !exists {
@@ -171,15 +248,21 @@ func (q *priorityQueue) removeTxsLocked(items []*item) {
 		for k, v := range item.tx.Weights() {
 			q.poolWeights[k] -= v
 		}
+
+		if sender := item.tx.Sender(); len(sender) > 0 {
+			q.removeSenderItemLocked(string(sender), item)
+			affectedSenders[string(sender)] = struct{}{}
+		}
+	}
+
+	// Update effective priorities of the remaining transactions from affected senders.
+	for sender := range affectedSenders {
+		q.updateSenderLocked(sender)
 	}
 
 	// Update lowest priority.
 	if len(items) > 0 {
-		if lpi := q.priorityIndex.Min(); lpi != nil {
-			q.lowestPriority = lpi.(*item).tx.Priority()
-		} else {
-			q.lowestPriority = 0
-		}
+		q.updateLowestPriorityLocked()
 	}
 
 	if mlen, qlen := len(q.transactions), q.priorityIndex.Len(); mlen != qlen {
@@ -198,6 +281,7 @@ func (q *priorityQueue) GetPrioritizedBatch(offset *hash.Hash, limit uint32) []*
 		batch      []*transaction.CheckedTransaction
 		toRemove   []*item
 		offsetItem btree.Item
+		nextSeqs   = make(map[string]uint64)
 	)
 	if offset != nil {
 		var exists bool
@@ -224,8 +308,13 @@ func (q *priorityQueue) GetPrioritizedBatch(offset *hash.Hash, limit uint32) []*
 			return true
 		}
 
+		if !q.isReadyLocked(item, nextSeqs) {
+			return true
+		}
+
 		// Add the tx to the batch.
 		batch = append(batch, item.tx)
+		item.scheduled = true
 		if uint32(len(batch)) >= limit { //nolint: gosimple
 			return false
 		}
@@ -282,8 +371,21 @@ func (q *priorityQueue) RemoveTxBatch(batch []hash.Hash) {
 
 	items := make([]*item, 0, len(batch))
 	for _, txHash := range batch {
-		if item, ok := q.transactions[txHash]; ok {
-			items = append(items, item)
+		item, ok := q.transactions[txHash]
+		if !ok {
+			continue
+		}
+		items = append(items, item)
+
+		// Remember the sequence number of processed transactions so that any gaps after it can be
+		// detected even when there are no more pending transactions from the same sender.
+		if sender := item.tx.Sender(); item.scheduled && len(sender) > 0 {
+			seq := item.tx.SenderSeq()
+			if last, ok := q.lastSenderSeqs.Peek(string(sender)); ok && last.(uint64) >= seq {
+				continue
+			}
+			// Put cannot fail as the cache's capacity is not in bytes.
+			_ = q.lastSenderSeqs.Put(string(sender), seq)
 		}
 	}
 	q.removeTxsLocked(items)
@@ -296,6 +398,19 @@ func (q *priorityQueue) IsQueued(txHash hash.Hash) bool {
 	return q.isQueuedLocked(txHash)
 }
 
+// Status returns whether the given transaction is queued and whether it has already been returned
+// as part of a batch.
+func (q *priorityQueue) Status(txHash hash.Hash) (queued bool, scheduled bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	item, ok := q.transactions[txHash]
+	if !ok {
+		return false, false
+	}
+	return true, item.scheduled
+}
+
 func (q *priorityQueue) Size() uint64 {
 	q.Lock()
 	defer q.Unlock()
@@ -311,6 +426,14 @@ func (q *priorityQueue) UpdateMaxPoolSize(maxPoolSize uint64) {
 	// Any transaction not within the new limits will get removed during GetBatch iteration.
 }
 
+func (q *priorityQueue) UpdateMaxPoolSizePerSender(maxPoolSizePerSender uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.maxTxPoolSizePerSender = maxPoolSizePerSender
+	// Existing transactions are kept, the new limit only applies to new transactions.
+}
+
 func (q *priorityQueue) UpdateWeightLimits(limits map[transaction.Weight]uint64) {
 	q.Lock()
 	defer q.Unlock()
@@ -325,6 +448,8 @@ func (q *priorityQueue) Clear() {
 
 	q.priorityIndex.Clear(true)
 	q.transactions = make(map[hash.Hash]*item)
+	q.senders = make(map[string][]*item)
+	q.lastSenderSeqs.Clear()
 	q.poolWeights = make(map[transaction.Weight]uint64)
 	q.lowestPriority = 0
 }
@@ -346,18 +471,168 @@ func (q *priorityQueue) checkTxLocked(tx *transaction.CheckedTransaction) error
 	return nil
 }
 
+// checkSenderLocked checks whether the given transaction can be added to the pending transactions
+// of its sender. In case the transaction replaces an existing pending transaction, the replaced
+// transaction is returned.
+//
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) checkSenderLocked(tx *transaction.CheckedTransaction) (*item, error) {
+	sender := tx.Sender()
+	if len(sender) == 0 {
+		return nil, nil
+	}
+
+	items := q.senders[string(sender)]
+	for _, item := range items {
+		if item.tx.SenderSeq() != tx.SenderSeq() {
+			continue
+		}
+
+		// A transaction with the same sequence number is already pending, only allow replacing it
+		// with a transaction of higher priority.
+		if tx.Priority() <= item.tx.Priority() {
+			return nil, fmt.Errorf("replacement tx priority too low (existing: %d new: %d)",
+				item.tx.Priority(), tx.Priority(),
+			)
+		}
+		return item, nil
+	}
+
+	if q.maxTxPoolSizePerSender > 0 && uint64(len(items)) >= q.maxTxPoolSizePerSender {
+		return nil, fmt.Errorf("too many pending txs from sender")
+	}
+	return nil, nil
+}
+
+// insertSenderItemLocked inserts the given item into the list of pending transactions of the
+// given sender and updates effective priorities.
+//
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) insertSenderItemLocked(sender string, item *item) {
+	items := q.senders[sender]
+	seq := item.tx.SenderSeq()
+	idx := sort.Search(len(items), func(i int) bool {
+		return items[i].tx.SenderSeq() > seq
+	})
+	items = append(items, nil)
+	copy(items[idx+1:], items[idx:])
+	items[idx] = item
+	q.senders[sender] = items
+
+	q.updateSenderLocked(sender)
+}
+
+// removeSenderItemLocked removes the given item from the list of pending transactions of the
+// given sender. The caller is responsible for updating effective priorities.
+//
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) removeSenderItemLocked(sender string, item *item) {
+	items := q.senders[sender]
+	for i, it := range items {
+		if it != item {
+			continue
+		}
+		items = append(items[:i], items[i+1:]...)
+		break
+	}
+
+	switch len(items) {
+	case 0:
+		delete(q.senders, sender)
+	default:
+		q.senders[sender] = items
+	}
+}
+
+// updateSenderLocked recomputes the effective priorities of pending transactions of the given
+// sender and reindexes any transactions whose effective priority changed.
+//
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) updateSenderLocked(sender string) {
+	minPriority := uint64(math.MaxUint64)
+	for _, item := range q.senders[sender] {
+		if p := item.tx.Priority(); p < minPriority {
+			minPriority = p
+		}
+		if item.effectivePriority == minPriority {
+			continue
+		}
+
+		// Items need to be removed from the index before their ordering key can be changed.
+		q.priorityIndex.Delete(item)
+		item.effectivePriority = minPriority
+		q.priorityIndex.ReplaceOrInsert(item)
+	}
+}
+
+// isReadyLocked checks whether the given transaction can be scheduled, which is not the case when
+// there is a gap between its sequence number and the sequence number of the last processed or
+// preceding pending transaction of the same sender. The given map is used to cache the next
+// expected sequence number of each sender within a single batch.
+//
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) isReadyLocked(item *item, nextSeqs map[string]uint64) bool {
+	sender := string(item.tx.Sender())
+	if sender == "" {
+		return true
+	}
+
+	nextSeq, ok := nextSeqs[sender]
+	if !ok {
+		items := q.senders[sender]
+		switch last, known := q.lastSenderSeqs.Peek(sender); known {
+		case true:
+			nextSeq = last.(uint64) + 1
+		case false:
+			// Without any processed transactions the lowest pending sequence number is assumed
+			// to be the next one.
+			nextSeq = items[0].tx.SenderSeq()
+		}
+		for _, it := range items {
+			seq := it.tx.SenderSeq()
+			if seq > nextSeq {
+				break
+			}
+			if seq == nextSeq {
+				nextSeq++
+			}
+		}
+		nextSeqs[sender] = nextSeq
+	}
+	return item.tx.SenderSeq() < nextSeq
+}
+
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) updateLowestPriorityLocked() {
+	if lpi := q.priorityIndex.Min(); lpi != nil {
+		q.lowestPriority = lpi.(*item).effectivePriority
+	} else {
+		q.lowestPriority = 0
+	}
+}
+
 // NOTE: Assumes lock is held.
 func (q *priorityQueue) isQueuedLocked(txHash hash.Hash) bool {
 	_, ok := q.transactions[txHash]
 	return ok
 }
 
-func newPriorityQueue(maxPoolSize uint64, weightLimits map[transaction.Weight]uint64) *priorityQueue {
+func newPriorityQueue(
+	maxPoolSize uint64,
+	maxPoolSizePerSender uint64,
+	weightLimits map[transaction.Weight]uint64,
+) *priorityQueue {
+	// Creating the cache cannot fail as the capacity is not in bytes.
+	lastSenderSeqs, _ := lru.New(lru.Capacity(maxLastSenderSeqs, false))
+
 	return &priorityQueue{
-		transactions:  make(map[hash.Hash]*item),
-		poolWeights:   make(map[transaction.Weight]uint64),
-		priorityIndex: btree.New(2),
-		maxTxPoolSize: maxPoolSize,
-		weightLimits:  weightLimits,
+		transactions:           make(map[hash.Hash]*item),
+		senders:                make(map[string][]*item),
+		lastSenderSeqs:         lastSenderSeqs,
+		poolWeights:            make(map[transaction.Weight]uint64),
+		priorityIndex:          btree.New(2),
+		maxTxPoolSize:          maxPoolSize,
+		maxTxPoolSizePerSender: maxPoolSizePerSender,
+		weightLimits:           weightLimits,
 	}
 }
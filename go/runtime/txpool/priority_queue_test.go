@@ -15,7 +15,7 @@ import (
 )
 
 func TestPriorityQueue(t *testing.T) {
-	queue := newPriorityQueue(10, nil)
+	queue := newPriorityQueue(10, 0, nil)
 
 	t.Run("TestBasic", func(t *testing.T) {
 		testBasic(t, queue)
@@ -40,6 +40,14 @@ func TestPriorityQueue(t *testing.T) {
 	t.Run("TestPriority", func(t *testing.T) {
 		testPriority(t, queue)
 	})
+
+	t.Run("TestSenders", func(t *testing.T) {
+		testSenders(t, queue)
+	})
+
+	t.Run("TestSenderGaps", func(t *testing.T) {
+		testSenderGaps(t, queue)
+	})
 }
 
 func testBasic(t *testing.T, queue *priorityQueue) {
@@ -379,8 +387,173 @@ func testPriority(t *testing.T, queue *priorityQueue) {
 	require.Error(t, err, "lower priority transaction should not get queued")
 }
 
+func testSenders(t *testing.T, queue *priorityQueue) {
+	queue.Clear()
+
+	queue.UpdateMaxPoolSize(10)
+	queue.UpdateMaxPoolSizePerSender(3)
+	queue.UpdateWeightLimits(map[transaction.Weight]uint64{
+		transaction.WeightCount:     10,
+		transaction.WeightSizeBytes: 100,
+	})
+
+	alice := []byte("alice")
+	bob := []byte("bob")
+	newTx := func(raw string, priority uint64, sender []byte, seq uint64) *transaction.CheckedTransaction {
+		return transaction.NewCheckedTransactionWithSender([]byte(raw), priority, nil, sender, seq)
+	}
+
+	// Submit transactions out of order, with later ones having higher priority.
+	alice1 := newTx("alice 1", 10, alice, 1)
+	alice0 := newTx("alice 0", 5, alice, 0)
+	alice2 := newTx("alice 2", 30, alice, 2)
+	bob0 := newTx("bob 0", 20, bob, 0)
+	other := newTx("other", 7, nil, 0)
+	for _, tx := range []*transaction.CheckedTransaction{alice1, alice2, bob0, alice0, other} {
+		require.NoError(t, queue.Add(tx), "Add")
+	}
+	require.EqualValues(t, 5, queue.Size(), "Size")
+
+	expected := []*transaction.CheckedTransaction{
+		bob0,   // 20
+		other,  // 7
+		alice0, // 5
+		alice1, // 10 (but must come after alice0)
+		alice2, // 30 (but must come after alice1)
+	}
+	batch := queue.GetBatch(true)
+	require.EqualValues(t, expected, batch, "transactions from the same sender should be ordered by sequence")
+	batch = queue.GetPrioritizedBatch(nil, 10)
+	require.EqualValues(t, expected, batch, "transactions from the same sender should be ordered by sequence")
+
+	offsetTx := alice0.Hash()
+	batch = queue.GetPrioritizedBatch(&offsetTx, 10)
+	require.EqualValues(t, expected[3:], batch, "offset should be respected")
+
+	// Per-sender limit.
+	err := queue.Add(newTx("alice 3", 100, alice, 3))
+	require.Error(t, err, "Add should fail when too many transactions are pending from the same sender")
+
+	// Replacement with the same or lower priority should fail.
+	err = queue.Add(newTx("alice 0 replacement low", 5, alice, 0))
+	require.Error(t, err, "replacement with the same priority should fail")
+	err = queue.Add(newTx("alice 0 replacement lower", 1, alice, 0))
+	require.Error(t, err, "replacement with a lower priority should fail")
+
+	// Replacement with higher priority should succeed.
+	alice0r := newTx("alice 0 replacement", 50, alice, 0)
+	require.NoError(t, queue.Add(alice0r), "replacement with a higher priority should succeed")
+	require.EqualValues(t, 5, queue.Size(), "Size")
+	require.False(t, queue.IsQueued(alice0.Hash()), "replaced transaction should be removed")
+
+	batch = queue.GetBatch(true)
+	require.EqualValues(t,
+		[]*transaction.CheckedTransaction{
+			alice0r, // 50
+			bob0,    // 20
+			alice1,  // 10
+			alice2,  // 30 (but must come after alice1)
+			other,   // 7
+		},
+		batch,
+		"effective priorities should be updated after replacement",
+	)
+
+	// If a transaction doesn't fit into the batch, subsequent ones from the same sender
+	// should not be scheduled either.
+	queue.UpdateWeightLimits(map[transaction.Weight]uint64{
+		transaction.WeightCount:     10,
+		transaction.WeightSizeBytes: 30,
+	})
+	bigTx := newTx("bob 1 which is a bit larger", 100, bob, 1)
+	bob2 := newTx("bob 2", 100, bob, 2)
+	require.NoError(t, queue.Add(bigTx), "Add")
+	require.NoError(t, queue.Add(bob2), "Add")
+	batch = queue.GetBatch(true)
+	require.NotContains(t, batch, bob2, "subsequent transaction should not be scheduled")
+
+	// Removing scheduled transactions should unblock subsequent ones.
+	queue.UpdateWeightLimits(map[transaction.Weight]uint64{
+		transaction.WeightCount:     10,
+		transaction.WeightSizeBytes: 100,
+	})
+	queue.RemoveTxBatch([]hash.Hash{bob0.Hash(), alice0r.Hash(), alice1.Hash()})
+	require.EqualValues(t, 4, queue.Size(), "Size")
+	batch = queue.GetBatch(true)
+	require.EqualValues(t,
+		[]*transaction.CheckedTransaction{
+			bigTx,  // 100
+			bob2,   // 100
+			alice2, // 30
+			other,  // 7
+		},
+		batch,
+		"remaining transactions should be ordered by priority",
+	)
+
+	// A full pool should evict the tail of a sender's queue first.
+	queue.Clear()
+	queue.UpdateMaxPoolSize(2)
+	require.NoError(t, queue.Add(newTx("alice 0", 10, alice, 0)), "Add")
+	tail := newTx("alice 1", 1, alice, 1)
+	require.NoError(t, queue.Add(tail), "Add")
+	require.NoError(t, queue.Add(newTx("bob 0", 5, bob, 0)), "Add")
+	require.False(t, queue.IsQueued(tail.Hash()), "lowest priority transaction should be evicted")
+	require.EqualValues(t, 2, queue.Size(), "Size")
+}
+
+func testSenderGaps(t *testing.T, queue *priorityQueue) {
+	queue.Clear()
+
+	queue.UpdateMaxPoolSize(10)
+	queue.UpdateMaxPoolSizePerSender(0)
+	queue.UpdateWeightLimits(map[transaction.Weight]uint64{
+		transaction.WeightCount:     10,
+		transaction.WeightSizeBytes: 100,
+	})
+
+	alice := []byte("alice")
+	newTx := func(raw string, priority uint64, seq uint64) *transaction.CheckedTransaction {
+		return transaction.NewCheckedTransactionWithSender([]byte(raw), priority, nil, alice, seq)
+	}
+
+	// Without any processed transactions, the lowest pending sequence number is the next one.
+	alice0 := newTx("alice 0", 10, 0)
+	alice2 := newTx("alice 2", 10, 2)
+	require.NoError(t, queue.Add(alice2), "Add")
+	require.NoError(t, queue.Add(alice0), "Add")
+	batch := queue.GetBatch(true)
+	require.EqualValues(t, []*transaction.CheckedTransaction{alice0}, batch, "transactions after a gap should be held back")
+	batch = queue.GetPrioritizedBatch(nil, 10)
+	require.EqualValues(t, []*transaction.CheckedTransaction{alice0}, batch, "transactions after a gap should be held back")
+
+	// Processing the scheduled transaction should keep the gap.
+	queue.RemoveTxBatch([]hash.Hash{alice0.Hash()})
+	batch = queue.GetBatch(true)
+	require.Empty(t, batch, "transactions after a gap should be held back after processing")
+
+	// Filling the gap should unblock subsequent transactions.
+	alice1 := newTx("alice 1", 5, 1)
+	require.NoError(t, queue.Add(alice1), "Add")
+	batch = queue.GetBatch(true)
+	require.EqualValues(t, []*transaction.CheckedTransaction{alice1, alice2}, batch, "filling the gap should unblock transactions")
+
+	// Out of order arrival with no pending transactions should be held back until the missing
+	// transaction arrives.
+	queue.RemoveTxBatch([]hash.Hash{alice1.Hash(), alice2.Hash()})
+	require.EqualValues(t, 0, queue.Size(), "Size")
+	alice4 := newTx("alice 4", 20, 4)
+	require.NoError(t, queue.Add(alice4), "Add")
+	batch = queue.GetBatch(true)
+	require.Empty(t, batch, "out of order transaction should be held back")
+	alice3 := newTx("alice 3", 10, 3)
+	require.NoError(t, queue.Add(alice3), "Add")
+	batch = queue.GetBatch(true)
+	require.EqualValues(t, []*transaction.CheckedTransaction{alice3, alice4}, batch, "transactions should be scheduled in order")
+}
+
 func BenchmarkPriorityQueue(b *testing.B) {
-	queue := newPriorityQueue(10, nil)
+	queue := newPriorityQueue(10, 0, nil)
 	values := prepareValues(b)
 	batchSize := 10000
 
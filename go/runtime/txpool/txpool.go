@@ -31,7 +31,10 @@ const (
 
 // Config is the transaction pool configuration.
 type Config struct {
-	MaxPoolSize          uint64
+	MaxPoolSize uint64
+	// MaxPoolSizePerSender is the maximum number of pending transactions from the same sender. This
+	// only applies to transactions for which the runtime reports a sender. Zero means no limit.
+	MaxPoolSizePerSender uint64
 	MaxCheckTxBatchSize  uint64
 	MaxLastSeenCacheSize uint64
 	MaxStaleCacheSize    uint64
@@ -41,6 +44,13 @@ type Config struct {
 	// RecheckInterval is the interval (in rounds) when any pending transactions are subject to a
 	// recheck and any non-passing transactions are removed.
 	RecheckInterval uint64
+
+	// MaxJournalSize is the maximum number of locally submitted transactions that are kept in the
+	// on-disk journal and resubmitted after the node restarts. Zero disables the journal.
+	MaxJournalSize uint64
+	// JournalExpiry is the time after which journaled transactions are discarded. Zero means that
+	// journaled transactions never expire.
+	JournalExpiry time.Duration
 }
 
 // TransactionMeta contains the per-transaction metadata.
@@ -56,6 +66,42 @@ type TransactionMeta struct {
 	Recheck bool
 }
 
+// TxStatus is the status of a transaction in the transaction pool.
+type TxStatus uint8
+
+const (
+	// TxStatusUnknown means that the transaction is not known to the transaction pool.
+	TxStatusUnknown TxStatus = iota
+	// TxStatusPendingCheck means that the transaction is waiting to be checked.
+	TxStatusPendingCheck
+	// TxStatusPendingSchedule means that the transaction has passed checks and is waiting to be
+	// scheduled.
+	TxStatusPendingSchedule
+	// TxStatusScheduled means that the transaction has been included in a batch by the local
+	// scheduler and is waiting for the batch to be finalized.
+	TxStatusScheduled
+	// TxStatusRejected means that the transaction has recently failed checks.
+	TxStatusRejected
+)
+
+// String returns a string representation of the transaction status.
+func (s TxStatus) String() string {
+	switch s {
+	case TxStatusUnknown:
+		return "unknown"
+	case TxStatusPendingCheck:
+		return "pending check"
+	case TxStatusPendingSchedule:
+		return "pending schedule"
+	case TxStatusScheduled:
+		return "scheduled"
+	case TxStatusRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("[unknown: %d]", uint8(s))
+	}
+}
+
 // TransactionPool is an interface for managing a pool of transactions.
 type TransactionPool interface {
 	// Start starts the service.
@@ -67,6 +113,9 @@ type TransactionPool interface {
 	// Quit returns a channel that will be closed when the service terminates.
 	Quit() <-chan struct{}
 
+	// Cleanup performs the service specific post-termination cleanup.
+	Cleanup()
+
 	// Submit adds the transaction into the transaction pool, first performing checks on it by
 	// invoking the runtime. This method waits for the checks to complete.
 	SubmitTx(ctx context.Context, tx []byte, meta *TransactionMeta) (*protocol.CheckTxResult, error)
@@ -119,6 +168,10 @@ type TransactionPool interface {
 
 	// PendingScheduleSize returns the number of transactions currently pending to be scheduled.
 	PendingScheduleSize() uint64
+
+	// GetTxStatus returns the status of the given transaction in the transaction pool. In case the
+	// transaction has been rejected, the check error is also returned.
+	GetTxStatus(txHash hash.Hash) (TxStatus, *protocol.Error)
 }
 
 // RuntimeHostProvisioner is a runtime host provisioner.
@@ -165,12 +218,18 @@ type txPool struct {
 	host        RuntimeHostProvisioner
 	txPublisher TransactionPublisher
 
+	// journal is the optional on-disk journal of locally submitted transactions.
+	journal *journal
+
 	// seenCache maps from transaction hashes to time.Time that specifies when the transaction was
 	// last published.
 	seenCache *lru.Cache
 	// staleCache maps from transaction hashes to *transaction.CheckedTransaction. It is populated
 	// when clearing the txpool and consulted only when fetching known batches.
 	staleCache *lru.Cache
+	// rejectedCache maps from transaction hashes to *protocol.Error. It is populated when a
+	// transaction fails checks and is consulted only when querying transaction status.
+	rejectedCache *lru.Cache
 
 	checkTxCh       *channels.RingChannel
 	checkTxQueue    *checkTxQueue
@@ -209,6 +268,12 @@ func (t *txPool) Quit() <-chan struct{} {
 	return t.quitCh
 }
 
+func (t *txPool) Cleanup() {
+	if t.journal != nil {
+		t.journal.Close()
+	}
+}
+
 func (t *txPool) SubmitTx(ctx context.Context, rawTx []byte, meta *TransactionMeta) (*protocol.CheckTxResult, error) {
 	notifyCh := make(chan *protocol.CheckTxResult, 1)
 	err := t.submitTx(ctx, rawTx, meta, notifyCh)
@@ -276,6 +341,7 @@ func (t *txPool) RemoveTxBatch(txs []hash.Hash) {
 	for _, txHash := range txs {
 		_ = t.staleCache.Remove(txHash)
 	}
+	t.unjournalTxs(txs)
 
 	pendingScheduleSize.With(t.getMetricLabels()).Set(float64(t.schedulerQueue.Size()))
 }
@@ -354,7 +420,7 @@ func (t *txPool) updateScheduler(bi *BlockInfo) error {
 		// We still need to initialize the scheduler queue.
 		t.logger.Debug("initializing transaction scheduler queue")
 
-		t.schedulerQueue = newPriorityQueue(t.cfg.MaxPoolSize, t.roundWeightLimits)
+		t.schedulerQueue = newPriorityQueue(t.cfg.MaxPoolSize, t.cfg.MaxPoolSizePerSender, t.roundWeightLimits)
 		close(t.initCh)
 	default:
 		// Scheduler already initialized, update weight limits.
@@ -450,6 +516,28 @@ func (t *txPool) PendingScheduleSize() uint64 {
 	return t.schedulerQueue.Size()
 }
 
+func (t *txPool) GetTxStatus(txHash hash.Hash) (TxStatus, *protocol.Error) {
+	t.schedulerLock.Lock()
+	if t.schedulerQueue != nil {
+		if queued, scheduled := t.schedulerQueue.Status(txHash); queued {
+			t.schedulerLock.Unlock()
+			if scheduled {
+				return TxStatusScheduled, nil
+			}
+			return TxStatusPendingSchedule, nil
+		}
+	}
+	t.schedulerLock.Unlock()
+
+	if t.checkTxQueue.IsQueued(txHash) {
+		return TxStatusPendingCheck, nil
+	}
+	if checkErr, rejected := t.rejectedCache.Peek(txHash); rejected {
+		return TxStatusRejected, checkErr.(*protocol.Error)
+	}
+	return TxStatusUnknown, nil
+}
+
 func (t *txPool) getCurrentBlockInfo() (*BlockInfo, error) {
 	t.blockInfoLock.Lock()
 	defer t.blockInfoLock.Unlock()
@@ -502,7 +590,10 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 
 	txs := make([]*transaction.CheckedTransaction, 0, len(results))
 	isLocal := make([]bool, 0, len(results))
-	var unschedule []hash.Hash
+	var (
+		unschedule []hash.Hash
+		unjournal  []hash.Hash
+	)
 	for i, res := range results {
 		// Send back the result of running the checks.
 		if batch[i].NotifyCh != nil {
@@ -521,6 +612,15 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 			if batch[i].Meta.Recheck {
 				unschedule = append(unschedule, batch[i].TxHash)
 			}
+			// Make sure that local transactions that are no longer valid are not resubmitted.
+			if batch[i].Meta.Local {
+				unjournal = append(unjournal, batch[i].TxHash)
+			}
+			// Remember why the transaction was rejected so that its status can be queried.
+			if !batch[i].Meta.Discard {
+				checkErr := res.Error
+				_ = t.rejectedCache.Put(batch[i].TxHash, &checkErr)
+			}
 			continue
 		}
 
@@ -528,12 +628,14 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 			continue
 		}
 
+		_ = t.rejectedCache.Remove(batch[i].TxHash)
 		txs = append(txs, res.ToCheckedTransaction(rawTxBatch[i]))
 		isLocal = append(isLocal, batch[i].Meta.Local)
 	}
 
 	// Unschedule any transactions that are being rechecked and have failed checks.
 	t.RemoveTxBatch(unschedule)
+	t.unjournalTxs(unjournal)
 
 	if len(txs) == 0 {
 		return
@@ -544,6 +646,11 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 	)
 
 	// Queue checked transactions for scheduling.
+	var (
+		scheduled      []*transaction.CheckedTransaction
+		scheduledLocal []bool
+		journal        []*transaction.CheckedTransaction
+	)
 	for i, tx := range txs {
 		t.schedulerLock.Lock()
 		// NOTE: Scheduler exists as otherwise there would be no current block info above.
@@ -554,9 +661,20 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 		}
 		t.schedulerLock.Unlock()
 
+		scheduled = append(scheduled, tx)
+		scheduledLocal = append(scheduledLocal, isLocal[i])
+		if isLocal[i] {
+			journal = append(journal, tx)
+		}
+	}
+
+	// Journal local transactions so that they can be resubmitted after a restart.
+	t.journalTxs(journal)
+
+	for i, tx := range scheduled {
 		// Publish local transactions immediately.
 		publishTime := time.Now()
-		if isLocal[i] {
+		if scheduledLocal[i] {
 			if err := t.txPublisher.PublishTx(ctx, tx.Raw()); err != nil {
 				t.logger.Warn("failed to publish local transaction",
 					"err", err,
@@ -581,6 +699,61 @@ func (t *txPool) checkTxBatch(ctx context.Context, rr host.RichRuntime) {
 	pendingScheduleSize.With(t.getMetricLabels()).Set(float64(t.PendingScheduleSize()))
 }
 
+// journalTxs adds the given local transactions to the journal (if enabled).
+func (t *txPool) journalTxs(txs []*transaction.CheckedTransaction) {
+	if t.journal == nil || len(txs) == 0 {
+		return
+	}
+
+	if err := t.journal.Add(txs); err != nil {
+		t.logger.Warn("failed to journal local transactions",
+			"err", err,
+			"num_txs", len(txs),
+		)
+	}
+}
+
+// unjournalTxs removes the given transactions from the journal (if enabled).
+func (t *txPool) unjournalTxs(txs []hash.Hash) {
+	if t.journal == nil || len(txs) == 0 {
+		return
+	}
+
+	if err := t.journal.Remove(txs); err != nil {
+		t.logger.Warn("failed to remove transactions from journal",
+			"err", err,
+		)
+	}
+}
+
+// replayJournal resubmits all journaled transactions that have not yet expired.
+func (t *txPool) replayJournal(ctx context.Context) {
+	if t.journal == nil {
+		return
+	}
+
+	txs, err := t.journal.Load()
+	if err != nil {
+		t.logger.Error("failed to load transaction journal",
+			"err", err,
+		)
+		return
+	}
+
+	t.logger.Info("resubmitting journaled transactions",
+		"num_txs", len(txs),
+	)
+
+	for _, tx := range txs {
+		if err = t.SubmitTxNoWait(ctx, tx, &TransactionMeta{Local: true}); err != nil {
+			t.logger.Warn("failed to resubmit journaled transaction",
+				"err", err,
+				"tx", tx,
+			)
+		}
+	}
+}
+
 func (t *txPool) ensureInitialized() error {
 	select {
 	case <-t.stopCh:
@@ -615,6 +788,9 @@ func (t *txPool) checkWorker() {
 		return
 	}
 
+	// Resubmit any transactions that were submitted locally before the node restarted.
+	t.replayJournal(ctx)
+
 	for {
 		select {
 		case <-t.stopCh:
@@ -798,8 +974,11 @@ func (t *txPool) flushWorker() {
 }
 
 // New creates a new transaction pool instance.
+//
+// The data directory is used to store the local transaction journal in case it is enabled.
 func New(
 	runtimeID common.Namespace,
+	dataDir string,
 	cfg *Config,
 	host RuntimeHostProvisioner,
 	txPublisher TransactionPublisher,
@@ -816,6 +995,19 @@ func New(
 		return nil, fmt.Errorf("error creating stale cache: %w", err)
 	}
 
+	rejectedCache, err := lru.New(lru.Capacity(cfg.MaxLastSeenCacheSize, false))
+	if err != nil {
+		return nil, fmt.Errorf("error creating rejected cache: %w", err)
+	}
+
+	var jrnl *journal
+	if cfg.MaxJournalSize > 0 {
+		jrnl, err = openJournal(dataDir, runtimeID, cfg.MaxJournalSize, cfg.JournalExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("error opening transaction journal: %w", err)
+		}
+	}
+
 	return &txPool{
 		logger:            logging.GetLogger("runtime/txpool"),
 		stopCh:            make(chan struct{}),
@@ -825,8 +1017,10 @@ func New(
 		cfg:               cfg,
 		host:              host,
 		txPublisher:       txPublisher,
+		journal:           jrnl,
 		seenCache:         seenCache,
 		staleCache:        staleCache,
+		rejectedCache:     rejectedCache,
 		checkTxQueue:      newCheckTxQueue(cfg.MaxPoolSize, cfg.MaxCheckTxBatchSize),
 		checkTxCh:         channels.NewRingChannel(1),
 		checkTxNotifier:   pubsub.NewBroker(false),
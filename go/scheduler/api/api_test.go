@@ -20,3 +20,38 @@ func TestSanityCheck(t *testing.T) {
 	require.NoError(t, q2e20.UnmarshalText([]byte("200_000_000_000_000_000_000")), "import q2e20")
 	require.Error(t, g.SanityCheck(q2e20), "sanity check total supply q2e20")
 }
+
+func TestConsensusParameterChanges(t *testing.T) {
+	require := require.New(t)
+
+	// Empty changes.
+	var emptyChanges ConsensusParameterChanges
+	require.Error(emptyChanges.SanityCheck(), "empty consensus parameter changes should be invalid")
+
+	params := ConsensusParameters{
+		MinValidators:          1,
+		MaxValidators:          10,
+		MaxValidatorsPerEntity: 1,
+	}
+	require.NoError(params.SanityCheck(), "consensus parameters should be valid")
+
+	// Valid changes.
+	maxValidators := 100
+	changes := ConsensusParameterChanges{
+		MaxValidators: &maxValidators,
+	}
+	require.NoError(changes.SanityCheck(), "consensus parameter changes should be valid")
+	require.NoError(changes.Apply(&params), "Apply")
+	require.Equal(maxValidators, params.MaxValidators, "max validators should be changed")
+	require.Equal(1, params.MinValidators, "min validators should not be changed")
+	require.NoError(params.SanityCheck(), "changed consensus parameters should be valid")
+
+	// Changes resulting in invalid parameters.
+	minValidators := 200
+	changes = ConsensusParameterChanges{
+		MinValidators: &minValidators,
+	}
+	require.NoError(changes.SanityCheck(), "consensus parameter changes should be valid")
+	require.NoError(changes.Apply(&params), "Apply")
+	require.Error(params.SanityCheck(), "min validators above max validators should be invalid")
+}
@@ -6,7 +6,9 @@ import (
 	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/address"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/encoding/bech32"
 )
@@ -16,6 +18,8 @@ var (
 	AddressV0Context = address.NewContext("oasis-core/address: staking", 0)
 	// AddressRuntimeV0Context is the unique context for v0 runtime account addresses.
 	AddressRuntimeV0Context = address.NewContext("oasis-core/address: runtime", 0)
+	// AddressMultisigV0Context is the unique context for v0 multisig account addresses.
+	AddressMultisigV0Context = address.NewContext("oasis-core/address: multisig", 0)
 	// AddressBech32HRP is the unique human readable part of Bech32 encoded
 	// staking account addresses.
 	AddressBech32HRP = address.NewBech32HRP("oasis")
@@ -98,6 +102,12 @@ func NewRuntimeAddress(id common.Namespace) (a Address) {
 	return (Address)(address.NewAddress(AddressRuntimeV0Context, nsData))
 }
 
+// NewMultisigAddress creates a new address for the given multisig account
+// descriptor.
+func NewMultisigAddress(account *multisig.Account) (a Address) {
+	return (Address)(address.NewAddress(AddressMultisigV0Context, cbor.Marshal(account)))
+}
+
 // NewReservedAddress creates a new reserved address from the given public key
 // or panics.
 // NOTE: The given public key is also blacklisted.
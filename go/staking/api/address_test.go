@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/multisig"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 )
 
@@ -58,3 +59,30 @@ func TestRuntimeAddress(t *testing.T) {
 	addrPk1 := NewAddress(pk1)
 	require.NotEqualValues(addr1, addrPk1, "runtime addresses should be separated from staking addresses")
 }
+
+func TestMultisigAddress(t *testing.T) {
+	require := require.New(t)
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	account := multisig.Account{
+		Signers:   []signature.PublicKey{pk1, pk2},
+		Threshold: 2,
+	}
+	addr := NewMultisigAddress(&account)
+	require.True(addr.IsValid(), "multisig address should be valid")
+
+	account2 := multisig.Account{
+		Signers:   []signature.PublicKey{pk1, pk2},
+		Threshold: 1,
+	}
+	require.NotEqualValues(addr, NewMultisigAddress(&account2), "multisig addresses for different thresholds should be different")
+
+	// Make sure domain separation works.
+	single := multisig.Account{
+		Signers:   []signature.PublicKey{pk1},
+		Threshold: 1,
+	}
+	require.NotEqualValues(NewAddress(pk1), NewMultisigAddress(&single), "multisig addresses should be separated from staking addresses")
+}
@@ -86,6 +86,10 @@ var (
 	// below the minimum allowed amount.
 	ErrBalanceTooLow = errors.New(ModuleName, 10, "staking: balance too low")
 
+	// ErrTooManyVestings is the error returned when the number of vesting schedules per account
+	// would exceed the maximum allowed number.
+	ErrTooManyVestings = errors.New(ModuleName, 11, "staking: too many vestings")
+
 	// MethodTransfer is the method name for transfers.
 	MethodTransfer = transaction.NewMethodName(ModuleName, "Transfer", Transfer{})
 	// MethodBurn is the method name for burns.
@@ -100,6 +104,8 @@ var (
 	MethodAllow = transaction.NewMethodName(ModuleName, "Allow", Allow{})
 	// MethodWithdraw is the method name for
 	MethodWithdraw = transaction.NewMethodName(ModuleName, "Withdraw", Withdraw{})
+	// MethodScheduleTransfer is the method name for scheduled transfers.
+	MethodScheduleTransfer = transaction.NewMethodName(ModuleName, "ScheduleTransfer", ScheduleTransfer{})
 
 	// Methods is the list of all methods supported by the staking backend.
 	Methods = []transaction.MethodName{
@@ -110,6 +116,7 @@ var (
 		MethodAmendCommissionSchedule,
 		MethodAllow,
 		MethodWithdraw,
+		MethodScheduleTransfer,
 	}
 
 	_ prettyprint.PrettyPrinter = (*Transfer)(nil)
@@ -119,6 +126,7 @@ var (
 	_ prettyprint.PrettyPrinter = (*AmendCommissionSchedule)(nil)
 	_ prettyprint.PrettyPrinter = (*Allow)(nil)
 	_ prettyprint.PrettyPrinter = (*Withdraw)(nil)
+	_ prettyprint.PrettyPrinter = (*ScheduleTransfer)(nil)
 	_ prettyprint.PrettyPrinter = (*SharePool)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeThreshold)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeAccumulator)(nil)
@@ -145,6 +153,11 @@ type Backend interface {
 	// LastBlockFees returns the collected fees for previous block.
 	LastBlockFees(ctx context.Context, height int64) (*quantity.Quantity, error)
 
+	// BaseGasPrice returns the current consensus-enforced minimum gas price.
+	//
+	// In case the fee market is disabled, zero is returned.
+	BaseGasPrice(ctx context.Context, height int64) (*quantity.Quantity, error)
+
 	// GovernanceDeposits returns the governance deposits account balance.
 	GovernanceDeposits(ctx context.Context, height int64) (*quantity.Quantity, error)
 
@@ -185,6 +198,9 @@ type Backend interface {
 	// Allowance looks up the allowance for the given owner/beneficiary combination.
 	Allowance(ctx context.Context, query *AllowanceQuery) (*quantity.Quantity, error)
 
+	// Vestings returns the vesting schedules of the given account.
+	Vestings(ctx context.Context, query *OwnerQuery) ([]*Vesting, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
@@ -261,6 +277,7 @@ type Event struct {
 	Burn            *BurnEvent            `json:"burn,omitempty"`
 	Escrow          *EscrowEvent          `json:"escrow,omitempty"`
 	AllowanceChange *AllowanceChangeEvent `json:"allowance_change,omitempty"`
+	Vesting         *VestingEvent         `json:"vesting,omitempty"`
 }
 
 // AddEscrowEvent is the event emitted when stake is transferred into an escrow
@@ -338,6 +355,26 @@ func (e *AllowanceChangeEvent) EventKind() string {
 	return "allowance_change"
 }
 
+// VestingEvent is the event emitted when stake is locked via a call to
+// ScheduleTransfer or when locked stake is released into the general balance
+// of the account holding it.
+type VestingEvent struct {
+	From   Address           `json:"from"`
+	To     Address           `json:"to"`
+	Amount quantity.Quantity `json:"amount"`
+	Start  beacon.EpochTime  `json:"start"`
+	End    beacon.EpochTime  `json:"end"`
+
+	// Released is true when the event signals that the given amount has been
+	// released and false when the given amount has been locked.
+	Released bool `json:"released,omitempty"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *VestingEvent) EventKind() string {
+	return "vesting"
+}
+
 // Transfer is a stake transfer.
 type Transfer struct {
 	To     Address           `json:"to"`
@@ -520,6 +557,39 @@ func NewWithdrawTx(nonce uint64, fee *transaction.Fee, withdraw *Withdraw) *tran
 	return transaction.NewTransaction(nonce, fee, MethodWithdraw, withdraw)
 }
 
+// ScheduleTransfer is a stake transfer that locks the transferred stake in the
+// destination account and releases it according to a vesting schedule.
+type ScheduleTransfer struct {
+	To     Address           `json:"to"`
+	Amount quantity.Quantity `json:"amount"`
+	Start  beacon.EpochTime  `json:"start"`
+	End    beacon.EpochTime  `json:"end"`
+}
+
+// PrettyPrint writes a pretty-printed representation of ScheduleTransfer to
+// the given writer.
+func (st ScheduleTransfer) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sTo:     %s\n", prefix, st.To)
+
+	fmt.Fprintf(w, "%sAmount: ", prefix)
+	token.PrettyPrintAmount(ctx, st.Amount, w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%sStart:  epoch %d\n", prefix, st.Start)
+	fmt.Fprintf(w, "%sEnd:    epoch %d\n", prefix, st.End)
+}
+
+// PrettyType returns a representation of ScheduleTransfer that can be used
+// for pretty printing.
+func (st ScheduleTransfer) PrettyType() (interface{}, error) {
+	return st, nil
+}
+
+// NewScheduleTransferTx creates a new scheduled transfer transaction.
+func NewScheduleTransferTx(nonce uint64, fee *transaction.Fee, xfer *ScheduleTransfer) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodScheduleTransfer, xfer)
+}
+
 // SharePool is a combined balance of several entries, the relative sizes
 // of which are tracked through shares.
 type SharePool struct {
@@ -1001,11 +1071,13 @@ func (e *EscrowAccount) RemoveStakeClaim(claim StakeClaim) error {
 
 // Account is an entry in the staking ledger.
 //
-// The same ledger entry can hold both general and escrow accounts. Escrow
-// accounts are used to hold funds delegated for staking.
+// The same ledger entry can hold general, escrow and vesting accounts. Escrow
+// accounts are used to hold funds delegated for staking while vesting accounts
+// hold funds locked by scheduled transfers.
 type Account struct {
 	General GeneralAccount `json:"general,omitempty"`
 	Escrow  EscrowAccount  `json:"escrow,omitempty"`
+	Vesting VestingAccount `json:"vesting,omitempty"`
 }
 
 // PrettyPrint writes a pretty-printed representation of Account to the given
@@ -1015,6 +1087,10 @@ func (a Account) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 	a.General.PrettyPrint(ctx, prefix+"  ", w)
 	fmt.Fprintf(w, "%sEscrow Account:\n", prefix)
 	a.Escrow.PrettyPrint(ctx, prefix+"  ", w)
+	if len(a.Vesting.Schedules) > 0 {
+		fmt.Fprintf(w, "%sVesting Account:\n", prefix)
+		a.Vesting.PrettyPrint(ctx, prefix+"  ", w)
+	}
 }
 
 // PrettyType returns a representation of Account that can be used for pretty
@@ -1121,6 +1197,10 @@ type ConsensusParameters struct { // nolint: maligned
 	// MaxAllowances is the maximum number of allowances an account can have. Zero means disabled.
 	MaxAllowances uint32 `json:"max_allowances,omitempty"`
 
+	// MaxVestings is the maximum number of vesting schedules an account can have. Zero means
+	// disabled.
+	MaxVestings uint32 `json:"max_vestings,omitempty"`
+
 	// FeeSplitWeightPropose is the proportion of block fee portions that go to the proposer.
 	FeeSplitWeightPropose quantity.Quantity `json:"fee_split_weight_propose"`
 	// FeeSplitWeightVote is the proportion of block fee portions that go to the validator that votes.
@@ -1134,6 +1214,182 @@ type ConsensusParameters struct { // nolint: maligned
 	// RewardFactorBlockProposed is the factor for a reward distributed per block
 	// to the entity that proposed the block.
 	RewardFactorBlockProposed quantity.Quantity `json:"reward_factor_block_proposed"`
+
+	// FeeMarket are the fee market parameters. If not set, the consensus layer does not enforce
+	// any minimum gas price.
+	FeeMarket *FeeMarketParameters `json:"fee_market,omitempty"`
+}
+
+// FeeMarketParameters are the fee market consensus parameters.
+//
+// The fee market maintains a network-wide base gas price which all transactions must pay. After
+// each block, the base gas price is adjusted based on how much gas the block used compared to the
+// target block gas. The base portion of the collected fees is moved to the common pool.
+type FeeMarketParameters struct {
+	// MinGasPrice is the minimum base gas price.
+	MinGasPrice quantity.Quantity `json:"min_gas_price"`
+	// TargetBlockGas is the amount of gas per block at which the base gas price does not change.
+	TargetBlockGas transaction.Gas `json:"target_block_gas"`
+	// BaseGasPriceChangeDenominator bounds the amount by which the base gas price can change
+	// between blocks. The maximum change is base gas price / denominator.
+	BaseGasPriceChangeDenominator uint64 `json:"base_gas_price_change_denominator"`
+}
+
+// SanityCheck performs a sanity check on the fee market parameters.
+func (p *FeeMarketParameters) SanityCheck() error {
+	if !p.MinGasPrice.IsValid() {
+		return fmt.Errorf("fee market minimum gas price has invalid value")
+	}
+	if p.TargetBlockGas == 0 {
+		return fmt.Errorf("fee market target block gas must be greater than zero")
+	}
+	if p.BaseGasPriceChangeDenominator == 0 {
+		return fmt.Errorf("fee market base gas price change denominator must be greater than zero")
+	}
+	return nil
+}
+
+// ConsensusParameterChanges are allowed staking consensus parameter changes.
+type ConsensusParameterChanges struct {
+	// DebondingInterval is the new debonding interval.
+	DebondingInterval *beacon.EpochTime `json:"debonding_interval,omitempty"`
+
+	// RewardSchedule is the new reward schedule.
+	RewardSchedule *[]RewardStep `json:"reward_schedule,omitempty"`
+
+	// GasCosts are the new gas costs.
+	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
+
+	// MinDelegationAmount is the new minimum delegation amount.
+	MinDelegationAmount *quantity.Quantity `json:"min_delegation,omitempty"`
+	// MinTransferAmount is the new minimum transfer amount.
+	MinTransferAmount *quantity.Quantity `json:"min_transfer,omitempty"`
+	// MinTransactBalance is the new minimum transact balance.
+	MinTransactBalance *quantity.Quantity `json:"min_transact_balance,omitempty"`
+
+	// DisableTransfers is the new disable transfers flag.
+	DisableTransfers *bool `json:"disable_transfers,omitempty"`
+	// DisableDelegation is the new disable delegation flag.
+	DisableDelegation *bool `json:"disable_delegation,omitempty"`
+	// AllowEscrowMessages is the new allow escrow messages flag.
+	AllowEscrowMessages *bool `json:"allow_escrow_messages,omitempty"`
+
+	// MaxAllowances is the new maximum number of allowances.
+	MaxAllowances *uint32 `json:"max_allowances,omitempty"`
+	// MaxVestings is the new maximum number of vesting schedules.
+	MaxVestings *uint32 `json:"max_vestings,omitempty"`
+
+	// FeeSplitWeightPropose is the new propose fee split weight.
+	FeeSplitWeightPropose *quantity.Quantity `json:"fee_split_weight_propose,omitempty"`
+	// FeeSplitWeightVote is the new vote fee split weight.
+	FeeSplitWeightVote *quantity.Quantity `json:"fee_split_weight_vote,omitempty"`
+	// FeeSplitWeightNextPropose is the new next propose fee split weight.
+	FeeSplitWeightNextPropose *quantity.Quantity `json:"fee_split_weight_next_propose,omitempty"`
+
+	// RewardFactorEpochSigned is the new epoch signed reward factor.
+	RewardFactorEpochSigned *quantity.Quantity `json:"reward_factor_epoch_signed,omitempty"`
+	// RewardFactorBlockProposed is the new block proposed reward factor.
+	RewardFactorBlockProposed *quantity.Quantity `json:"reward_factor_block_proposed,omitempty"`
+
+	// FeeMarket are the new fee market parameters.
+	FeeMarket *FeeMarketParameters `json:"fee_market,omitempty"`
+	// DisableFeeMarket is the new disable fee market flag. If set to true, the fee market
+	// parameters are removed which disables the fee market.
+	DisableFeeMarket *bool `json:"disable_fee_market,omitempty"`
+}
+
+// SanityCheck performs a sanity check on the consensus parameter changes.
+func (c *ConsensusParameterChanges) SanityCheck() error {
+	if c.DebondingInterval == nil &&
+		c.RewardSchedule == nil &&
+		c.GasCosts == nil &&
+		c.MinDelegationAmount == nil &&
+		c.MinTransferAmount == nil &&
+		c.MinTransactBalance == nil &&
+		c.DisableTransfers == nil &&
+		c.DisableDelegation == nil &&
+		c.AllowEscrowMessages == nil &&
+		c.MaxAllowances == nil &&
+		c.MaxVestings == nil &&
+		c.FeeSplitWeightPropose == nil &&
+		c.FeeSplitWeightVote == nil &&
+		c.FeeSplitWeightNextPropose == nil &&
+		c.RewardFactorEpochSigned == nil &&
+		c.RewardFactorBlockProposed == nil &&
+		c.FeeMarket == nil &&
+		c.DisableFeeMarket == nil {
+		return fmt.Errorf("consensus parameter changes should not be empty")
+	}
+	if c.FeeMarket != nil && c.DisableFeeMarket != nil && *c.DisableFeeMarket {
+		return fmt.Errorf("consensus parameter changes should not both change and disable the fee market")
+	}
+	return nil
+}
+
+// Apply applies changes to the given consensus parameters.
+func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
+	if c.DebondingInterval != nil {
+		params.DebondingInterval = *c.DebondingInterval
+	}
+	if c.RewardSchedule != nil {
+		params.RewardSchedule = *c.RewardSchedule
+	}
+	if c.GasCosts != nil {
+		params.GasCosts = make(transaction.Costs, len(c.GasCosts))
+		for k, v := range c.GasCosts {
+			params.GasCosts[k] = v
+		}
+	}
+	if c.MinDelegationAmount != nil {
+		params.MinDelegationAmount = *c.MinDelegationAmount.Clone()
+	}
+	if c.MinTransferAmount != nil {
+		params.MinTransferAmount = *c.MinTransferAmount.Clone()
+	}
+	if c.MinTransactBalance != nil {
+		params.MinTransactBalance = *c.MinTransactBalance.Clone()
+	}
+	if c.DisableTransfers != nil {
+		params.DisableTransfers = *c.DisableTransfers
+	}
+	if c.DisableDelegation != nil {
+		params.DisableDelegation = *c.DisableDelegation
+	}
+	if c.AllowEscrowMessages != nil {
+		params.AllowEscrowMessages = *c.AllowEscrowMessages
+	}
+	if c.MaxAllowances != nil {
+		params.MaxAllowances = *c.MaxAllowances
+	}
+	if c.MaxVestings != nil {
+		params.MaxVestings = *c.MaxVestings
+	}
+	if c.FeeSplitWeightPropose != nil {
+		params.FeeSplitWeightPropose = *c.FeeSplitWeightPropose.Clone()
+	}
+	if c.FeeSplitWeightVote != nil {
+		params.FeeSplitWeightVote = *c.FeeSplitWeightVote.Clone()
+	}
+	if c.FeeSplitWeightNextPropose != nil {
+		params.FeeSplitWeightNextPropose = *c.FeeSplitWeightNextPropose.Clone()
+	}
+	if c.RewardFactorEpochSigned != nil {
+		params.RewardFactorEpochSigned = *c.RewardFactorEpochSigned.Clone()
+	}
+	if c.RewardFactorBlockProposed != nil {
+		params.RewardFactorBlockProposed = *c.RewardFactorBlockProposed.Clone()
+	}
+	if c.FeeMarket != nil {
+		params.FeeMarket = &FeeMarketParameters{
+			MinGasPrice:                   *c.FeeMarket.MinGasPrice.Clone(),
+			TargetBlockGas:                c.FeeMarket.TargetBlockGas,
+			BaseGasPriceChangeDenominator: c.FeeMarket.BaseGasPriceChangeDenominator,
+		}
+	}
+	if c.DisableFeeMarket != nil && *c.DisableFeeMarket {
+		params.FeeMarket = nil
+	}
+	return nil
 }
 
 const (
@@ -1151,6 +1407,8 @@ const (
 	GasOpAllow transaction.Op = "allow"
 	// GasOpWithdraw is the gas operation identifier for withdraw.
 	GasOpWithdraw transaction.Op = "withdraw"
+	// GasOpScheduleTransfer is the gas operation identifier for schedule transfer.
+	GasOpScheduleTransfer transaction.Op = "schedule_transfer"
 )
 
 // TransferResult is the result of staking transfer.
@@ -44,6 +44,68 @@ func TestConsensusParameters(t *testing.T) {
 		FeeSplitWeightNextPropose: mustInitQuantity(t, 0),
 	}
 	require.Error(degenerateFeeSplit.SanityCheck(), "consensus parameters with degenerate fee split should be invalid")
+
+	// Fee market.
+	feeMarketParams := validThresholdsParams
+	feeMarketParams.FeeMarket = &FeeMarketParameters{
+		MinGasPrice:                   mustInitQuantity(t, 1),
+		TargetBlockGas:                1000,
+		BaseGasPriceChangeDenominator: 8,
+	}
+	require.NoError(feeMarketParams.SanityCheck(), "consensus parameters with valid fee market should be valid")
+	feeMarketParams.FeeMarket.TargetBlockGas = 0
+	require.Error(feeMarketParams.SanityCheck(), "consensus parameters with zero target block gas should be invalid")
+	feeMarketParams.FeeMarket.TargetBlockGas = 1000
+	feeMarketParams.FeeMarket.BaseGasPriceChangeDenominator = 0
+	require.Error(feeMarketParams.SanityCheck(), "consensus parameters with zero change denominator should be invalid")
+}
+
+func TestConsensusParameterChanges(t *testing.T) {
+	require := require.New(t)
+
+	// Empty changes.
+	var emptyChanges ConsensusParameterChanges
+	require.Error(emptyChanges.SanityCheck(), "empty consensus parameter changes should be invalid")
+
+	// Valid changes.
+	debondingInterval := api.EpochTime(42)
+	disableTransfers := true
+	minTransferAmount := mustInitQuantity(t, 10)
+	changes := ConsensusParameterChanges{
+		DebondingInterval: &debondingInterval,
+		DisableTransfers:  &disableTransfers,
+		MinTransferAmount: &minTransferAmount,
+	}
+	require.NoError(changes.SanityCheck(), "consensus parameter changes should be valid")
+
+	params := ConsensusParameters{
+		DebondingInterval:   1,
+		MinDelegationAmount: mustInitQuantity(t, 5),
+	}
+	require.NoError(changes.Apply(&params), "Apply")
+	require.EqualValues(debondingInterval, params.DebondingInterval, "debonding interval should be changed")
+	require.True(params.DisableTransfers, "disable transfers should be changed")
+	require.Equal(minTransferAmount, params.MinTransferAmount, "min transfer amount should be changed")
+	require.Equal(mustInitQuantity(t, 5), params.MinDelegationAmount, "min delegation amount should not be changed")
+
+	// Fee market changes.
+	feeMarket := &FeeMarketParameters{
+		MinGasPrice:                   mustInitQuantity(t, 1),
+		TargetBlockGas:                1000,
+		BaseGasPriceChangeDenominator: 8,
+	}
+	changes = ConsensusParameterChanges{FeeMarket: feeMarket}
+	require.NoError(changes.SanityCheck(), "fee market changes should be valid")
+	require.NoError(changes.Apply(&params), "Apply")
+	require.EqualValues(feeMarket, params.FeeMarket, "fee market should be enabled")
+
+	disableFeeMarket := true
+	changes = ConsensusParameterChanges{FeeMarket: feeMarket, DisableFeeMarket: &disableFeeMarket}
+	require.Error(changes.SanityCheck(), "changing and disabling the fee market should be invalid")
+	changes = ConsensusParameterChanges{DisableFeeMarket: &disableFeeMarket}
+	require.NoError(changes.SanityCheck(), "disabling the fee market should be valid")
+	require.NoError(changes.Apply(&params), "Apply")
+	require.Nil(params.FeeMarket, "fee market should be disabled")
 }
 
 func TestThresholdKind(t *testing.T) {
@@ -24,6 +24,8 @@ var (
 	methodCommonPool = serviceName.NewMethod("CommonPool", int64(0))
 	// methodLastBlockFees is the LastBlockFees method.
 	methodLastBlockFees = serviceName.NewMethod("LastBlockFees", int64(0))
+	// methodBaseGasPrice is the BaseGasPrice method.
+	methodBaseGasPrice = serviceName.NewMethod("BaseGasPrice", int64(0))
 	// methodGovernanceDeposits is the GovernanceDeposits method.
 	methodGovernanceDeposits = serviceName.NewMethod("methodGovernanceDeposits", int64(0))
 	// methodThreshold is the Threshold method.
@@ -46,6 +48,8 @@ var (
 	methodDebondingDelegationsTo = serviceName.NewMethod("DebondingDelegationsTo", OwnerQuery{})
 	// methodAllowance is the Allowance method.
 	methodAllowance = serviceName.NewMethod("Allowance", AllowanceQuery{})
+	// methodVestings is the Vestings method.
+	methodVestings = serviceName.NewMethod("Vestings", OwnerQuery{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodConsensusParameters is the ConsensusParameters method.
@@ -81,6 +85,10 @@ var (
 				MethodName: methodLastBlockFees.ShortName(),
 				Handler:    handlerLastBlockFees,
 			},
+			{
+				MethodName: methodBaseGasPrice.ShortName(),
+				Handler:    handlerBaseGasPrice,
+			},
 			{
 				MethodName: methodGovernanceDeposits.ShortName(),
 				Handler:    handlerGovernanceDeposits,
@@ -125,6 +133,10 @@ var (
 				MethodName: methodAllowance.ShortName(),
 				Handler:    handlerAllowance,
 			},
+			{
+				MethodName: methodVestings.ShortName(),
+				Handler:    handlerVestings,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -255,6 +267,29 @@ func handlerLastBlockFees( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerBaseGasPrice( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).BaseGasPrice(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodBaseGasPrice.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).BaseGasPrice(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerGovernanceDeposits( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -508,6 +543,29 @@ func handlerAllowance( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerVestings( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query OwnerQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).Vestings(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodVestings.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).Vestings(ctx, req.(*OwnerQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -654,6 +712,14 @@ func (c *stakingClient) LastBlockFees(ctx context.Context, height int64) (*quant
 	return &rsp, nil
 }
 
+func (c *stakingClient) BaseGasPrice(ctx context.Context, height int64) (*quantity.Quantity, error) {
+	var rsp quantity.Quantity
+	if err := c.conn.Invoke(ctx, methodBaseGasPrice.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *stakingClient) GovernanceDeposits(ctx context.Context, height int64) (*quantity.Quantity, error) {
 	var rsp quantity.Quantity
 	if err := c.conn.Invoke(ctx, methodGovernanceDeposits.FullName(), height, &rsp); err != nil {
@@ -742,6 +808,14 @@ func (c *stakingClient) Allowance(ctx context.Context, query *AllowanceQuery) (*
 	return &rsp, nil
 }
 
+func (c *stakingClient) Vestings(ctx context.Context, query *OwnerQuery) ([]*Vesting, error) {
+	var rsp []*Vesting
+	if err := c.conn.Invoke(ctx, methodVestings.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *stakingClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -37,6 +37,13 @@ func (p *ConsensusParameters) SanityCheck() error {
 		return fmt.Errorf("fee split proportions are all zero")
 	}
 
+	// Fee market.
+	if p.FeeMarket != nil {
+		if err := p.FeeMarket.SanityCheck(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -72,6 +79,7 @@ func SanityCheckAccount(
 	_ = total.Add(&acct.General.Balance)
 	_ = total.Add(&acct.Escrow.Active.Balance)
 	_ = total.Add(&acct.Escrow.Debonding.Balance)
+	_ = total.Add(&acct.Vesting.Balance)
 
 	commissionScheduleShallowCopy := acct.Escrow.CommissionSchedule
 	if err := commissionScheduleShallowCopy.PruneAndValidateForGenesis(&parameters.CommissionScheduleRules, now); err != nil {
@@ -90,6 +98,23 @@ func SanityCheckAccount(
 		}
 	}
 
+	var locked quantity.Quantity
+	for i, vesting := range acct.Vesting.Schedules {
+		if err := vesting.ValidateBasic(); err != nil {
+			return fmt.Errorf("staking: sanity check failed: account %s vesting schedule %d is invalid: %w", addr, i, err)
+		}
+		if vesting.IsDone() {
+			return fmt.Errorf("staking: sanity check failed: account %s vesting schedule %d is fully released", addr, i)
+		}
+		_ = locked.Add(vesting.Remaining())
+	}
+	if locked.Cmp(&acct.Vesting.Balance) != 0 {
+		return fmt.Errorf(
+			"staking: sanity check failed: account %s vesting balance (%s) does not match remaining vested amounts (%s)",
+			addr, acct.Vesting.Balance, locked,
+		)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+var (
+	_ prettyprint.PrettyPrinter = (*Vesting)(nil)
+	_ prettyprint.PrettyPrinter = (*VestingAccount)(nil)
+)
+
+// Vesting is a schedule according to which locked stake is released into the
+// general balance of the account holding it.
+//
+// If Start is equal to End, the whole amount is released at once when the
+// given epoch is reached. Otherwise the amount is released linearly, with
+// everything being released at End.
+type Vesting struct {
+	// From is the address of the account that scheduled the transfer.
+	From Address `json:"from"`
+	// Amount is the total amount of stake to be released.
+	Amount quantity.Quantity `json:"amount"`
+	// Released is the amount of stake that has already been released.
+	Released quantity.Quantity `json:"released,omitempty"`
+	// Start is the epoch when the release starts.
+	Start beacon.EpochTime `json:"start"`
+	// End is the epoch at which the whole amount has been released.
+	End beacon.EpochTime `json:"end"`
+}
+
+// PrettyPrint writes a pretty-printed representation of Vesting to the given
+// writer.
+func (v Vesting) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sFrom:     %s\n", prefix, v.From)
+
+	fmt.Fprintf(w, "%sAmount:   ", prefix)
+	token.PrettyPrintAmount(ctx, v.Amount, w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%sReleased: ", prefix)
+	token.PrettyPrintAmount(ctx, v.Released, w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%sStart:    epoch %d\n", prefix, v.Start)
+	fmt.Fprintf(w, "%sEnd:      epoch %d\n", prefix, v.End)
+}
+
+// PrettyType returns a representation of Vesting that can be used for pretty
+// printing.
+func (v Vesting) PrettyType() (interface{}, error) {
+	return v, nil
+}
+
+// ValidateBasic performs basic vesting schedule validity checks.
+func (v *Vesting) ValidateBasic() error {
+	if !v.From.IsValid() {
+		return fmt.Errorf("invalid source address: %s", v.From)
+	}
+	if !v.Amount.IsValid() || v.Amount.IsZero() {
+		return fmt.Errorf("invalid amount")
+	}
+	if !v.Released.IsValid() || v.Released.Cmp(&v.Amount) > 0 {
+		return fmt.Errorf("invalid released amount")
+	}
+	if v.End < v.Start {
+		return fmt.Errorf("end epoch (%d) is before start epoch (%d)", v.End, v.Start)
+	}
+	return nil
+}
+
+// VestedAt computes the total amount of stake that is released at the given
+// epoch.
+func (v *Vesting) VestedAt(epoch beacon.EpochTime) (*quantity.Quantity, error) {
+	switch {
+	case epoch >= v.End:
+		return v.Amount.Clone(), nil
+	case epoch < v.Start:
+		return quantity.NewQuantity(), nil
+	}
+
+	// Linear release between start and end epochs:
+	//
+	//     vested = amount * (epoch - start) / (end - start)
+	//
+	q := v.Amount.Clone()
+	// Multiply first.
+	if err := q.Mul(quantity.NewFromUint64(uint64(epoch - v.Start))); err != nil {
+		return nil, err
+	}
+	if err := q.Quo(quantity.NewFromUint64(uint64(v.End - v.Start))); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Remaining returns the amount of stake that has not yet been released.
+func (v *Vesting) Remaining() *quantity.Quantity {
+	q := v.Amount.Clone()
+	_ = q.Sub(&v.Released)
+	return q
+}
+
+// IsDone returns true iff the whole amount has been released.
+func (v *Vesting) IsDone() bool {
+	return v.Released.Cmp(&v.Amount) >= 0
+}
+
+// VestingAccount holds stake that is locked according to vesting schedules.
+type VestingAccount struct {
+	// Balance is the total amount of stake that is still locked.
+	Balance quantity.Quantity `json:"balance,omitempty"`
+	// Schedules are the vesting schedules that have not yet been fully
+	// released.
+	Schedules []Vesting `json:"schedules,omitempty"`
+}
+
+// PrettyPrint writes a pretty-printed representation of VestingAccount to the
+// given writer.
+func (va VestingAccount) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sBalance: ", prefix)
+	token.PrettyPrintAmount(ctx, va.Balance, w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%sSchedules:\n", prefix)
+	for i, v := range va.Schedules {
+		fmt.Fprintf(w, "%s  (%d)\n", prefix, i+1)
+		v.PrettyPrint(ctx, prefix+"      ", w)
+	}
+}
+
+// PrettyType returns a representation of VestingAccount that can be used for
+// pretty printing.
+func (va VestingAccount) PrettyType() (interface{}, error) {
+	return va, nil
+}
+
+// Release moves any stake that has been vested at the given epoch into the
+// destination balance and removes fully released vesting schedules.
+//
+// The returned list contains a copy of each schedule for which stake has been
+// released with Released set to the amount released by this call.
+func (va *VestingAccount) Release(dst *quantity.Quantity, epoch beacon.EpochTime) ([]Vesting, error) {
+	var (
+		released  []Vesting
+		remaining []Vesting
+	)
+	for _, v := range va.Schedules {
+		vested, err := v.VestedAt(epoch)
+		if err != nil {
+			return nil, err
+		}
+		// Only release what has not yet been released before.
+		if _, err = vested.SubUpTo(&v.Released); err != nil {
+			return nil, err
+		}
+		if !vested.IsZero() {
+			if err = quantity.Move(dst, &va.Balance, vested); err != nil {
+				return nil, fmt.Errorf("staking: failed to release vested stake: %w", err)
+			}
+			if err = v.Released.Add(vested); err != nil {
+				return nil, err
+			}
+
+			rel := v
+			rel.Released = *vested
+			released = append(released, rel)
+		}
+		if !v.IsDone() {
+			remaining = append(remaining, v)
+		}
+	}
+	va.Schedules = remaining
+
+	return released, nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+func TestVesting(t *testing.T) {
+	require := require.New(t)
+
+	from := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	for _, tc := range []struct {
+		vesting Vesting
+		valid   bool
+		msg     string
+	}{
+		{Vesting{From: from, Start: 1, End: 2}, false, "zero amount"},
+		{Vesting{From: from, Amount: *quantity.NewFromUint64(10), Released: *quantity.NewFromUint64(11), Start: 1, End: 2}, false, "released more than amount"},
+		{Vesting{From: from, Amount: *quantity.NewFromUint64(10), Start: 2, End: 1}, false, "end before start"},
+		{Vesting{From: from, Amount: *quantity.NewFromUint64(10), Start: 1, End: 1}, true, "cliff"},
+		{Vesting{From: from, Amount: *quantity.NewFromUint64(10), Start: 1, End: 2}, true, "linear"},
+	} {
+		err := tc.vesting.ValidateBasic()
+		switch tc.valid {
+		case true:
+			require.NoError(err, tc.msg)
+		case false:
+			require.Error(err, tc.msg)
+		}
+	}
+
+	linear := Vesting{From: from, Amount: *quantity.NewFromUint64(100), Start: 10, End: 14}
+	cliff := Vesting{From: from, Amount: *quantity.NewFromUint64(100), Start: 12, End: 12}
+	for _, tc := range []struct {
+		epoch  beacon.EpochTime
+		linear uint64
+		cliff  uint64
+	}{
+		{0, 0, 0},
+		{10, 0, 0},
+		{11, 25, 0},
+		{12, 50, 100},
+		{13, 75, 100},
+		{14, 100, 100},
+		{100, 100, 100},
+	} {
+		vested, err := linear.VestedAt(tc.epoch)
+		require.NoError(err, "VestedAt")
+		require.Zero(quantity.NewFromUint64(tc.linear).Cmp(vested), "linear vesting at epoch %d", tc.epoch)
+
+		vested, err = cliff.VestedAt(tc.epoch)
+		require.NoError(err, "VestedAt")
+		require.Zero(quantity.NewFromUint64(tc.cliff).Cmp(vested), "cliff vesting at epoch %d", tc.epoch)
+	}
+}
+
+func TestVestingAccountRelease(t *testing.T) {
+	require := require.New(t)
+
+	from := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	va := VestingAccount{
+		Balance: *quantity.NewFromUint64(150),
+		Schedules: []Vesting{
+			{From: from, Amount: *quantity.NewFromUint64(100), Start: 10, End: 14},
+			{From: from, Amount: *quantity.NewFromUint64(50), Start: 12, End: 12},
+		},
+	}
+	var general quantity.Quantity
+
+	released, err := va.Release(&general, 11)
+	require.NoError(err, "Release")
+	require.Len(released, 1, "one schedule should release stake")
+	require.Zero(quantity.NewFromUint64(25).Cmp(&released[0].Released), "released amount")
+	require.Zero(quantity.NewFromUint64(25).Cmp(&general), "general balance")
+	require.Zero(quantity.NewFromUint64(125).Cmp(&va.Balance), "vesting balance")
+
+	// Releasing at the same epoch again should be a no-op.
+	released, err = va.Release(&general, 11)
+	require.NoError(err, "Release")
+	require.Empty(released, "nothing should be released twice")
+
+	released, err = va.Release(&general, 12)
+	require.NoError(err, "Release")
+	require.Len(released, 2, "both schedules should release stake")
+	require.Zero(quantity.NewFromUint64(25).Cmp(&released[0].Released), "released linear amount")
+	require.Zero(quantity.NewFromUint64(50).Cmp(&released[1].Released), "released cliff amount")
+	require.Len(va.Schedules, 1, "fully released schedules should be removed")
+
+	_, err = va.Release(&general, 20)
+	require.NoError(err, "Release")
+	require.Empty(va.Schedules, "all schedules should be removed")
+	require.True(va.Balance.IsZero(), "vesting balance should be zero")
+	require.Zero(quantity.NewFromUint64(150).Cmp(&general), "general balance")
+}
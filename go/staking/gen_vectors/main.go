@@ -143,6 +143,21 @@ func main() {
 					vectors = append(vectors, testvectors.MakeTestVector("Withdraw", tx, true))
 				}
 			}
+
+			// Generate schedule transfer transactions.
+			vestingDst := memorySigner.NewTestSigner("oasis-core staking test vectors: ScheduleTransfer dst")
+			vestingDstAddr := staking.NewAddress(vestingDst.Public())
+			for _, amt := range []uint64{0, 1000, 10_000_000} {
+				for _, period := range []uint64{0, 10, 1000} {
+					tx := staking.NewScheduleTransferTx(nonce, fee, &staking.ScheduleTransfer{
+						To:     vestingDstAddr,
+						Amount: *quantity.NewFromUint64(amt),
+						Start:  beacon.EpochTime(100),
+						End:    beacon.EpochTime(100 + period),
+					})
+					vectors = append(vectors, testvectors.MakeTestVector("ScheduleTransfer", tx, true))
+				}
+			}
 		}
 	}
 
@@ -49,6 +49,7 @@ func GenesisState() api.Genesis {
 			MinTransferAmount:   *quantity.NewFromUint64(10),
 			// Zero MinTransactBalance is normal.
 			MaxAllowances:           32,
+			MaxVestings:             32,
 			FeeSplitWeightVote:      *quantity.NewFromUint64(1),
 			RewardFactorEpochSigned: *quantity.NewFromUint64(1),
 			// Zero RewardFactorBlockProposed is normal.
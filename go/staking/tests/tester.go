@@ -154,6 +154,7 @@ func StakingImplementationTests(
 		{"Thresholds", testThresholds},
 		{"CommonPool", testCommonPool},
 		{"LastBlockFees", testLastBlockFees},
+		{"BaseGasPrice", testBaseGasPrice},
 		{"GovernanceDeposits", testGovernanceDeposits},
 		{"Delegations", testDelegations},
 		{"Transfer", testTransfer},
@@ -162,6 +163,7 @@ func StakingImplementationTests(
 		{"Escrow", testEscrow},
 		{"EscrowSelf", testSelfEscrow},
 		{"Allowance", testAllowance},
+		{"ScheduleTransfer", testScheduleTransfer},
 	} {
 		state := newStakingTestsState(t, backend, consensus)
 		t.Run(tc.n, func(t *testing.T) { tc.fn(t, state, backend, consensus) })
@@ -190,6 +192,7 @@ func StakingClientImplementationTests(t *testing.T, backend api.Backend, consens
 		{"Escrow", testEscrow},
 		{"EscrowSelf", testSelfEscrow},
 		{"Allowance", testAllowance},
+		{"ScheduleTransfer", testScheduleTransfer},
 	} {
 		state := newStakingTestsState(t, backend, consensus)
 		t.Run(tc.n, func(t *testing.T) { tc.fn(t, state, backend, consensus) })
@@ -236,6 +239,21 @@ func testLastBlockFees(t *testing.T, state *stakingTestsState, backend api.Backe
 	require.True(lastBlockFeesAcc.General.Balance.IsZero(), "LastBlockFees Account - initial value")
 }
 
+func testBaseGasPrice(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
+	require := require.New(t)
+
+	params, err := backend.ConsensusParameters(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "ConsensusParameters")
+
+	baseGasPrice, err := backend.BaseGasPrice(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "BaseGasPrice")
+	if params.FeeMarket == nil {
+		require.True(baseGasPrice.IsZero(), "BaseGasPrice - should be zero without a fee market")
+	} else {
+		require.True(baseGasPrice.Cmp(&params.FeeMarket.MinGasPrice) >= 0, "BaseGasPrice - should be at least the minimum gas price")
+	}
+}
+
 func testGovernanceDeposits(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
 	require := require.New(t)
 
@@ -1110,6 +1128,81 @@ AllowWaitLoop:
 	require.Equal(expectedNewAllowance, *newAllowance, "Allowance should return the correct value")
 }
 
+func testScheduleTransfer(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
+	require := require.New(t)
+
+	srcAccData := state.accounts.getAccount(1)
+	destAccData := state.accounts.getAccount(2)
+
+	srcAcc, err := backend.Account(context.Background(), &api.OwnerQuery{Owner: srcAccData.Address, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "src: Account - before")
+
+	vestings, err := backend.Vestings(context.Background(), &api.OwnerQuery{Owner: destAccData.Address, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "Vestings - before")
+
+	epoch, err := consensus.Beacon().GetEpoch(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetEpoch")
+
+	ch, sub, err := backend.WatchEvents(context.Background())
+	require.NoError(err, "WatchEvents")
+	defer sub.Close()
+
+	// Schedule a transfer far enough in the future so that nothing is released during the test.
+	xfer := &api.ScheduleTransfer{
+		To:     destAccData.Address,
+		Amount: *quantity.NewFromUint64(math.MaxUint8),
+		Start:  epoch + 100,
+		End:    epoch + 200,
+	}
+	tx := api.NewScheduleTransferTx(srcAcc.General.Nonce, nil, xfer)
+	err = consensusAPI.SignAndSubmitTx(context.Background(), consensus, srcAccData.Signer, tx)
+	require.NoError(err, "ScheduleTransfer")
+
+ScheduleWaitLoop:
+	for {
+		select {
+		case ev := <-ch:
+			if ev.Vesting == nil {
+				continue
+			}
+			ve := ev.Vesting
+
+			require.Equal(srcAccData.Address, ve.From, "Event: from")
+			require.Equal(destAccData.Address, ve.To, "Event: to")
+			require.Equal(xfer.Amount, ve.Amount, "Event: amount")
+			require.Equal(xfer.Start, ve.Start, "Event: start")
+			require.Equal(xfer.End, ve.End, "Event: end")
+			require.False(ve.Released, "Event: released")
+			break ScheduleWaitLoop
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive vesting event")
+		}
+	}
+
+	newVestings, err := backend.Vestings(context.Background(), &api.OwnerQuery{Owner: destAccData.Address, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "Vestings - after")
+	require.Len(newVestings, len(vestings)+1, "Vestings should include the new schedule")
+	v := newVestings[len(newVestings)-1]
+	require.Equal(srcAccData.Address, v.From, "Vesting: from")
+	require.Equal(xfer.Amount, v.Amount, "Vesting: amount")
+	require.True(v.Released.IsZero(), "Vesting: released")
+	require.Equal(xfer.Start, v.Start, "Vesting: start")
+	require.Equal(xfer.End, v.End, "Vesting: end")
+
+	newSrcAcc, err := backend.Account(context.Background(), &api.OwnerQuery{Owner: srcAccData.Address, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "src: Account - after")
+	expectedBalance := srcAcc.General.Balance.Clone()
+	require.NoError(expectedBalance.Sub(&xfer.Amount), "src: expected balance")
+	require.Equal(*expectedBalance, newSrcAcc.General.Balance, "src: general balance should be reduced")
+
+	// Schedules that end in the past should be rejected.
+	xfer.Start = 0
+	xfer.End = 0
+	tx = api.NewScheduleTransferTx(newSrcAcc.General.Nonce, nil, xfer)
+	err = consensusAPI.SignAndSubmitTx(context.Background(), consensus, srcAccData.Signer, tx)
+	require.Error(err, "ScheduleTransfer (end in the past)")
+}
+
 func testSlashConsensusEquivocation(
 	t *testing.T,
 	state *stakingTestsState,
@@ -177,6 +177,9 @@ type Proof = syncer.Proof
 // NodeDB is a node database.
 type NodeDB = nodedb.NodeDB
 
+// NodeDBStats are node database statistics.
+type NodeDBStats = nodedb.Stats
+
 // ApplyRequest is an Apply request.
 type ApplyRequest struct {
 	Namespace common.Namespace `json:"namespace"`
@@ -207,6 +210,17 @@ type GetDiffRequest struct {
 	Options   SyncOptions `json:"options"`
 }
 
+// GetDiffRangeRequest is a GetDiffRange request.
+type GetDiffRangeRequest struct {
+	StartRoot Root `json:"start_root"`
+	EndRoot   Root `json:"end_root"`
+
+	// MaxSize is the maximum total size (in bytes) of all keys and values in the write logs that
+	// are walked to produce the compacted write log. In case the write logs are larger,
+	// ErrLimitReached is returned. Zero means that there is no limit.
+	MaxSize uint64 `json:"max_size,omitempty"`
+}
+
 // Backend is a storage backend implementation.
 type Backend interface {
 	syncer.ReadSyncer
@@ -235,6 +249,11 @@ type LocalBackend interface {
 	// Apply is ignored.
 	Apply(ctx context.Context, request *ApplyRequest) error
 
+	// GetDiffRange returns a compacted write log that must be applied to get from the first given
+	// root to the second one. The roots may be many versions apart, in which case the write logs
+	// of all intermediate versions are merged so that only the net changes are included.
+	GetDiffRange(ctx context.Context, request *GetDiffRangeRequest) (WriteLog, error)
+
 	// Checkpointer returns the checkpoint creator/restorer for this storage backend.
 	Checkpointer() checkpoint.CreateRestorer
 
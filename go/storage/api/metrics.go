@@ -122,6 +122,10 @@ func (w *metricsWrapper) Apply(ctx context.Context, request *ApplyRequest) error
 	return nil
 }
 
+func (w *localMetricsWrapper) GetDiffRange(ctx context.Context, request *GetDiffRangeRequest) (WriteLog, error) {
+	return w.Backend.(LocalBackend).GetDiffRange(ctx, request)
+}
+
 func (w *localMetricsWrapper) Checkpointer() checkpoint.CreateRestorer {
 	return w.Backend.(LocalBackend).Checkpointer()
 }
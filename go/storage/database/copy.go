@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// CopyProgressFunc is a callback invoked after each version has been copied.
+type CopyProgressFunc func(version, earliestVersion, latestVersion uint64)
+
+// CopyNodeDB copies all finalized versions and roots for the given namespace from the source node
+// database into the (empty) destination node database.
+//
+// Where possible, roots are reconstructed by replaying the write logs stored in the source
+// database on top of already copied roots so that the destination database also contains the
+// write logs. Otherwise the full contents of the root are copied.
+func CopyNodeDB(ctx context.Context, namespace common.Namespace, dst, src nodedb.NodeDB, progressFn CopyProgressFunc) error {
+	earliestVersion, err := src.GetEarliestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("storage/database: failed to get earliest version: %w", err)
+	}
+	latestVersion, err := src.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("storage/database: failed to get latest version: %w", err)
+	}
+
+	var prevRoots []node.Root
+	for version := earliestVersion; version <= latestVersion; version++ {
+		roots, err := src.GetRootsForVersion(ctx, version)
+		if err != nil {
+			return fmt.Errorf("storage/database: failed to get roots for version %d: %w", version, err)
+		}
+
+		var copied []node.Root
+		for len(roots) > 0 {
+			// Roots can be derived from other roots in the same version, so keep going until all
+			// of the roots that can be derived from already copied roots are copied.
+			var remaining []node.Root
+			for _, root := range roots {
+				var ok bool
+				if ok, err = copyRootFromWriteLog(ctx, dst, src, root, append(prevRoots, copied...)); err != nil {
+					return err
+				}
+				if ok {
+					copied = append(copied, root)
+				} else {
+					remaining = append(remaining, root)
+				}
+			}
+			if len(remaining) == len(roots) {
+				// No progress, copy the first remaining root in full.
+				if err = copyRootFull(ctx, dst, src, remaining[0]); err != nil {
+					return err
+				}
+				copied = append(copied, remaining[0])
+				remaining = remaining[1:]
+			}
+			roots = remaining
+		}
+
+		finalizedRoots := copied
+		if len(finalizedRoots) == 0 {
+			// Finalization requires at least one root, so use an empty root.
+			emptyRoot := node.Root{
+				Namespace: namespace,
+				Version:   version,
+				Type:      node.RootTypeState,
+			}
+			emptyRoot.Hash.Empty()
+			finalizedRoots = []node.Root{emptyRoot}
+		}
+		if err = dst.Finalize(ctx, finalizedRoots); err != nil {
+			return fmt.Errorf("storage/database: failed to finalize version %d: %w", version, err)
+		}
+		prevRoots = copied
+
+		if progressFn != nil {
+			progressFn(version, earliestVersion, latestVersion)
+		}
+	}
+	return nil
+}
+
+// copyRootFromWriteLog attempts to reconstruct the given root in the destination database by
+// applying a write log from the source database on top of one of the given candidate roots.
+func copyRootFromWriteLog(ctx context.Context, dst, src nodedb.NodeDB, root node.Root, candidates []node.Root) (bool, error) {
+	emptyRoot := node.Root{
+		Namespace: root.Namespace,
+		Version:   root.Version,
+		Type:      root.Type,
+	}
+	emptyRoot.Hash.Empty()
+
+	for _, oldRoot := range append(candidates, emptyRoot) {
+		if !root.Follows(&oldRoot) {
+			continue
+		}
+
+		wl, err := src.GetWriteLog(ctx, oldRoot, root)
+		switch {
+		case err == nil:
+		case errors.Is(err, nodedb.ErrWriteLogNotFound):
+			continue
+		default:
+			return false, fmt.Errorf("storage/database: failed to get write log for root %s: %w", root, err)
+		}
+
+		if err = applyAndCommit(ctx, dst, oldRoot, root, wl); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// copyRootFull copies the full contents of the given root from the source database into the
+// destination database.
+func copyRootFull(ctx context.Context, dst, src nodedb.NodeDB, root node.Root) error {
+	srcTree := mkvs.NewWithRoot(nil, src, root)
+	defer srcTree.Close()
+
+	emptyRoot := node.Root{
+		Namespace: root.Namespace,
+		Version:   root.Version,
+		Type:      root.Type,
+	}
+	emptyRoot.Hash.Empty()
+	tree := mkvs.NewWithRoot(nil, dst, emptyRoot)
+	defer tree.Close()
+
+	it := srcTree.NewIterator(ctx)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		if err := tree.Insert(ctx, it.Key(), it.Value()); err != nil {
+			return fmt.Errorf("storage/database: failed to insert into root %s: %w", root, err)
+		}
+	}
+	if it.Err() != nil {
+		return fmt.Errorf("storage/database: failed to iterate root %s: %w", root, it.Err())
+	}
+
+	if _, err := tree.CommitKnown(ctx, root); err != nil {
+		return fmt.Errorf("storage/database: failed to commit root %s: %w", root, err)
+	}
+	return nil
+}
+
+func applyAndCommit(ctx context.Context, dst nodedb.NodeDB, oldRoot, root node.Root, wl writelog.Iterator) error {
+	tree := mkvs.NewWithRoot(nil, dst, oldRoot)
+	defer tree.Close()
+
+	if err := tree.ApplyWriteLog(ctx, wl); err != nil {
+		return fmt.Errorf("storage/database: failed to apply write log for root %s: %w", root, err)
+	}
+	if _, err := tree.CommitKnown(ctx, root); err != nil {
+		return fmt.Errorf("storage/database: failed to commit root %s: %w", root, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	bboltNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/bbolt"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestCopyNodeDB(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("database copy test ns"), 0)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-copy-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	src, err := badgerNodedb.New(&nodedb.Config{
+		DB:           filepath.Join(dir, DBFileBadgerDB),
+		NoFsync:      true,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "badger.New()")
+	defer src.Close()
+
+	// Populate the source database with a few versions of state and I/O roots.
+	const numVersions = 5
+	stateRoot := node.Root{
+		Namespace: testNs,
+		Type:      node.RootTypeState,
+	}
+	stateRoot.Hash.Empty()
+	for version := uint64(0); version < numVersions; version++ {
+		stateTree := mkvs.NewWithRoot(nil, src, stateRoot)
+		for i := uint64(0); i <= version; i++ {
+			err = stateTree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d/%d", i, version)))
+			require.NoError(err, "Insert()")
+		}
+		if version > 1 {
+			err = stateTree.Remove(ctx, []byte("key 0"))
+			require.NoError(err, "Remove()")
+		}
+		_, stateHash, err := stateTree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit()")
+		stateTree.Close()
+		stateRoot = node.Root{Namespace: testNs, Version: version, Type: node.RootTypeState, Hash: stateHash}
+
+		ioRoot := node.Root{Namespace: testNs, Version: version, Type: node.RootTypeIO}
+		ioRoot.Hash.Empty()
+		ioTree := mkvs.NewWithRoot(nil, src, ioRoot)
+		err = ioTree.Insert(ctx, []byte("input"), []byte(fmt.Sprintf("input %d", version)))
+		require.NoError(err, "Insert()")
+		_, ioHash, err := ioTree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit()")
+		ioTree.Close()
+		ioRoot.Hash = ioHash
+
+		ioTree = mkvs.NewWithRoot(nil, src, ioRoot)
+		err = ioTree.Insert(ctx, []byte("output"), []byte(fmt.Sprintf("output %d", version)))
+		require.NoError(err, "Insert()")
+		_, ioHash, err = ioTree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit()")
+		ioTree.Close()
+		ioRoot.Hash = ioHash
+
+		err = src.Finalize(ctx, []node.Root{stateRoot, ioRoot})
+		require.NoError(err, "Finalize()")
+	}
+	err = src.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+
+	dst, err := bboltNodedb.New(&nodedb.Config{
+		DB:        filepath.Join(dir, DBFileBBoltDB),
+		NoFsync:   true,
+		Namespace: testNs,
+	})
+	require.NoError(err, "bbolt.New()")
+	defer dst.Close()
+
+	var copiedVersions []uint64
+	err = CopyNodeDB(ctx, testNs, dst, src, func(version, earliestVersion, latestVersion uint64) {
+		copiedVersions = append(copiedVersions, version)
+	})
+	require.NoError(err, "CopyNodeDB()")
+	require.EqualValues([]uint64{1, 2, 3, 4}, copiedVersions, "all versions should be copied")
+
+	earliestVersion, err := dst.GetEarliestVersion(ctx)
+	require.NoError(err, "GetEarliestVersion()")
+	require.EqualValues(1, earliestVersion, "earliest version should be copied")
+	latestVersion, err := dst.GetLatestVersion(ctx)
+	require.NoError(err, "GetLatestVersion()")
+	require.EqualValues(numVersions-1, latestVersion, "latest version should be copied")
+
+	sortRoots := func(roots []node.Root) {
+		sort.Slice(roots, func(i, j int) bool {
+			if roots[i].Type != roots[j].Type {
+				return roots[i].Type < roots[j].Type
+			}
+			return bytes.Compare(roots[i].Hash[:], roots[j].Hash[:]) < 0
+		})
+	}
+	for version := earliestVersion; version <= latestVersion; version++ {
+		srcRoots, err := src.GetRootsForVersion(ctx, version)
+		require.NoError(err, "GetRootsForVersion()")
+		dstRoots, err := dst.GetRootsForVersion(ctx, version)
+		require.NoError(err, "GetRootsForVersion()")
+		sortRoots(srcRoots)
+		sortRoots(dstRoots)
+		require.EqualValues(srcRoots, dstRoots, "roots should be copied")
+
+		for _, root := range dstRoots {
+			srcTree := mkvs.NewWithRoot(nil, src, root)
+			dstTree := mkvs.NewWithRoot(nil, dst, root)
+			srcIt := srcTree.NewIterator(ctx)
+			dstIt := dstTree.NewIterator(ctx)
+			dstIt.Rewind()
+			for srcIt.Rewind(); srcIt.Valid(); srcIt.Next() {
+				require.True(dstIt.Valid(), "destination iterator should be valid")
+				require.EqualValues(srcIt.Key(), dstIt.Key(), "keys should be equal")
+				require.EqualValues(srcIt.Value(), dstIt.Value(), "values should be equal")
+				dstIt.Next()
+			}
+			require.NoError(srcIt.Err(), "source iterator should not fail")
+			require.False(dstIt.Valid(), "destination iterator should be exhausted")
+			srcIt.Close()
+			dstIt.Close()
+			srcTree.Close()
+			dstTree.Close()
+		}
+	}
+
+	// Write logs should be available for the copied state roots.
+	startRoots, err := dst.GetRootsForVersion(ctx, latestVersion-1)
+	require.NoError(err, "GetRootsForVersion()")
+	endRoots, err := dst.GetRootsForVersion(ctx, latestVersion)
+	require.NoError(err, "GetRootsForVersion()")
+	sortRoots(startRoots)
+	sortRoots(endRoots)
+	_, err = dst.GetWriteLog(ctx, startRoots[0], endRoots[0])
+	require.NoError(err, "GetWriteLog()")
+}
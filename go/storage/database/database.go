@@ -12,6 +12,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	bboltNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/bbolt"
 )
 
 const (
@@ -21,6 +22,12 @@ const (
 	// DBFileBadgerDB is the default BadgerDB backing store filename.
 	DBFileBadgerDB = "mkvs_storage.badger.db"
 
+	// BackendNameBBoltDB is the name of the bbolt backed database backend.
+	BackendNameBBoltDB = "bbolt"
+
+	// DBFileBBoltDB is the default bbolt backing store directory name.
+	DBFileBBoltDB = "mkvs_storage.bbolt.db"
+
 	checkpointDir = "checkpoints"
 )
 
@@ -30,11 +37,25 @@ func DefaultFileName(backend string) string {
 	switch backend {
 	case BackendNameBadgerDB:
 		return DBFileBadgerDB
+	case BackendNameBBoltDB:
+		return DBFileBBoltDB
 	default:
 		panic("storage/database: can't get default filename for unknown backend")
 	}
 }
 
+// NewNodeDB constructs a new node database for the specified backend.
+func NewNodeDB(backend string, cfg *nodedb.Config) (nodedb.NodeDB, error) {
+	switch backend {
+	case BackendNameBadgerDB:
+		return badgerNodedb.New(cfg)
+	case BackendNameBBoltDB:
+		return bboltNodedb.New(cfg)
+	default:
+		return nil, errors.New("storage/database: unsupported backend")
+	}
+}
+
 type databaseBackend struct {
 	nodedb       nodedb.NodeDB
 	checkpointer checkpoint.CreateRestorer
@@ -49,16 +70,7 @@ type databaseBackend struct {
 func New(cfg *api.Config) (api.LocalBackend, error) {
 	ndbCfg := cfg.ToNodeDB()
 
-	var (
-		ndb nodedb.NodeDB
-		err error
-	)
-	switch cfg.Backend {
-	case BackendNameBadgerDB:
-		ndb, err = badgerNodedb.New(ndbCfg)
-	default:
-		err = errors.New("storage/database: unsupported backend")
-	}
+	ndb, err := NewNodeDB(cfg.Backend, ndbCfg)
 	if err != nil {
 		return nil, fmt.Errorf("storage/database: failed to create node database: %w", err)
 	}
@@ -136,6 +148,11 @@ func (ba *databaseBackend) GetDiff(ctx context.Context, request *api.GetDiffRequ
 	return ba.nodedb.GetWriteLog(ctx, request.StartRoot, request.EndRoot)
 }
 
+// Implements api.LocalBackend.
+func (ba *databaseBackend) GetDiffRange(ctx context.Context, request *api.GetDiffRangeRequest) (api.WriteLog, error) {
+	return getDiffRange(ctx, ba.nodedb, request.StartRoot, request.EndRoot, request.MaxSize)
+}
+
 func (ba *databaseBackend) GetCheckpoints(ctx context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
 	return ba.checkpointer.GetCheckpoints(ctx, request)
 }
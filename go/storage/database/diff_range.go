@@ -0,0 +1,140 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// getDiffRange returns a compacted write log that transforms the start root into the end root.
+//
+// The chain of roots between the two roots is walked backwards, one version at a time, and the
+// write logs along the way are merged so that only the most recent update for each key is kept.
+// Updates that leave a key unchanged with respect to the start root are dropped.
+//
+// In case maxSize is non-zero and the total size of the keys and values in all of the walked write
+// logs exceeds it, the walk is aborted and api.ErrLimitReached is returned. Counting the walked
+// rather than the merged entries bounds the amount of work done for a single request even when
+// the same keys are updated in many versions.
+func getDiffRange(ctx context.Context, ndb nodedb.NodeDB, startRoot, endRoot node.Root, maxSize uint64) (writelog.WriteLog, error) {
+	if endRoot.Type != startRoot.Type || !endRoot.Namespace.Equal(&startRoot.Namespace) || endRoot.Version < startRoot.Version {
+		return nil, api.ErrRootMustFollowOld
+	}
+
+	var size uint64
+	updates := make(map[string][]byte)
+	cur := endRoot
+	for !cur.Equal(&startRoot) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		prev, it, err := getPreviousWriteLog(ctx, ndb, startRoot, cur)
+		if err != nil {
+			return nil, err
+		}
+		if it != nil {
+			var walked uint64
+			if walked, err = MergeWriteLog(updates, it); err != nil {
+				return nil, err
+			}
+			size += walked
+			if maxSize > 0 && size > maxSize {
+				return nil, fmt.Errorf("%w: write log size exceeds %d bytes", api.ErrLimitReached, maxSize)
+			}
+		}
+		cur = prev
+	}
+
+	// Drop all updates that do not change anything compared to the start root.
+	tree := mkvs.NewWithRoot(nil, ndb, startRoot)
+	defer tree.Close()
+
+	wl := make(writelog.WriteLog, 0, len(updates))
+	for key, value := range updates {
+		oldValue, err := tree.Get(ctx, []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("storage/database: failed to get key from start root: %w", err)
+		}
+		if (oldValue == nil) == (value == nil) && bytes.Equal(oldValue, value) {
+			continue
+		}
+		wl = append(wl, writelog.LogEntry{Key: []byte(key), Value: value})
+	}
+	sort.Slice(wl, func(i, j int) bool {
+		return bytes.Compare(wl[i].Key, wl[j].Key) < 0
+	})
+	return wl, nil
+}
+
+// getPreviousWriteLog finds the root from which the given root has been derived, either in the
+// same version (only in case of the start root) or in the preceding version, together with the
+// write log between the two roots. In case the roots are the same, the returned write log is nil.
+func getPreviousWriteLog(
+	ctx context.Context,
+	ndb nodedb.NodeDB,
+	startRoot node.Root,
+	root node.Root,
+) (node.Root, writelog.Iterator, error) {
+	candidates := []node.Root{startRoot}
+	if version := root.Version - 1; root.Version > startRoot.Version && version != startRoot.Version {
+		roots, err := ndb.GetRootsForVersion(ctx, version)
+		if err != nil {
+			return node.Root{}, nil, fmt.Errorf("storage/database: failed to get roots for version %d: %w", version, err)
+		}
+		candidates = roots
+	}
+
+	for _, prev := range candidates {
+		if prev.Type != root.Type || !root.Follows(&prev) {
+			continue
+		}
+		if prev.Hash.Equal(&root.Hash) {
+			return prev, nil, nil
+		}
+
+		it, err := ndb.GetWriteLog(ctx, prev, root)
+		switch {
+		case err == nil:
+			return prev, it, nil
+		case errors.Is(err, api.ErrWriteLogNotFound):
+			continue
+		default:
+			return node.Root{}, nil, fmt.Errorf("storage/database: failed to get write log for root %s: %w", root, err)
+		}
+	}
+	return node.Root{}, nil, api.ErrWriteLogNotFound
+}
+
+// MergeWriteLog merges the given write log into the given set of updates, keeping any existing
+// updates. Write logs should therefore be merged starting with the most recent one. It returns
+// the total size of the keys and values in the write log.
+func MergeWriteLog(updates map[string][]byte, it writelog.Iterator) (uint64, error) {
+	var size uint64
+	for {
+		more, err := it.Next()
+		if err != nil {
+			return 0, fmt.Errorf("storage/database: failed to iterate write log: %w", err)
+		}
+		if !more {
+			return size, nil
+		}
+		entry, err := it.Value()
+		if err != nil {
+			return 0, fmt.Errorf("storage/database: failed to iterate write log: %w", err)
+		}
+		size += uint64(len(entry.Key) + len(entry.Value))
+		if _, ok := updates[string(entry.Key)]; ok {
+			continue
+		}
+		updates[string(entry.Key)] = entry.Value
+	}
+}
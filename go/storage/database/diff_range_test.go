@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+func TestGetDiffRange(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("database diff range test ns"), 0)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-diff-range-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerNodedb.New(&nodedb.Config{
+		DB:           filepath.Join(dir, DBFileBadgerDB),
+		NoFsync:      true,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "badger.New()")
+	defer ndb.Close()
+
+	// Each version applies the given updates (nil values are removals).
+	versionUpdates := []map[string][]byte{
+		{"a": []byte("a0"), "b": []byte("b0"), "c": []byte("c0")},
+		{"a": []byte("a1"), "d": []byte("d1")},
+		{}, // No changes.
+		{"b": nil, "e": []byte("e3"), "c": []byte("c3")},
+		{"d": nil, "e": nil, "c": []byte("c0")},
+		{"a": []byte("a5")},
+	}
+
+	var roots []node.Root
+	root := node.Root{
+		Namespace: testNs,
+		Type:      node.RootTypeState,
+	}
+	root.Hash.Empty()
+	for version, updates := range versionUpdates {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		for key, value := range updates {
+			if value == nil {
+				err = tree.Remove(ctx, []byte(key))
+				require.NoError(err, "Remove()")
+			} else {
+				err = tree.Insert(ctx, []byte(key), value)
+				require.NoError(err, "Insert()")
+			}
+		}
+		_, rootHash, err := tree.Commit(ctx, testNs, uint64(version))
+		require.NoError(err, "Commit()")
+		tree.Close()
+
+		root = node.Root{Namespace: testNs, Version: uint64(version), Type: node.RootTypeState, Hash: rootHash}
+		err = ndb.Finalize(ctx, []node.Root{root})
+		require.NoError(err, "Finalize()")
+		roots = append(roots, root)
+	}
+
+	for _, tc := range []struct {
+		start, end int
+		expected   writelog.WriteLog
+	}{
+		{0, 0, writelog.WriteLog{}},
+		{0, 1, writelog.WriteLog{{Key: []byte("a"), Value: []byte("a1")}, {Key: []byte("d"), Value: []byte("d1")}}},
+		{1, 2, writelog.WriteLog{}},
+		{2, 4, writelog.WriteLog{{Key: []byte("b"), Value: nil}, {Key: []byte("d"), Value: nil}}},
+		{0, 5, writelog.WriteLog{{Key: []byte("a"), Value: []byte("a5")}, {Key: []byte("b"), Value: nil}}},
+	} {
+		wl, err := getDiffRange(ctx, ndb, roots[tc.start], roots[tc.end], 0)
+		require.NoError(err, "getDiffRange(%d, %d)", tc.start, tc.end)
+		require.EqualValues(tc.expected, wl, "getDiffRange(%d, %d) should return the net changes", tc.start, tc.end)
+
+		// Applying the write log to the start root should result in the end root.
+		tree := mkvs.NewWithRoot(nil, ndb, roots[tc.start])
+		err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+		require.NoError(err, "ApplyWriteLog()")
+		_, rootHash, err := tree.Commit(ctx, testNs, roots[tc.end].Version, mkvs.NoPersist())
+		require.NoError(err, "Commit()")
+		require.EqualValues(roots[tc.end].Hash, rootHash, "applying the write log should result in the end root")
+		tree.Close()
+	}
+
+	// A compacted write log should be usable to skip versions in a database that only has the
+	// start root.
+	ndb2, err := badgerNodedb.New(&nodedb.Config{
+		DB:           filepath.Join(dir, "skip-"+DBFileBadgerDB),
+		NoFsync:      true,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "badger.New()")
+	defer ndb2.Close()
+
+	wl, err := getDiffRange(ctx, ndb, emptyRoot(roots[0]), roots[0], 0)
+	require.NoError(err, "getDiffRange()")
+	tree := mkvs.NewWithRoot(nil, ndb2, emptyRoot(roots[0]))
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, err = tree.CommitKnown(ctx, roots[0])
+	require.NoError(err, "CommitKnown()")
+	tree.Close()
+	err = ndb2.Finalize(ctx, []node.Root{roots[0]})
+	require.NoError(err, "Finalize()")
+
+	endRoot := roots[len(roots)-1]
+	wl, err = getDiffRange(ctx, ndb, roots[0], endRoot, 0)
+	require.NoError(err, "getDiffRange()")
+	err = ndb2.StartMultipartInsert(endRoot.Version)
+	require.NoError(err, "StartMultipartInsert()")
+	tree = mkvs.NewWithRoot(nil, ndb2, roots[0])
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+	require.NoError(err, "ApplyWriteLog()")
+	_, rootHash, err := tree.Commit(ctx, testNs, endRoot.Version, mkvs.Multipart())
+	require.NoError(err, "Commit()")
+	require.EqualValues(endRoot.Hash, rootHash, "applying the write log should result in the end root")
+	tree.Close()
+	err = ndb2.Finalize(ctx, []node.Root{endRoot})
+	require.NoError(err, "Finalize()")
+
+	latestVersion, err := ndb2.GetLatestVersion(ctx)
+	require.NoError(err, "GetLatestVersion()")
+	require.EqualValues(endRoot.Version, latestVersion, "latest version should be the end version")
+	tree = mkvs.NewWithRoot(nil, ndb2, endRoot)
+	value, err := tree.Get(ctx, []byte("a"))
+	require.NoError(err, "Get()")
+	require.EqualValues([]byte("a5"), value, "value should be correct at the end version")
+	value, err = tree.Get(ctx, []byte("b"))
+	require.NoError(err, "Get()")
+	require.Nil(value, "removed value should not exist at the end version")
+	tree.Close()
+
+	// Pruning the start version must not remove any nodes shared with the end root.
+	err = ndb2.Prune(ctx, roots[0].Version)
+	require.NoError(err, "Prune()")
+	tree = mkvs.NewWithRoot(nil, ndb2, endRoot)
+	for _, key := range []string{"a", "c"} {
+		value, err = tree.Get(ctx, []byte(key))
+		require.NoError(err, "Get(%s) after pruning", key)
+		require.NotNil(value, "value should exist at the end version after pruning")
+	}
+	tree.Close()
+
+	// The end root must follow the start root.
+	_, err = getDiffRange(ctx, ndb, roots[3], roots[1], 0)
+	require.ErrorIs(err, api.ErrRootMustFollowOld, "getDiffRange should fail for a reversed range")
+
+	// Unknown end roots should fail.
+	invalidRoot := roots[3]
+	invalidRoot.Hash.FromBytes([]byte("invalid root"))
+	_, err = getDiffRange(ctx, ndb, roots[0], invalidRoot, 0)
+	require.Error(err, "getDiffRange should fail for an unknown end root")
+
+	// Using a different root type should fail.
+	ioRoot := roots[0]
+	ioRoot.Type = node.RootTypeIO
+	_, err = getDiffRange(ctx, ndb, ioRoot, roots[3], 0)
+	require.ErrorIs(err, api.ErrRootMustFollowOld, "getDiffRange should fail for different root types")
+
+	// Write logs exceeding the size limit should fail.
+	_, err = getDiffRange(ctx, ndb, roots[0], endRoot, 4)
+	require.ErrorIs(err, api.ErrLimitReached, "getDiffRange should fail when exceeding the size limit")
+	_, err = getDiffRange(ctx, ndb, roots[0], endRoot, 1024)
+	require.NoError(err, "getDiffRange should succeed within the size limit")
+
+	// The size limit applies to all walked write logs, not only to the compacted one.
+	_, err = getDiffRange(ctx, ndb, roots[0], roots[5], 12)
+	require.ErrorIs(err, api.ErrLimitReached, "getDiffRange should count all walked write log entries")
+	_, err = getDiffRange(ctx, ndb, roots[0], roots[5], 21)
+	require.NoError(err, "getDiffRange should succeed when the walked write logs are within the limit")
+}
+
+func emptyRoot(root node.Root) node.Root {
+	root.Hash.Empty()
+	return root
+}
@@ -0,0 +1,234 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	archiveVersion = 1
+
+	archiveMetadataEntry = "meta"
+	// maxArchiveMetadataSize is the maximum size of the archive metadata entry.
+	maxArchiveMetadataSize = 16 * 1024 * 1024
+)
+
+// ArchiveMetadata is the metadata of a checkpoint archive.
+//
+// A checkpoint archive is a tar file containing the CBOR-encoded archive metadata, followed by
+// the chunks of all included checkpoints in order. Chunk digests are part of the checkpoint
+// metadata so the archive can be fully verified while it is being restored.
+type ArchiveMetadata struct {
+	// Version is the archive format version.
+	Version uint16 `json:"version"`
+	// Checkpoints are the checkpoints contained in the archive. All checkpoints are for the same
+	// namespace and root version, one per root type.
+	Checkpoints []*Metadata `json:"checkpoints"`
+}
+
+// Roots returns the roots of all checkpoints contained in the archive.
+func (m *ArchiveMetadata) Roots() []node.Root {
+	roots := make([]node.Root, 0, len(m.Checkpoints))
+	for _, cp := range m.Checkpoints {
+		roots = append(roots, cp.Root)
+	}
+	return roots
+}
+
+// ValidateBasic performs basic archive metadata validity checks.
+func (m *ArchiveMetadata) ValidateBasic() error {
+	if m.Version != archiveVersion {
+		return fmt.Errorf("unsupported archive version: %d", m.Version)
+	}
+	if len(m.Checkpoints) == 0 {
+		return fmt.Errorf("archive contains no checkpoints")
+	}
+
+	first := m.Checkpoints[0]
+	rootTypes := make(map[node.RootType]bool)
+	for _, cp := range m.Checkpoints {
+		if cp == nil {
+			return fmt.Errorf("archive contains a malformed checkpoint")
+		}
+		if cp.Version != checkpointVersion {
+			return fmt.Errorf("unsupported checkpoint version: %d", cp.Version)
+		}
+		if !cp.Root.Namespace.Equal(&first.Root.Namespace) {
+			return fmt.Errorf("checkpoints for different namespaces")
+		}
+		if cp.Root.Version != first.Root.Version {
+			return fmt.Errorf("checkpoints for different root versions")
+		}
+		if rootTypes[cp.Root.Type] {
+			return fmt.Errorf("duplicate checkpoint for root type %s", cp.Root.Type)
+		}
+		rootTypes[cp.Root.Type] = true
+		if len(cp.Chunks) == 0 {
+			return fmt.Errorf("checkpoint for root %s has no chunks", cp.Root)
+		}
+	}
+	return nil
+}
+
+func archiveChunkEntry(cpIndex int, chunkIndex uint64) string {
+	return fmt.Sprintf("%s/%d/%d", chunksDir, cpIndex, chunkIndex)
+}
+
+// WriteArchive writes the given checkpoints into a single self-describing archive, fetching
+// checkpoint chunks from the given chunk provider.
+func WriteArchive(ctx context.Context, provider ChunkProvider, checkpoints []*Metadata, w io.Writer) error {
+	meta := &ArchiveMetadata{
+		Version:     archiveVersion,
+		Checkpoints: checkpoints,
+	}
+	if err := meta.ValidateBasic(); err != nil {
+		return fmt.Errorf("checkpoint: invalid archive: %w", err)
+	}
+
+	tw := tar.NewWriter(w)
+	writeEntry := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(data)),
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeEntry(archiveMetadataEntry, cbor.Marshal(meta)); err != nil {
+		return fmt.Errorf("checkpoint: failed to write archive metadata: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for cpIndex, cp := range checkpoints {
+		for idx := range cp.Chunks {
+			chunk, err := cp.GetChunkMetadata(uint64(idx))
+			if err != nil {
+				return err
+			}
+
+			// The chunk size needs to be known before the entry can be written, so buffer it.
+			buf.Reset()
+			if err = provider.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+				return fmt.Errorf("checkpoint: failed to get chunk %d of root %s: %w", idx, cp.Root, err)
+			}
+			if err = writeEntry(archiveChunkEntry(cpIndex, chunk.Index), buf.Bytes()); err != nil {
+				return fmt.Errorf("checkpoint: failed to write chunk %d of root %s: %w", idx, cp.Root, err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("checkpoint: failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// RestoreArchive restores all checkpoints contained in the given archive into the node database
+// and finalizes the restored version.
+//
+// The optional verification function is called with the archive metadata before anything is
+// restored and can be used to check that the archive roots are the expected ones. Each chunk is
+// verified against the checkpoint metadata as it is being restored.
+func RestoreArchive(
+	ctx context.Context,
+	ndb db.NodeDB,
+	r io.Reader,
+	verifyFn func(*ArchiveMetadata) error,
+) (*ArchiveMetadata, error) {
+	tr := tar.NewReader(r)
+
+	// Read and verify archive metadata.
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read metadata: %s", ErrArchiveCorrupted, err)
+	}
+	if hdr.Name != archiveMetadataEntry || hdr.Size > maxArchiveMetadataSize {
+		return nil, fmt.Errorf("%w: malformed metadata entry", ErrArchiveCorrupted)
+	}
+	data, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read metadata: %s", ErrArchiveCorrupted, err)
+	}
+	var meta ArchiveMetadata
+	if err = cbor.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("%w: malformed metadata: %s", ErrArchiveCorrupted, err)
+	}
+	if err = meta.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchiveCorrupted, err)
+	}
+	if verifyFn != nil {
+		if err = verifyFn(&meta); err != nil {
+			return nil, err
+		}
+	}
+
+	rs, err := NewRestorer(ndb)
+	if err != nil {
+		return nil, err
+	}
+
+	version := meta.Checkpoints[0].Root.Version
+	if err = ndb.StartMultipartInsert(version); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to start multipart insert: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		_ = rs.AbortRestore(ctx)
+		_ = ndb.AbortMultipartInsert()
+	}()
+
+	for cpIndex, cp := range meta.Checkpoints {
+		if err = rs.StartRestore(ctx, cp); err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to start restore: %w", err)
+		}
+
+		var done bool
+		for idx := range cp.Chunks {
+			if hdr, err = tr.Next(); err != nil {
+				return nil, fmt.Errorf("%w: failed to read chunk %d of root %s: %s", ErrArchiveCorrupted, idx, cp.Root, err)
+			}
+			if hdr.Name != archiveChunkEntry(cpIndex, uint64(idx)) {
+				err = fmt.Errorf("%w: unexpected entry '%s'", ErrArchiveCorrupted, hdr.Name)
+				return nil, err
+			}
+			if done, err = rs.RestoreChunk(ctx, uint64(idx), tr); err != nil {
+				return nil, fmt.Errorf("checkpoint: failed to restore chunk %d of root %s: %w", idx, cp.Root, err)
+			}
+		}
+		if !done {
+			err = fmt.Errorf("%w: checkpoint for root %s not fully restored", ErrArchiveCorrupted, cp.Root)
+			return nil, err
+		}
+	}
+
+	// Make sure there is no trailing data.
+	switch _, err = tr.Next(); {
+	case errors.Is(err, io.EOF):
+	case err == nil:
+		err = fmt.Errorf("%w: trailing entries", ErrArchiveCorrupted)
+		return nil, err
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrArchiveCorrupted, err)
+	}
+
+	if err = ndb.Finalize(ctx, meta.Roots()); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to finalize version %d: %w", version, err)
+	}
+	return &meta, nil
+}
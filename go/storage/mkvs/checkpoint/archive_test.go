@@ -0,0 +1,129 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpoint.archive")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	// Generate a state and an I/O root.
+	ctx := context.Background()
+	var roots []node.Root
+	for _, rootType := range []node.RootType{node.RootTypeState, node.RootTypeIO} {
+		tree := mkvs.New(nil, ndb, rootType)
+		for i := 0; i < 1000; i++ {
+			err = tree.Insert(ctx, []byte(fmt.Sprintf("%s %d", rootType, i)), []byte(strconv.Itoa(i)))
+			require.NoError(err, "Insert")
+		}
+		_, rootHash, err := tree.Commit(ctx, testNs, 1)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		roots = append(roots, node.Root{
+			Namespace: testNs,
+			Version:   1,
+			Type:      rootType,
+			Hash:      rootHash,
+		})
+	}
+	err = ndb.Finalize(ctx, roots)
+	require.NoError(err, "Finalize")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	var cps []*Metadata
+	for _, root := range roots {
+		cp, err := fc.CreateCheckpoint(ctx, root, 16*1024)
+		require.NoError(err, "CreateCheckpoint")
+		cps = append(cps, cp)
+	}
+
+	// Export the checkpoints into an archive.
+	var buf bytes.Buffer
+	err = WriteArchive(ctx, fc, cps, &buf)
+	require.NoError(err, "WriteArchive")
+	archive := buf.Bytes()
+
+	err = WriteArchive(ctx, fc, nil, &buf)
+	require.Error(err, "WriteArchive should fail without checkpoints")
+
+	newNodeDB := func(name string) db.NodeDB {
+		ndb2, err := badgerDb.New(&db.Config{
+			DB:           filepath.Join(dir, name),
+			Namespace:    testNs,
+			MaxCacheSize: 16 * 1024 * 1024,
+		})
+		require.NoError(err, "New")
+		return ndb2
+	}
+
+	// Restoring should fail when verification fails.
+	ndb2 := newNodeDB("db2")
+	defer ndb2.Close()
+	errVerify := fmt.Errorf("verification failed")
+	_, err = RestoreArchive(ctx, ndb2, bytes.NewReader(archive), func(meta *ArchiveMetadata) error {
+		return errVerify
+	})
+	require.True(errors.Is(err, errVerify), "RestoreArchive should fail when verification fails")
+
+	// Restoring a corrupted archive should fail.
+	corrupted := append([]byte{}, archive...)
+	corrupted[len(corrupted)/2] ^= 0xff
+	_, err = RestoreArchive(ctx, ndb2, bytes.NewReader(corrupted), nil)
+	require.Error(err, "RestoreArchive should fail with a corrupted archive")
+
+	// Restoring a truncated archive should fail.
+	_, err = RestoreArchive(ctx, ndb2, bytes.NewReader(archive[:len(archive)/2]), nil)
+	require.Error(err, "RestoreArchive should fail with a truncated archive")
+
+	// Restoring a valid archive should work.
+	meta, err := RestoreArchive(ctx, ndb2, bytes.NewReader(archive), func(meta *ArchiveMetadata) error {
+		require.EqualValues(roots, meta.Roots(), "archive roots should be correct")
+		return nil
+	})
+	require.NoError(err, "RestoreArchive")
+	require.EqualValues(cps, meta.Checkpoints, "archive checkpoints should be correct")
+
+	latestVersion, err := ndb2.GetLatestVersion(ctx)
+	require.NoError(err, "GetLatestVersion")
+	require.EqualValues(1, latestVersion, "restored version should be finalized")
+
+	for _, root := range roots {
+		tree := mkvs.NewWithRoot(nil, ndb2, root)
+		for i := 0; i < 1000; i++ {
+			value, err := tree.Get(ctx, []byte(fmt.Sprintf("%s %d", root.Type, i)))
+			require.NoError(err, "Get")
+			require.Equal([]byte(strconv.Itoa(i)), value)
+		}
+		tree.Close()
+	}
+}
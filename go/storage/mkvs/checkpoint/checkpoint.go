@@ -35,6 +35,9 @@ var (
 
 	// ErrChunkCorrupted is the error when a chunk is corrupted.
 	ErrChunkCorrupted = errors.New(moduleName, 7, "chunk: corrupted chunk")
+
+	// ErrArchiveCorrupted is the error when a checkpoint archive is corrupted.
+	ErrArchiveCorrupted = errors.New(moduleName, 8, "archive: corrupted archive")
 )
 
 // ChunkProvider is a chunk provider.
@@ -40,6 +40,55 @@ type CheckpointerConfig struct {
 	//
 	// This must return exactly RootsPerVersion roots.
 	GetRoots func(context.Context, uint64) ([]node.Root, error)
+
+	// Retention is the node-local checkpoint retention policy.
+	Retention RetentionPolicy
+}
+
+// RetentionPolicy is a node-local checkpoint retention policy. Checkpoints retained by the policy
+// are kept in addition to the checkpoints required by the creation parameters and are never
+// garbage collected by the checkpointer.
+type RetentionPolicy struct {
+	// KeepEvery specifies that checkpoints at every KeepEvery-th version (relative to the initial
+	// version) should be created and kept. Zero disables the rule.
+	KeepEvery uint64
+
+	// KeepLast specifies the number of most recent checkpoints to keep. If it is smaller than the
+	// number of checkpoints required by the creation parameters, the latter is used instead.
+	KeepLast uint64
+
+	// KeepSince specifies that all checkpoints at or after the given version should be kept. Zero
+	// disables the rule.
+	KeepSince uint64
+}
+
+// numKept returns the number of most recent checkpoints to keep.
+func (p *RetentionPolicy) numKept(params *CreationParameters) uint64 {
+	if p.KeepLast > params.NumKept {
+		return p.KeepLast
+	}
+	return params.NumKept
+}
+
+// keepEveryVersion returns the most recent version at or before the given version that should be
+// checkpointed according to the KeepEvery rule.
+func (p *RetentionPolicy) keepEveryVersion(version uint64, params *CreationParameters) (uint64, bool) {
+	if p.KeepEvery == 0 || version < params.InitialVersion {
+		return 0, false
+	}
+	return ((version-params.InitialVersion)/p.KeepEvery)*p.KeepEvery + params.InitialVersion, true
+}
+
+// retains returns true iff the checkpoint at the given version must be kept regardless of how
+// many more recent checkpoints exist.
+func (p *RetentionPolicy) retains(version uint64, params *CreationParameters) bool {
+	if p.KeepSince > 0 && version >= p.KeepSince {
+		return true
+	}
+	if p.KeepEvery > 0 && version >= params.InitialVersion && (version-params.InitialVersion)%p.KeepEvery == 0 {
+		return true
+	}
+	return false
 }
 
 // CreationParameters are the checkpoint creation parameters used by the checkpointer.
@@ -57,6 +106,11 @@ type CreationParameters struct {
 	InitialVersion uint64
 }
 
+// isScheduled returns true iff the given version is part of the regular checkpoint schedule.
+func (p *CreationParameters) isScheduled(version uint64) bool {
+	return version >= p.InitialVersion && (version-p.InitialVersion)%p.Interval == 0
+}
+
 // Checkpointer is a checkpointer.
 type Checkpointer interface {
 	// NotifyNewVersion notifies the checkpointer that a new version has been finalized.
@@ -196,7 +250,9 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 		return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
 	}
 
-	// Check if we need to create a new checkpoint based on the list of existing checkpoints.
+	// Check if we need to create a new checkpoint based on the list of existing checkpoints. Only
+	// checkpoints on the regular schedule are considered so that checkpoints created solely due to
+	// the retention policy do not prevent failed regular checkpoints from being retried.
 	var lastCheckpointVersion uint64
 	var cpVersions []uint64
 	cpsByVersion := make(map[uint64][]node.Root)
@@ -205,7 +261,9 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 			cpVersions = append(cpVersions, cp.Root.Version)
 		}
 		cpsByVersion[cp.Root.Version] = append(cpsByVersion[cp.Root.Version], cp.Root)
-		if len(cpsByVersion[cp.Root.Version]) == c.cfg.RootsPerVersion && cp.Root.Version > lastCheckpointVersion {
+		if len(cpsByVersion[cp.Root.Version]) == c.cfg.RootsPerVersion &&
+			params.isScheduled(cp.Root.Version) &&
+			cp.Root.Version > lastCheckpointVersion {
 			lastCheckpointVersion = cp.Root.Version
 		}
 	}
@@ -226,6 +284,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 
 	// Checkpoint any missing versions in descending order, stopping at NumKept checkpoints.
 	newCheckpointVersion := ((version-params.InitialVersion)/params.Interval)*params.Interval + params.InitialVersion
+	createdVersions := make(map[uint64]bool)
 	var numAddedCheckpoints uint64
 	for cpVersion := newCheckpointVersion; cpVersion >= firstCheckpointVersion; {
 		c.logger.Info("checkpointing version",
@@ -239,6 +298,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 			)
 			break
 		}
+		createdVersions[cpVersion] = true
 
 		// Move to the next version, avoiding possible underflow.
 		if cpVersion < params.Interval {
@@ -254,14 +314,41 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 		}
 	}
 
-	// Garbage collect old checkpoints.
-	if int(params.NumKept) < len(cpVersions) {
+	// Make sure that checkpoints required by the retention policy are created even when they are
+	// not part of the regular checkpoint schedule.
+	if keepVersion, ok := c.cfg.Retention.keepEveryVersion(version, params); ok {
+		if keepVersion >= firstCheckpointVersion && !createdVersions[keepVersion] && len(cpsByVersion[keepVersion]) == 0 {
+			c.logger.Info("checkpointing version required by retention policy",
+				"version", keepVersion,
+			)
+
+			if err = c.checkpoint(ctx, keepVersion, params); err != nil {
+				c.logger.Error("failed to checkpoint version",
+					"version", keepVersion,
+					"err", err,
+				)
+			}
+		}
+	}
+
+	// Garbage collect old checkpoints. Checkpoints retained by the retention policy are kept in
+	// addition to the most recent ones so they must not be counted towards NumKept.
+	var gcVersions []uint64
+	for _, version := range cpVersions {
+		if c.cfg.Retention.retains(version, params) {
+			continue
+		}
+		gcVersions = append(gcVersions, version)
+	}
+	numKept := c.cfg.Retention.numKept(params)
+	if int(numKept) < len(gcVersions) {
 		c.logger.Info("performing checkpoint garbage collection",
 			"num_checkpoints", len(cpVersions),
-			"num_kept", params.NumKept,
+			"num_retained", len(cpVersions)-len(gcVersions),
+			"num_kept", numKept,
 		)
 
-		for _, version := range cpVersions[:len(cpVersions)-int(params.NumKept)] {
+		for _, version := range gcVersions[:len(gcVersions)-int(numKept)] {
 			for _, root := range cpsByVersion[version] {
 				if err = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root); err != nil {
 					c.logger.Warn("failed to garbage collect checkpoint",
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
@@ -184,3 +185,115 @@ func TestCheckpointer(t *testing.T) {
 		testCheckpointer(t, 0, 10, false)
 	})
 }
+
+// testCheckpointerRetention runs the checkpointer with the given retention policy up to the given
+// round and returns the versions of all remaining checkpoints. The failures map specifies how many
+// times creating a checkpoint of a given version should fail.
+func testCheckpointerRetention(t *testing.T, retention RetentionPolicy, lastRound uint64, failures map[uint64]int) []uint64 {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpointer.retention")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	cpCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cp, err := NewCheckpointer(cpCtx, ndb, fc, CheckpointerConfig{
+		Name:            "test",
+		Namespace:       testNs,
+		CheckInterval:   testCheckInterval,
+		RootsPerVersion: 1,
+		Parameters: &CreationParameters{
+			Interval:  2,
+			NumKept:   testNumKept,
+			ChunkSize: 16 * 1024,
+		},
+		GetRoots: func(ctx context.Context, version uint64) ([]node.Root, error) {
+			if failures[version] > 0 {
+				failures[version]--
+				return nil, fmt.Errorf("induced failure")
+			}
+			return ndb.GetRootsForVersion(ctx, version)
+		},
+		Retention: retention,
+	})
+	require.NoError(err, "NewCheckpointer")
+
+	var root node.Root
+	root.Empty()
+	root.Namespace = testNs
+	root.Type = node.RootTypeState
+
+	for round := uint64(0); round <= lastRound; round++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("round %d", round)), []byte(fmt.Sprintf("value %d", round)))
+		require.NoError(err, "Insert")
+
+		_, rootHash, err := tree.Commit(ctx, testNs, round)
+		require.NoError(err, "Commit")
+
+		root.Version = round
+		root.Hash = rootHash
+
+		err = ndb.Finalize(ctx, []node.Root{root})
+		require.NoError(err, "Finalize")
+		cp.NotifyNewVersion(round)
+
+		select {
+		case <-cp.(*checkpointer).statusCh:
+		case <-time.After(2 * testCheckInterval):
+			t.Fatalf("failed to wait for checkpointer to checkpoint")
+		}
+	}
+
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: testNs,
+	})
+	require.NoError(err, "GetCheckpoints")
+
+	var versions []uint64
+	for _, cpm := range cps {
+		versions = append(versions, cpm.Root.Version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+func TestCheckpointerRetention(t *testing.T) {
+	t.Run("KeepEveryAndKeepSince", func(t *testing.T) {
+		versions := testCheckpointerRetention(t, RetentionPolicy{
+			KeepEvery: 5,
+			KeepSince: 7,
+		}, 14, nil)
+		require.EqualValues(t, []uint64{4, 5, 6, 8, 10, 12, 14}, versions, "checkpoints should be retained according to policy")
+	})
+	t.Run("RetainedAmongMostRecent", func(t *testing.T) {
+		// The checkpoint at version 5 is among the NumKept most recent checkpoints, but it should
+		// not cause any of the regular checkpoints to be garbage collected.
+		versions := testCheckpointerRetention(t, RetentionPolicy{
+			KeepEvery: 5,
+		}, 7, nil)
+		require.EqualValues(t, []uint64{4, 5, 6}, versions, "retained checkpoints should be kept in addition to the most recent ones")
+	})
+	t.Run("RetryBelowRetained", func(t *testing.T) {
+		// The regular checkpoint at version 4 fails until the checkpoint at version 5 is created
+		// due to the retention policy, but it should still be retried afterwards.
+		versions := testCheckpointerRetention(t, RetentionPolicy{
+			KeepEvery: 5,
+		}, 7, map[uint64]int{4: 2})
+		require.EqualValues(t, []uint64{4, 5, 6}, versions, "failed regular checkpoints should be retried")
+	})
+}
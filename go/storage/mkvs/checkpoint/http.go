@@ -0,0 +1,317 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// HTTPCheckpointsPath is the path under which checkpoint metadata is served.
+	HTTPCheckpointsPath = "/checkpoints"
+	// HTTPChunksPath is the path prefix under which checkpoint chunks are served by digest.
+	HTTPChunksPath = "/chunks/"
+
+	// httpContentTypeCBOR is the content type used for checkpoint metadata.
+	httpContentTypeCBOR = "application/cbor"
+	// httpCheckpointsMaxAge is the maximum age of cached checkpoint metadata lists.
+	httpCheckpointsMaxAge = 1 * time.Minute
+	// httpMaxMetadataSize is the maximum size of a checkpoint metadata list accepted by the client.
+	httpMaxMetadataSize = 16 * 1024 * 1024
+	// httpMaxChunkSize is the maximum size of a checkpoint chunk accepted by the client.
+	httpMaxChunkSize = 256 * 1024 * 1024
+	// httpChunkIndexRefreshInterval is the minimum interval between rebuilding the chunk index
+	// due to requests for unknown chunks.
+	httpChunkIndexRefreshInterval = 10 * time.Second
+)
+
+type httpHandler struct {
+	sync.Mutex
+
+	provider ChunkProvider
+	chunks   map[hash.Hash]*ChunkMetadata
+
+	lastIndexed     time.Time
+	refreshInterval time.Duration
+
+	logger *logging.Logger
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case r.URL.Path == HTTPCheckpointsPath:
+		h.serveCheckpoints(w, r)
+	case strings.HasPrefix(r.URL.Path, HTTPChunksPath):
+		h.serveChunk(w, r, strings.TrimPrefix(r.URL.Path, HTTPChunksPath))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *httpHandler) getCheckpoints(ctx context.Context) ([]*Metadata, error) {
+	return h.provider.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version: checkpointVersion,
+	})
+}
+
+func (h *httpHandler) serveCheckpoints(w http.ResponseWriter, r *http.Request) {
+	cps, err := h.getCheckpoints(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get checkpoints",
+			"err", err,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// Clients fetch the checkpoint list before fetching any chunks, so make sure that all chunks
+	// of the served checkpoints can be found.
+	h.Lock()
+	err = h.indexChunksLocked(cps)
+	h.Unlock()
+	if err != nil {
+		h.logger.Error("failed to index checkpoint chunks",
+			"err", err,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", httpContentTypeCBOR)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(httpCheckpointsMaxAge.Seconds())))
+	_, _ = w.Write(cbor.Marshal(cps))
+}
+
+// indexChunksLocked rebuilds the chunk index from the given checkpoints.
+func (h *httpHandler) indexChunksLocked(cps []*Metadata) error {
+	chunks := make(map[hash.Hash]*ChunkMetadata)
+	for _, cp := range cps {
+		for idx := range cp.Chunks {
+			cm, err := cp.GetChunkMetadata(uint64(idx))
+			if err != nil {
+				return err
+			}
+			chunks[cm.Digest] = cm
+		}
+	}
+	h.chunks = chunks
+	h.lastIndexed = time.Now()
+	return nil
+}
+
+// lookupChunk returns the chunk metadata for a chunk with the given digest.
+//
+// In case the chunk is not known, the chunk index is rebuilt from the current checkpoints, but at
+// most once per refresh interval so that requests for unknown chunks remain cheap.
+func (h *httpHandler) lookupChunk(ctx context.Context, digest hash.Hash) (*ChunkMetadata, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if cm, ok := h.chunks[digest]; ok {
+		return cm, nil
+	}
+	if time.Since(h.lastIndexed) < h.refreshInterval {
+		return nil, ErrChunkNotFound
+	}
+
+	cps, err := h.getCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.indexChunksLocked(cps); err != nil {
+		return nil, err
+	}
+
+	if cm, ok := h.chunks[digest]; ok {
+		return cm, nil
+	}
+	return nil, ErrChunkNotFound
+}
+
+// chunkResponseWriter is a writer that streams a chunk to the HTTP response. Response headers are
+// only sent on the first write so that errors can still be reported in case no chunk data has
+// been written yet.
+type chunkResponseWriter struct {
+	w      http.ResponseWriter
+	digest hash.Hash
+
+	wroteHeader bool
+}
+
+func (cw *chunkResponseWriter) writeHeader() {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	// Chunks are addressed by their digest so they never change and can be cached indefinitely.
+	cw.w.Header().Set("Content-Type", "application/octet-stream")
+	cw.w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	cw.w.Header().Set("ETag", fmt.Sprintf("%q", cw.digest))
+	cw.w.WriteHeader(http.StatusOK)
+}
+
+func (cw *chunkResponseWriter) Write(p []byte) (int, error) {
+	cw.writeHeader()
+	return cw.w.Write(p)
+}
+
+func (h *httpHandler) serveChunk(w http.ResponseWriter, r *http.Request, digestHex string) {
+	var digest hash.Hash
+	if err := digest.UnmarshalHex(digestHex); err != nil {
+		http.Error(w, "malformed chunk digest", http.StatusBadRequest)
+		return
+	}
+
+	cm, err := h.lookupChunk(r.Context(), digest)
+	switch err {
+	case nil:
+	case ErrChunkNotFound:
+		http.NotFound(w, r)
+		return
+	default:
+		h.logger.Error("failed to look up chunk",
+			"err", err,
+			"digest", digest,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") == fmt.Sprintf("%q", digest) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	cw := &chunkResponseWriter{w: w, digest: digest}
+	err = h.provider.GetCheckpointChunk(r.Context(), cm, cw)
+	switch {
+	case err == nil:
+		// Make sure headers are sent even for empty chunks.
+		cw.writeHeader()
+	case cw.wroteHeader:
+		// Part of the chunk has already been sent so the only option is to abort the response.
+		h.logger.Error("failed to stream chunk",
+			"err", err,
+			"digest", digest,
+		)
+		panic(http.ErrAbortHandler)
+	case err == ErrChunkNotFound:
+		// The checkpoint may have been removed in the meantime.
+		http.NotFound(w, r)
+	default:
+		h.logger.Error("failed to get chunk",
+			"err", err,
+			"digest", digest,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// NewHTTPHandler creates a new HTTP handler serving checkpoints from the given provider.
+//
+// Checkpoint metadata is served at HTTPCheckpointsPath and chunks are served by their digest
+// under HTTPChunksPath.
+func NewHTTPHandler(provider ChunkProvider) http.Handler {
+	return &httpHandler{
+		provider:        provider,
+		chunks:          make(map[hash.Hash]*ChunkMetadata),
+		refreshInterval: httpChunkIndexRefreshInterval,
+		logger:          logging.GetLogger("storage/mkvs/checkpoint/http"),
+	}
+}
+
+type httpClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (c *httpClient) get(ctx context.Context, path string, maxSize int64, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrChunkNotFound
+	default:
+		return fmt.Errorf("checkpoint: unexpected HTTP status from %s: %s", c.baseURL, rsp.Status)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(rsp.Body, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("checkpoint: failed to read HTTP response: %w", err)
+	}
+	if n > maxSize {
+		return fmt.Errorf("checkpoint: HTTP response too large")
+	}
+	return nil
+}
+
+func (c *httpClient) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
+	// Only a single version is served over HTTP.
+	if request.Version != checkpointVersion {
+		return []*Metadata{}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := c.get(ctx, HTTPCheckpointsPath, httpMaxMetadataSize, &buf); err != nil {
+		return nil, err
+	}
+
+	var cps []*Metadata
+	if err := cbor.Unmarshal(buf.Bytes(), &cps); err != nil {
+		return nil, fmt.Errorf("checkpoint: malformed checkpoint metadata from %s: %w", c.baseURL, err)
+	}
+
+	if request.RootVersion == nil {
+		return cps, nil
+	}
+	var filtered []*Metadata
+	for _, cp := range cps {
+		if cp.Root.Version == *request.RootVersion {
+			filtered = append(filtered, cp)
+		}
+	}
+	return filtered, nil
+}
+
+func (c *httpClient) GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error {
+	return c.get(ctx, HTTPChunksPath+chunk.Digest.String(), httpMaxChunkSize, w)
+}
+
+// NewHTTPClient creates a new chunk provider that fetches checkpoints from an HTTP endpoint
+// served by a handler created via NewHTTPHandler (or a cache in front of it).
+//
+// In case client is nil, http.DefaultClient is used.
+func NewHTTPClient(baseURL string, client *http.Client) ChunkProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
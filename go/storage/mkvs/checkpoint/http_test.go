@@ -0,0 +1,149 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+type testChunkProvider struct {
+	checkpoints []*Metadata
+	chunks      map[hash.Hash][]byte
+
+	numGetCheckpoints int
+}
+
+func (p *testChunkProvider) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
+	p.numGetCheckpoints++
+	return p.checkpoints, nil
+}
+
+func (p *testChunkProvider) GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error {
+	data, ok := p.chunks[chunk.Digest]
+	if !ok {
+		return ErrChunkNotFound
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func (p *testChunkProvider) addCheckpoint(version uint64, chunks ...[]byte) {
+	cp := &Metadata{
+		Version: checkpointVersion,
+		Root: node.Root{
+			Namespace: testNs,
+			Version:   version,
+			Type:      node.RootTypeState,
+		},
+	}
+	cp.Root.Hash.Empty()
+	for _, data := range chunks {
+		digest := hash.NewFromBytes(data)
+		cp.Chunks = append(cp.Chunks, digest)
+		p.chunks[digest] = data
+	}
+	p.checkpoints = append(p.checkpoints, cp)
+}
+
+func TestHTTP(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	provider := &testChunkProvider{chunks: make(map[hash.Hash][]byte)}
+	provider.addCheckpoint(10, []byte("chunk 10/0"), []byte("chunk 10/1"))
+
+	handler := NewHTTPHandler(provider).(*httpHandler)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	client := NewHTTPClient(srv.URL+"/", srv.Client())
+
+	cps, err := client.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: checkpointVersion})
+	require.NoError(err, "GetCheckpoints")
+	require.EqualValues(provider.checkpoints, cps, "GetCheckpoints should return all checkpoints")
+
+	rootVersion := uint64(11)
+	cps, err = client.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: checkpointVersion, RootVersion: &rootVersion})
+	require.NoError(err, "GetCheckpoints")
+	require.Empty(cps, "GetCheckpoints should filter by root version")
+
+	cps, err = client.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 2})
+	require.NoError(err, "GetCheckpoints")
+	require.Empty(cps, "GetCheckpoints should return nothing for unsupported versions")
+
+	fetchChunk := func(cp *Metadata, idx uint64) ([]byte, error) {
+		cm, err := cp.GetChunkMetadata(idx)
+		require.NoError(err, "GetChunkMetadata")
+		var buf bytes.Buffer
+		err = client.GetCheckpointChunk(ctx, cm, &buf)
+		return buf.Bytes(), err
+	}
+	for idx := range provider.checkpoints[0].Chunks {
+		data, err := fetchChunk(provider.checkpoints[0], uint64(idx))
+		require.NoError(err, "GetCheckpointChunk")
+		require.EqualValues(fmt.Sprintf("chunk 10/%d", idx), string(data), "GetCheckpointChunk should return the chunk")
+	}
+
+	// Chunks of checkpoints created after the chunk index was built should be found once the
+	// checkpoint list has been fetched.
+	provider.addCheckpoint(20, []byte("chunk 20/0"))
+	_, err = client.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: checkpointVersion})
+	require.NoError(err, "GetCheckpoints")
+	data, err := fetchChunk(provider.checkpoints[1], 0)
+	require.NoError(err, "GetCheckpointChunk")
+	require.EqualValues("chunk 20/0", string(data), "GetCheckpointChunk should return the chunk")
+
+	// Unknown chunks should not be found and should not cause the chunk index to be rebuilt more
+	// than once per refresh interval.
+	numGetCheckpoints := provider.numGetCheckpoints
+	for i := 0; i < 10; i++ {
+		err = client.GetCheckpointChunk(ctx, &ChunkMetadata{Digest: hash.NewFromBytes([]byte(fmt.Sprintf("unknown %d", i)))}, ioutil.Discard)
+		require.ErrorIs(err, ErrChunkNotFound, "GetCheckpointChunk should fail for unknown chunks")
+	}
+	require.Equal(numGetCheckpoints, provider.numGetCheckpoints, "unknown chunks should not rebuild the chunk index")
+
+	// Chunks of new checkpoints should be found after the refresh interval even if the checkpoint
+	// list has not been fetched.
+	handler.refreshInterval = 0
+	provider.addCheckpoint(30, []byte("chunk 30/0"))
+	data, err = fetchChunk(provider.checkpoints[2], 0)
+	require.NoError(err, "GetCheckpointChunk")
+	require.EqualValues("chunk 30/0", string(data), "GetCheckpointChunk should return the chunk")
+
+	// Chunks should be cacheable.
+	rsp, err := srv.Client().Get(srv.URL + HTTPChunksPath + provider.checkpoints[0].Chunks[0].String())
+	require.NoError(err, "Get")
+	rsp.Body.Close()
+	require.EqualValues(http.StatusOK, rsp.StatusCode)
+	require.Contains(rsp.Header.Get("Cache-Control"), "immutable")
+	etag := rsp.Header.Get("ETag")
+	require.NotEmpty(etag)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+HTTPChunksPath+provider.checkpoints[0].Chunks[0].String(), nil)
+	require.NoError(err, "NewRequestWithContext")
+	req.Header.Set("If-None-Match", etag)
+	rsp, err = srv.Client().Do(req)
+	require.NoError(err, "Do")
+	rsp.Body.Close()
+	require.EqualValues(http.StatusNotModified, rsp.StatusCode, "cached chunks should not be resent")
+
+	// Malformed digests and other methods should be rejected.
+	rsp, err = srv.Client().Get(srv.URL + HTTPChunksPath + "invalid")
+	require.NoError(err, "Get")
+	rsp.Body.Close()
+	require.EqualValues(http.StatusBadRequest, rsp.StatusCode)
+
+	rsp, err = srv.Client().Post(srv.URL+HTTPCheckpointsPath, "", nil)
+	require.NoError(err, "Post")
+	rsp.Body.Close()
+	require.EqualValues(http.StatusMethodNotAllowed, rsp.StatusCode)
+}
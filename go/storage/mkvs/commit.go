@@ -21,8 +21,22 @@ func NoPersist() CommitOption {
 	}
 }
 
+// Multipart returns a commit option that makes the Commit persist the updated nodes as part of a
+// multipart insert in progress in the underlying node database (see StartMultipartInsert). In
+// this case no write logs are stored.
+//
+// The new root is recorded as derived from the root that the tree has been opened at, which may
+// be from any earlier version. This makes sure that shared nodes are retained and that replaced
+// nodes are removed once the earlier versions are pruned.
+func Multipart() CommitOption {
+	return func(o *commitOptions) {
+		o.multipart = true
+	}
+}
+
 type commitOptions struct {
 	noPersist bool
+	multipart bool
 }
 
 // Implements Tree.
@@ -72,7 +86,7 @@ func (t *tree) commitWithHooks(
 	var err error
 	switch opts.noPersist {
 	case false:
-		batch, err = t.cache.db.NewBatch(oldRoot, version, false)
+		batch, err = t.cache.db.NewBatch(oldRoot, version, opts.multipart)
 	case true:
 		// Do not persist anything -- use a dummy batch.
 		nopDb, _ := db.NewNopNodeDB()
@@ -128,13 +142,17 @@ func (t *tree) commitWithHooks(
 		Type:      oldRoot.Type,
 		Hash:      rootHash,
 	}
-	if err := batch.PutWriteLog(log, logAnns); err != nil {
-		return nil, hash.Hash{}, err
+	if !opts.multipart {
+		if err := batch.PutWriteLog(log, logAnns); err != nil {
+			return nil, hash.Hash{}, err
+		}
 	}
 
 	// Store removed nodes.
-	if err := batch.RemoveNodes(t.pendingRemovedNodes); err != nil {
-		return nil, hash.Hash{}, err
+	if !opts.multipart || !oldRoot.Hash.IsEmpty() {
+		if err := batch.RemoveNodes(t.pendingRemovedNodes); err != nil {
+			return nil, hash.Hash{}, err
+		}
 	}
 
 	// And finally commit to the database.
@@ -79,6 +79,29 @@ type Config struct { // nolint: maligned
 	DiscardWriteLogs bool
 }
 
+// VersionStats are node database statistics for a single version.
+type VersionStats struct {
+	// Version is the version.
+	Version uint64 `json:"version"`
+	// Nodes is the number of live nodes that were created in this version.
+	Nodes uint64 `json:"nodes"`
+	// WriteLogs is the number of write logs stored for this version.
+	WriteLogs uint64 `json:"write_logs"`
+	// WriteLogSize is the total size of write logs stored for this version in bytes.
+	WriteLogSize uint64 `json:"write_log_size"`
+}
+
+// Stats are node database statistics.
+type Stats struct {
+	// Versions are the per-version statistics, ordered by version.
+	Versions []VersionStats `json:"versions"`
+	// OrphanedNodes is an estimate of the number of node entries that are no longer reachable
+	// from any stored root but have not yet been reclaimed by compaction.
+	OrphanedNodes uint64 `json:"orphaned_nodes"`
+	// DiskSize is the on-disk size breakdown in bytes, keyed by backend-specific component.
+	DiskSize map[string]int64 `json:"disk_size"`
+}
+
 // NodeDB is the persistence layer used for persisting the in-memory tree.
 type NodeDB interface {
 	// GetNode looks up a node in the database.
@@ -114,6 +137,10 @@ type NodeDB interface {
 	// existing root. Chunks may contain unresolved pointers (e.g., pointers that point to hashes
 	// which are not present in the database). Committing a chunk batch will prevent the version
 	// from being finalized.
+	//
+	// In case the old root of a chunk batch is not empty, the committed root is recorded as being
+	// derived from the old root, even when the old root is from an earlier (non-adjacent) version.
+	// This makes sure that nodes shared with the old root are not pruned.
 	NewBatch(oldRoot node.Root, version uint64, chunk bool) (Batch, error)
 
 	// HasRoot checks whether the given root exists.
@@ -131,6 +158,14 @@ type NodeDB interface {
 	// Size returns the size of the database in bytes.
 	Size() (int64, error)
 
+	// Stats returns node database statistics.
+	//
+	// Collecting statistics requires a full scan of the database and may be slow.
+	Stats(ctx context.Context) (*Stats, error)
+
+	// Compact reclaims space used by pruned data. It is safe to call while the database is in use.
+	Compact(ctx context.Context) error
+
 	// Sync syncs the database to disk. This is useful if the NoFsync option is used to explicitly
 	// perform a sync.
 	Sync() error
@@ -252,6 +287,14 @@ func (d *nopNodeDB) Size() (int64, error) {
 	return 0, nil
 }
 
+func (d *nopNodeDB) Stats(ctx context.Context) (*Stats, error) {
+	return &Stats{}, nil
+}
+
+func (d *nopNodeDB) Compact(ctx context.Context) error {
+	return nil
+}
+
 func (d *nopNodeDB) Sync() error {
 	return nil
 }
@@ -297,3 +340,14 @@ func (s *nopSubtree) VisitCleanNode(depth node.Depth, ptr *node.Pointer) error {
 func (s *nopSubtree) Commit() error {
 	return nil
 }
+
+// MultipartFollows checks whether a root committed as part of a multipart insert may be derived
+// from the given old root. Unlike regular commits, such roots may skip any number of versions.
+func MultipartFollows(root, oldRoot *node.Root) bool {
+	if oldRoot.Hash.IsEmpty() {
+		return root.Follows(oldRoot)
+	}
+	return root.Type == oldRoot.Type &&
+		root.Namespace.Equal(&oldRoot.Namespace) &&
+		root.Version >= oldRoot.Version
+}
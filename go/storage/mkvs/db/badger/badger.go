@@ -37,7 +37,7 @@ var (
 	// old root).
 	//
 	// Value is CBOR-serialized write log.
-	writeLogKeyFmt = keyformat.New(0x01, uint64(0), &typedHash{}, &typedHash{})
+	writeLogKeyFmt = keyformat.New(0x01, uint64(0), &api.TypedHash{}, &api.TypedHash{})
 	// rootsMetadataKeyFmt is the key format for roots metadata. The key format is (version).
 	//
 	// Value is CBOR-serialized rootsMetadata.
@@ -47,7 +47,7 @@ var (
 	// the finalized roots. They key format is (version, root).
 	//
 	// Value is CBOR-serialized []updatedNode.
-	rootUpdatedNodesKeyFmt = keyformat.New(0x03, uint64(0), &typedHash{})
+	rootUpdatedNodesKeyFmt = keyformat.New(0x03, uint64(0), &api.TypedHash{})
 	// metadataKeyFmt is the key format for metadata.
 	//
 	// Value is CBOR-serialized metadata.
@@ -58,11 +58,11 @@ var (
 	// with these entries.
 	//
 	// Value is empty.
-	multipartRestoreNodeLogKeyFmt = keyformat.New(0x05, &typedHash{})
+	multipartRestoreNodeLogKeyFmt = keyformat.New(0x05, &api.TypedHash{})
 	// rootNodeKeyFmt is the key format for root nodes (typed node hash).
 	//
 	// Value is empty.
-	rootNodeKeyFmt = keyformat.New(0x06, &typedHash{})
+	rootNodeKeyFmt = keyformat.New(0x06, &api.TypedHash{})
 )
 
 // New creates a new BadgerDB-backed node database.
@@ -181,7 +181,7 @@ func (d *badgerNodeDB) sanityCheckNamespace(ns common.Namespace) error {
 }
 
 func (d *badgerNodeDB) checkRoot(txn *badger.Txn, root node.Root) error {
-	rootHash := typedHashFromRoot(root)
+	rootHash := api.TypedHashFromRoot(root)
 	if _, err := txn.Get(rootNodeKeyFmt.Encode(&rootHash)); err != nil {
 		switch err {
 		case badger.ErrKeyNotFound:
@@ -229,7 +229,7 @@ func (d *badgerNodeDB) cleanMultipartLocked(removeNodes bool) error {
 				d.logger.Info("removing some nodes from a multipart restore")
 				logged = true
 			}
-			var hash typedHash
+			var hash api.TypedHash
 			if !multipartRestoreNodeLogKeyFmt.Decode(key, &hash) {
 				panic("mkvs/badger: bad iterator")
 			}
@@ -358,14 +358,14 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 
 	type wlItem struct {
 		depth       uint8
-		endRootHash typedHash
+		endRootHash api.TypedHash
 		logKeys     [][]byte
-		logRoots    []typedHash
+		logRoots    []api.TypedHash
 	}
 	// NOTE: We could use a proper deque, but as long as we keep the number of hops and
 	//       forks low, this should not be a problem.
-	queue := []*wlItem{{depth: 0, endRootHash: typedHashFromRoot(endRoot)}}
-	startRootHash := typedHashFromRoot(startRoot)
+	queue := []*wlItem{{depth: 0, endRootHash: api.TypedHashFromRoot(endRoot)}}
+	startRootHash := api.TypedHashFromRoot(startRoot)
 	for len(queue) > 0 {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -388,8 +388,8 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 				item := it.Item()
 
 				var decVersion uint64
-				var decEndRootHash typedHash
-				var decStartRootHash typedHash
+				var decEndRootHash api.TypedHash
+				var decStartRootHash api.TypedHash
 
 				if !writeLogKeyFmt.Decode(item.Key(), &decVersion, &decEndRootHash, &decStartRootHash) {
 					// This should not happen as the Badger iterator should take care of it.
@@ -525,7 +525,7 @@ func (d *badgerNodeDB) HasRoot(root node.Root) bool {
 		panic(err)
 	}
 
-	_, exists := rootsMeta.Roots[typedHashFromRoot(root)]
+	_, exists := rootsMeta.Roots[api.TypedHashFromRoot(root)]
 	return exists
 }
 
@@ -565,12 +565,12 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, roots []node.Root) error {
 
 	// Determine the set of finalized roots. Finalization is transitive, so if
 	// a parent root is finalized the child should be considered finalized too.
-	finalizedRoots := make(map[typedHash]bool)
+	finalizedRoots := make(map[api.TypedHash]bool)
 	for _, root := range roots {
 		if root.Version != version {
 			return fmt.Errorf("mkvs/badger: roots to finalize don't have matching versions")
 		}
-		finalizedRoots[typedHashFromRoot(root)] = true
+		finalizedRoots[api.TypedHashFromRoot(root)] = true
 	}
 
 	var rootsChanged bool
@@ -971,8 +971,8 @@ func (ba *badgerBatch) PutWriteLog(writeLog writelog.WriteLog, annotations write
 }
 
 func (ba *badgerBatch) RemoveNodes(nodes []node.Node) error {
-	if ba.chunk {
-		return fmt.Errorf("mkvs/badger: cannot remove nodes in chunk mode")
+	if ba.chunk && ba.oldRoot.Hash.IsEmpty() {
+		return fmt.Errorf("mkvs/badger: cannot remove nodes in chunk mode without an old root")
 	}
 
 	for _, n := range nodes {
@@ -995,8 +995,15 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 	if err := ba.db.sanityCheckNamespace(root.Namespace); err != nil {
 		return err
 	}
-	if !root.Follows(&ba.oldRoot) {
-		return api.ErrRootMustFollowOld
+	switch ba.chunk {
+	case true:
+		if !api.MultipartFollows(&root, &ba.oldRoot) {
+			return api.ErrRootMustFollowOld
+		}
+	case false:
+		if !root.Follows(&ba.oldRoot) {
+			return api.ErrRootMustFollowOld
+		}
 	}
 
 	// Make sure that the version that we try to commit into has not yet been finalized.
@@ -1014,15 +1021,20 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 		return err
 	}
 
-	rootHash := typedHashFromRoot(root)
-	if err = ba.bat.Set(rootNodeKeyFmt.Encode(&rootHash), []byte{}); err != nil {
-		return err
-	}
+	rootHash := api.TypedHashFromRoot(root)
+	rootKey := rootNodeKeyFmt.Encode(&rootHash)
 	if ba.multipartNodes != nil {
-		if err = ba.multipartNodes.Set(multipartRestoreNodeLogKeyFmt.Encode(&rootHash), []byte{}); err != nil {
-			return err
+		// Only log roots that did not exist before, so that aborting the multipart insert does
+		// not remove a root that was already present (e.g., a derived root with the same hash).
+		if _, err = ba.readTxn.Get(rootKey); err != nil && errors.Is(err, badger.ErrKeyNotFound) {
+			if err = ba.multipartNodes.Set(multipartRestoreNodeLogKeyFmt.Encode(&rootHash), []byte{}); err != nil {
+				return err
+			}
 		}
 	}
+	if err = ba.bat.Set(rootKey, []byte{}); err != nil {
+		return err
+	}
 
 	if rootsMeta.Roots[rootHash] != nil {
 		// Root already exists, no need to do anything since if the hash matches, everything will
@@ -1035,43 +1047,46 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 		}
 	} else {
 		// Create root with no derived roots.
-		rootsMeta.Roots[rootHash] = []typedHash{}
+		rootsMeta.Roots[rootHash] = []api.TypedHash{}
 
 		if err = rootsMeta.save(tx); err != nil {
 			return fmt.Errorf("mkvs/badger: failed to save roots metadata: %w", err)
 		}
 	}
 
-	if ba.chunk {
-		// Skip most of metadata updates if we are just importing chunks.
-		key := rootUpdatedNodesKeyFmt.Encode(root.Version, &rootHash)
-		if err = tx.Set(key, cbor.Marshal([]updatedNode{})); err != nil {
-			return fmt.Errorf("mkvs/badger: set returned error: %w", err)
+	// Update the root link for the old root.
+	oldRootHash := api.TypedHashFromRoot(ba.oldRoot)
+	if !ba.oldRoot.Hash.IsEmpty() {
+		if ba.oldRoot.Version < ba.db.meta.getEarliestVersion() && ba.oldRoot.Version != root.Version {
+			return api.ErrPreviousVersionMismatch
 		}
-	} else {
-		// Update the root link for the old root.
-		oldRootHash := typedHashFromRoot(ba.oldRoot)
-		if !ba.oldRoot.Hash.IsEmpty() {
-			if ba.oldRoot.Version < ba.db.meta.getEarliestVersion() && ba.oldRoot.Version != root.Version {
-				return api.ErrPreviousVersionMismatch
-			}
 
-			var oldRootsMeta *rootsMetadata
-			oldRootsMeta, err = loadRootsMetadata(tx, ba.oldRoot.Version)
-			if err != nil {
-				return err
-			}
+		var oldRootsMeta *rootsMetadata
+		oldRootsMeta, err = loadRootsMetadata(tx, ba.oldRoot.Version)
+		if err != nil {
+			return err
+		}
 
-			if _, ok := oldRootsMeta.Roots[oldRootHash]; !ok {
-				return api.ErrRootNotFound
-			}
+		derivedRoots, ok := oldRootsMeta.Roots[oldRootHash]
+		if !ok {
+			return api.ErrRootNotFound
+		}
 
-			oldRootsMeta.Roots[oldRootHash] = append(oldRootsMeta.Roots[oldRootHash], rootHash)
+		if !api.ContainsTypedHash(derivedRoots, rootHash) {
+			oldRootsMeta.Roots[oldRootHash] = append(derivedRoots, rootHash)
 			if err = oldRootsMeta.save(tx); err != nil {
 				return fmt.Errorf("mkvs/badger: failed to save old roots metadata: %w", err)
 			}
 		}
+	}
 
+	if ba.chunk && ba.oldRoot.Hash.IsEmpty() {
+		// Skip most of metadata updates if we are just importing chunks.
+		key := rootUpdatedNodesKeyFmt.Encode(root.Version, &rootHash)
+		if err = tx.Set(key, cbor.Marshal([]updatedNode{})); err != nil {
+			return fmt.Errorf("mkvs/badger: set returned error: %w", err)
+		}
+	} else {
 		// Store updated nodes (only needed until the version is finalized).
 		key := rootUpdatedNodesKeyFmt.Encode(root.Version, &rootHash)
 		if err = tx.Set(key, cbor.Marshal(ba.updatedNodes)); err != nil {
@@ -1137,7 +1152,7 @@ func (s *badgerSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
 	nodeKey := nodeKeyFmt.Encode(&h)
 	if s.batch.multipartNodes != nil {
 		if _, err = s.batch.readTxn.Get(nodeKey); err != nil && errors.Is(err, badger.ErrKeyNotFound) {
-			th := typedHashFromParts(node.RootTypeInvalid, h)
+			th := api.TypedHashFromParts(node.RootTypeInvalid, h)
 			if err = s.batch.multipartNodes.Set(multipartRestoreNodeLogKeyFmt.Encode(&th), []byte{}); err != nil {
 				return err
 			}
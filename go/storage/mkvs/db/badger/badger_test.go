@@ -224,6 +224,9 @@ func TestMultipartRestore(t *testing.T) {
 	t.Run("Abort", wrap(testAbort, testValues))
 	t.Run("Finalize", wrap(testFinalize, testValues))
 	t.Run("ExistingNodes", wrap(testExistingNodes, testValues[:1]))
+	t.Run("DerivedAbort", wrap(testDerivedAbort, testValues))
+	t.Run("DerivedFinalize", wrap(testDerivedFinalize, testValues))
+	t.Run("DerivedTreeAbort", wrap(testDerivedTreeAbort, testValues))
 }
 
 func testAbort(ctx *test) {
@@ -295,6 +298,118 @@ func testExistingNodes(ctx *test) {
 	verifyNodes(ctx.require, ctx.badgerdb, ctx.ckNodes)
 }
 
+func commitDerived(ctx *test) node.Root {
+	// Restore and finalize the checkpoint so that there is an existing root to derive from.
+	restoreCheckpoint(ctx, ctx.ckMeta, ctx.ckNodes)
+	err := ctx.badgerdb.Finalize(ctx.ctx, []node.Root{ctx.ckMeta.Root})
+	ctx.require.NoError(err, "Finalize()")
+
+	version := ctx.ckMeta.Root.Version + 5
+	err = ctx.badgerdb.StartMultipartInsert(version)
+	ctx.require.NoError(err, "StartMultipartInsert()")
+
+	// Removing nodes is not possible without an old root.
+	emptyRoot := node.Root{
+		Namespace: testNs,
+		Version:   version,
+		Type:      node.RootTypeState,
+	}
+	emptyRoot.Hash.Empty()
+	batch, err := ctx.badgerdb.NewBatch(emptyRoot, version, true)
+	ctx.require.NoError(err, "NewBatch(emptyRoot)")
+	err = batch.RemoveNodes(nil)
+	ctx.require.Error(err, "RemoveNodes() without an old root should fail")
+	batch.Reset()
+
+	// Derive a root from the checkpoint root, skipping some versions.
+	batch, err = ctx.badgerdb.NewBatch(ctx.ckMeta.Root, version, true)
+	ctx.require.NoError(err, "NewBatch(ckRoot)")
+	defer batch.Reset()
+	err = batch.RemoveNodes(nil)
+	ctx.require.NoError(err, "RemoveNodes()")
+
+	badRoot := ctx.ckMeta.Root
+	badRoot.Version = version
+	badRoot.Type = node.RootTypeIO
+	err = batch.Commit(badRoot)
+	ctx.require.ErrorIs(err, api.ErrRootMustFollowOld, "Commit() with a root of a different type should fail")
+
+	derivedRoot := ctx.ckMeta.Root
+	derivedRoot.Version = version
+	err = batch.Commit(derivedRoot)
+	ctx.require.NoError(err, "Commit()")
+
+	return derivedRoot
+}
+
+func testDerivedAbort(ctx *test) {
+	// Derive a root from an existing root as part of a multipart insert and abort. The existing
+	// root and its nodes must remain intact.
+	_ = commitDerived(ctx)
+
+	err := ctx.badgerdb.AbortMultipartInsert()
+	ctx.require.NoError(err, "AbortMultipartInsert()")
+
+	verifyNodes(ctx.require, ctx.badgerdb, ctx.ckNodes)
+	checkNoLogKeys(ctx.require, ctx.badgerdb)
+
+	ctx.require.True(ctx.badgerdb.HasRoot(ctx.ckMeta.Root), "checkpoint root should still exist")
+
+	tree := mkvs.NewWithRoot(nil, ctx.badgerdb, ctx.ckMeta.Root)
+	defer tree.Close()
+	value, err := tree.Get(ctx.ctx, []byte("0"))
+	ctx.require.NoError(err, "Get()")
+	ctx.require.Equal(testValues[0], value, "checkpoint root should remain readable")
+}
+
+func testDerivedFinalize(ctx *test) {
+	// Derive a root from an existing root as part of a multipart insert and finalize. The
+	// derived root must be usable and share all nodes with the existing root.
+	derivedRoot := commitDerived(ctx)
+
+	err := ctx.badgerdb.Finalize(ctx.ctx, []node.Root{derivedRoot})
+	ctx.require.NoError(err, "Finalize()")
+
+	verifyNodes(ctx.require, ctx.badgerdb, ctx.ckNodes)
+	checkNoLogKeys(ctx.require, ctx.badgerdb)
+
+	ctx.require.True(ctx.badgerdb.HasRoot(derivedRoot), "derived root should exist")
+
+	tree := mkvs.NewWithRoot(nil, ctx.badgerdb, derivedRoot)
+	defer tree.Close()
+	value, err := tree.Get(ctx.ctx, []byte("0"))
+	ctx.require.NoError(err, "Get()")
+	ctx.require.Equal(testValues[0], value, "derived root should share the checkpoint nodes")
+}
+
+func testDerivedTreeAbort(ctx *test) {
+	// Update an existing root in a tree committed as part of a multipart insert and abort. Exactly
+	// the nodes of the existing root should remain.
+	restoreCheckpoint(ctx, ctx.ckMeta, ctx.ckNodes)
+	err := ctx.badgerdb.Finalize(ctx.ctx, []node.Root{ctx.ckMeta.Root})
+	ctx.require.NoError(err, "Finalize()")
+
+	version := ctx.ckMeta.Root.Version + 5
+	err = ctx.badgerdb.StartMultipartInsert(version)
+	ctx.require.NoError(err, "StartMultipartInsert()")
+
+	tree := mkvs.NewWithRoot(nil, ctx.badgerdb, ctx.ckMeta.Root)
+	defer tree.Close()
+	err = tree.ApplyWriteLog(ctx.ctx, writelog.NewStaticIterator(writelog.WriteLog{
+		{Key: []byte("0"), Value: []byte("replaced")},
+		{Key: []byte("derived"), Value: []byte("value")},
+	}))
+	ctx.require.NoError(err, "ApplyWriteLog()")
+	_, _, err = tree.Commit(ctx.ctx, testNs, version, mkvs.Multipart())
+	ctx.require.NoError(err, "Commit()")
+
+	err = ctx.badgerdb.AbortMultipartInsert()
+	ctx.require.NoError(err, "AbortMultipartInsert()")
+
+	verifyNodes(ctx.require, ctx.badgerdb, ctx.ckNodes)
+	checkNoLogKeys(ctx.require, ctx.badgerdb)
+}
+
 func TestVersionChecks(t *testing.T) {
 	require := require.New(t)
 	ndb, err := New(dbCfg)
@@ -380,3 +495,59 @@ func TestFinalizeBasic(t *testing.T) {
 	err = ndb.Finalize(ctx, []node.Root{root2})
 	require.Errorf(err, "mkvs: root not found", "Finalize({root2-broken})")
 }
+
+func TestStatsAndCompact(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	root1 := fillDB(ctx, require, testValues, nil, 0, 1, ndb)
+	err = ndb.Finalize(ctx, []node.Root{root1})
+	require.NoError(err, "Finalize({root1})")
+
+	values2 := append([][]byte{}, testValues...)
+	values2[0] = []byte("a value that is different")
+	root2 := fillDB(ctx, require, values2, &root1, 1, 2, ndb)
+	err = ndb.Finalize(ctx, []node.Root{root2})
+	require.NoError(err, "Finalize({root2})")
+
+	stats, err := ndb.Stats(ctx)
+	require.NoError(err, "Stats()")
+	require.Len(stats.Versions, 2, "there should be stats for two versions")
+	require.EqualValues(1, stats.Versions[0].Version)
+	require.EqualValues(2, stats.Versions[1].Version)
+	require.NotZero(stats.Versions[0].Nodes, "there should be nodes in the first version")
+	require.NotZero(stats.Versions[1].Nodes, "there should be nodes in the second version")
+	require.EqualValues(1, stats.Versions[0].WriteLogs, "there should be a write log in the first version")
+	require.NotZero(stats.Versions[0].WriteLogSize, "write log size should be non-zero")
+	require.Zero(stats.OrphanedNodes, "there should be no orphaned nodes")
+	require.Contains(stats.DiskSize, "lsm")
+	require.Contains(stats.DiskSize, "vlog")
+
+	// Pruning the first version should orphan the nodes that were replaced in the second version.
+	err = ndb.Prune(ctx, 1)
+	require.NoError(err, "Prune(1)")
+
+	prunedStats, err := ndb.Stats(ctx)
+	require.NoError(err, "Stats()")
+	require.Len(prunedStats.Versions, 2, "there should be stats for two versions")
+	require.Less(prunedStats.Versions[0].Nodes, stats.Versions[0].Nodes, "nodes should be removed from the first version")
+	require.Zero(prunedStats.Versions[0].WriteLogs, "write logs should be removed from the first version")
+	require.EqualValues(stats.Versions[1], prunedStats.Versions[1], "the second version should not change")
+	require.NotZero(prunedStats.OrphanedNodes, "there should be orphaned nodes")
+
+	err = ndb.Compact(ctx)
+	require.NoError(err, "Compact()")
+
+	// Data should still be readable after compaction.
+	tree := mkvs.NewWithRoot(nil, ndb, root2)
+	defer tree.Close()
+	for i, val := range values2 {
+		v, err := tree.Get(ctx, []byte(strconv.Itoa(i)))
+		require.NoError(err, "Get()")
+		require.EqualValues(val, v)
+	}
+}
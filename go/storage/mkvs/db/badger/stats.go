@@ -0,0 +1,133 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dgraph-io/badger/v3"
+
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+// flattenWorkers is the number of workers used when flattening the LSM tree during compaction.
+const flattenWorkers = 1
+
+func (d *badgerNodeDB) Stats(ctx context.Context) (*api.Stats, error) {
+	versions := make(map[uint64]*api.VersionStats)
+	getVersionStats := func(version uint64) *api.VersionStats {
+		vs := versions[version]
+		if vs == nil {
+			vs = &api.VersionStats{Version: version}
+			versions[version] = vs
+		}
+		return vs
+	}
+
+	var stats api.Stats
+	tx := d.db.NewTransactionAt(maxTimestamp, false)
+	defer tx.Discard()
+
+	// Count live nodes by the version in which they were created. Entries that are shadowed by a
+	// newer entry at or before the earliest version can no longer be read and will be removed by
+	// compaction.
+	earliestTs := versionToTs(d.meta.getEarliestVersion())
+	it := tx.NewIterator(badger.IteratorOptions{
+		Prefix:      nodeKeyFmt.Encode(),
+		AllVersions: true,
+	})
+	defer it.Close()
+
+	var (
+		lastKey  []byte
+		shadowTs uint64
+	)
+	for it.Rewind(); it.Valid(); it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		item := it.Item()
+		if !bytes.Equal(lastKey, item.Key()) {
+			// Entries for each key are ordered from newest to oldest.
+			lastKey = item.KeyCopy(lastKey[:0])
+			shadowTs = maxTimestamp
+		}
+
+		switch {
+		case item.IsDeletedOrExpired():
+		case shadowTs > earliestTs:
+			getVersionStats(tsToVersion(item.Version())).Nodes++
+		default:
+			stats.OrphanedNodes++
+		}
+		shadowTs = item.Version()
+	}
+
+	// Collect write log sizes.
+	wit := tx.NewIterator(badger.IteratorOptions{Prefix: writeLogKeyFmt.Encode()})
+	defer wit.Close()
+
+	for wit.Rewind(); wit.Valid(); wit.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var (
+			version          uint64
+			srcRoot, dstRoot api.TypedHash
+		)
+		item := wit.Item()
+		if !writeLogKeyFmt.Decode(item.Key(), &version, &dstRoot, &srcRoot) {
+			return nil, fmt.Errorf("mkvs/badger: undecodable write log key (%v)", item.Key())
+		}
+
+		vs := getVersionStats(version)
+		vs.WriteLogs++
+		vs.WriteLogSize += uint64(item.ValueSize())
+	}
+
+	for _, vs := range versions {
+		stats.Versions = append(stats.Versions, *vs)
+	}
+	sort.Slice(stats.Versions, func(i, j int) bool {
+		return stats.Versions[i].Version < stats.Versions[j].Version
+	})
+
+	lsm, vlog := d.db.Size()
+	stats.DiskSize = map[string]int64{
+		"lsm":  lsm,
+		"vlog": vlog,
+	}
+
+	return &stats, nil
+}
+
+func (d *badgerNodeDB) Compact(ctx context.Context) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Force compaction of all LSM tree levels so that deleted and shadowed entries below the
+	// discard timestamp are dropped.
+	if err := d.db.Flatten(flattenWorkers); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flatten: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Rewrite value log files to reclaim space used by values of dropped entries.
+	if d.db.Opts().InMemory {
+		return nil
+	}
+	if err := cmnBadger.RunValueLogGC(d.db); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to run value log GC: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,1161 @@
+// Package bbolt provides a bbolt-backed node database.
+package bbolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+const (
+	// DBFilename is the name of the bbolt database file inside the database directory.
+	DBFilename = "mkvs.bbolt"
+
+	dbVersion = 1
+
+	// multipartVersionNone is the value used for the multipart version in metadata
+	// when no multipart restore is in progress.
+	multipartVersionNone uint64 = 0
+
+	// openTimeout is the amount of time to wait for the database file lock.
+	openTimeout = 1 * time.Second
+)
+
+var (
+	// bucketName is the name of the bucket holding all of the node database state.
+	bucketName = []byte("mkvs")
+
+	// nodeKeyFmt is the key format for nodes (node hash, version).
+	//
+	// Value is a versioned entry holding the serialized node.
+	nodeKeyFmt = keyformat.New(0x00, &hash.Hash{}, uint64(0))
+	// writeLogKeyFmt is the key format for write logs (version, new root,
+	// old root).
+	//
+	// Value is CBOR-serialized write log.
+	writeLogKeyFmt = keyformat.New(0x01, uint64(0), &api.TypedHash{}, &api.TypedHash{})
+	// rootsMetadataKeyFmt is the key format for roots metadata. The key format is (version).
+	//
+	// Value is CBOR-serialized rootsMetadata.
+	rootsMetadataKeyFmt = keyformat.New(0x02, uint64(0))
+	// rootUpdatedNodesKeyFmt is the key format for the pending updated nodes for the
+	// given root that need to be removed only in case the given root is not among
+	// the finalized roots. They key format is (version, root).
+	//
+	// Value is CBOR-serialized []updatedNode.
+	rootUpdatedNodesKeyFmt = keyformat.New(0x03, uint64(0), &api.TypedHash{})
+	// metadataKeyFmt is the key format for metadata.
+	//
+	// Value is CBOR-serialized metadata.
+	metadataKeyFmt = keyformat.New(0x04)
+	// multipartRestoreNodeLogKeyFmt is the key format for the nodes inserted during a chunk restore.
+	// Once a set of chunks is fully restored, these entries should be removed. If chunk restoration
+	// is interrupted for any reason, the nodes associated with these keys should be removed, along
+	// with these entries.
+	//
+	// Value is empty.
+	multipartRestoreNodeLogKeyFmt = keyformat.New(0x05, &api.TypedHash{})
+	// rootNodeKeyFmt is the key format for root nodes (typed node hash, version).
+	//
+	// Value is an empty versioned entry.
+	rootNodeKeyFmt = keyformat.New(0x06, &api.TypedHash{}, uint64(0))
+	// nodeGarbageKeyFmt is the key format for node entries that are shadowed starting with the
+	// given version (version, node hash).
+	//
+	// Value is empty.
+	nodeGarbageKeyFmt = keyformat.New(0x07, uint64(0), &hash.Hash{})
+	// rootGarbageKeyFmt is the key format for root node entries that are shadowed starting with
+	// the given version (version, typed node hash).
+	//
+	// Value is empty.
+	rootGarbageKeyFmt = keyformat.New(0x08, uint64(0), &api.TypedHash{})
+
+	nodeStore = &versionedKeyFormat{
+		keyFmt:        nodeKeyFmt,
+		garbageKeyFmt: nodeGarbageKeyFmt,
+		newID:         func() interface{} { return &hash.Hash{} },
+	}
+	rootStore = &versionedKeyFormat{
+		keyFmt:        rootNodeKeyFmt,
+		garbageKeyFmt: rootGarbageKeyFmt,
+		newID:         func() interface{} { return &api.TypedHash{} },
+	}
+)
+
+// New creates a new bbolt-backed node database.
+//
+// As bbolt stores everything in a single file, the configured database path is used as a
+// directory containing the database file.
+func New(cfg *api.Config) (api.NodeDB, error) {
+	db := &bboltNodeDB{
+		logger:           logging.GetLogger("mkvs/db/bbolt"),
+		namespace:        cfg.Namespace,
+		readOnly:         cfg.ReadOnly,
+		discardWriteLogs: cfg.DiscardWriteLogs,
+	}
+
+	dir := cfg.DB
+	if cfg.MemoryOnly {
+		db.logger.Warn("memory-only mode is not supported, using a temporary directory instead")
+
+		var err error
+		if dir, err = ioutil.TempDir("", "oasis-mkvs-bbolt"); err != nil {
+			return nil, fmt.Errorf("mkvs/bbolt: failed to create temporary directory: %w", err)
+		}
+		db.tempDir = dir
+	}
+	if !cfg.ReadOnly {
+		if err := common.Mkdir(dir); err != nil {
+			db.removeTempDir()
+			return nil, fmt.Errorf("mkvs/bbolt: failed to create database directory: %w", err)
+		}
+	}
+
+	var err error
+	db.db, err = bolt.Open(filepath.Join(dir, DBFilename), 0o600, &bolt.Options{
+		Timeout:        openTimeout,
+		ReadOnly:       cfg.ReadOnly,
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	})
+	if err != nil {
+		db.removeTempDir()
+		return nil, fmt.Errorf("mkvs/bbolt: failed to open database: %w", err)
+	}
+	db.db.NoSync = cfg.NoFsync
+
+	// Load database metadata.
+	if err = db.load(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("mkvs/bbolt: failed to load metadata: %w", err)
+	}
+
+	// Cleanup any multipart restore remnants, since they can't be used anymore.
+	if err = db.cleanMultipartLocked(true); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("mkvs/bbolt: failed to clean leftovers from multipart restore: %w", err)
+	}
+
+	return db, nil
+}
+
+type bboltNodeDB struct { // nolint: maligned
+	logger *logging.Logger
+
+	namespace common.Namespace
+
+	readOnly         bool
+	discardWriteLogs bool
+
+	multipartVersion uint64
+
+	db      *bolt.DB
+	tempDir string
+
+	// metaUpdateLock must be held at any point where the metadata is read and updated.
+	metaUpdateLock sync.Mutex
+	meta           metadata
+
+	closeOnce sync.Once
+}
+
+func (d *bboltNodeDB) load() error {
+	loadFn := func(b *bolt.Bucket) (bool, error) {
+		data := b.Get(metadataKeyFmt.Encode())
+		if data == nil {
+			return false, nil
+		}
+
+		// Metadata already exists, just load it and verify that it is
+		// compatible with what we have here.
+		if err := cbor.UnmarshalTrusted(data, &d.meta.value); err != nil {
+			return false, err
+		}
+
+		if d.meta.value.Version != dbVersion {
+			return false, fmt.Errorf("incompatible database version (expected: %d got: %d)",
+				dbVersion,
+				d.meta.value.Version,
+			)
+		}
+		if !d.meta.value.Namespace.Equal(&d.namespace) {
+			return false, fmt.Errorf("incompatible namespace (expected: %s got: %s)",
+				d.namespace,
+				d.meta.value.Namespace,
+			)
+		}
+		return true, nil
+	}
+
+	if d.readOnly {
+		return d.db.View(func(tx *bolt.Tx) error {
+			b := tx.Bucket(bucketName)
+			if b == nil {
+				return fmt.Errorf("database not initialized")
+			}
+			exists, err := loadFn(b)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("database metadata missing")
+			}
+			return nil
+		})
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		exists, err := loadFn(b)
+		if err != nil || exists {
+			return err
+		}
+
+		// No metadata exists, create some.
+		d.meta.value.Version = dbVersion
+		d.meta.value.Namespace = d.namespace
+		return d.meta.save(b)
+	})
+}
+
+func (d *bboltNodeDB) view(fn func(b *bolt.Bucket) error) error {
+	return d.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(bucketName))
+	})
+}
+
+func (d *bboltNodeDB) update(fn func(b *bolt.Bucket) error) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(bucketName))
+	})
+}
+
+func (d *bboltNodeDB) sanityCheckNamespace(ns common.Namespace) error {
+	if !ns.Equal(&d.namespace) {
+		return api.ErrBadNamespace
+	}
+	return nil
+}
+
+func (d *bboltNodeDB) checkRoot(b *bolt.Bucket, root node.Root) error {
+	rootHash := api.TypedHashFromRoot(root)
+	if !rootStore.exists(b, &rootHash, root.Version) {
+		return api.ErrRootNotFound
+	}
+	return nil
+}
+
+// Assumes metaUpdateLock is held when called.
+func (d *bboltNodeDB) cleanMultipartLocked(removeNodes bool) error {
+	var version uint64
+
+	if d.multipartVersion != multipartVersionNone {
+		version = d.multipartVersion
+	} else {
+		version = d.meta.getMultipartVersion()
+	}
+	if version == multipartVersionNone {
+		// No multipart in progress, but it's not an error to call in a situation like this.
+		return nil
+	}
+
+	err := d.update(func(b *bolt.Bucket) error {
+		var logKeys [][]byte
+		c := b.Cursor()
+		prefix := multipartRestoreNodeLogKeyFmt.Encode()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			logKeys = append(logKeys, append([]byte{}, k...))
+		}
+
+		if removeNodes && len(logKeys) > 0 {
+			d.logger.Info("removing some nodes from a multipart restore")
+		}
+		for _, key := range logKeys {
+			if removeNodes {
+				var hash api.TypedHash
+				if !multipartRestoreNodeLogKeyFmt.Decode(key, &hash) {
+					panic("mkvs/bbolt: bad iterator")
+				}
+				switch hash.Type() {
+				case node.RootTypeInvalid:
+					h := hash.Hash()
+					if err := nodeStore.delete(b, &h, version); err != nil {
+						return err
+					}
+				default:
+					if err := rootStore.delete(b, &hash, version); err != nil {
+						return err
+					}
+				}
+			}
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return d.meta.setMultipartVersion(b, multipartVersionNone)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.multipartVersion = multipartVersionNone
+	return nil
+}
+
+func (d *bboltNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	if ptr == nil || !ptr.IsClean() {
+		panic("mkvs/bbolt: attempted to get invalid pointer from node database")
+	}
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return nil, err
+	}
+	// If the version is earlier than the earliest version, we don't have the node (it was pruned).
+	if root.Version < d.meta.getEarliestVersion() {
+		return nil, api.ErrNodeNotFound
+	}
+
+	var n node.Node
+	err := d.view(func(b *bolt.Bucket) error {
+		// Check if the root actually exists.
+		if err := d.checkRoot(b, root); err != nil {
+			return err
+		}
+
+		data, ok := nodeStore.get(b, &ptr.Hash, root.Version)
+		if !ok {
+			return api.ErrNodeNotFound
+		}
+
+		var err error
+		if n, err = node.UnmarshalBinary(data); err != nil {
+			d.logger.Error("failed to unmarshal node",
+				"err", err,
+			)
+			return fmt.Errorf("mkvs/bbolt: failed to unmarshal node: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (d *bboltNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.Root) (writelog.Iterator, error) {
+	if d.discardWriteLogs {
+		return nil, api.ErrWriteLogNotFound
+	}
+	if !endRoot.Follows(&startRoot) {
+		return nil, api.ErrRootMustFollowOld
+	}
+	if err := d.sanityCheckNamespace(startRoot.Namespace); err != nil {
+		return nil, err
+	}
+	// If the version is earlier than the earliest version, we don't have the roots.
+	if endRoot.Version < d.meta.getEarliestVersion() {
+		return nil, api.ErrWriteLogNotFound
+	}
+
+	// Start at the end root and search towards the start root. This assumes that the
+	// chains are not long and that there is not a lot of forks as in that case performance
+	// would suffer.
+	//
+	// In reality the two common cases are:
+	// - State updates: s -> s' (a single hop)
+	// - I/O updates: empty -> i -> io (two hops)
+	//
+	// For this reason, we currently refuse to traverse more than two hops.
+	const maxAllowedHops = 2
+
+	type wlItem struct {
+		depth       uint8
+		endRootHash api.TypedHash
+		logKeys     [][]byte
+		logRoots    []api.TypedHash
+	}
+
+	// Since bbolt read transactions block database file growth, the write logs are loaded
+	// eagerly instead of keeping the transaction open while the logs are being streamed.
+	var (
+		logs     []api.HashedDBWriteLog
+		logRoots []api.TypedHash
+	)
+	err := d.view(func(b *bolt.Bucket) error {
+		// Check if the root actually exists.
+		if err := d.checkRoot(b, endRoot); err != nil {
+			return err
+		}
+
+		// NOTE: We could use a proper deque, but as long as we keep the number of hops and
+		//       forks low, this should not be a problem.
+		queue := []*wlItem{{depth: 0, endRootHash: api.TypedHashFromRoot(endRoot)}}
+		startRootHash := api.TypedHashFromRoot(startRoot)
+		c := b.Cursor()
+		for len(queue) > 0 {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			curItem := queue[0]
+			queue = queue[1:]
+
+			// Iterate over all write logs that result in the current item.
+			prefix := writeLogKeyFmt.Encode(endRoot.Version, &curItem.endRootHash)
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				var decVersion uint64
+				var decEndRootHash api.TypedHash
+				var decStartRootHash api.TypedHash
+
+				if !writeLogKeyFmt.Decode(k, &decVersion, &decEndRootHash, &decStartRootHash) {
+					panic("mkvs/bbolt: bad iterator")
+				}
+
+				nextItem := wlItem{
+					depth:       curItem.depth + 1,
+					endRootHash: decStartRootHash,
+					// Only store log keys to avoid keeping everything in memory while
+					// we are searching for the right path.
+					logKeys:  append(append([][]byte{}, curItem.logKeys...), append([]byte{}, k...)),
+					logRoots: append(append([]api.TypedHash{}, curItem.logRoots...), curItem.endRootHash),
+				}
+				if nextItem.endRootHash.Equal(&startRootHash) {
+					// Path has been found, deserialize the write logs.
+					for _, key := range nextItem.logKeys {
+						var log api.HashedDBWriteLog
+						if err := cbor.UnmarshalTrusted(b.Get(key), &log); err != nil {
+							return err
+						}
+						logs = append(logs, log)
+					}
+					logRoots = nextItem.logRoots
+					return nil
+				}
+
+				if nextItem.depth < maxAllowedHops {
+					queue = append(queue, &nextItem)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if logRoots == nil {
+		return nil, api.ErrWriteLogNotFound
+	}
+
+	var index int
+	return api.ReviveHashedDBWriteLogs(ctx,
+		func() (node.Root, api.HashedDBWriteLog, error) {
+			if index >= len(logs) {
+				return node.Root{}, nil, nil
+			}
+
+			root := node.Root{
+				Namespace: endRoot.Namespace,
+				Version:   endRoot.Version,
+				Type:      logRoots[index].Type(),
+				Hash:      logRoots[index].Hash(),
+			}
+			log := logs[index]
+			index++
+			return root, log, nil
+		},
+		func(root node.Root, h hash.Hash) (*node.LeafNode, error) {
+			leaf, err := d.GetNode(root, &node.Pointer{Hash: h, Clean: true})
+			if err != nil {
+				return nil, err
+			}
+			return leaf.(*node.LeafNode), nil
+		},
+		func() {},
+	)
+}
+
+func (d *bboltNodeDB) GetLatestVersion(ctx context.Context) (uint64, error) {
+	version, _ := d.meta.getLastFinalizedVersion()
+	return version, nil
+}
+
+func (d *bboltNodeDB) GetEarliestVersion(ctx context.Context) (uint64, error) {
+	return d.meta.getEarliestVersion(), nil
+}
+
+func (d *bboltNodeDB) GetRootsForVersion(ctx context.Context, version uint64) (roots []node.Root, err error) {
+	// If the version is earlier than the earliest version, we don't have the roots.
+	if version < d.meta.getEarliestVersion() {
+		return nil, nil
+	}
+
+	var rootsMeta *rootsMetadata
+	if err = d.view(func(b *bolt.Bucket) (vErr error) {
+		rootsMeta, vErr = loadRootsMetadata(b, version)
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	for rootHash := range rootsMeta.Roots {
+		roots = append(roots, node.Root{
+			Namespace: d.namespace,
+			Version:   version,
+			Type:      rootHash.Type(),
+			Hash:      rootHash.Hash(),
+		})
+	}
+	return
+}
+
+func (d *bboltNodeDB) HasRoot(root node.Root) bool {
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return false
+	}
+
+	// An empty root is always implicitly present.
+	if root.Hash.IsEmpty() {
+		return true
+	}
+
+	// If the version is earlier than the earliest version, we don't have the root.
+	if root.Version < d.meta.getEarliestVersion() {
+		return false
+	}
+
+	var rootsMeta *rootsMetadata
+	if err := d.view(func(b *bolt.Bucket) (vErr error) {
+		rootsMeta, vErr = loadRootsMetadata(b, root.Version)
+		return
+	}); err != nil {
+		panic(err)
+	}
+
+	_, exists := rootsMeta.Roots[api.TypedHashFromRoot(root)]
+	return exists
+}
+
+func (d *bboltNodeDB) Finalize(ctx context.Context, roots []node.Root) error { // nolint: gocyclo
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+
+	if len(roots) == 0 {
+		return fmt.Errorf("mkvs/bbolt: need at least one root to finalize")
+	}
+	version := roots[0].Version
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.multipartVersion != multipartVersionNone && d.multipartVersion != version {
+		return api.ErrInvalidMultipartVersion
+	}
+
+	// Make sure that the previous version has been finalized (if we are not restoring).
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if d.multipartVersion == multipartVersionNone && version > 0 && exists && lastFinalizedVersion < (version-1) {
+		return api.ErrNotFinalized
+	}
+	// Make sure that this version has not yet been finalized.
+	if exists && version <= lastFinalizedVersion {
+		return api.ErrAlreadyFinalized
+	}
+
+	// Determine the set of finalized roots. Finalization is transitive, so if
+	// a parent root is finalized the child should be considered finalized too.
+	finalizedRoots := make(map[api.TypedHash]bool)
+	for _, root := range roots {
+		if root.Version != version {
+			return fmt.Errorf("mkvs/bbolt: roots to finalize don't have matching versions")
+		}
+		finalizedRoots[api.TypedHashFromRoot(root)] = true
+	}
+
+	err := d.update(func(b *bolt.Bucket) error {
+		var rootsChanged bool
+		rootsMeta, err := loadRootsMetadata(b, version)
+		if err != nil {
+			return err
+		}
+
+		for updated := true; updated; {
+			updated = false
+
+			for rootHash, derivedRoots := range rootsMeta.Roots {
+				if len(derivedRoots) == 0 {
+					continue
+				}
+
+				for _, nextRoot := range derivedRoots {
+					if !finalizedRoots[rootHash] && finalizedRoots[nextRoot] {
+						finalizedRoots[rootHash] = true
+						updated = true
+					}
+				}
+			}
+		}
+
+		// Sanity check the input roots list.
+		for iroot := range finalizedRoots {
+			h := iroot.Hash()
+			if _, ok := rootsMeta.Roots[iroot]; !ok && !h.IsEmpty() {
+				return api.ErrRootNotFound
+			}
+		}
+
+		// Go through all roots and prune them based on whether they are finalized or not.
+		maybeLoneNodes := make(map[hash.Hash]bool)
+		notLoneNodes := make(map[hash.Hash]bool)
+
+		for rootHash := range rootsMeta.Roots {
+			rootUpdatedNodesKey := rootUpdatedNodesKeyFmt.Encode(version, &rootHash)
+
+			// Load hashes of nodes added during this version for this root.
+			data := b.Get(rootUpdatedNodesKey)
+			if data == nil {
+				panic(fmt.Errorf("mkvs/bbolt: missing root updated nodes index"))
+			}
+
+			var updatedNodes []updatedNode
+			if err = cbor.UnmarshalTrusted(data, &updatedNodes); err != nil {
+				panic(fmt.Errorf("mkvs/bbolt: corrupted root updated nodes index: %w", err))
+			}
+
+			if finalizedRoots[rootHash] {
+				// Make sure not to remove any nodes shared with finalized roots.
+				for _, n := range updatedNodes {
+					if n.Removed {
+						maybeLoneNodes[n.Hash] = true
+					} else {
+						notLoneNodes[n.Hash] = true
+					}
+				}
+			} else {
+				// Remove any non-finalized roots. It is safe to remove these nodes as they are
+				// only removed starting with this version, so any later versions that resurrect
+				// them will have their own entries.
+				for _, n := range updatedNodes {
+					if !n.Removed {
+						maybeLoneNodes[n.Hash] = true
+					}
+				}
+
+				delete(rootsMeta.Roots, rootHash)
+				rootsChanged = true
+
+				// Remove write logs for the non-finalized root.
+				if !d.discardWriteLogs {
+					if err = deletePrefix(b, writeLogKeyFmt.Encode(version, &rootHash)); err != nil {
+						return err
+					}
+				}
+			}
+
+			// Set of updated nodes no longer needed after finalization.
+			if err = b.Delete(rootUpdatedNodesKey); err != nil {
+				return err
+			}
+		}
+
+		// Clean any lone nodes.
+		for h := range maybeLoneNodes {
+			if notLoneNodes[h] {
+				continue
+			}
+
+			h := h
+			if err = nodeStore.delete(b, &h, version); err != nil {
+				return err
+			}
+		}
+
+		// Save roots metadata if changed.
+		if rootsChanged {
+			if err = rootsMeta.save(b); err != nil {
+				return fmt.Errorf("mkvs/bbolt: failed to save roots metadata: %w", err)
+			}
+		}
+
+		// Update last finalized version.
+		if err = d.meta.setLastFinalizedVersion(b, version); err != nil {
+			return fmt.Errorf("mkvs/bbolt: failed to set last finalized version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Clean multipart metadata if there is any.
+	if d.multipartVersion != multipartVersionNone {
+		if err := d.cleanMultipartLocked(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *bboltNodeDB) Prune(ctx context.Context, version uint64) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.multipartVersion != multipartVersionNone {
+		return api.ErrMultipartInProgress
+	}
+
+	// Make sure that the version that we try to prune has been finalized.
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if !exists || lastFinalizedVersion < version {
+		return api.ErrNotFinalized
+	}
+	// Make sure that the version that we are trying to prune is the earliest version.
+	if version != d.meta.getEarliestVersion() {
+		return api.ErrNotEarliest
+	}
+
+	var rootsMeta *rootsMetadata
+	if err := d.view(func(b *bolt.Bucket) (vErr error) {
+		rootsMeta, vErr = loadRootsMetadata(b, version)
+		return
+	}); err != nil {
+		return err
+	}
+
+	// Collect all items created in this version by traversing the lone roots. This needs to be
+	// done before starting the write transaction as traversal requires read transactions.
+	var (
+		loneRoots []api.TypedHash
+		loneNodes []hash.Hash
+	)
+	for rootHash, derivedRoots := range rootsMeta.Roots {
+		if len(derivedRoots) > 0 {
+			// Not a lone root.
+			continue
+		}
+		loneRoots = append(loneRoots, rootHash)
+
+		root := node.Root{
+			Namespace: d.namespace,
+			Version:   version,
+			Type:      rootHash.Type(),
+			Hash:      rootHash.Hash(),
+		}
+		var innerErr error
+		err := api.Visit(ctx, d, root, func(ctx context.Context, n node.Node) bool {
+			h := n.GetHash()
+			innerErr = d.view(func(b *bolt.Bucket) error {
+				k, v := nodeStore.latest(b, &h, version)
+				if len(v) == 0 || v[0] != valueLive {
+					return api.ErrNodeNotFound
+				}
+				if nodeStore.decodeVersion(k) == version {
+					loneNodes = append(loneNodes, h)
+				}
+				return nil
+			})
+			return innerErr == nil
+		})
+		if innerErr != nil {
+			return innerErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err := d.update(func(b *bolt.Bucket) error {
+		// Remove all roots in version.
+		for i := range loneNodes {
+			if err := nodeStore.delete(b, &loneNodes[i], version); err != nil {
+				return err
+			}
+		}
+		for i := range loneRoots {
+			if err := rootStore.delete(b, &loneRoots[i], version); err != nil {
+				return err
+			}
+		}
+
+		// Delete roots metadata.
+		if err := b.Delete(rootsMetadataKeyFmt.Encode(version)); err != nil {
+			return fmt.Errorf("mkvs/bbolt: failed to remove roots metadata: %w", err)
+		}
+
+		// Prune all write logs in version.
+		if !d.discardWriteLogs {
+			if err := deletePrefix(b, writeLogKeyFmt.Encode(version)); err != nil {
+				return err
+			}
+		}
+
+		// Discard everything invalidated at or below the new earliest version.
+		if err := nodeStore.collectGarbage(b, version+1); err != nil {
+			return fmt.Errorf("mkvs/bbolt: failed to collect node garbage: %w", err)
+		}
+		if err := rootStore.collectGarbage(b, version+1); err != nil {
+			return fmt.Errorf("mkvs/bbolt: failed to collect root garbage: %w", err)
+		}
+
+		// Update metadata.
+		if err := d.meta.setEarliestVersion(b, version+1); err != nil {
+			return fmt.Errorf("mkvs/bbolt: failed to set earliest version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("mkvs/bbolt: failed to commit: %w", err)
+	}
+	return nil
+}
+
+func (d *bboltNodeDB) StartMultipartInsert(version uint64) error {
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if version == multipartVersionNone {
+		return api.ErrInvalidMultipartVersion
+	}
+
+	if d.multipartVersion != multipartVersionNone {
+		if d.multipartVersion != version {
+			return api.ErrMultipartInProgress
+		}
+		// Multipart already initialized at the same version, so this was
+		// probably called e.g. as part of a further checkpoint restore.
+		return nil
+	}
+
+	if err := d.update(func(b *bolt.Bucket) error {
+		return d.meta.setMultipartVersion(b, version)
+	}); err != nil {
+		return err
+	}
+
+	d.multipartVersion = version
+
+	return nil
+}
+
+func (d *bboltNodeDB) AbortMultipartInsert() error {
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	return d.cleanMultipartLocked(true)
+}
+
+func (d *bboltNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) (api.Batch, error) {
+	if d.readOnly {
+		return nil, api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.multipartVersion != multipartVersionNone && d.multipartVersion != version {
+		return nil, api.ErrInvalidMultipartVersion
+	}
+	if chunk != (d.multipartVersion != multipartVersionNone) {
+		return nil, api.ErrMultipartInProgress
+	}
+
+	return &bboltBatch{
+		db:        d,
+		version:   version,
+		multipart: d.multipartVersion != multipartVersionNone,
+		oldRoot:   oldRoot,
+		chunk:     chunk,
+	}, nil
+}
+
+// Size returns the number of bytes in use by the stored data.
+//
+// NOTE: The database file is grown in large increments and freed pages are reused, so the size
+// is computed by traversing all pages instead of using the file size.
+func (d *bboltNodeDB) Size() (size int64, err error) {
+	err = d.view(func(b *bolt.Bucket) error {
+		stats := b.Stats()
+		size = int64(stats.BranchInuse + stats.LeafInuse + stats.InlineBucketInuse)
+		return nil
+	})
+	return
+}
+
+func (d *bboltNodeDB) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *bboltNodeDB) Close() {
+	d.closeOnce.Do(func() {
+		if err := d.db.Close(); err != nil {
+			d.logger.Error("close returned error",
+				"err", err,
+			)
+		}
+		d.removeTempDir()
+	})
+}
+
+func (d *bboltNodeDB) removeTempDir() {
+	if d.tempDir == "" {
+		return
+	}
+	if err := os.RemoveAll(d.tempDir); err != nil {
+		d.logger.Error("failed to remove temporary directory",
+			"err", err,
+			"dir", d.tempDir,
+		)
+	}
+}
+
+type pendingNode struct {
+	hash hash.Hash
+	data []byte
+}
+
+type bboltBatch struct {
+	api.BaseBatch
+
+	db *bboltNodeDB
+
+	version   uint64
+	multipart bool
+
+	oldRoot node.Root
+	chunk   bool
+
+	nodes        []pendingNode
+	writeLog     writelog.WriteLog
+	annotations  writelog.Annotations
+	updatedNodes []updatedNode
+}
+
+func (ba *bboltBatch) MaybeStartSubtree(subtree api.Subtree, depth node.Depth, subtreeRoot *node.Pointer) api.Subtree {
+	if subtree == nil {
+		return &bboltSubtree{batch: ba}
+	}
+	return subtree
+}
+
+func (ba *bboltBatch) PutWriteLog(writeLog writelog.WriteLog, annotations writelog.Annotations) error {
+	if ba.chunk {
+		return fmt.Errorf("mkvs/bbolt: cannot put write log in chunk mode")
+	}
+	if ba.db.discardWriteLogs {
+		return nil
+	}
+
+	ba.writeLog = writeLog
+	ba.annotations = annotations
+	return nil
+}
+
+func (ba *bboltBatch) RemoveNodes(nodes []node.Node) error {
+	if ba.chunk && ba.oldRoot.Hash.IsEmpty() {
+		return fmt.Errorf("mkvs/bbolt: cannot remove nodes in chunk mode without an old root")
+	}
+
+	for _, n := range nodes {
+		ba.updatedNodes = append(ba.updatedNodes, updatedNode{
+			Removed: true,
+			Hash:    n.GetHash(),
+		})
+	}
+	return nil
+}
+
+func (ba *bboltBatch) Commit(root node.Root) error {
+	ba.db.metaUpdateLock.Lock()
+	defer ba.db.metaUpdateLock.Unlock()
+
+	if ba.db.multipartVersion != multipartVersionNone && ba.db.multipartVersion != root.Version {
+		return api.ErrInvalidMultipartVersion
+	}
+
+	if err := ba.db.sanityCheckNamespace(root.Namespace); err != nil {
+		return err
+	}
+	switch ba.chunk {
+	case true:
+		if !api.MultipartFollows(&root, &ba.oldRoot) {
+			return api.ErrRootMustFollowOld
+		}
+	case false:
+		if !root.Follows(&ba.oldRoot) {
+			return api.ErrRootMustFollowOld
+		}
+	}
+
+	// Make sure that the version that we try to commit into has not yet been finalized.
+	lastFinalizedVersion, exists := ba.db.meta.getLastFinalizedVersion()
+	if exists && lastFinalizedVersion >= root.Version {
+		return api.ErrAlreadyFinalized
+	}
+
+	err := ba.db.update(func(b *bolt.Bucket) error {
+		// Update the set of roots for this version.
+		rootsMeta, err := loadRootsMetadata(b, root.Version)
+		if err != nil {
+			return err
+		}
+
+		rootHash := api.TypedHashFromRoot(root)
+		if rootsMeta.Roots[rootHash] != nil {
+			// Root already exists, no need to do anything since if the hash matches, everything will
+			// be identical and we would just be duplicating work.
+			//
+			// If we are importing a chunk, there can be multiple commits for the same root.
+			if !ba.chunk {
+				return nil
+			}
+		} else {
+			// Create root with no derived roots.
+			rootsMeta.Roots[rootHash] = []api.TypedHash{}
+
+			if err = rootsMeta.save(b); err != nil {
+				return fmt.Errorf("mkvs/bbolt: failed to save roots metadata: %w", err)
+			}
+		}
+
+		// Only log roots that did not exist before, so that aborting the multipart insert does
+		// not remove a root that was already present (e.g., a derived root with the same hash).
+		if ba.multipart && !rootStore.exists(b, &rootHash, ba.version) {
+			if err = b.Put(multipartRestoreNodeLogKeyFmt.Encode(&rootHash), []byte{}); err != nil {
+				return err
+			}
+		}
+		if err = rootStore.put(b, &rootHash, ba.version, []byte{}); err != nil {
+			return err
+		}
+
+		// Store nodes. Since bbolt only rebalances the tree on commit, inserting keys in order
+		// is much faster than inserting them in random order.
+		sort.Slice(ba.nodes, func(i, j int) bool {
+			return bytes.Compare(ba.nodes[i].hash[:], ba.nodes[j].hash[:]) < 0
+		})
+		for i := range ba.nodes {
+			n := &ba.nodes[i]
+			if ba.multipart && !nodeStore.exists(b, &n.hash, ba.version) {
+				th := api.TypedHashFromParts(node.RootTypeInvalid, n.hash)
+				if err = b.Put(multipartRestoreNodeLogKeyFmt.Encode(&th), []byte{}); err != nil {
+					return err
+				}
+			}
+			if err = nodeStore.put(b, &n.hash, ba.version, n.data); err != nil {
+				return err
+			}
+		}
+
+		if ba.chunk && ba.oldRoot.Hash.IsEmpty() {
+			// Skip most of metadata updates if we are just importing chunks.
+			key := rootUpdatedNodesKeyFmt.Encode(root.Version, &rootHash)
+			if err = b.Put(key, cbor.Marshal([]updatedNode{})); err != nil {
+				return fmt.Errorf("mkvs/bbolt: set returned error: %w", err)
+			}
+			return nil
+		}
+
+		// Update the root link for the old root.
+		oldRootHash := api.TypedHashFromRoot(ba.oldRoot)
+		if !ba.oldRoot.Hash.IsEmpty() {
+			if ba.oldRoot.Version < ba.db.meta.getEarliestVersion() && ba.oldRoot.Version != root.Version {
+				return api.ErrPreviousVersionMismatch
+			}
+
+			var oldRootsMeta *rootsMetadata
+			oldRootsMeta, err = loadRootsMetadata(b, ba.oldRoot.Version)
+			if err != nil {
+				return err
+			}
+
+			derivedRoots, ok := oldRootsMeta.Roots[oldRootHash]
+			if !ok {
+				return api.ErrRootNotFound
+			}
+
+			if !api.ContainsTypedHash(derivedRoots, rootHash) {
+				oldRootsMeta.Roots[oldRootHash] = append(derivedRoots, rootHash)
+				if err = oldRootsMeta.save(b); err != nil {
+					return fmt.Errorf("mkvs/bbolt: failed to save old roots metadata: %w", err)
+				}
+			}
+		}
+
+		// Store updated nodes (only needed until the version is finalized).
+		key := rootUpdatedNodesKeyFmt.Encode(root.Version, &rootHash)
+		if err = b.Put(key, cbor.Marshal(ba.updatedNodes)); err != nil {
+			return fmt.Errorf("mkvs/bbolt: set returned error: %w", err)
+		}
+
+		// Store write log.
+		if ba.writeLog != nil && ba.annotations != nil {
+			log := api.MakeHashedDBWriteLog(ba.writeLog, ba.annotations)
+			key := writeLogKeyFmt.Encode(root.Version, &rootHash, &oldRootHash)
+			if err = b.Put(key, cbor.Marshal(log)); err != nil {
+				return fmt.Errorf("mkvs/bbolt: set new write log returned error: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ba.Reset()
+	return ba.BaseBatch.Commit(root)
+}
+
+func (ba *bboltBatch) Reset() {
+	ba.nodes = nil
+	ba.writeLog = nil
+	ba.annotations = nil
+	ba.updatedNodes = nil
+}
+
+type bboltSubtree struct {
+	batch *bboltBatch
+}
+
+func (s *bboltSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
+	data, err := ptr.Node.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	h := ptr.Node.GetHash()
+	s.batch.updatedNodes = append(s.batch.updatedNodes, updatedNode{Hash: h})
+	s.batch.nodes = append(s.batch.nodes, pendingNode{hash: h, data: data})
+	return nil
+}
+
+func (s *bboltSubtree) VisitCleanNode(depth node.Depth, ptr *node.Pointer) error {
+	return nil
+}
+
+func (s *bboltSubtree) Commit() error {
+	return nil
+}
@@ -0,0 +1,201 @@
+package bbolt
+
+import (
+	"bytes"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+)
+
+const (
+	// valueTombstone is the value prefix marking an entry as removed at the given version.
+	valueTombstone byte = 0
+	// valueLive is the value prefix marking an entry as present at the given version.
+	valueLive byte = 1
+)
+
+// versionedKeyFormat emulates multi-version storage for a set of keys on top of a plain ordered
+// key-value store.
+//
+// Each entry is stored under (id, version) and its value is prefixed by either valueLive or
+// valueTombstone. Reading an entry at a given version returns the most recent entry that was
+// written at or before that version. Whenever an entry shadows an older one, a garbage key of the
+// form (version, id) is recorded so that the shadowed entries can be removed once the version
+// becomes the earliest version.
+type versionedKeyFormat struct {
+	keyFmt        *keyformat.KeyFormat
+	garbageKeyFmt *keyformat.KeyFormat
+
+	newID func() interface{}
+}
+
+func (f *versionedKeyFormat) decodeVersion(key []byte) uint64 {
+	var version uint64
+	if !f.keyFmt.Decode(key, f.newID(), &version) {
+		panic("mkvs/bbolt: bad versioned key")
+	}
+	return version
+}
+
+// latest returns the raw key and value of the most recent entry for the given id written at or
+// before the given version. In case there is no such entry, nil is returned.
+func (f *versionedKeyFormat) latest(b *bolt.Bucket, id interface{}, version uint64) ([]byte, []byte) {
+	key := f.keyFmt.Encode(id, version)
+	c := b.Cursor()
+	k, v := c.Seek(key)
+	switch {
+	case k == nil:
+		k, v = c.Last()
+	case bytes.Equal(k, key):
+		return k, v
+	default:
+		k, v = c.Prev()
+	}
+	if k == nil || !bytes.HasPrefix(k, f.keyFmt.Encode(id)) {
+		return nil, nil
+	}
+	return k, v
+}
+
+// next returns the version of the earliest entry for the given id written after the given
+// version.
+func (f *versionedKeyFormat) next(b *bolt.Bucket, id interface{}, version uint64) (uint64, bool) {
+	if version == ^uint64(0) {
+		return 0, false
+	}
+	k, _ := b.Cursor().Seek(f.keyFmt.Encode(id, version+1))
+	if k == nil || !bytes.HasPrefix(k, f.keyFmt.Encode(id)) {
+		return 0, false
+	}
+	return f.decodeVersion(k), true
+}
+
+// get returns a copy of the value visible at the given version.
+func (f *versionedKeyFormat) get(b *bolt.Bucket, id interface{}, version uint64) ([]byte, bool) {
+	_, v := f.latest(b, id, version)
+	if len(v) == 0 || v[0] != valueLive {
+		return nil, false
+	}
+	return append([]byte{}, v[1:]...), true
+}
+
+// exists checks whether a live entry is visible at the given version.
+func (f *versionedKeyFormat) exists(b *bolt.Bucket, id interface{}, version uint64) bool {
+	_, v := f.latest(b, id, version)
+	return len(v) > 0 && v[0] == valueLive
+}
+
+// put stores the given value at the given version.
+func (f *versionedKeyFormat) put(b *bolt.Bucket, id interface{}, version uint64, value []byte) error {
+	return f.write(b, id, version, append([]byte{valueLive}, value...))
+}
+
+// delete removes the entry visible at the given version, starting at that version.
+func (f *versionedKeyFormat) delete(b *bolt.Bucket, id interface{}, version uint64) error {
+	k, v := f.latest(b, id, version)
+	if len(v) == 0 || v[0] != valueLive {
+		// Nothing is visible at the given version.
+		return nil
+	}
+
+	if f.decodeVersion(k) == version {
+		// In case there is nothing older that would become visible, the entry can be removed.
+		var olderLive bool
+		if version > 0 {
+			_, ov := f.latest(b, id, version-1)
+			olderLive = len(ov) > 0 && ov[0] == valueLive
+		}
+		if !olderLive {
+			return b.Delete(f.keyFmt.Encode(id, version))
+		}
+	}
+	return f.write(b, id, version, []byte{valueTombstone})
+}
+
+func (f *versionedKeyFormat) write(b *bolt.Bucket, id interface{}, version uint64, value []byte) error {
+	// Record any entries that are shadowed by (or shadow) this entry.
+	if version > 0 {
+		if k, _ := f.latest(b, id, version-1); k != nil {
+			if err := b.Put(f.garbageKeyFmt.Encode(version, id), []byte{}); err != nil {
+				return err
+			}
+		}
+	}
+	if nextVersion, ok := f.next(b, id, version); ok {
+		if err := b.Put(f.garbageKeyFmt.Encode(nextVersion, id), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return b.Put(f.keyFmt.Encode(id, version), value)
+}
+
+// collectGarbage removes all entries that can no longer be read as no reads are performed at
+// versions earlier than the given earliest version.
+func (f *versionedKeyFormat) collectGarbage(b *bolt.Bucket, earliestVersion uint64) error {
+	type garbageItem struct {
+		key []byte
+		id  interface{}
+	}
+
+	var items []garbageItem
+	c := b.Cursor()
+	prefix := f.garbageKeyFmt.Encode()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		var version uint64
+		id := f.newID()
+		if !f.garbageKeyFmt.Decode(k, &version, id) {
+			panic("mkvs/bbolt: bad garbage key")
+		}
+		if version > earliestVersion {
+			break
+		}
+		items = append(items, garbageItem{key: append([]byte{}, k...), id: id})
+	}
+
+	for _, item := range items {
+		// Find all entries that are visible at or before the earliest version. Only the most
+		// recent one can still be read and even that one is not needed if it is a tombstone.
+		var (
+			keys          [][]byte
+			lastTombstone bool
+		)
+		idPrefix := f.keyFmt.Encode(item.id)
+		for k, v := c.Seek(idPrefix); k != nil && bytes.HasPrefix(k, idPrefix); k, v = c.Next() {
+			if f.decodeVersion(k) > earliestVersion {
+				break
+			}
+			keys = append(keys, append([]byte{}, k...))
+			lastTombstone = len(v) == 0 || v[0] != valueLive
+		}
+		if len(keys) > 0 && !lastTombstone {
+			keys = keys[:len(keys)-1]
+		}
+
+		for _, key := range keys {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := b.Delete(item.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePrefix removes all keys with the given prefix.
+func deletePrefix(b *bolt.Bucket, prefix []byte) error {
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+	for _, key := range keys {
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package bbolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+func (d *bboltNodeDB) Stats(ctx context.Context) (*api.Stats, error) {
+	versions := make(map[uint64]*api.VersionStats)
+	getVersionStats := func(version uint64) *api.VersionStats {
+		vs := versions[version]
+		if vs == nil {
+			vs = &api.VersionStats{Version: version}
+			versions[version] = vs
+		}
+		return vs
+	}
+
+	var stats api.Stats
+	err := d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+
+		// Node entries are ordered by (hash, version). Entries that are shadowed by a newer entry
+		// at or before the earliest version can no longer be read and will be removed when
+		// pruning.
+		earliestVersion := d.meta.getEarliestVersion()
+		var (
+			lastHash    hash.Hash
+			lastVersion uint64
+			lastLive    bool
+		)
+		c := b.Cursor()
+		prefix := nodeKeyFmt.Encode()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var (
+				h       hash.Hash
+				version uint64
+			)
+			if !nodeKeyFmt.Decode(k, &h, &version) {
+				return fmt.Errorf("mkvs/bbolt: undecodable node key (%v)", k)
+			}
+
+			switch {
+			case !lastLive:
+			case !h.Equal(&lastHash), version > earliestVersion:
+				getVersionStats(lastVersion).Nodes++
+			default:
+				stats.OrphanedNodes++
+			}
+			lastHash = h
+			lastVersion = version
+			lastLive = len(v) > 0 && v[0] == valueLive
+		}
+		if lastLive {
+			getVersionStats(lastVersion).Nodes++
+		}
+
+		// Collect write log sizes.
+		prefix = writeLogKeyFmt.Encode()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var (
+				version          uint64
+				srcRoot, dstRoot api.TypedHash
+			)
+			if !writeLogKeyFmt.Decode(k, &version, &dstRoot, &srcRoot) {
+				return fmt.Errorf("mkvs/bbolt: undecodable write log key (%v)", k)
+			}
+
+			vs := getVersionStats(version)
+			vs.WriteLogs++
+			vs.WriteLogSize += uint64(len(v))
+		}
+
+		bs := b.Stats()
+		stats.DiskSize = map[string]int64{
+			"in_use": int64(bs.BranchInuse + bs.LeafInuse + bs.InlineBucketInuse),
+			"file":   tx.Size(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vs := range versions {
+		stats.Versions = append(stats.Versions, *vs)
+	}
+	sort.Slice(stats.Versions, func(i, j int) bool {
+		return stats.Versions[i].Version < stats.Versions[j].Version
+	})
+
+	return &stats, nil
+}
+
+// Compact is a no-op as bbolt reuses pages freed during pruning and cannot shrink the database
+// file while it is open.
+func (d *bboltNodeDB) Compact(ctx context.Context) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const absenceProofsName = "absence_proofs"
+
+var (
+	absenceProofsFixture = absenceProofs{}
+
+	// AbsenceProofsPresentKeys are the keys inserted into the tree by the absence proofs fixture.
+	AbsenceProofsPresentKeys = [][]byte{
+		[]byte("foo"),
+		[]byte("foobar"),
+		[]byte("foobaz"),
+		[]byte("moo"),
+		[]byte("moo\x00"),
+		[]byte("zap"),
+	}
+
+	// AbsenceProofsAbsentKeys are the keys that do not exist in the tree populated by the absence
+	// proofs fixture. They cover the different ways in which a lookup for a key can terminate.
+	AbsenceProofsAbsentKeys = [][]byte{
+		// Empty key, ending at the root internal node which has no leaf.
+		{},
+		// Key shorter than the label of an internal node.
+		[]byte("fo"),
+		// Key ending at an internal node without a leaf.
+		[]byte("fooba"),
+		// Key reaching a leaf node with a different key.
+		[]byte("foobax"),
+		[]byte("moo\x01"),
+		// Key longer than all existing keys on its path.
+		[]byte("foobarbaz"),
+		// Keys diverging from all existing keys.
+		[]byte("b"),
+		[]byte("zzz"),
+	}
+)
+
+type absenceProofs struct{}
+
+func (a *absenceProofs) Name() string {
+	return absenceProofsName
+}
+
+func (a *absenceProofs) Populate(ctx context.Context, ndb db.NodeDB) (*node.Root, error) {
+	var err error
+	testRoot := storage.Root{
+		Type:    storage.RootTypeState,
+		Version: 1,
+	}
+
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	defer tree.Close()
+
+	for _, key := range AbsenceProofsPresentKeys {
+		if err = tree.Insert(ctx, key, []byte(fmt.Sprintf("value of %s", key))); err != nil {
+			return nil, fmt.Errorf("absence-proofs: failed to insert key: %w", err)
+		}
+	}
+	_, testRoot.Hash, err = tree.Commit(ctx, common.Namespace{}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("absence-proofs: failed to commit tree: %w", err)
+	}
+	if err = ndb.Finalize(ctx, []node.Root{testRoot}); err != nil {
+		return nil, fmt.Errorf("absence-proofs: failed to finalize test root: %w", err)
+	}
+
+	return &testRoot, nil
+}
+
+func init() {
+	Register(&absenceProofsFixture)
+}
@@ -0,0 +1,78 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+func TestAbsenceProofs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.interop.absence_proofs")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           dir,
+		NoFsync:      true,
+		Namespace:    common.Namespace{},
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	fixture, err := GetFixture(absenceProofsName)
+	require.NoError(err, "GetFixture")
+	root, err := fixture.Populate(ctx, ndb)
+	require.NoError(err, "Populate")
+
+	tree := mkvs.NewWithRoot(nil, ndb, *root)
+	defer tree.Close()
+
+	var pv syncer.ProofVerifier
+	for _, key := range AbsenceProofsAbsentKeys {
+		proof, err := tree.GetProofOfAbsence(ctx, key)
+		require.NoError(err, "GetProofOfAbsence(%X)", key)
+
+		err = pv.VerifyProofOfAbsence(ctx, root.Hash, key, proof)
+		require.NoError(err, "VerifyProofOfAbsence(%X)", key)
+
+		// The proof should not be usable for any of the existing keys.
+		for _, presentKey := range AbsenceProofsPresentKeys {
+			err = pv.VerifyProofOfAbsence(ctx, root.Hash, presentKey, proof)
+			require.Error(err, "VerifyProofOfAbsence(%X) should fail with a proof for %X", presentKey, key)
+		}
+	}
+	for _, key := range AbsenceProofsPresentKeys {
+		_, err = tree.GetProofOfAbsence(ctx, key)
+		require.ErrorIs(err, mkvs.ErrKeyExists, "GetProofOfAbsence(%X) should fail for an existing key", key)
+	}
+
+	// Proofs should be stable.
+	proof, err := tree.GetProofOfAbsence(ctx, []byte("foobax"))
+	require.NoError(err, "GetProofOfAbsence")
+	require.EqualValues(
+		"omdlbnRyaWVziUYBAQMAYAJGAQEBAAACWB0BARQAZvbwAAMAZm9vDAAAAHZhbHVlIG9mIGZvb0gBARQAYmFwAlghAmwp/bw+jUKGRpS4j9tzMvAd+0WFIBzONyZyQjRKgj2jWB0BAAYAZm9vYmF6DwAAAHZhbHVlIG9mIGZvb2JhevZYIQIc8EBx2/CZXRtrOR76ts5uiBbKv01hKla2NWuKr/93glghAoAd4YRYW3XPvN1kCU4ngOzCN3EqxnZYbSc6BRkMO4ukbnVudHJ1c3RlZF9yb290WCBPzM5lwbci/8l+Ttrj0F2Lm2elvUN2m8PrxdSe5sQ71g==",
+		base64.StdEncoding.EncodeToString(cbor.Marshal(proof)),
+	)
+	require.EqualValues("4fccce65c1b722ffc97e4edae3d05d8b9b67a5bd43769bc3ebc5d49ee6c43bd6", root.Hash.String())
+
+	// A proof that does not include the lookup path should fail.
+	proof, err = tree.GetProofOfAbsence(ctx, []byte("b"))
+	require.NoError(err, "GetProofOfAbsence")
+	err = pv.VerifyProofOfAbsence(ctx, root.Hash, []byte("zzz"), proof)
+	require.ErrorIs(err, syncer.ErrIncompleteProof, "VerifyProofOfAbsence should fail with an incomplete proof")
+}
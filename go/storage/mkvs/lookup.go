@@ -66,6 +66,33 @@ func (t *tree) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer
 	}, nil
 }
 
+// Implements Tree.
+func (t *tree) GetProofOfAbsence(ctx context.Context, key []byte) (*syncer.Proof, error) {
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	if t.cache.isClosed() {
+		return nil, ErrClosed
+	}
+	if !t.cache.pendingRoot.IsClean() {
+		return nil, syncer.ErrDirtyRoot
+	}
+
+	// Remember where the path from root to target node ends (will end).
+	t.cache.markPosition()
+
+	// Always anchor the proof at the root so that it can be verified independently.
+	pb := syncer.NewProofBuilder(t.cache.syncRoot.Hash, t.cache.syncRoot.Hash)
+	value, err := t.doGet(ctx, t.cache.pendingRoot, 0, key, doGetOptions{proofBuilder: pb}, false)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		return nil, ErrKeyExists
+	}
+	return pb.Build(ctx)
+}
+
 func (t *tree) newFetcherSyncGet(key node.Key, includeSiblings bool) readSyncFetcher {
 	return func(ctx context.Context, ptr *node.Pointer, rs syncer.ReadSyncer) (*syncer.Proof, error) {
 		rsp, err := rs.SyncGet(ctx, &syncer.GetRequest{
@@ -20,6 +20,9 @@ var (
 	// ErrKnownRootMismatch is the error returned by CommitKnown when the known
 	// root mismatches.
 	ErrKnownRootMismatch = errors.New("mkvs: known root mismatch")
+
+	// ErrKeyExists is the error returned by GetProofOfAbsence when the key exists.
+	ErrKeyExists = errors.New("mkvs: key exists")
 )
 
 // ImmutableKeyValueTree is the immutable key-value store tree interface.
@@ -75,6 +78,16 @@ type Tree interface {
 	// starting with given prefixes.
 	PrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit uint16) error
 
+	// GetProofOfAbsence returns a proof that the given key does not exist in the tree. The proof
+	// can be verified using syncer.ProofVerifier.VerifyProofOfAbsence.
+	//
+	// In case the key exists, ErrKeyExists is returned.
+	GetProofOfAbsence(ctx context.Context, key []byte) (*syncer.Proof, error)
+
+	// GetRange fetches all entries in the given key range (up to the given limit) together with
+	// a proof that can be verified using syncer.ProofVerifier.VerifyRange.
+	GetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.GetRangeResponse, error)
+
 	// ApplyWriteLog applies the operations from a write log to the current tree.
 	//
 	// The caller is responsible for calling Commit.
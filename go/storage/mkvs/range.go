@@ -0,0 +1,54 @@
+package mkvs
+
+import (
+	"context"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// Implements Tree.
+func (t *tree) GetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.GetRangeResponse, error) {
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	if t.cache.isClosed() {
+		return nil, ErrClosed
+	}
+	if !request.Root.Equal(&t.cache.syncRoot) {
+		return nil, syncer.ErrInvalidRoot
+	}
+	if !t.cache.pendingRoot.IsClean() {
+		return nil, syncer.ErrDirtyRoot
+	}
+
+	// Always anchor the proof at the root as the range may encompass many subtrees.
+	it := t.NewIterator(ctx,
+		WithProof(request.Root.Hash),
+		IteratorPrefetch(request.Limit),
+	)
+	defer it.Close()
+
+	var rsp syncer.GetRangeResponse
+	for it.Seek(request.Start); it.Valid(); it.Next() {
+		if request.End != nil && it.Key().Compare(request.End) >= 0 {
+			break
+		}
+		rsp.Entries = append(rsp.Entries, syncer.RangeEntry{
+			Key:   it.Key(),
+			Value: it.Value(),
+		})
+		if request.Limit > 0 && len(rsp.Entries) >= int(request.Limit) {
+			break
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	proof, err := it.GetProof()
+	if err != nil {
+		return nil, err
+	}
+	rsp.Proof = *proof
+	return &rsp, nil
+}
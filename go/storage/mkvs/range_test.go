@@ -0,0 +1,115 @@
+package mkvs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+func TestGetRange(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Include keys that are prefixes of other keys.
+	keys := [][]byte{[]byte("a"), []byte("ab"), []byte("abc"), []byte("b"), []byte("ba"), []byte("\x00"), []byte("\xff")}
+	generatedKeys, _ := generateKeyValuePairsEx("range", 100)
+	keys = append(keys, generatedKeys...)
+	values := make(map[string][]byte)
+	for i, key := range keys {
+		values[string(key)] = []byte(fmt.Sprintf("value %d", i))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	var ns common.Namespace
+	tree := New(nil, nil, node.RootTypeState)
+	defer tree.Close()
+	for _, key := range keys {
+		err := tree.Insert(ctx, key, values[string(key)])
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+	root := node.Root{Namespace: ns, Version: 0, Type: node.RootTypeState, Hash: rootHash}
+
+	expectedRange := func(start, end []byte, limit uint16) []syncer.RangeEntry {
+		var entries []syncer.RangeEntry
+		for _, key := range keys {
+			if bytes.Compare(key, start) < 0 || (end != nil && bytes.Compare(key, end) >= 0) {
+				continue
+			}
+			entries = append(entries, syncer.RangeEntry{Key: key, Value: values[string(key)]})
+			if limit > 0 && len(entries) >= int(limit) {
+				break
+			}
+		}
+		return entries
+	}
+
+	var pv syncer.ProofVerifier
+	boundaries := [][]byte{nil, []byte("a"), []byte("aa"), []byte("ab"), []byte("abcd"), []byte("b"), []byte("\xff\xff")}
+	rng := rand.New(rand.NewSource(42)) // nolint: gosec
+	for i := 0; i < 20; i++ {
+		boundaries = append(boundaries, keys[rng.Intn(len(keys))])
+		boundaries = append(boundaries, []byte(fmt.Sprintf("%s%d", keys[rng.Intn(len(keys))], i)))
+	}
+	for _, start := range boundaries {
+		for _, end := range boundaries {
+			if end != nil && bytes.Compare(start, end) > 0 {
+				continue
+			}
+			for _, limit := range []uint16{0, 1, 5} {
+				req := &syncer.GetRangeRequest{Root: root, Start: start, End: end, Limit: limit}
+				rsp, err := tree.GetRange(ctx, req)
+				require.NoError(err, "GetRange(%X, %X, %d)", start, end, limit)
+				require.EqualValues(expectedRange(start, end, limit), rsp.Entries, "GetRange(%X, %X, %d) should return the correct entries", start, end, limit)
+
+				err = pv.VerifyRange(ctx, rootHash, req, rsp)
+				require.NoError(err, "VerifyRange(%X, %X, %d)", start, end, limit)
+			}
+		}
+	}
+
+	req := &syncer.GetRangeRequest{Root: root, Start: []byte("a"), End: []byte("b"), Limit: 2}
+	rsp, err := tree.GetRange(ctx, req)
+	require.NoError(err, "GetRange")
+	require.Len(rsp.Entries, 2, "GetRange should respect the limit")
+
+	// Omitting entries should fail verification.
+	tampered := *rsp
+	tampered.Entries = rsp.Entries[:1]
+	err = pv.VerifyRange(ctx, rootHash, req, &tampered)
+	require.Error(err, "VerifyRange should fail with omitted entries")
+
+	// Modified values should fail verification.
+	tampered.Entries = []syncer.RangeEntry{rsp.Entries[0], {Key: rsp.Entries[1].Key, Value: []byte("bogus")}}
+	err = pv.VerifyRange(ctx, rootHash, req, &tampered)
+	require.Error(err, "VerifyRange should fail with modified values")
+
+	// Using the proof for a larger range should fail verification.
+	err = pv.VerifyRange(ctx, rootHash, &syncer.GetRangeRequest{Root: root, Start: []byte("a"), End: []byte("b")}, rsp)
+	require.Error(err, "VerifyRange should fail when the proof does not cover the range")
+	err = pv.VerifyRange(ctx, rootHash, &syncer.GetRangeRequest{Root: root}, rsp)
+	require.ErrorIs(err, syncer.ErrIncompleteProof, "VerifyRange should fail when the proof does not cover the range")
+
+	// Using a different root should fail verification.
+	err = pv.VerifyRange(ctx, hash.NewFromBytes([]byte("i am a bogus hash")), req, rsp)
+	require.Error(err, "VerifyRange should fail with a different root")
+
+	// Requesting a range for a different root should fail.
+	otherRoot := root
+	otherRoot.Version = 1
+	_, err = tree.GetRange(ctx, &syncer.GetRangeRequest{Root: otherRoot})
+	require.ErrorIs(err, syncer.ErrInvalidRoot, "GetRange should fail for a different root")
+}
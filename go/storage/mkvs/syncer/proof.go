@@ -9,6 +9,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
+// ErrKeyExists is the error returned when verifying a proof of absence for a key that exists.
+var ErrKeyExists = errors.New("verifier: key exists")
+
 const (
 	// proofEntryFull is the proof entry type for full nodes.
 	proofEntryFull byte = 0x01
@@ -256,3 +259,61 @@ func (pv *ProofVerifier) verifyProof(ctx context.Context, proof *Proof, idx int)
 		return -1, nil, fmt.Errorf("verifier: unexpected entry in proof (%x)", entry[0])
 	}
 }
+
+// VerifyProofOfAbsence verifies that the given key does not exist in the tree with the given
+// root. The proof must include all nodes on the lookup path of the key.
+func (pv *ProofVerifier) VerifyProofOfAbsence(ctx context.Context, root hash.Hash, key []byte, proof *Proof) error {
+	rootPtr, err := pv.VerifyProof(ctx, root, proof)
+	if err != nil {
+		return err
+	}
+
+	exists, err := lookupVerified(ctx, rootPtr, 0, key)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrKeyExists
+	}
+	return nil
+}
+
+// lookupVerified checks whether the given key exists in a verified subtree, following the same
+// lookup path as the tree itself.
+func lookupVerified(ctx context.Context, ptr *node.Pointer, bitDepth node.Depth, key node.Key) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if ptr == nil {
+		return false, nil
+	}
+	if ptr.Node == nil {
+		if ptr.Hash.IsEmpty() {
+			return false, nil
+		}
+		return false, ErrIncompleteProof
+	}
+
+	switch n := ptr.Node.(type) {
+	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+
+		// Does lookup key end here? Look into LeafNode.
+		if key.BitLength() == bitLength {
+			return lookupVerified(ctx, n.LeafNode, bitLength, key)
+		}
+		// Lookup key is too short for the current label. It's not stored.
+		if key.BitLength() < bitLength {
+			return false, nil
+		}
+		// Continue recursively based on a bit value.
+		if key.GetBit(bitLength) {
+			return lookupVerified(ctx, n.Right, bitLength, key)
+		}
+		return lookupVerified(ctx, n.Left, bitLength, key)
+	case *node.LeafNode:
+		return n.Key.Equal(key), nil
+	default:
+		return false, fmt.Errorf("verifier: unexpected node type %T", n)
+	}
+}
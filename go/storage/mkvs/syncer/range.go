@@ -0,0 +1,177 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// ErrIncompleteProof is the error returned when a range proof does not include all of the nodes
+// needed to determine the entries in the requested range.
+var ErrIncompleteProof = errors.New("verifier: incomplete range proof")
+
+// GetRangeRequest is a request for the GetRange operation.
+type GetRangeRequest struct {
+	// Root is the root of the tree to query.
+	Root node.Root `json:"root"`
+	// Start is the first key of the range (inclusive).
+	Start []byte `json:"start,omitempty"`
+	// End is the last key of the range (exclusive). If not set, the range extends to the end of
+	// the tree.
+	End []byte `json:"end,omitempty"`
+	// Limit is the maximum number of entries to return. If zero, all entries in the range are
+	// returned.
+	Limit uint16 `json:"limit,omitempty"`
+}
+
+// RangeEntry is a key/value pair returned by the GetRange operation.
+type RangeEntry struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// GetRangeResponse is a response for the GetRange operation.
+type GetRangeResponse struct {
+	// Entries are the entries in the requested range, ordered by key.
+	Entries []RangeEntry `json:"entries"`
+	// Proof is the proof that the entries are exactly the entries in the requested range.
+	Proof Proof `json:"proof"`
+}
+
+// VerifyRange verifies a GetRange response against the given root hash.
+//
+// Verification succeeds only if the proof is valid and the response entries are exactly the
+// entries in the requested range (up to the requested limit).
+func (pv *ProofVerifier) VerifyRange(
+	ctx context.Context,
+	root hash.Hash,
+	request *GetRangeRequest,
+	response *GetRangeResponse,
+) error {
+	if request.End != nil && bytes.Compare(request.Start, request.End) > 0 {
+		return fmt.Errorf("verifier: invalid range")
+	}
+
+	rootPtr, err := pv.VerifyProof(ctx, root, &response.Proof)
+	if err != nil {
+		return err
+	}
+
+	rv := rangeVerifier{
+		ctx:     ctx,
+		request: request,
+	}
+	if err = rv.walk(rootPtr, 0, node.Key{}, 0); err != nil {
+		return err
+	}
+
+	if len(rv.entries) != len(response.Entries) {
+		return fmt.Errorf("verifier: bad number of range entries (expected: %d got: %d)",
+			len(rv.entries),
+			len(response.Entries),
+		)
+	}
+	for i, entry := range rv.entries {
+		if !bytes.Equal(entry.Key, response.Entries[i].Key) || !bytes.Equal(entry.Value, response.Entries[i].Value) {
+			return fmt.Errorf("verifier: bad range entry for key %X", entry.Key)
+		}
+	}
+	return nil
+}
+
+type rangeVerifier struct {
+	ctx     context.Context
+	request *GetRangeRequest
+	entries []RangeEntry
+}
+
+func (rv *rangeVerifier) done() bool {
+	return rv.request.Limit > 0 && len(rv.entries) >= int(rv.request.Limit)
+}
+
+// overlaps checks whether the subtree with the given key prefix may contain any keys in the
+// requested range.
+func (rv *rangeVerifier) overlaps(path node.Key, prefixLength node.Depth) bool {
+	// All keys in the subtree are smaller than the start key.
+	if comparePrefix(rv.request.Start, path, prefixLength) > 0 {
+		return false
+	}
+	if rv.request.End != nil {
+		switch comparePrefix(rv.request.End, path, prefixLength) {
+		case -1:
+			// All keys in the subtree are larger than the end key.
+			return false
+		case 0:
+			// The end key is a prefix of all keys in the subtree.
+			if node.Key(rv.request.End).BitLength() <= prefixLength {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// walk traverses the subtree in key order, collecting entries in the requested range.
+//
+// The path is the key prefix of the subtree and prefixLength is its length in bits. It may be
+// longer than bitDepth which is the depth at which the label of the subtree root starts.
+func (rv *rangeVerifier) walk(ptr *node.Pointer, bitDepth node.Depth, path node.Key, prefixLength node.Depth) error {
+	if rv.ctx.Err() != nil {
+		return rv.ctx.Err()
+	}
+	if ptr == nil || rv.done() || !rv.overlaps(path, prefixLength) {
+		return nil
+	}
+	if ptr.Node == nil {
+		if ptr.Hash.IsEmpty() {
+			return nil
+		}
+		return ErrIncompleteProof
+	}
+
+	switch n := ptr.Node.(type) {
+	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+		newPath := path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+		if err := rv.walk(n.LeafNode, bitLength, newPath, bitLength); err != nil {
+			return err
+		}
+		if err := rv.walk(n.Left, bitLength, newPath.AppendBit(bitLength, false), bitLength+1); err != nil {
+			return err
+		}
+		return rv.walk(n.Right, bitLength, newPath.AppendBit(bitLength, true), bitLength+1)
+	case *node.LeafNode:
+		if n.Key.Compare(rv.request.Start) < 0 {
+			return nil
+		}
+		if rv.request.End != nil && n.Key.Compare(rv.request.End) >= 0 {
+			return nil
+		}
+		rv.entries = append(rv.entries, RangeEntry{Key: n.Key, Value: n.Value})
+		return nil
+	default:
+		return fmt.Errorf("verifier: unexpected node type %T", n)
+	}
+}
+
+// comparePrefix compares the first bitLength bits of the given key, padded with zero bits if
+// needed, with the first bitLength bits of the given prefix.
+func comparePrefix(key, prefix node.Key, bitLength node.Depth) int {
+	for bit := node.Depth(0); bit < bitLength; bit++ {
+		keyBit := bit < key.BitLength() && key.GetBit(bit)
+		prefixBit := prefix.GetBit(bit)
+		switch {
+		case keyBit == prefixBit:
+		case keyBit:
+			return 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
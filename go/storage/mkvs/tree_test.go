@@ -18,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	bboltDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/bbolt"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 	mkvsTests "github.com/oasisprotocol/oasis-core/go/storage/mkvs/tests"
@@ -1414,6 +1415,84 @@ func testPruneManyVersions(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	}
 }
 
+func testPruneMultipartDerived(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+
+	// Create a root that is derived from a root of an earlier (non-adjacent) version as part of
+	// a multipart insert, as done when catching up via a compacted write log. Make sure that
+	// pruning the earlier versions keeps the shared nodes and removes the replaced ones.
+	const numPairs = 50
+	insertPairs := func(tree Tree, version int) {
+		for p := 0; p < numPairs; p++ {
+			key := []byte(fmt.Sprintf("key %d/%d", version, p))
+			value := []byte(fmt.Sprintf("value %d/%d", version, p))
+			err := tree.Insert(ctx, key, value)
+			require.NoError(t, err, "Insert")
+		}
+	}
+
+	tree := New(nil, ndb, node.RootTypeState)
+	var root node.Root
+	for v := 0; v < 2; v++ {
+		insertPairs(tree, v)
+		_, rootHash, err := tree.Commit(ctx, testNs, uint64(v))
+		require.NoError(t, err, "Commit")
+		root = node.Root{Namespace: testNs, Version: uint64(v), Type: node.RootTypeState, Hash: rootHash}
+		err = ndb.Finalize(ctx, []node.Root{root})
+		require.NoError(t, err, "Finalize")
+	}
+	tree.Close()
+
+	const multipartVersion = 5
+	err := ndb.StartMultipartInsert(multipartVersion)
+	require.NoError(t, err, "StartMultipartInsert")
+	tree = NewWithRoot(nil, ndb, root)
+	insertPairs(tree, multipartVersion)
+	err = tree.Insert(ctx, []byte("key 1/0"), []byte("replaced"))
+	require.NoError(t, err, "Insert")
+	_, rootHash, err := tree.Commit(ctx, testNs, multipartVersion, Multipart())
+	require.NoError(t, err, "Commit")
+	tree.Close()
+	root = node.Root{Namespace: testNs, Version: multipartVersion, Type: node.RootTypeState, Hash: rootHash}
+	err = ndb.Finalize(ctx, []node.Root{root})
+	require.NoError(t, err, "Finalize")
+	err = ndb.AbortMultipartInsert()
+	require.NoError(t, err, "AbortMultipartInsert")
+
+	// Prune all versions before the multipart version.
+	for v := 0; v < multipartVersion; v++ {
+		err = ndb.Prune(ctx, uint64(v))
+		require.NoError(t, err, "Prune")
+	}
+
+	// Reopen database to force compaction.
+	ndb.Close()
+	ndb, err = factory(testNs)
+	require.NoError(t, err, "ndb.New")
+	defer ndb.Close()
+
+	tree = NewWithRoot(nil, ndb, root)
+	defer tree.Close()
+	for _, v := range []int{0, 1, multipartVersion} {
+		for p := 0; p < numPairs; p++ {
+			key := []byte(fmt.Sprintf("key %d/%d", v, p))
+			expected := []byte(fmt.Sprintf("value %d/%d", v, p))
+			if v == 1 && p == 0 {
+				expected = []byte("replaced")
+			}
+			value, err := tree.Get(ctx, key)
+			require.NoError(t, err, "Get")
+			require.EqualValues(t, expected, value, "value for key %s should be retained", key)
+		}
+	}
+
+	// The replaced leaf node should have been removed.
+	leaf := &node.LeafNode{Key: []byte("key 1/0"), Value: []byte("value 1/0")}
+	leaf.UpdateHash()
+	_, err = ndb.GetNode(root, &node.Pointer{Clean: true, Hash: leaf.Hash})
+	require.ErrorIs(t, err, db.ErrNodeNotFound, "replaced node should be removed")
+}
+
 func testPruneForkedRoots(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	ctx := context.Background()
 
@@ -2239,6 +2318,7 @@ func testBackend(
 		{"PruneLoneRootsShared3", testPruneLoneRootsShared3},
 		{"PruneLoneRootsShared4", testPruneLoneRootsShared4},
 		{"PruneForkedRoots", testPruneForkedRoots},
+		{"PruneMultipartDerived", testPruneMultipartDerived},
 		{"SpecialCase1", testSpecialCase1},
 		{"SpecialCase2", testSpecialCase2},
 		{"SpecialCase3", testSpecialCase3},
@@ -2293,6 +2373,29 @@ func TestBadgerBackend(t *testing.T) {
 	}, nil)
 }
 
+func TestBBoltBackend(t *testing.T) {
+	testBackend(t, func(t *testing.T) (NodeDBFactory, func()) {
+		// Create a new random temporary directory under /tmp.
+		dir, err := ioutil.TempDir("", "mkvs.test.bbolt")
+		require.NoError(t, err, "TempDir")
+
+		// Create a bbolt-backed Node DB factory.
+		factory := func(ns common.Namespace) (db.NodeDB, error) {
+			return bboltDb.New(&db.Config{
+				DB:        dir,
+				NoFsync:   true,
+				Namespace: ns,
+			})
+		}
+
+		cleanup := func() {
+			os.RemoveAll(dir)
+		}
+
+		return factory, cleanup
+	}, nil)
+}
+
 func BenchmarkInsertCommitBatch1(b *testing.B) {
 	benchmarkInsertBatch(b, 1, true)
 }
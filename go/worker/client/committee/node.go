@@ -12,17 +12,28 @@ import (
 	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/indexer"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 )
 
+// txStatusPollInterval is the interval at which the status of watched transactions is refreshed
+// in case no new blocks have been indexed in the meantime.
+const txStatusPollInterval = 1 * time.Second
+
+// backfillChunkSize is the maximum number of rounds indexed from history before processing any new
+// blocks.
+const backfillChunkSize = 100
+
 type pendingTx struct {
 	txHash hash.Hash
 	ch     chan *api.SubmitTxResult
@@ -31,6 +42,7 @@ type pendingTx struct {
 // Node is a client node.
 type Node struct {
 	commonNode *committee.Node
+	indexer    indexer.Indexer
 
 	stopCh   chan struct{}
 	stopOnce sync.Once
@@ -40,6 +52,8 @@ type Node struct {
 	checkCh *channels.InfiniteChannel
 	txCh    *channels.InfiniteChannel
 
+	indexedNotifier *pubsub.Broker
+
 	logger *logging.Logger
 }
 
@@ -66,6 +80,9 @@ func (n *Node) Quit() <-chan struct{} {
 
 // Cleanup performs the service specific post-termination cleanup.
 func (n *Node) Cleanup() {
+	if n.indexer != nil {
+		n.indexer.Close()
+	}
 }
 
 // Initialized returns a channel that will be closed when the node is
@@ -74,6 +91,11 @@ func (n *Node) Initialized() <-chan struct{} {
 	return n.initCh
 }
 
+// Indexer returns the runtime event indexer or nil in case indexing is disabled.
+func (n *Node) Indexer() indexer.Indexer {
+	return n.indexer
+}
+
 func (n *Node) HandlePeerTx(ctx context.Context, tx []byte) error {
 	// Nothing to do here.
 	return nil
@@ -101,6 +123,11 @@ func (n *Node) HandleRuntimeHostEvent(*host.Event) {
 }
 
 func (n *Node) SubmitTx(ctx context.Context, tx []byte) (<-chan *api.SubmitTxResult, *protocol.Error, error) {
+	// Transactions cannot be checked without a hosted runtime.
+	if !n.commonNode.Runtime.HasHost() {
+		return nil, nil, api.ErrNoHostedRuntime
+	}
+
 	// Make sure consensus is synced.
 	select {
 	case <-n.commonNode.Consensus.Synced():
@@ -129,6 +156,9 @@ func (n *Node) SubmitTx(ctx context.Context, tx []byte) (<-chan *api.SubmitTxRes
 }
 
 func (n *Node) CheckTx(ctx context.Context, tx []byte) (*protocol.CheckTxResult, error) {
+	if !n.commonNode.Runtime.HasHost() {
+		return nil, api.ErrNoHostedRuntime
+	}
 	return n.commonNode.TxPool.SubmitTx(ctx, tx, &txpool.TransactionMeta{Local: true, Discard: true})
 }
 
@@ -166,16 +196,217 @@ func (n *Node) Query(ctx context.Context, round uint64, method string, args []by
 	return hrt.Query(ctx, annBlk.Block, lb, epoch, maxMessages, method, args)
 }
 
-func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[hash.Hash]*pendingTx) error {
-	if blk.Header.IORoot.IsEmpty() {
-		return nil
+// GetTransactionStatus returns the current status of the given transaction.
+func (n *Node) GetTransactionStatus(ctx context.Context, txHash hash.Hash) (*api.TransactionStatus, error) {
+	// First check whether the transaction has already been included in a block.
+	loc, err := n.commonNode.Runtime.History().GetTransactionLocation(ctx, txHash)
+	switch err {
+	case nil:
+		return &api.TransactionStatus{
+			Kind:       api.TransactionStatusIncluded,
+			Round:      loc.Round,
+			BatchOrder: loc.BatchOrder,
+		}, nil
+	case roothash.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("client: failed to query transaction location: %w", err)
 	}
 
-	// If there's no pending transactions, we can skip the check.
-	if len(pending) == 0 {
-		return nil
+	// Then consult the transaction pool.
+	status, checkErr := n.commonNode.TxPool.GetTxStatus(txHash)
+	switch status {
+	case txpool.TxStatusPendingCheck:
+		return &api.TransactionStatus{Kind: api.TransactionStatusPendingCheck}, nil
+	case txpool.TxStatusPendingSchedule:
+		return &api.TransactionStatus{Kind: api.TransactionStatusInPool}, nil
+	case txpool.TxStatusScheduled:
+		return &api.TransactionStatus{Kind: api.TransactionStatusScheduled}, nil
+	case txpool.TxStatusRejected:
+		return &api.TransactionStatus{
+			Kind:         api.TransactionStatusRejected,
+			CheckTxError: checkErr,
+		}, nil
+	default:
+		return &api.TransactionStatus{Kind: api.TransactionStatusUnknown}, nil
+	}
+}
+
+// WatchTransaction subscribes to status changes of the given transaction.
+func (n *Node) WatchTransaction(ctx context.Context, txHash hash.Hash) (<-chan *api.TransactionStatus, pubsub.ClosableSubscription, error) {
+	// Subscribe before querying the initial status to avoid missing any updates.
+	indexedSub := n.indexedNotifier.Subscribe()
+	indexedCh := indexedSub.Untyped()
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *api.TransactionStatus)
+	go func() {
+		defer close(ch)
+		defer indexedSub.Close()
+
+		ticker := time.NewTicker(txStatusPollInterval)
+		defer ticker.Stop()
+
+		var last *api.TransactionStatus
+		for {
+			status, err := n.GetTransactionStatus(ctx, txHash)
+			if err != nil {
+				n.logger.Error("failed to get transaction status",
+					"err", err,
+					"tx_hash", txHash,
+				)
+				return
+			}
+
+			if last == nil || status.Kind != last.Kind {
+				select {
+				case ch <- status:
+				case <-ctx.Done():
+					return
+				}
+				last = status
+			}
+			if status.IsFinal() {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-indexedCh:
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+// indexBlock fetches the I/O of the given block from storage and indexes all transactions included
+// and all tags emitted in the block so that they can later be looked up. It returns the
+// transactions included in the block.
+func (n *Node) indexBlock(ctx context.Context, blk *block.Block) ([]*transaction.Transaction, error) {
+	var (
+		txs  []*transaction.Transaction
+		tags transaction.Tags
+	)
+	if !blk.Header.IORoot.IsEmpty() {
+		ioRoot := storage.Root{
+			Namespace: blk.Header.Namespace,
+			Version:   blk.Header.Round,
+			Type:      storage.RootTypeIO,
+			Hash:      blk.Header.IORoot,
+		}
+
+		tree := transaction.NewTree(n.commonNode.Runtime.Storage(), ioRoot)
+		defer tree.Close()
+
+		var err error
+		if txs, err = tree.GetTransactions(ctx); err != nil {
+			return nil, fmt.Errorf("error getting block I/O from storage: %w", err)
+		}
+		if tags, err = tree.GetTags(ctx); err != nil {
+			return nil, fmt.Errorf("error getting block tags from storage: %w", err)
+		}
+	}
+
+	indexed := make([]history.IndexedTransaction, 0, len(txs))
+	for _, tx := range txs {
+		indexed = append(indexed, history.IndexedTransaction{
+			TxHash:     tx.Hash(),
+			BatchOrder: tx.BatchOrder,
+		})
+	}
+
+	err := n.commonNode.Runtime.History().CommitTransactions(blk.Header.Round, indexed)
+	switch err {
+	case nil:
+	case roothash.ErrNotFound:
+		// Round has already been pruned from history, nothing to index.
+		n.logger.Warn("not indexing transactions of a pruned round",
+			"round", blk.Header.Round,
+		)
+		return txs, nil
+	default:
+		return nil, fmt.Errorf("error indexing transactions: %w", err)
+	}
+
+	if err = n.indexer.Index(blk.Header.Round, tags); err != nil {
+		return nil, fmt.Errorf("error indexing events: %w", err)
+	}
+
+	n.indexedNotifier.Broadcast(blk.Header.Round)
+
+	return txs, nil
+}
+
+// startBackfill returns the first round that is present in runtime history but has not yet been
+// indexed, e.g., because the indexer was added to an existing node or because the node was offline
+// while history caught up. In case there is nothing to backfill, false is returned.
+func (n *Node) startBackfill(ctx context.Context) (uint64, bool, error) {
+	rtHistory := n.commonNode.Runtime.History()
+	earliestBlk, err := rtHistory.GetEarliestBlock(ctx)
+	switch err {
+	case nil:
+	case roothash.ErrNotFound:
+		// Nothing in history yet.
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf("failed to get earliest block: %w", err)
 	}
 
+	fromRound := earliestBlk.Header.Round
+	lastIndexedRound, err := n.indexer.LastIndexedRound(ctx)
+	switch err {
+	case nil:
+		if lastIndexedRound >= fromRound {
+			fromRound = lastIndexedRound + 1
+		}
+	case roothash.ErrNotFound:
+	default:
+		return 0, false, fmt.Errorf("failed to get last indexed round: %w", err)
+	}
+	return fromRound, true, nil
+}
+
+// backfillIndex indexes at most backfillChunkSize rounds from runtime history, starting at the
+// given round. It returns the next round to index and whether there are any more rounds in history
+// that need to be indexed.
+func (n *Node) backfillIndex(ctx context.Context, fromRound uint64) (uint64, bool, error) {
+	rtHistory := n.commonNode.Runtime.History()
+	latestBlk, err := rtHistory.GetBlock(ctx, roothash.RoundLatest)
+	if err != nil {
+		return fromRound, true, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	latestRound := latestBlk.Header.Round
+	if fromRound > latestRound {
+		return fromRound, false, nil
+	}
+	toRound := latestRound
+	if toRound-fromRound >= backfillChunkSize {
+		toRound = fromRound + backfillChunkSize - 1
+	}
+	for round := fromRound; round <= toRound; round++ {
+		blk, err := rtHistory.GetBlock(ctx, round)
+		switch err {
+		case nil:
+		case roothash.ErrNotFound:
+			// History may have gaps (e.g., when it has been pruned in the meantime).
+			continue
+		default:
+			return round, true, fmt.Errorf("failed to get block for round %d: %w", round, err)
+		}
+
+		if _, err = n.indexBlock(ctx, blk); err != nil {
+			return round, true, fmt.Errorf("failed to index round %d: %w", round, err)
+		}
+	}
+
+	return toRound + 1, toRound < latestRound, nil
+}
+
+// getPendingTransactions fetches the pending transactions included in the given block.
+func (n *Node) getPendingTransactions(ctx context.Context, blk *block.Block, pending map[hash.Hash]*pendingTx) ([]*transaction.Transaction, error) {
 	ioRoot := storage.Root{
 		Namespace: blk.Header.Namespace,
 		Version:   blk.Header.Round,
@@ -186,20 +417,56 @@ func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[has
 	tree := transaction.NewTree(n.commonNode.Runtime.Storage(), ioRoot)
 	defer tree.Close()
 
-	// Check if there's anything interesting in this block.
-	var txHashes []hash.Hash
+	txHashes := make([]hash.Hash, 0, len(pending))
 	for txHash := range pending {
 		txHashes = append(txHashes, txHash)
 	}
 
 	matches, err := tree.GetTransactionMultiple(ctx, txHashes)
 	if err != nil {
-		return fmt.Errorf("error getting block I/O from storage: %w", err)
+		return nil, fmt.Errorf("error getting block I/O from storage: %w", err)
+	}
+
+	txs := make([]*transaction.Transaction, 0, len(matches))
+	for _, tx := range matches {
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// checkBlock notifies any submitters of pending transactions included in the given block and, in
+// case index is true, indexes the block.
+func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[hash.Hash]*pendingTx, index bool) error {
+	var (
+		txs []*transaction.Transaction
+		err error
+	)
+	switch {
+	case index:
+		// Index all transactions and tags so that they can be queried.
+		txs, err = n.indexBlock(ctx, blk)
+	case len(pending) > 0 && !blk.Header.IORoot.IsEmpty():
+		// The block is not indexed here so only look up pending transactions.
+		txs, err = n.getPendingTransactions(ctx, blk, pending)
+	default:
+		// If there's no pending transactions and the block is not indexed, we can skip the check.
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	var processed []hash.Hash
-	for txHash, tx := range matches {
-		pTx := pending[txHash]
+	// Check if there's anything interesting in this block.
+	processed := make([]hash.Hash, 0, len(txs))
+	for _, tx := range txs {
+		txHash := tx.Hash()
+		processed = append(processed, txHash)
+
+		pTx, ok := pending[txHash]
+		if !ok {
+			continue
+		}
+
 		pTx.ch <- &api.SubmitTxResult{
 			Result: &api.SubmitTxMetaResponse{
 				Round:      blk.Header.Round,
@@ -209,7 +476,6 @@ func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[has
 		}
 		close(pTx.ch)
 		delete(pending, txHash)
-		processed = append(processed, txHash)
 	}
 
 	// Remove processed transactions from pool.
@@ -240,6 +506,32 @@ func (n *Node) worker() {
 	// We are initialized.
 	close(n.initCh)
 
+	// Index any rounds that are in history but have not been indexed yet. Backfilling is done in
+	// bounded chunks between processing new blocks so that it does not delay notifying submitters
+	// of included transactions. While backfilling, new blocks are not indexed directly as they are
+	// already in history and will be indexed in order by the backfill so that the last indexed
+	// round never skips over unindexed rounds.
+	var (
+		backfilling    bool
+		backfillFailed bool
+		backfillRound  uint64
+	)
+	if n.indexer != nil {
+		var err error
+		if backfillRound, backfilling, err = n.startBackfill(ctx); err != nil {
+			n.logger.Error("failed to start backfilling index from history",
+				"err", err,
+			)
+		}
+		if backfilling {
+			n.logger.Info("backfilling index from history",
+				"from_round", backfillRound,
+			)
+		}
+	}
+	backfillReadyCh := make(chan struct{})
+	close(backfillReadyCh)
+
 	var (
 		recheckTicker *backoff.Ticker
 		blocks        []*block.Block
@@ -250,6 +542,10 @@ func (n *Node) worker() {
 		if recheckTicker != nil {
 			recheckCh = recheckTicker.C
 		}
+		var backfillCh <-chan struct{}
+		if backfilling && !backfillFailed {
+			backfillCh = backfillReadyCh
+		}
 
 		select {
 		case <-n.stopCh:
@@ -261,13 +557,26 @@ func (n *Node) worker() {
 			continue
 		case blk := <-n.checkCh.Out():
 			blocks = append(blocks, blk.(*block.Block))
+		case <-backfillCh:
+			var err error
+			if backfillRound, backfilling, err = n.backfillIndex(ctx, backfillRound); err != nil {
+				n.logger.Error("failed to backfill index from history",
+					"err", err,
+					"round", backfillRound,
+				)
+				backfillFailed = true
+			}
+			if !backfilling {
+				n.logger.Info("finished backfilling index from history")
+			}
 		case <-recheckCh:
+			backfillFailed = false
 		}
 
 		// Check blocks.
 		var failedBlocks []*block.Block
 		for _, blk := range blocks {
-			if err := n.checkBlock(ctx, blk, pending); err != nil {
+			if err := n.checkBlock(ctx, blk, pending, n.indexer != nil && !backfilling); err != nil {
 				n.logger.Error("error checking block",
 					"err", err,
 					"round", blk.Header.Round,
@@ -279,7 +588,8 @@ func (n *Node) worker() {
 			n.logger.Warn("failed roothash blocks",
 				"num_failed_blocks", len(failedBlocks),
 			)
-
+		}
+		if len(failedBlocks) > 0 || backfillFailed {
 			// Start recheck ticker.
 			if recheckTicker == nil {
 				boff := cmnBackoff.NewExponentialBackOff()
@@ -295,15 +605,21 @@ func (n *Node) worker() {
 }
 
 // NewNode creates a new client node.
-func NewNode(commonNode *committee.Node) (*Node, error) {
+//
+// In case the indexer is nil, indexing of runtime transactions and events is disabled.
+func NewNode(commonNode *committee.Node, idx indexer.Indexer) (*Node, error) {
 	n := &Node{
 		commonNode: commonNode,
+		indexer:    idx,
 		stopCh:     make(chan struct{}),
 		quitCh:     make(chan struct{}),
 		initCh:     make(chan struct{}),
 		checkCh:    channels.NewInfiniteChannel(),
 		txCh:       channels.NewInfiniteChannel(),
-		logger:     logging.GetLogger("worker/client/committee").With("runtime_id", commonNode.Runtime.ID()),
+
+		indexedNotifier: pubsub.NewBroker(false),
+
+		logger: logging.GetLogger("worker/client/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 	return n, nil
 }
@@ -0,0 +1,39 @@
+package committee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
+)
+
+// unhostedRuntime is a runtime which is not hosted by the current node (e.g., in storage replica
+// mode).
+type unhostedRuntime struct {
+	runtimeRegistry.Runtime
+}
+
+func (r *unhostedRuntime) HasHost() bool {
+	return false
+}
+
+func TestNoHostedRuntime(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	n := &Node{
+		commonNode: &committee.Node{
+			Runtime: &unhostedRuntime{},
+		},
+	}
+
+	_, _, err := n.SubmitTx(ctx, []byte("tx"))
+	require.ErrorIs(err, api.ErrNoHostedRuntime, "SubmitTx should fail without a hosted runtime")
+
+	_, err = n.CheckTx(ctx, []byte("tx"))
+	require.ErrorIs(err, api.ErrNoHostedRuntime, "CheckTx should fail without a hosted runtime")
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// CfgIndexerEnabled enables indexing of all transactions and events included in runtime blocks.
+//
+// When disabled, block I/O is only fetched in case there are pending locally submitted
+// transactions, events cannot be queried and transactions can only be looked up by hash while they
+// are still in the transaction pool.
+const CfgIndexerEnabled = "worker.client.indexer.enabled"
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+func init() {
+	Flags.Bool(CfgIndexerEnabled, true, "Enable indexing of runtime transactions and events")
+
+	_ = viper.BindPFlags(Flags)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -13,8 +14,11 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/indexer"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 type service struct {
@@ -102,6 +106,26 @@ func (s *service) CheckTx(ctx context.Context, request *api.CheckTxRequest) erro
 	return nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) GetTransactionStatus(ctx context.Context, request *api.GetTransactionStatusRequest) (*api.TransactionStatus, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+
+	return rt.GetTransactionStatus(ctx, request.TxHash)
+}
+
+// Implements api.RuntimeClient.
+func (s *service) WatchTransaction(ctx context.Context, request *api.GetTransactionStatusRequest) (<-chan *api.TransactionStatus, pubsub.ClosableSubscription, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, nil, api.ErrNoHostedRuntime
+	}
+
+	return rt.WatchTransaction(ctx, request.TxHash)
+}
+
 // Implements api.RuntimeClient.
 func (s *service) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	return s.w.commonWorker.Consensus.RootHash().WatchBlocks(ctx, runtimeID)
@@ -263,11 +287,134 @@ func (s *service) GetEvents(ctx context.Context, request *api.GetEventsRequest)
 			Key:    tag.Key,
 			Value:  tag.Value,
 			TxHash: tag.TxHash,
+			Round:  blk.Header.Round,
 		})
 	}
 	return events, nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) GetTransactionByHash(ctx context.Context, request *api.GetTransactionByHashRequest) (*api.GetTransactionByHashResponse, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+
+	runtime, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := runtime.History().GetTransactionLocation(ctx, request.TxHash)
+	switch err {
+	case nil:
+	case roothash.ErrNotFound:
+		return nil, api.ErrNotFound
+	default:
+		return nil, err
+	}
+
+	blk, err := s.GetBlock(ctx, &api.GetBlockRequest{RuntimeID: request.RuntimeID, Round: loc.Round})
+	if err != nil {
+		return nil, err
+	}
+
+	tree := s.getTxnTree(runtime.Storage(), blk)
+	defer tree.Close()
+
+	tx, err := tree.GetTransaction(ctx, request.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := tree.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var events []*api.PlainEvent
+	for _, tag := range tags {
+		if !tag.TxHash.Equal(&request.TxHash) {
+			continue
+		}
+		events = append(events, &api.PlainEvent{
+			Key:   tag.Key,
+			Value: tag.Value,
+		})
+	}
+
+	return &api.GetTransactionByHashResponse{
+		Round:      loc.Round,
+		BatchOrder: loc.BatchOrder,
+		Transaction: &api.TransactionWithResults{
+			Tx:     tx.Input,
+			Result: tx.Output,
+			Events: events,
+		},
+	}, nil
+}
+
+// Implements api.RuntimeClient.
+func (s *service) QueryEvents(ctx context.Context, request *api.QueryEventsRequest) ([]*api.Event, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+
+	if rt.Indexer() == nil {
+		return nil, api.ErrIndexerDisabled
+	}
+
+	limit := request.Limit
+	switch {
+	case limit == 0:
+		limit = api.DefaultQueryEventsLimit
+	case limit > api.MaxQueryEventsLimit:
+		return nil, errors.WithContext(api.ErrInvalidArgument, fmt.Sprintf("limit must not exceed %d", api.MaxQueryEventsLimit))
+	}
+
+	indexed, err := rt.Indexer().QueryEvents(ctx, &indexer.EventQuery{
+		Key:      request.Key,
+		Value:    request.Value,
+		RoundMin: request.RoundMin,
+		RoundMax: request.RoundMax,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*api.Event, 0, len(indexed))
+	for _, ev := range indexed {
+		events = append(events, &api.Event{
+			Key:    ev.Key,
+			Value:  ev.Value,
+			TxHash: ev.TxHash,
+			Round:  ev.Round,
+		})
+	}
+	return events, nil
+}
+
+// Implements api.RuntimeClient.
+func (s *service) GetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.GetRangeResponse, error) {
+	if request.Limit == 0 || request.Limit > api.MaxGetRangeLimit {
+		return nil, errors.WithContext(api.ErrInvalidArgument, fmt.Sprintf("limit must be between 1 and %d", api.MaxGetRangeLimit))
+	}
+	if request.End != nil && bytes.Compare(request.Start, request.End) > 0 {
+		return nil, errors.WithContext(api.ErrInvalidArgument, "start key must not be greater than end key")
+	}
+
+	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := mkvs.NewWithRoot(rt.Storage(), nil, request.Root)
+	defer tree.Close()
+
+	return tree.GetRange(ctx, request)
+}
+
 // Implements api.RuntimeClient.
 func (s *service) Query(ctx context.Context, request *api.QueryRequest) (*api.QueryResponse, error) {
 	rt := s.w.runtimes[request.RuntimeID]
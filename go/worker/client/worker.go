@@ -2,10 +2,15 @@
 package client
 
 import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/indexer"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	"github.com/oasisprotocol/oasis-core/go/worker/client/committee"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
@@ -124,9 +129,25 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 		"runtime_id", id,
 	)
 
+	// Create the event indexer (if enabled) and make sure it is pruned together with history.
+	var idx indexer.Indexer
+	if viper.GetBool(CfgIndexerEnabled) {
+		path, err := runtimeRegistry.EnsureRuntimeStateDir(w.commonWorker.DataDir, id)
+		if err != nil {
+			return err
+		}
+		if idx, err = indexer.New(path, id); err != nil {
+			return fmt.Errorf("failed to create indexer for runtime %s: %w", id, err)
+		}
+		commonNode.Runtime.History().Pruner().RegisterHandler(idx)
+	}
+
 	// Create committee node for the given runtime.
-	node, err := committee.NewNode(commonNode)
+	node, err := committee.NewNode(commonNode, idx)
 	if err != nil {
+		if idx != nil {
+			idx.Close()
+		}
 		return err
 	}
 
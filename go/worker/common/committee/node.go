@@ -10,6 +10,7 @@ import (
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
@@ -172,6 +173,7 @@ func (n *Node) Quit() <-chan struct{} {
 
 // Cleanup performs the service specific post-termination cleanup.
 func (n *Node) Cleanup() {
+	n.TxPool.Cleanup()
 }
 
 // Initialized returns a channel that will be closed when the node is
@@ -272,7 +274,7 @@ func (n *Node) handleSuspendLocked(height int64) {
 }
 
 func (n *Node) updateHostedRuntimeVersionLocked() {
-	if n.CurrentDescriptor == nil {
+	if n.CurrentDescriptor == nil || !n.Runtime.HasHost() {
 		return
 	}
 
@@ -507,43 +509,47 @@ func (n *Node) worker() {
 	}
 	defer eventsSub.Close()
 
-	// Provision the hosted runtime.
-	hrt, hrtNotifier, err := n.ProvisionHostedRuntime(n.ctx)
-	if err != nil {
-		n.logger.Error("failed to provision hosted runtime",
-			"err", err,
-		)
-		return
-	}
+	// Provision the hosted runtime if this node hosts runtimes.
+	var hrtEventCh <-chan *host.Event
+	if n.Runtime.HasHost() {
+		hrt, hrtNotifier, err := n.ProvisionHostedRuntime(n.ctx)
+		if err != nil {
+			n.logger.Error("failed to provision hosted runtime",
+				"err", err,
+			)
+			return
+		}
 
-	hrtEventCh, hrtSub, err := hrt.WatchEvents(n.ctx)
-	if err != nil {
-		n.logger.Error("failed to subscribe to hosted runtime events",
-			"err", err,
-		)
-		return
-	}
-	defer hrtSub.Close()
+		var hrtSub pubsub.ClosableSubscription
+		hrtEventCh, hrtSub, err = hrt.WatchEvents(n.ctx)
+		if err != nil {
+			n.logger.Error("failed to subscribe to hosted runtime events",
+				"err", err,
+			)
+			return
+		}
+		defer hrtSub.Close()
 
-	if err = hrt.Start(); err != nil {
-		n.logger.Error("failed to start hosted runtime",
-			"err", err,
-		)
-		return
-	}
-	defer hrt.Stop()
+		if err = hrt.Start(); err != nil {
+			n.logger.Error("failed to start hosted runtime",
+				"err", err,
+			)
+			return
+		}
+		defer hrt.Stop()
 
-	if err = hrtNotifier.Start(); err != nil {
-		n.logger.Error("failed to start runtime notifier",
-			"err", err,
-		)
-		return
-	}
-	defer hrtNotifier.Stop()
+		if err = hrtNotifier.Start(); err != nil {
+			n.logger.Error("failed to start runtime notifier",
+				"err", err,
+			)
+			return
+		}
+		defer hrtNotifier.Stop()
 
-	// Perform initial hosted runtime version update to ensure we have something even in cases where
-	// initial block processing fails for any reason.
-	n.updateHostedRuntimeVersionLocked()
+		// Perform initial hosted runtime version update to ensure we have something even in cases
+		// where initial block processing fails for any reason.
+		n.updateHostedRuntimeVersionLocked()
+	}
 
 	initialized := false
 	for {
@@ -609,6 +615,7 @@ func NewNode(
 	keymanager keymanager.Backend,
 	consensus consensus.Backend,
 	p2pHost *p2p.P2P,
+	dataDir string,
 	txPoolCfg *txpool.Config,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
@@ -648,7 +655,7 @@ func NewNode(
 	n.RuntimeHostNode = rhn
 
 	// Prepare transaction pool.
-	txPool, err := txpool.New(runtime.ID(), txPoolCfg, n, n)
+	txPool, err := txpool.New(runtime.ID(), dataDir, txPoolCfg, n, n)
 	if err != nil {
 		return nil, fmt.Errorf("error creating transaction pool: %w", err)
 	}
@@ -24,11 +24,14 @@ var (
 	// connect to.
 	CfgSentryAddresses = "worker.sentry.address"
 
-	cfgMaxTxPoolSize       = "worker.tx_pool.schedule_max_tx_pool_size"
-	cfgScheduleTxCacheSize = "worker.tx_pool.schedule_tx_cache_size"
-	cfgStaleTxCacheSize    = "worker.tx_pool.stale_tx_cache_size"
-	cfgCheckTxMaxBatchSize = "worker.tx_pool.check_tx_max_batch_size"
-	cfgRecheckInterval     = "worker.tx_pool.recheck_interval"
+	cfgMaxTxPoolSize          = "worker.tx_pool.schedule_max_tx_pool_size"
+	cfgMaxTxPoolSizePerSender = "worker.tx_pool.schedule_max_tx_pool_size_per_sender"
+	cfgScheduleTxCacheSize    = "worker.tx_pool.schedule_tx_cache_size"
+	cfgStaleTxCacheSize       = "worker.tx_pool.stale_tx_cache_size"
+	cfgCheckTxMaxBatchSize    = "worker.tx_pool.check_tx_max_batch_size"
+	cfgRecheckInterval        = "worker.tx_pool.recheck_interval"
+	cfgMaxJournalSize         = "worker.tx_pool.journal.max_size"
+	cfgJournalExpiry          = "worker.tx_pool.journal.expiry"
 
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
@@ -94,6 +97,7 @@ func NewConfig() (*Config, error) {
 		SentryAddresses: sentryAddresses,
 		TxPool: txpool.Config{
 			MaxPoolSize:          viper.GetUint64(cfgMaxTxPoolSize),
+			MaxPoolSizePerSender: viper.GetUint64(cfgMaxTxPoolSizePerSender),
 			MaxCheckTxBatchSize:  viper.GetUint64(cfgCheckTxMaxBatchSize),
 			MaxLastSeenCacheSize: viper.GetUint64(cfgScheduleTxCacheSize),
 			MaxStaleCacheSize:    viper.GetUint64(cfgStaleTxCacheSize),
@@ -102,6 +106,9 @@ func NewConfig() (*Config, error) {
 			RepublishInterval: 60 * time.Second,
 
 			RecheckInterval: viper.GetUint64(cfgRecheckInterval),
+
+			MaxJournalSize: viper.GetUint64(cfgMaxJournalSize),
+			JournalExpiry:  viper.GetDuration(cfgJournalExpiry),
 		},
 		logger: logging.GetLogger("worker/config"),
 	}
@@ -115,10 +122,13 @@ func init() {
 	Flags.StringSlice(CfgSentryAddresses, []string{}, "Address(es) of sentry node(s) to connect to of the form [PubKey@]ip:port (where PubKey@ part represents base64 encoded node TLS public key)")
 
 	Flags.Uint64(cfgMaxTxPoolSize, 10_000, "Maximum size of the scheduling transaction pool")
+	Flags.Uint64(cfgMaxTxPoolSizePerSender, 100, "Maximum number of pending transactions from the same sender (0 = unlimited)")
 	Flags.Uint64(cfgScheduleTxCacheSize, 10_000, "Maximum cache size of recently scheduled transactions to prevent re-scheduling")
 	Flags.Uint64(cfgStaleTxCacheSize, 64, "Maximum cache size of recently cleared transactions")
 	Flags.Uint64(cfgCheckTxMaxBatchSize, 10_000, "Maximum check tx batch size")
 	Flags.Uint64(cfgRecheckInterval, 32, "Transaction recheck interval (in rounds)")
+	Flags.Uint64(cfgMaxJournalSize, 0, "Maximum number of locally submitted transactions to persist across restarts (0 = disabled)")
+	Flags.Duration(cfgJournalExpiry, 1*time.Hour, "Time after which persisted locally submitted transactions are discarded (0 = never)")
 
 	_ = viper.BindPFlags(Flags)
 }
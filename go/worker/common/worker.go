@@ -168,6 +168,11 @@ func (w *Worker) registerRuntime(runtime runtimeRegistry.Runtime) error {
 		"runtime_id", id,
 	)
 
+	dataDir, err := runtimeRegistry.EnsureRuntimeStateDir(w.DataDir, id)
+	if err != nil {
+		return err
+	}
+
 	node, err := committee.NewNode(
 		w.HostNode,
 		runtime,
@@ -175,6 +180,7 @@ func (w *Worker) registerRuntime(runtime runtimeRegistry.Runtime) error {
 		w.KeyManager,
 		w.Consensus,
 		w.P2P,
+		dataDir,
 		&w.cfg.TxPool,
 	)
 	if err != nil {
@@ -17,6 +17,9 @@ var (
 	// ErrCantPauseCheckpointer is the error returned when trying to pause the checkpointer without
 	// setting the debug flag.
 	ErrCantPauseCheckpointer = errors.New(ModuleName, 2, "worker/storage: pausing checkpointer only available in debug mode")
+	// ErrCheckpointerDisabled is the error returned when trying to control the checkpointer while
+	// it is disabled.
+	ErrCheckpointerDisabled = errors.New(ModuleName, 3, "worker/storage: checkpointer is disabled")
 )
 
 // StorageWorker is the storage worker control API interface.
@@ -31,6 +34,13 @@ type StorageWorker interface {
 
 	// PauseCheckpointer pauses or unpauses the storage worker's checkpointer.
 	PauseCheckpointer(ctx context.Context, request *PauseCheckpointerRequest) error
+
+	// GetStats returns statistics about the storage worker's local node database.
+	GetStats(ctx context.Context, request *GetStatsRequest) (*storage.NodeDBStats, error)
+
+	// Compact compacts the storage worker's local node database, reclaiming space used by pruned
+	// data. The checkpointer is paused while compaction is in progress.
+	Compact(ctx context.Context, request *CompactRequest) error
 }
 
 // GetLastSyncedRoundRequest is a GetLastSyncedRound request.
@@ -63,6 +73,16 @@ type PauseCheckpointerRequest struct {
 	Pause     bool             `json:"pause"`
 }
 
+// GetStatsRequest is a GetStats request.
+type GetStatsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
+
+// CompactRequest is a Compact request.
+type CompactRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
+
 // Status is the storage worker status.
 type Status struct {
 	// LastFinalizedRound is the last synced and finalized round.
@@ -6,6 +6,7 @@ import (
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 )
 
 var (
@@ -18,6 +19,10 @@ var (
 	methodWaitForRound = serviceName.NewMethod("WaitForRound", &WaitForRoundRequest{})
 	// methodPauseCheckpointer is the PauseCheckpointer method.
 	methodPauseCheckpointer = serviceName.NewMethod("PauseCheckpointer", &PauseCheckpointerRequest{})
+	// methodGetStats is the GetStats method.
+	methodGetStats = serviceName.NewMethod("GetStats", &GetStatsRequest{})
+	// methodCompact is the Compact method.
+	methodCompact = serviceName.NewMethod("Compact", &CompactRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -36,6 +41,14 @@ var (
 				MethodName: methodPauseCheckpointer.ShortName(),
 				Handler:    handlerPauseCheckpointer,
 			},
+			{
+				MethodName: methodGetStats.ShortName(),
+				Handler:    handlerGetStats,
+			},
+			{
+				MethodName: methodCompact.ShortName(),
+				Handler:    handlerCompact,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -110,6 +123,52 @@ func handlerPauseCheckpointer( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerGetStats( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(GetStatsRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageWorker).GetStats(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetStats.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageWorker).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerCompact( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(CompactRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(StorageWorker).Compact(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCompact.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(StorageWorker).Compact(ctx, req.(*CompactRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 // RegisterService registers a new storage worker service with the given gRPC server.
 func RegisterService(server *grpc.Server, service StorageWorker) {
 	server.RegisterService(&serviceDesc, service)
@@ -139,6 +198,18 @@ func (c *storageWorkerClient) PauseCheckpointer(ctx context.Context, req *PauseC
 	return c.conn.Invoke(ctx, methodPauseCheckpointer.FullName(), req, nil)
 }
 
+func (c *storageWorkerClient) GetStats(ctx context.Context, req *GetStatsRequest) (*storage.NodeDBStats, error) {
+	var rsp storage.NodeDBStats
+	if err := c.conn.Invoke(ctx, methodGetStats.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *storageWorkerClient) Compact(ctx context.Context, req *CompactRequest) error {
+	return c.conn.Invoke(ctx, methodCompact.FullName(), req, nil)
+}
+
 // NewStorageWorkerClient creates a new gRPC transaction scheduler
 // client service.
 func NewStorageWorkerClient(c *grpc.ClientConn) StorageWorker {
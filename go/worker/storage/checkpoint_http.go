@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+// checkpointHTTPShutdownTimeout is the timeout for gracefully shutting down the checkpoint HTTP
+// server.
+const checkpointHTTPShutdownTimeout = 5 * time.Second
+
+// checkpointHTTPServer serves local checkpoints of all runtimes over HTTP(S).
+//
+// Checkpoints of each runtime are served under the runtime identifier (e.g., /<runtime-id>/checkpoints
+// and /<runtime-id>/chunks/<digest>) so that the endpoint can be used as a checkpoint mirror.
+type checkpointHTTPServer struct {
+	address  string
+	certFile string
+	keyFile  string
+
+	mux      *http.ServeMux
+	listener net.Listener
+	server   *http.Server
+
+	logger *logging.Logger
+}
+
+func (s *checkpointHTTPServer) registerRuntime(id common.Namespace, provider checkpoint.ChunkProvider) {
+	prefix := "/" + id.String()
+	s.mux.Handle(prefix+"/", http.StripPrefix(prefix, checkpoint.NewHTTPHandler(provider)))
+}
+
+func (s *checkpointHTTPServer) start() error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("checkpoint HTTP endpoint is enabled",
+		"address", listener.Addr(),
+		"tls", s.certFile != "",
+	)
+
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		var err error
+		switch s.certFile {
+		case "":
+			err = s.server.Serve(s.listener)
+		default:
+			err = s.server.ServeTLS(s.listener, s.certFile, s.keyFile)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			s.logger.Error("checkpoint HTTP server terminated uncleanly",
+				"err", err,
+			)
+		}
+	}()
+
+	return nil
+}
+
+func (s *checkpointHTTPServer) stop() {
+	if s.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointHTTPShutdownTimeout)
+	defer cancel()
+	_ = s.server.Shutdown(ctx)
+	s.server = nil
+}
+
+func newCheckpointHTTPServer(address, certFile, keyFile string) *checkpointHTTPServer {
+	return &checkpointHTTPServer{
+		address:  address,
+		certFile: certFile,
+		keyFile:  keyFile,
+		mux:      http.NewServeMux(),
+		logger:   logging.GetLogger("worker/storage/checkpoint_http"),
+	}
+}
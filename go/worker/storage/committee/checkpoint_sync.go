@@ -6,12 +6,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/rpc"
 	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
 )
 
@@ -21,6 +25,16 @@ const (
 	// cpRestoreTimeout is the timeout for restoring a checkpoint chunk from a node.
 	cpRestoreTimeout = 60 * time.Second
 
+	// cpMirrorMaxFailures is the number of consecutive failures after which a checkpoint mirror
+	// is temporarily no longer used.
+	cpMirrorMaxFailures = 3
+	// cpMirrorRetryInterval is the initial interval after which a checkpoint mirror that has been
+	// failing is tried again. The interval doubles with each subsequent failure.
+	cpMirrorRetryInterval = 10 * time.Second
+	// cpMirrorMaxRetryInterval is the maximum interval after which a failing checkpoint mirror is
+	// tried again.
+	cpMirrorMaxRetryInterval = 5 * time.Minute
+
 	checkpointStatusDone = 0
 	checkpointStatusNext = 1
 	checkpointStatusBail = 2
@@ -40,6 +54,10 @@ type CheckpointSyncConfig struct {
 
 	// ChunkFetcherCount specifies the number of parallel checkpoint chunk fetchers.
 	ChunkFetcherCount uint
+
+	// HTTPMirrors is a list of base URLs of HTTP checkpoint mirrors that are used as an
+	// additional source of checkpoints. The runtime identifier is appended to each URL.
+	HTTPMirrors []string
 }
 
 // Validate performs configuration checks.
@@ -47,9 +65,83 @@ func (cfg *CheckpointSyncConfig) Validate() error {
 	if !cfg.Disabled && cfg.ChunkFetcherCount == 0 {
 		return fmt.Errorf("number of checkpoint chunk fetchers must be greater than zero")
 	}
+	for _, mirror := range cfg.HTTPMirrors {
+		u, err := url.Parse(mirror)
+		if err != nil {
+			return fmt.Errorf("malformed checkpoint mirror URL '%s': %w", mirror, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("unsupported checkpoint mirror URL scheme: '%s'", mirror)
+		}
+	}
 	return nil
 }
 
+// checkpointMirror is an HTTP mirror serving checkpoints of a single runtime.
+type checkpointMirror struct {
+	sync.Mutex
+
+	url      string
+	provider checkpoint.ChunkProvider
+
+	failures  uint32
+	retryTime time.Time
+}
+
+func newCheckpointMirror(baseURL string, runtimeID common.Namespace) *checkpointMirror {
+	mirrorURL := strings.TrimSuffix(baseURL, "/") + "/" + runtimeID.String()
+	return &checkpointMirror{
+		url:      mirrorURL,
+		provider: checkpoint.NewHTTPClient(mirrorURL, nil),
+	}
+}
+
+func (m *checkpointMirror) usable() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.failures < cpMirrorMaxFailures || !time.Now().Before(m.retryTime)
+}
+
+func (m *checkpointMirror) recordFailures(n uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.failures += n
+	if m.failures < cpMirrorMaxFailures {
+		return
+	}
+
+	// Back off exponentially, so that a mirror which is down is not hammered with requests
+	// while still being retried eventually.
+	retryInterval := cpMirrorMaxRetryInterval
+	if shift := m.failures - cpMirrorMaxFailures; shift < 16 {
+		if d := cpMirrorRetryInterval << shift; d < retryInterval {
+			retryInterval = d
+		}
+	}
+	m.retryTime = time.Now().Add(retryInterval)
+}
+
+// RecordSuccess implements rpc.PeerFeedback.
+func (m *checkpointMirror) RecordSuccess() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.failures = 0
+	m.retryTime = time.Time{}
+}
+
+// RecordFailure implements rpc.PeerFeedback.
+func (m *checkpointMirror) RecordFailure() {
+	m.recordFailures(1)
+}
+
+// RecordBadPeer implements rpc.PeerFeedback.
+func (m *checkpointMirror) RecordBadPeer() {
+	m.recordFailures(cpMirrorMaxFailures)
+}
+
 type chunkHeap struct {
 	array  []*checkpoint.ChunkMetadata
 	length int
@@ -71,6 +163,41 @@ func (h *chunkHeap) Pop() interface{} {
 	return ret
 }
 
+// fetchCheckpointChunk fetches the given checkpoint chunk, preferring configured HTTP mirrors and
+// falling back to peers.
+func (n *Node) fetchCheckpointChunk(ctx context.Context, chunk *checkpoint.ChunkMetadata) ([]byte, rpc.PeerFeedback, error) {
+	// Spread chunks among mirrors so that a single mirror is not used for all requests.
+	for i := range n.checkpointMirrors {
+		mirror := n.checkpointMirrors[(int(chunk.Index)+i)%len(n.checkpointMirrors)]
+		if !mirror.usable() {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := mirror.provider.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+			n.logger.Warn("failed to fetch chunk from checkpoint mirror",
+				"err", err,
+				"mirror", mirror.url,
+				"chunk", chunk.Index,
+			)
+			mirror.RecordFailure()
+			continue
+		}
+		return buf.Bytes(), mirror, nil
+	}
+
+	rsp, pf, err := n.storageSync.GetCheckpointChunk(ctx, &storageSync.GetCheckpointChunkRequest{
+		Version: chunk.Version,
+		Root:    chunk.Root,
+		Index:   chunk.Index,
+		Digest:  chunk.Digest,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp.Chunk, pf, nil
+}
+
 func (n *Node) checkpointChunkFetcher(
 	ctx context.Context,
 	chunkDispatchCh chan *checkpoint.ChunkMetadata,
@@ -92,15 +219,10 @@ func (n *Node) checkpointChunkFetcher(
 		chunkCtx, cancel := context.WithTimeout(ctx, cpRestoreTimeout)
 		defer cancel()
 
-		// Fetch chunk from peers.
-		rsp, pf, err := n.storageSync.GetCheckpointChunk(chunkCtx, &storageSync.GetCheckpointChunkRequest{
-			Version: chunk.Version,
-			Root:    chunk.Root,
-			Index:   chunk.Index,
-			Digest:  chunk.Digest,
-		})
+		// Fetch chunk from mirrors or peers.
+		data, pf, err := n.fetchCheckpointChunk(chunkCtx, chunk)
 		if err != nil {
-			n.logger.Error("failed to fetch chunk from peers",
+			n.logger.Error("failed to fetch chunk",
 				"err", err,
 				"chunk", chunk.Index,
 			)
@@ -109,7 +231,7 @@ func (n *Node) checkpointChunkFetcher(
 		}
 
 		// Restore fetched chunk.
-		done, err := n.localStorage.Checkpointer().RestoreChunk(chunkCtx, chunk.Index, bytes.NewBuffer(rsp.Chunk))
+		done, err := n.localStorage.Checkpointer().RestoreChunk(chunkCtx, chunk.Index, bytes.NewBuffer(data))
 		cancel()
 
 		switch {
@@ -264,10 +386,34 @@ func (n *Node) getCheckpointList() ([]*checkpoint.Metadata, error) {
 	ctx, cancel := context.WithTimeout(n.ctx, cpListsTimeout)
 	defer cancel()
 
+	var list []*checkpoint.Metadata
 	cps, err := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
 		Version: 1,
 	})
-	if err != nil {
+	if err == nil {
+		list = append(list, cps.Checkpoints...)
+	}
+
+	// Also fetch checkpoints from any configured mirrors. Only checkpoints for known roots are
+	// used and all chunks are verified during restoration so mirrors need not be trusted.
+	var mirrorsOk bool
+	for _, mirror := range n.checkpointMirrors {
+		mcps, merr := mirror.provider.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+			Version: 1,
+		})
+		if merr != nil {
+			n.logger.Warn("failed to retrieve checkpoints from checkpoint mirror",
+				"err", merr,
+				"mirror", mirror.url,
+			)
+			mirror.RecordFailure()
+			continue
+		}
+		mirrorsOk = true
+		list = append(list, mcps...)
+	}
+
+	if err != nil && !mirrorsOk {
 		n.logger.Error("failed to retrieve any checkpoints",
 			"err", err,
 		)
@@ -275,7 +421,6 @@ func (n *Node) getCheckpointList() ([]*checkpoint.Metadata, error) {
 	}
 
 	// Prepare the list: sort and deduplicate.
-	list := cps.Checkpoints
 	sort.Slice(list, func(i, j int) bool {
 		// Descending!
 		if list[j].Root.Version == list[i].Root.Version {
@@ -291,6 +436,7 @@ func (n *Node) getCheckpointList() ([]*checkpoint.Metadata, error) {
 			retList[cursor] = list[i]
 			cursor++
 		}
+		prevCheckpoint = list[i]
 	}
 
 	return retList[:cursor], nil
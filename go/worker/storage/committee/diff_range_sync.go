@@ -0,0 +1,150 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/rpc"
+	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
+)
+
+// diffRangeSyncRetryDelay is the minimum delay between failed diff range sync attempts.
+const diffRangeSyncRetryDelay = 1 * time.Minute
+
+// DiffRangeSyncConfig is the diff range sync configuration.
+type DiffRangeSyncConfig struct {
+	// Threshold is the number of rounds that the node must be behind before it catches up by
+	// fetching a compacted write log spanning many rounds instead of syncing round by round.
+	//
+	// The intermediate rounds are skipped and never stored locally, so the node is unable to serve
+	// GetDiff requests for them to other nodes.
+	//
+	// Zero disables diff range sync.
+	Threshold uint64
+}
+
+// shouldSyncDiffRange checks whether the node is far enough behind the given round that it should
+// catch up via diff range sync.
+func (n *Node) shouldSyncDiffRange(lastSyncedRound, round uint64) bool {
+	if n.diffRangeSyncCfg.Threshold == 0 || lastSyncedRound == n.undefinedRound {
+		return false
+	}
+	if time.Since(n.diffRangeSyncLastFailure) < diffRangeSyncRetryDelay {
+		return false
+	}
+	return round > lastSyncedRound && round-lastSyncedRound > n.diffRangeSyncCfg.Threshold
+}
+
+// syncDiffRange catches up from the last synced round towards the given block by fetching
+// a compacted state write log spanning all intermediate rounds together with the I/O write log of
+// the target round. The intermediate rounds are skipped and will not be available locally.
+func (n *Node) syncDiffRange(lastSyncedRound uint64, blk *block.Block) (*blockSummary, error) {
+	// Limit the number of rounds to what a single request can span.
+	if blk.Header.Round-lastSyncedRound > storageSync.MaxGetDiffRangeRounds {
+		var err error
+		blk, err = n.commonNode.Runtime.History().GetBlock(n.ctx, lastSyncedRound+storageSync.MaxGetDiffRangeRounds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target block: %w", err)
+		}
+	}
+
+	_, _, startRoot := n.GetLastSynced()
+	summary := summaryFromBlock(blk)
+	var ioRoot, stateRoot storageApi.Root
+	for _, root := range summary.Roots {
+		switch root.Type {
+		case storageApi.RootTypeIO:
+			ioRoot = root
+		case storageApi.RootTypeState:
+			stateRoot = root
+		}
+	}
+
+	n.logger.Info("syncing diff range, intermediate rounds will not be available locally",
+		"start_root", startRoot,
+		"end_root", stateRoot,
+	)
+
+	ctx, cancel := context.WithCancel(n.ctx)
+	defer cancel()
+
+	stateRsp, statePf, err := n.storageSync.GetDiffRange(ctx, &storageSync.GetDiffRangeRequest{
+		StartRoot: startRoot,
+		EndRoot:   stateRoot,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch state diff range: %w", err)
+	}
+
+	// I/O roots aren't chained, so fetch the I/O write log of the target round only.
+	emptyIORoot := storageApi.Root{
+		Namespace: ioRoot.Namespace,
+		Version:   ioRoot.Version,
+		Type:      storageApi.RootTypeIO,
+	}
+	emptyIORoot.Hash.Empty()
+	var (
+		ioWriteLog storageApi.WriteLog
+		ioPf       = rpc.NewNopPeerFeedback()
+	)
+	if !ioRoot.Hash.IsEmpty() {
+		var ioRsp *storageSync.GetDiffResponse
+		ioRsp, ioPf, err = n.storageSync.GetDiff(ctx, &storageSync.GetDiffRequest{
+			StartRoot: emptyIORoot,
+			EndRoot:   ioRoot,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch I/O diff: %w", err)
+		}
+		ioWriteLog = ioRsp.WriteLog
+	}
+
+	// Apply both write logs as a multipart insert so that the target round can be finalized even
+	// though the intermediate rounds are missing.
+	ndb := n.localStorage.NodeDB()
+	if err = ndb.StartMultipartInsert(blk.Header.Round); err != nil {
+		return nil, fmt.Errorf("failed to start multipart insert: %w", err)
+	}
+	defer func() {
+		if err := ndb.AbortMultipartInsert(); err != nil {
+			n.logger.Error("failed to abort multipart insert", "err", err)
+		}
+	}()
+
+	apply := func(oldRoot, newRoot storageApi.Root, wl storageApi.WriteLog, pf rpc.PeerFeedback) error {
+		tree := mkvs.NewWithRoot(nil, ndb, oldRoot)
+		defer tree.Close()
+
+		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl)); err != nil {
+			return fmt.Errorf("failed to apply write log for root %s: %w", newRoot, err)
+		}
+		_, rootHash, err := tree.Commit(ctx, newRoot.Namespace, newRoot.Version, mkvs.Multipart())
+		if err != nil {
+			return fmt.Errorf("failed to commit root %s: %w", newRoot, err)
+		}
+		if !rootHash.Equal(&newRoot.Hash) {
+			pf.RecordBadPeer()
+			return fmt.Errorf("%w: root %s (got: %s)", storageApi.ErrExpectedRootMismatch, newRoot, rootHash)
+		}
+		pf.RecordSuccess()
+		return nil
+	}
+	if err = apply(startRoot, stateRoot, stateRsp.WriteLog, statePf); err != nil {
+		return nil, err
+	}
+	if !ioRoot.Hash.IsEmpty() {
+		if err = apply(emptyIORoot, ioRoot, ioWriteLog, ioPf); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = ndb.Finalize(n.ctx, summary.Roots); err != nil {
+		return nil, fmt.Errorf("failed to finalize round %d: %w", blk.Header.Round, err)
+	}
+	return summary, nil
+}
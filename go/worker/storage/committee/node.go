@@ -145,8 +145,14 @@ type Node struct { // nolint: maligned
 	workerCommonCfg workerCommon.Config
 
 	checkpointer         checkpoint.Checkpointer
+	checkpointerLock     sync.Mutex
+	checkpointerPaused   bool
 	checkpointSyncCfg    *CheckpointSyncConfig
 	checkpointSyncForced bool
+	checkpointMirrors    []*checkpointMirror
+
+	diffRangeSyncCfg         *DiffRangeSyncConfig
+	diffRangeSyncLastFailure time.Time
 
 	syncedLock   sync.RWMutex
 	syncedState  watcherState
@@ -175,6 +181,7 @@ func NewNode(
 	localStorage storageApi.LocalBackend,
 	checkpointerCfg *checkpoint.CheckpointerConfig,
 	checkpointSyncCfg *CheckpointSyncConfig,
+	diffRangeSyncCfg *DiffRangeSyncConfig,
 ) (*Node, error) {
 	initMetrics()
 
@@ -195,6 +202,7 @@ func NewNode(
 		stateStore: store,
 
 		checkpointSyncCfg: checkpointSyncCfg,
+		diffRangeSyncCfg:  diffRangeSyncCfg,
 
 		blockCh:    channels.NewInfiniteChannel(),
 		diffCh:     make(chan *fetchedDiff),
@@ -209,6 +217,9 @@ func NewNode(
 	if err := checkpointSyncCfg.Validate(); err != nil {
 		return nil, fmt.Errorf("bad checkpoint sync configuration: %w", err)
 	}
+	for _, mirror := range checkpointSyncCfg.HTTPMirrors {
+		n.checkpointMirrors = append(n.checkpointMirrors, newCheckpointMirror(mirror, commonNode.Runtime.ID()))
+	}
 
 	n.syncedState.LastBlock.Round = defaultUndefinedRound
 	rtID := commonNode.Runtime.ID()
@@ -225,6 +236,7 @@ func NewNode(
 			Name:            "runtime",
 			Namespace:       commonNode.Runtime.ID(),
 			CheckInterval:   checkpointerCfg.CheckInterval,
+			Retention:       checkpointerCfg.Retention,
 			RootsPerVersion: 2, // State root and I/O root.
 			GetParameters: func(ctx context.Context) (*checkpoint.CreationParameters, error) {
 				rt, rerr := commonNode.Runtime.ActiveDescriptor(ctx)
@@ -370,7 +382,43 @@ func (n *Node) PauseCheckpointer(pause bool) error {
 	if !commonFlags.DebugDontBlameOasis() {
 		return api.ErrCantPauseCheckpointer
 	}
+	if n.checkpointer == nil {
+		return api.ErrCheckpointerDisabled
+	}
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
 	n.checkpointer.Pause(pause)
+	n.checkpointerPaused = pause
+	return nil
+}
+
+// GetStorageStats returns statistics about the local node database.
+func (n *Node) GetStorageStats(ctx context.Context) (*storageApi.NodeDBStats, error) {
+	return n.localStorage.NodeDB().Stats(ctx)
+}
+
+// CompactStorage compacts the local node database.
+//
+// The checkpointer is paused while compaction is in progress so that checkpoint creation does not
+// compete with compaction for disk bandwidth.
+func (n *Node) CompactStorage(ctx context.Context) error {
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
+	if n.checkpointer != nil && !n.checkpointerPaused {
+		n.checkpointer.Pause(true)
+		defer n.checkpointer.Pause(false)
+	}
+
+	n.logger.Info("compacting local storage")
+	if err := n.localStorage.NodeDB().Compact(ctx); err != nil {
+		n.logger.Error("failed to compact local storage",
+			"err", err,
+		)
+		return err
+	}
+	n.logger.Info("local storage compaction completed")
 	return nil
 }
 
@@ -585,6 +633,35 @@ func (n *Node) initGenesis(rt *registryApi.Runtime, genesisBlock *block.Block) e
 	return nil
 }
 
+// getRestoredState checks whether the latest finalized version in the local database has been
+// restored without the worker being aware of it and matches the corresponding block in the local
+// runtime history. In this case the summary of that block is returned.
+func (n *Node) getRestoredState(genesisRound uint64) (*blockSummary, error) {
+	latestVersion, err := n.localStorage.NodeDB().GetLatestVersion(n.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if latestVersion <= genesisRound {
+		return nil, nil
+	}
+
+	blk, err := n.commonNode.Runtime.History().GetBlock(n.ctx, latestVersion)
+	switch {
+	case err == nil:
+	case errors.Is(err, roothashApi.ErrNotFound):
+		// Without an authoritative block the state cannot be used.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to get block for round %d: %w", latestVersion, err)
+	}
+	for _, root := range blk.Header.StorageRoots() {
+		if !n.localStorage.NodeDB().HasRoot(root) {
+			return nil, nil
+		}
+	}
+	return summaryFromBlock(blk), nil
+}
+
 func (n *Node) flushSyncedState(summary *blockSummary) uint64 {
 	n.syncedLock.Lock()
 	defer n.syncedLock.Unlock()
@@ -754,6 +831,10 @@ func (n *Node) consensusCheckpointSyncer() {
 
 // This is only called from the main worker goroutine, so no locking should be necessary.
 func (n *Node) nudgeAvailability(lastSynced, latest uint64) {
+	if n.roleProvider == nil {
+		// Nodes without a role provider (e.g., storage replicas) do not register.
+		return
+	}
 	if lastSynced == n.undefinedRound || latest == n.undefinedRound {
 		return
 	}
@@ -810,6 +891,23 @@ func (n *Node) worker() { // nolint: gocyclo
 		cachedLastRound = n.undefinedRound
 	}
 
+	// Check if the state has been restored out-of-band (e.g., imported from a checkpoint archive).
+	if cachedLastRound == n.undefinedRound {
+		var summary *blockSummary
+		if summary, err = n.getRestoredState(genesisBlock.Header.Round); err != nil {
+			n.logger.Error("failed to check for restored state",
+				"err", err,
+			)
+			return
+		}
+		if summary != nil {
+			n.logger.Info("using previously restored state",
+				"round", summary.Round,
+			)
+			cachedLastRound = n.flushSyncedState(summary)
+		}
+	}
+
 	// Initialize genesis from the runtime descriptor.
 	if cachedLastRound == n.undefinedRound {
 		var rt *registryApi.Runtime
@@ -1164,6 +1262,43 @@ mainLoop:
 			latestBlockRound = blk.Header.Round
 			n.nudgeAvailability(cachedLastRound, latestBlockRound)
 
+			// In case we are far behind and nothing is in flight, try to catch up using a
+			// compacted diff spanning many rounds instead of syncing round by round.
+			if lastFullyAppliedRound == cachedLastRound && len(syncingRounds) == 0 &&
+				len(*outOfOrderDoneDiffs) == 0 && len(*outOfOrderFinalizable) == 0 &&
+				n.shouldSyncDiffRange(cachedLastRound, blk.Header.Round) {
+				summary, err := n.syncDiffRange(cachedLastRound, blk)
+				if err != nil {
+					n.logger.Warn("failed to sync diff range, falling back to incremental sync",
+						"err", err,
+						"last_synced", cachedLastRound,
+						"round", blk.Header.Round,
+					)
+					n.diffRangeSyncLastFailure = time.Now()
+				} else {
+					n.logger.Info("synced diff range",
+						"last_synced", cachedLastRound,
+						"round", summary.Round,
+					)
+					for round := range hashCache {
+						if round < summary.Round {
+							delete(hashCache, round)
+						}
+					}
+					hashCache[summary.Round] = summary
+					cachedLastRound = n.flushSyncedState(summary)
+					lastFullyAppliedRound = cachedLastRound
+					storageWorkerLastSyncedRound.With(n.getMetricLabels()).Set(float64(summary.Round))
+					storageWorkerLastFullRound.With(n.getMetricLabels()).Set(float64(summary.Round))
+
+					n.nudgeAvailability(cachedLastRound, latestBlockRound)
+
+					if n.checkpointer != nil {
+						n.checkpointer.NotifyNewVersion(summary.Round)
+					}
+				}
+			}
+
 			if _, ok := hashCache[lastFullyAppliedRound]; !ok && lastFullyAppliedRound == n.undefinedRound {
 				dummy := blockSummary{
 					Namespace: blk.Header.Namespace,
@@ -16,6 +16,7 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 const (
@@ -29,9 +30,41 @@ const (
 	CfgWorkerCheckpointerDisabled = "worker.storage.checkpointer.disabled"
 	// CfgWorkerCheckpointCheckInterval configures the checkpointer check interval.
 	CfgWorkerCheckpointCheckInterval = "worker.storage.checkpointer.check_interval"
+	// CfgWorkerCheckpointerRetentionKeepEvery configures the checkpointer to create and keep
+	// checkpoints at every N-th round.
+	CfgWorkerCheckpointerRetentionKeepEvery = "worker.storage.checkpointer.retention.keep_every"
+	// CfgWorkerCheckpointerRetentionKeepLast configures the number of most recent checkpoints kept
+	// by the checkpointer.
+	CfgWorkerCheckpointerRetentionKeepLast = "worker.storage.checkpointer.retention.keep_last"
+	// CfgWorkerCheckpointerRetentionKeepSince configures the checkpointer to keep all checkpoints
+	// at or after the given round.
+	CfgWorkerCheckpointerRetentionKeepSince = "worker.storage.checkpointer.retention.keep_since"
+	// CfgWorkerCheckpointerRetentionRuntimes configures per-runtime checkpoint retention policies
+	// which override the defaults. It is a map from runtime identifiers to policies containing any
+	// of the keep_every, keep_last and keep_since keys.
+	CfgWorkerCheckpointerRetentionRuntimes = "worker.storage.checkpointer.retention.runtimes"
 
 	// CfgCheckpointSyncDisabled disables syncing from checkpoints on worker startup.
 	CfgWorkerCheckpointSyncDisabled = "worker.storage.checkpoint_sync.disabled"
+	// CfgWorkerCheckpointSyncHTTPMirrors configures the base URLs of HTTP checkpoint mirrors.
+	CfgWorkerCheckpointSyncHTTPMirrors = "worker.storage.checkpoint_sync.http_mirrors"
+
+	// CfgWorkerCheckpointHTTPAddress enables serving local checkpoints over HTTP at the given
+	// address.
+	CfgWorkerCheckpointHTTPAddress = "worker.storage.checkpoint_http.address"
+	// CfgWorkerCheckpointHTTPTLSCertFile configures the TLS certificate file of the checkpoint
+	// HTTP endpoint.
+	CfgWorkerCheckpointHTTPTLSCertFile = "worker.storage.checkpoint_http.tls.cert_file"
+	// CfgWorkerCheckpointHTTPTLSKeyFile configures the TLS private key file of the checkpoint
+	// HTTP endpoint.
+	CfgWorkerCheckpointHTTPTLSKeyFile = "worker.storage.checkpoint_http.tls.key_file"
+
+	// CfgWorkerDiffRangeSyncThreshold configures the number of rounds the node must be behind
+	// before it catches up using compacted diffs spanning many rounds.
+	//
+	// Rounds skipped this way are never stored locally, so the node is unable to serve GetDiff
+	// requests (or state queries) for them.
+	CfgWorkerDiffRangeSyncThreshold = "worker.storage.diff_range_sync.threshold"
 
 	// CfgBackend configures the storage backend flag.
 	CfgBackend = "worker.storage.backend"
@@ -68,7 +101,7 @@ func NewLocalBackend(
 		impl api.LocalBackend
 	)
 	switch cfg.Backend {
-	case database.BackendNameBadgerDB:
+	case database.BackendNameBadgerDB, database.BackendNameBBoltDB:
 		cfg.DB = GetLocalBackendDBDir(dataDir, cfg.Backend)
 		impl, err = database.New(cfg)
 	default:
@@ -86,14 +119,52 @@ func NewLocalBackend(
 	return api.NewMetricsWrapper(impl).(api.LocalBackend), nil
 }
 
+// getCheckpointRetentionPolicy returns the node-local checkpoint retention policy for the given
+// runtime based on the configuration flags.
+func getCheckpointRetentionPolicy(runtimeID common.Namespace) checkpoint.RetentionPolicy {
+	policy := checkpoint.RetentionPolicy{
+		KeepEvery: viper.GetUint64(CfgWorkerCheckpointerRetentionKeepEvery),
+		KeepLast:  viper.GetUint64(CfgWorkerCheckpointerRetentionKeepLast),
+		KeepSince: viper.GetUint64(CfgWorkerCheckpointerRetentionKeepSince),
+	}
+
+	// Apply any per-runtime overrides.
+	sub := viper.Sub(CfgWorkerCheckpointerRetentionRuntimes)
+	if sub == nil {
+		return policy
+	}
+	rtSub := sub.Sub(runtimeID.String())
+	if rtSub == nil {
+		return policy
+	}
+	if rtSub.IsSet("keep_every") {
+		policy.KeepEvery = rtSub.GetUint64("keep_every")
+	}
+	if rtSub.IsSet("keep_last") {
+		policy.KeepLast = rtSub.GetUint64("keep_last")
+	}
+	if rtSub.IsSet("keep_since") {
+		policy.KeepSince = rtSub.GetUint64("keep_since")
+	}
+	return policy
+}
+
 func init() {
 	Flags.Uint(cfgWorkerFetcherCount, 4, "Number of concurrent storage diff fetchers")
 	Flags.Bool(CfgWorkerPublicRPCEnabled, false, "Enable storage RPC access for all nodes")
 	Flags.Bool(CfgWorkerCheckpointerDisabled, false, "Disable the storage checkpointer")
 	Flags.Duration(CfgWorkerCheckpointCheckInterval, 1*time.Minute, "Storage checkpointer check interval")
+	Flags.Uint64(CfgWorkerCheckpointerRetentionKeepEvery, 0, "Create and keep storage checkpoints at every N-th round (0 disables)")
+	Flags.Uint64(CfgWorkerCheckpointerRetentionKeepLast, 0, "Number of most recent storage checkpoints to keep (at least the runtime-configured number is kept)")
+	Flags.Uint64(CfgWorkerCheckpointerRetentionKeepSince, 0, "Keep all storage checkpoints at or after the given round (0 disables)")
 	Flags.Bool(CfgWorkerCheckpointSyncDisabled, false, "Disable initial storage sync from checkpoints")
+	Flags.StringSlice(CfgWorkerCheckpointSyncHTTPMirrors, []string{}, "Base URLs of HTTP checkpoint mirrors used during initial storage sync")
+	Flags.String(CfgWorkerCheckpointHTTPAddress, "", "Serve local checkpoints over HTTP at the given address (empty disables)")
+	Flags.String(CfgWorkerCheckpointHTTPTLSCertFile, "", "TLS certificate file for the checkpoint HTTP endpoint (enables HTTPS)")
+	Flags.String(CfgWorkerCheckpointHTTPTLSKeyFile, "", "TLS private key file for the checkpoint HTTP endpoint")
+	Flags.Uint64(CfgWorkerDiffRangeSyncThreshold, 0, "Number of rounds behind after which storage is synced using compacted diffs, skipping intermediate rounds which can then not be served to other nodes (0 disables)")
 
-	Flags.String(CfgBackend, database.BackendNameBadgerDB, "Storage backend")
+	Flags.String(CfgBackend, database.BackendNameBadgerDB, fmt.Sprintf("Storage backend (%s, %s)", database.BackendNameBadgerDB, database.BackendNameBBoltDB))
 	Flags.String(CfgMaxCacheSize, "64mb", "Maximum in-memory cache size")
 
 	Flags.Bool(cfgCrashEnabled, false, "UNSAFE: Enable the crashing storage wrapper")
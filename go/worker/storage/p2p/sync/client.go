@@ -14,6 +14,10 @@ type Client interface {
 	// to the second one.
 	GetDiff(ctx context.Context, request *GetDiffRequest) (*GetDiffResponse, rpc.PeerFeedback, error)
 
+	// GetDiffRange requests a compacted write log of entries that must be applied to get from the
+	// first given root to the second one, which may be many rounds apart.
+	GetDiffRange(ctx context.Context, request *GetDiffRangeRequest) (*GetDiffRangeResponse, rpc.PeerFeedback, error)
+
 	// GetCheckpoints returns a list of checkpoint metadata for all known checkpoints.
 	GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) (*GetCheckpointsResponse, error)
 
@@ -34,6 +38,15 @@ func (c *client) GetDiff(ctx context.Context, request *GetDiffRequest) (*GetDiff
 	return &rsp, pf, nil
 }
 
+func (c *client) GetDiffRange(ctx context.Context, request *GetDiffRangeRequest) (*GetDiffRangeResponse, rpc.PeerFeedback, error) {
+	var rsp GetDiffRangeResponse
+	pf, err := c.rc.Call(ctx, MethodGetDiffRange, request, &rsp, MaxGetDiffRangeResponseTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rsp, pf, nil
+}
+
 func (c *client) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) (*GetCheckpointsResponse, error) {
 	var rsp GetCheckpointsResponse
 	rsps, pfs, err := c.rc.CallMulti(ctx, MethodGetCheckpoints, request, rsp, MaxGetCheckpointsResponseTime, MaxGetCheckpointsParallelRequests)
@@ -13,7 +13,7 @@ import (
 const StorageSyncProtocolID = "storagesync"
 
 // StorageSyncProtocolVersion is the supported version of the storage sync protocol.
-var StorageSyncProtocolVersion = version.Version{Major: 1, Minor: 0, Patch: 0}
+var StorageSyncProtocolVersion = version.Version{Major: 1, Minor: 1, Patch: 0}
 
 // Constants related to the GetDiff method.
 const (
@@ -32,6 +32,30 @@ type GetDiffResponse struct {
 	WriteLog storage.WriteLog `json:"write_log,omitempty"`
 }
 
+// Constants related to the GetDiffRange method.
+const (
+	MethodGetDiffRange          = "GetDiffRange"
+	MaxGetDiffRangeResponseTime = 60 * time.Second
+	// MaxGetDiffRangeRounds is the maximum number of rounds that a single GetDiffRange request
+	// can span.
+	MaxGetDiffRangeRounds = 1_000
+	// MaxGetDiffRangeWriteLogSize is the maximum total size (in bytes) of keys and values in the
+	// write logs that the server walks to serve a single GetDiffRange request. Requests for ranges
+	// with larger write logs fail and the client should fall back to syncing a shorter range.
+	MaxGetDiffRangeWriteLogSize = 16 * 1024 * 1024
+)
+
+// GetDiffRangeRequest is a GetDiffRange request.
+type GetDiffRangeRequest struct {
+	StartRoot storage.Root `json:"start_root"`
+	EndRoot   storage.Root `json:"end_root"`
+}
+
+// GetDiffRangeResponse is a response to a GetDiffRange request.
+type GetDiffRangeResponse struct {
+	WriteLog storage.WriteLog `json:"write_log,omitempty"`
+}
+
 // Constants related to the GetCheckpoints method.
 const (
 	MethodGetCheckpoints              = "GetCheckpoints"
@@ -42,6 +66,10 @@ const (
 // GetCheckpointsRequest is a GetCheckpoints request.
 type GetCheckpointsRequest struct {
 	Version uint16 `json:"version"`
+
+	// RootVersion specifies an optional root version to limit the request to. This is useful when
+	// querying nodes that retain many checkpoints.
+	RootVersion *uint64 `json:"root_version,omitempty"`
 }
 
 // GetCheckpointsResponse is a response to a GetCheckpoints request.
@@ -24,6 +24,13 @@ func (s *service) HandleRequest(ctx context.Context, method string, body cbor.Ra
 		}
 
 		return s.handleGetDiff(ctx, &rq)
+	case MethodGetDiffRange:
+		var rq GetDiffRangeRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.handleGetDiffRange(ctx, &rq)
 	case MethodGetCheckpoints:
 		var rq GetCheckpointsRequest
 		if err := cbor.Unmarshal(body, &rq); err != nil {
@@ -71,9 +78,34 @@ func (s *service) handleGetDiff(ctx context.Context, request *GetDiffRequest) (*
 	return &rsp, nil
 }
 
+func (s *service) handleGetDiffRange(ctx context.Context, request *GetDiffRangeRequest) (*GetDiffRangeResponse, error) {
+	// Compacting write logs requires access to the local node database.
+	backend, ok := s.backend.(storage.LocalBackend)
+	if !ok {
+		return nil, rpc.ErrMethodNotSupported
+	}
+	if request.EndRoot.Version < request.StartRoot.Version ||
+		request.EndRoot.Version-request.StartRoot.Version > MaxGetDiffRangeRounds {
+		return nil, rpc.ErrBadRequest
+	}
+
+	wl, err := backend.GetDiffRange(ctx, &storage.GetDiffRangeRequest{
+		StartRoot: request.StartRoot,
+		EndRoot:   request.EndRoot,
+		MaxSize:   MaxGetDiffRangeWriteLogSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GetDiffRangeResponse{
+		WriteLog: wl,
+	}, nil
+}
+
 func (s *service) handleGetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) (*GetCheckpointsResponse, error) {
 	cps, err := s.backend.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
-		Version: request.Version,
+		Version:     request.Version,
+		RootVersion: request.RootVersion,
 	})
 	if err != nil {
 		return nil, err
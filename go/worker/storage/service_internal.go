@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
@@ -49,3 +50,21 @@ func (w *Worker) PauseCheckpointer(ctx context.Context, request *api.PauseCheckp
 
 	return node.PauseCheckpointer(request.Pause)
 }
+
+func (w *Worker) GetStats(ctx context.Context, request *api.GetStatsRequest) (*storage.NodeDBStats, error) {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return nil, api.ErrRuntimeNotFound
+	}
+
+	return node.GetStorageStats(ctx)
+}
+
+func (w *Worker) Compact(ctx context.Context, request *api.CompactRequest) error {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return api.ErrRuntimeNotFound
+	}
+
+	return node.CompactStorage(ctx)
+}
@@ -69,6 +69,13 @@ func (s *syncedStorage) Apply(ctx context.Context, request *storage.ApplyRequest
 	return s.wrapped.Apply(ctx, request)
 }
 
+func (s *syncedStorage) GetDiffRange(ctx context.Context, request *storage.GetDiffRangeRequest) (storage.WriteLog, error) {
+	if err := s.wait(ctx, request.EndRoot); err != nil {
+		return nil, fmt.Errorf("worker/storage: GetDiffRange to local storage failed: %w", err)
+	}
+	return s.wrapped.GetDiffRange(ctx, request)
+}
+
 func (s *syncedStorage) Checkpointer() checkpoint.CreateRestorer {
 	return s.wrapped.Checkpointer()
 }
@@ -37,6 +37,8 @@ type Worker struct {
 	runtimes   map[common.Namespace]*committee.Node
 	watchState *persistent.ServiceStore
 	fetchPool  *workerpool.Pool
+
+	checkpointHTTP *checkpointHTTPServer
 }
 
 // New constructs a new storage worker.
@@ -49,8 +51,9 @@ func New(
 ) (*Worker, error) {
 	var enabled bool
 	switch commonWorker.RuntimeRegistry.Mode() {
-	case runtimeRegistry.RuntimeModeCompute, runtimeRegistry.RuntimeModeClient:
-		// When configured in compute or stateful client mode, enable the storage worker.
+	case runtimeRegistry.RuntimeModeCompute, runtimeRegistry.RuntimeModeClient, runtimeRegistry.RuntimeModeStorageReplica:
+		// When configured in compute, stateful client or storage replica mode, enable the storage
+		// worker.
 		enabled = true
 	default:
 		enabled = false
@@ -80,11 +83,15 @@ func New(
 		return nil, err
 	}
 
-	var checkpointerCfg *checkpoint.CheckpointerConfig
-	if !viper.GetBool(CfgWorkerCheckpointerDisabled) {
-		checkpointerCfg = &checkpoint.CheckpointerConfig{
-			CheckInterval: viper.GetDuration(CfgWorkerCheckpointCheckInterval),
+	checkpointerCfg := newCheckpointerConfig(commonWorker.RuntimeRegistry.Mode())
+
+	if address := viper.GetString(CfgWorkerCheckpointHTTPAddress); address != "" {
+		certFile := viper.GetString(CfgWorkerCheckpointHTTPTLSCertFile)
+		keyFile := viper.GetString(CfgWorkerCheckpointHTTPTLSKeyFile)
+		if (certFile == "") != (keyFile == "") {
+			return nil, fmt.Errorf("storage worker: both TLS certificate and key must be configured for the checkpoint HTTP endpoint")
 		}
+		s.checkpointHTTP = newCheckpointHTTPServer(address, certFile, keyFile)
 	}
 
 	// Start storage node for every runtime.
@@ -106,15 +113,21 @@ func (w *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 		"runtime_id", id,
 	)
 
-	rp, err := w.registration.NewRuntimeRoleProvider(node.RoleComputeWorker, id)
-	if err != nil {
-		return fmt.Errorf("failed to create role provider: %w", err)
-	}
-	var rpRPC registration.RoleProvider
-	if viper.GetBool(CfgWorkerPublicRPCEnabled) {
-		rpRPC, err = w.registration.NewRuntimeRoleProvider(node.RoleStorageRPC, id)
+	// Storage replicas never register so they do not need any role providers.
+	var (
+		rp, rpRPC registration.RoleProvider
+		err       error
+	)
+	if !w.isReplica() {
+		rp, err = w.registration.NewRuntimeRoleProvider(node.RoleComputeWorker, id)
 		if err != nil {
-			return fmt.Errorf("failed to create rpc role provider: %w", err)
+			return fmt.Errorf("failed to create role provider: %w", err)
+		}
+		if viper.GetBool(CfgWorkerPublicRPCEnabled) {
+			rpRPC, err = w.registration.NewRuntimeRoleProvider(node.RoleStorageRPC, id)
+			if err != nil {
+				return fmt.Errorf("failed to create rpc role provider: %w", err)
+			}
 		}
 	}
 
@@ -128,6 +141,12 @@ func (w *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 		return fmt.Errorf("can't create local storage backend: %w", err)
 	}
 
+	if checkpointerCfg != nil {
+		rtCheckpointerCfg := *checkpointerCfg
+		rtCheckpointerCfg.Retention = getCheckpointRetentionPolicy(id)
+		checkpointerCfg = &rtCheckpointerCfg
+	}
+
 	node, err := committee.NewNode(
 		commonNode,
 		w.fetchPool,
@@ -140,6 +159,10 @@ func (w *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 		&committee.CheckpointSyncConfig{
 			Disabled:          viper.GetBool(CfgWorkerCheckpointSyncDisabled),
 			ChunkFetcherCount: viper.GetUint(cfgWorkerFetcherCount),
+			HTTPMirrors:       viper.GetStringSlice(CfgWorkerCheckpointSyncHTTPMirrors),
+		},
+		&committee.DiffRangeSyncConfig{
+			Threshold: viper.GetUint64(CfgWorkerDiffRangeSyncThreshold),
 		},
 	)
 	if err != nil {
@@ -149,6 +172,10 @@ func (w *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 	commonNode.AddHooks(node)
 	w.runtimes[id] = node
 
+	if w.checkpointHTTP != nil {
+		w.checkpointHTTP.registerRuntime(id, localStorage.Checkpointer())
+	}
+
 	w.logger.Info("new runtime registered",
 		"runtime_id", id,
 	)
@@ -156,6 +183,23 @@ func (w *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 	return nil
 }
 
+// newCheckpointerConfig returns the checkpointer configuration for the given runtime mode or nil
+// in case checkpoints should not be created.
+func newCheckpointerConfig(mode runtimeRegistry.RuntimeMode) *checkpoint.CheckpointerConfig {
+	// Storage replicas only follow finalized state and never create checkpoints.
+	if viper.GetBool(CfgWorkerCheckpointerDisabled) || mode == runtimeRegistry.RuntimeModeStorageReplica {
+		return nil
+	}
+	return &checkpoint.CheckpointerConfig{
+		CheckInterval: viper.GetDuration(CfgWorkerCheckpointCheckInterval),
+	}
+}
+
+// isReplica returns true iff the node is configured as a read-only storage replica.
+func (w *Worker) isReplica() bool {
+	return w.commonWorker.RuntimeRegistry.Mode() == runtimeRegistry.RuntimeModeStorageReplica
+}
+
 // Name returns the service name.
 func (w *Worker) Name() string {
 	return "storage worker"
@@ -183,6 +227,12 @@ func (w *Worker) Start() error {
 		return nil
 	}
 
+	if w.checkpointHTTP != nil {
+		if err := w.checkpointHTTP.start(); err != nil {
+			return fmt.Errorf("storage worker: failed to start checkpoint HTTP server: %w", err)
+		}
+	}
+
 	// Wait for all runtimes to terminate.
 	go func() {
 		defer close(w.quitCh)
@@ -225,6 +275,9 @@ func (w *Worker) Stop() {
 		return
 	}
 
+	if w.checkpointHTTP != nil {
+		w.checkpointHTTP.stop()
+	}
 	for _, r := range w.runtimes {
 		r.Stop()
 	}
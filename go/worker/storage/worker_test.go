@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+)
+
+func TestCheckpointerConfig(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(viper.BindPFlags(Flags), "BindPFlags")
+	viper.Set(CfgWorkerCheckpointCheckInterval, 5*time.Second)
+
+	for _, mode := range []runtimeRegistry.RuntimeMode{
+		runtimeRegistry.RuntimeModeCompute,
+		runtimeRegistry.RuntimeModeClient,
+	} {
+		cfg := newCheckpointerConfig(mode)
+		require.NotNil(cfg, "checkpointer should be enabled in %s mode", mode)
+		require.Equal(5*time.Second, cfg.CheckInterval, "checkpointer should use the configured interval")
+	}
+
+	cfg := newCheckpointerConfig(runtimeRegistry.RuntimeModeStorageReplica)
+	require.Nil(cfg, "checkpointer should be skipped in storage replica mode")
+
+	viper.Set(CfgWorkerCheckpointerDisabled, true)
+	defer viper.Set(CfgWorkerCheckpointerDisabled, false)
+	cfg = newCheckpointerConfig(runtimeRegistry.RuntimeModeCompute)
+	require.Nil(cfg, "checkpointer should be skipped when disabled")
+}
@@ -39,12 +39,14 @@ struct ProtocolServerReadSyncer {
 pub enum Fixture {
     None,
     ConsensusMock,
+    AbsenceProofs,
 }
 
 impl fmt::Display for Fixture {
     fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
         match self {
             Fixture::ConsensusMock => write!(f, "consensus_mock"),
+            Fixture::AbsenceProofs => write!(f, "absence_proofs"),
             _ => write!(f, ""),
         }
     }
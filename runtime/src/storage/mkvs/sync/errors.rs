@@ -4,4 +4,8 @@ use thiserror::Error;
 pub enum SyncerError {
     #[error("mkvs: method not supported")]
     Unsupported,
+    #[error("verifier: key exists")]
+    KeyExists,
+    #[error("verifier: incomplete range proof")]
+    IncompleteProof,
 }
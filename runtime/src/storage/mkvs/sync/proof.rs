@@ -6,7 +6,7 @@ use io_context::Context;
 
 use crate::{
     common::crypto::hash::Hash,
-    storage::mkvs::{marshal::Marshal, tree::*},
+    storage::mkvs::{marshal::Marshal, sync::SyncerError, tree::*},
 };
 
 /// Proof entry type for full nodes.
@@ -95,6 +95,56 @@ impl ProofVerifier {
         Ok(root_node)
     }
 
+    /// Verify a proof that the given key does not exist in the tree with
+    /// the given root.
+    pub fn verify_proof_of_absence(
+        &self,
+        ctx: Context,
+        root: Hash,
+        key: &[u8],
+        proof: &Proof,
+    ) -> Result<()> {
+        let root_node = self.verify_proof(ctx, root, proof)?;
+        if self.lookup_verified(&root_node, 0, &key.to_vec())? {
+            return Err(SyncerError::KeyExists.into());
+        }
+        Ok(())
+    }
+
+    /// Check whether the given key exists in a verified subtree, following
+    /// the same lookup path as the tree itself.
+    fn lookup_verified(&self, ptr: &NodePtrRef, bit_depth: Depth, key: &Key) -> Result<bool> {
+        let ptr = ptr.borrow();
+        let node_ref = match &ptr.node {
+            Some(node_ref) => node_ref.clone(),
+            None if ptr.is_null() => return Ok(false),
+            None => return Err(SyncerError::IncompleteProof.into()),
+        };
+
+        let node = node_ref.borrow();
+        match &*node {
+            NodeBox::Internal(n) => {
+                let bit_length = bit_depth + n.label_bit_length;
+
+                // Does lookup key end here? Look into LeafNode.
+                if key.bit_length() == bit_length {
+                    return self.lookup_verified(&n.leaf_node, bit_length, key);
+                }
+                // Lookup key is too short for the current label. It's not stored.
+                if key.bit_length() < bit_length {
+                    return Ok(false);
+                }
+                // Continue recursively based on a bit value.
+                if key.get_bit(bit_length) {
+                    self.lookup_verified(&n.right, bit_length, key)
+                } else {
+                    self.lookup_verified(&n.left, bit_length, key)
+                }
+            }
+            NodeBox::Leaf(n) => Ok(n.key == *key),
+        }
+    }
+
     fn _verify_proof(&self, proof: &Proof, idx: usize) -> Result<(usize, NodePtrRef)> {
         if idx >= proof.entries.len() {
             return Err(anyhow!("verifier: malformed proof"));
@@ -239,4 +289,56 @@ uO/mFPzJZey4liX5fxf4fwcQRhM=",
             "verify proof should fail with invalid proof"
         );
     }
+
+    #[test]
+    fn test_proof_of_absence() {
+        // Test vector generated by Go (proof of absence for "foobax").
+        let test_vector_proof = base64::decode(
+            "omdlbnRyaWVziUYBAQMAYAJGAQEBAAACWB0BARQAZvbwAAMAZm9vDAAAAHZhbHVlIG9mIGZvb0gBARQAYmFwAlgh\
+Amwp/bw+jUKGRpS4j9tzMvAd+0WFIBzONyZyQjRKgj2jWB0BAAYAZm9vYmF6DwAAAHZhbHVlIG9mIGZvb2JhevZYIQIc8EBx2/CZXRtrOR76ts5uiBbK\
+v01hKla2NWuKr/93glghAoAd4YRYW3XPvN1kCU4ngOzCN3EqxnZYbSc6BRkMO4ukbnVudHJ1c3RlZF9yb290WCBPzM5lwbci/8l+Ttrj0F2Lm2elvUN2\
+m8PrxdSe5sQ71g==",
+        )
+        .unwrap();
+        let test_vector_root_hash =
+            "4fccce65c1b722ffc97e4edae3d05d8b9b67a5bd43769bc3ebc5d49ee6c43bd6";
+
+        let proof: Proof = cbor::from_slice(&test_vector_proof).expect("proof should deserialize");
+        let root_hash = Hash::from(test_vector_root_hash);
+
+        // Proof should verify.
+        let pv = ProofVerifier;
+        pv.verify_proof_of_absence(Context::background(), root_hash, b"foobax", &proof)
+            .expect("verify proof of absence should not fail with a valid proof");
+
+        // Proof should not verify for keys that exist.
+        let result =
+            pv.verify_proof_of_absence(Context::background(), root_hash, b"foobaz", &proof);
+        assert!(
+            matches!(
+                result.unwrap_err().downcast_ref::<SyncerError>(),
+                Some(SyncerError::KeyExists)
+            ),
+            "verify proof of absence should fail for an existing key"
+        );
+
+        // Proof should not verify for keys whose lookup path is not included.
+        let result = pv.verify_proof_of_absence(Context::background(), root_hash, b"zzz", &proof);
+        assert!(
+            matches!(
+                result.unwrap_err().downcast_ref::<SyncerError>(),
+                Some(SyncerError::IncompleteProof)
+            ),
+            "verify proof of absence should fail with an incomplete proof"
+        );
+
+        // Different root.
+        let bogus_hash = Hash::digest_bytes(b"i am a bogus hash");
+        let result =
+            pv.verify_proof_of_absence(Context::background(), bogus_hash, b"foobax", &proof);
+        assert!(
+            result.is_err(),
+            "verify proof of absence should fail with a proof for a different root"
+        );
+    }
 }
@@ -5,7 +5,8 @@ use std::{collections::HashSet, fs::File, io::BufReader, iter, iter::FromIterato
 use crate::{
     common::crypto::hash::Hash,
     storage::mkvs::{
-        interop::{Driver, ProtocolServer},
+        interop::{Driver, Fixture, ProtocolServer},
+        sync::{GetRequest, ProofVerifier, TreeID},
         tests,
         tree::*,
         Iterator, LogEntry, LogEntryKind, WriteLog, MKVS,
@@ -1022,6 +1023,78 @@ fn test_node_eviction() {
     );
 }
 
+#[test]
+fn test_syncer_proof_of_absence() {
+    // Keep in sync with go/storage/mkvs/interop/fixtures/absence_proofs.go.
+    let server = ProtocolServer::new(Fixture::AbsenceProofs.into());
+    let root = Root {
+        version: 1,
+        root_type: RootType::State,
+        hash: Hash::from("4fccce65c1b722ffc97e4edae3d05d8b9b67a5bd43769bc3ebc5d49ee6c43bd6"),
+        ..Default::default()
+    };
+    let present_keys: &[&[u8]] = &[b"foo", b"foobar", b"foobaz", b"moo", b"moo\x00", b"zap"];
+    let absent_keys: &[&[u8]] = &[
+        b"",
+        b"fo",
+        b"fooba",
+        b"foobax",
+        b"moo\x01",
+        b"foobarbaz",
+        b"b",
+        b"zzz",
+    ];
+
+    let remote_tree = Tree::builder()
+        .with_capacity(0, 0)
+        .with_root(root)
+        .build(server.read_sync());
+    for &key in present_keys {
+        let value = remote_tree
+            .get(Context::background(), key)
+            .expect("get")
+            .expect("get_some");
+        assert_eq!([&b"value of "[..], key].concat(), value);
+    }
+    for &key in absent_keys {
+        let value = remote_tree.get(Context::background(), key).expect("get");
+        assert!(value.is_none(), "absent keys should not be found");
+    }
+
+    // Proofs returned by the server should verify as proofs of absence only for absent keys.
+    let pv = ProofVerifier;
+    let mut read_sync = server.read_sync();
+    let mut get_proof = |key: &[u8]| {
+        read_sync
+            .sync_get(
+                Context::background(),
+                GetRequest {
+                    tree: TreeID {
+                        root,
+                        position: root.hash,
+                    },
+                    key: key.to_vec(),
+                    include_siblings: false,
+                },
+            )
+            .expect("sync_get")
+            .proof
+    };
+    for &key in absent_keys {
+        let proof = get_proof(key);
+        pv.verify_proof_of_absence(Context::background(), root.hash, key, &proof)
+            .expect("verify proof of absence should not fail for an absent key");
+    }
+    for &key in present_keys {
+        let proof = get_proof(key);
+        let result = pv.verify_proof_of_absence(Context::background(), root.hash, key, &proof);
+        assert!(
+            result.is_err(),
+            "verify proof of absence should fail for an existing key"
+        );
+    }
+}
+
 /// Location of the test vectors directory (from Go).
 const TEST_VECTORS_DIR: &str = "../go/storage/mkvs/testdata";
 
@@ -359,6 +359,22 @@ pub struct CheckTxMetadata {
 
     #[cbor(optional)]
     pub weights: Option<BTreeMap<TransactionWeight, u64>>,
+
+    /// Unique identifier of the transaction sender.
+    ///
+    /// If set, the scheduler will schedule transactions from the same sender in `sender_seq`
+    /// order and allow replacing a pending transaction with one that has the same `sender_seq`
+    /// and a higher priority.
+    #[cbor(optional)]
+    #[cbor(default)]
+    #[cbor(skip_serializing_if = "Vec::is_empty")]
+    pub sender: Vec<u8>,
+
+    /// Per-sender sequence number of the transaction (e.g., the nonce).
+    #[cbor(optional)]
+    #[cbor(default)]
+    #[cbor(skip_serializing_if = "num_traits::Zero::is_zero")]
+    pub sender_seq: u64,
 }
 
 /// Transaction weight kind.